@@ -42,6 +42,7 @@ import (
 	stakingclient "github.com/hbtc-chain/bhchain/x/staking/client"
 	"github.com/hbtc-chain/bhchain/x/supply"
 	"github.com/hbtc-chain/bhchain/x/transfer"
+	transferclient "github.com/hbtc-chain/bhchain/x/transfer/client"
 	"github.com/hbtc-chain/bhchain/x/upgrade"
 	upgradeclient "github.com/hbtc-chain/bhchain/x/upgrade/client"
 )
@@ -71,7 +72,7 @@ var (
 			token.AddTokenProposalHandler, token.TokenParamsChangeProposalHandler,
 			upgradeclient.PostProposalHandler, upgradeclient.CancelProposalHandler,
 			mappingclient.AddMappingProposalHandler, mappingclient.SwitchMappingProposalHandler,
-			stakingclient.UpdateKeyNodesProposalHandler),
+			stakingclient.UpdateKeyNodesProposalHandler, transferclient.FreezeAddressesProposalHandler),
 		params.AppModuleBasic{},
 		crisis.AppModuleBasic{},
 		slashing.AppModuleBasic{},
@@ -209,6 +210,7 @@ func Newbhexapp(
 	app.crisisKeeper = crisis.NewKeeper(crisisSubspace, invCheckPeriod, app.supplyKeeper, custodianunit.FeeCollectorName)
 	app.keygenKeeper = keygen.NewKeeper(keys[keygen.StoreKey], app.cdc, &app.tokenKeeper, app.cuKeeper, app.ibcassetKeeper, &app.orderKeeper, &app.receiptKeeper, &stakingKeeper, app.distrKeeper, app.transferKeeper, cn)
 	app.tokenKeeper.SetStakingKeeper(&stakingKeeper)
+	app.tokenKeeper.SetCUKeeper(app.cuKeeper)
 
 	app.hrc10Keeper = hrc10.NewKeeper(app.cdc, keys[hrc10.StoreKey], hrc10Subspace, &app.tokenKeeper, app.distrKeeper, app.supplyKeeper, &app.receiptKeeper, app.transferKeeper)
 	app.mappingKeeper = mapping.NewKeeper(keys[mapping.StoreKey], app.cdc, &app.tokenKeeper, app.cuKeeper, &app.receiptKeeper, app.transferKeeper, mappingSubspace)
@@ -216,8 +218,15 @@ func Newbhexapp(
 
 	app.transferKeeper.SetEvidenceKeeper(app.evidenceKeeper)
 	app.tokenKeeper.SetEvidenceKeeper(app.evidenceKeeper)
+	app.keygenKeeper.SetEvidenceKeeper(app.evidenceKeeper)
 
 	app.upgradeKeeper = upgrade.NewKeeper(skipUpgradeHeights, keys[upgrade.StoreKey], app.cdc, home)
+	app.upgradeKeeper.SetUpgradeHandler(order.OrderIndexUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.orderKeeper.BuildOrderIndexes(ctx)
+	})
+	app.upgradeKeeper.SetUpgradeHandler(evidence.BehaviourParamsUpgrade, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.evidenceKeeper.SetMissingBehaviourParams(ctx)
+	})
 	app.openswapKeeper = openswap.NewKeeper(app.cdc, keys[openswap.StoreKey], &app.tokenKeeper, &app.receiptKeeper, app.supplyKeeper, app.transferKeeper, openswapSubspace)
 	app.cuKeeper.SetStakingKeeper(stakingKeeper)
 
@@ -240,7 +249,8 @@ func Newbhexapp(
 		AddRoute(token.RouterKey, token.NewTokenProposalHandler(app.tokenKeeper)).
 		AddRoute(upgrade.RouterKey, upgrade.NewSoftwareUpgradeProposalHandler(app.upgradeKeeper)).
 		AddRoute(mapping.RouterKey, mapping.NewMappingProposalHandler(app.mappingKeeper)).
-		AddRoute(staking.RouterKey, staking.NewStakingProposalHandler(app.stakingKeeper))
+		AddRoute(staking.RouterKey, staking.NewStakingProposalHandler(app.stakingKeeper)).
+		AddRoute(transfer.RouterKey, transfer.NewTransferProposalHandler(app.transferKeeper))
 	app.govKeeper = gov.NewKeeper(app.cdc, keys[gov.StoreKey], app.paramsKeeper, govSubspace,
 		app.supplyKeeper, &stakingKeeper, app.distrKeeper, app.transferKeeper, gov.DefaultCodespace, govRouter)
 
@@ -276,7 +286,7 @@ func Newbhexapp(
 	// there is nothing left over in the validator fee pool, so as to keep the
 	// CanWithdrawInvariant invariant.
 	app.mm.SetOrderBeginBlockers(upgrade.ModuleName, mint.ModuleName, distr.ModuleName, openswap.ModuleName, slashing.ModuleName)
-	app.mm.SetOrderEndBlockers(crisis.ModuleName, gov.ModuleName, staking.ModuleName, openswap.ModuleName, evidence.ModuleName)
+	app.mm.SetOrderEndBlockers(crisis.ModuleName, gov.ModuleName, staking.ModuleName, openswap.ModuleName, evidence.ModuleName, transfer.ModuleName, keygen.ModuleName)
 
 	// NOTE: The genutils moodule must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
@@ -296,7 +306,7 @@ func Newbhexapp(
 	// initialize BaseApp
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
-	app.SetAnteHandler(custodianunit.NewAnteHandler(app.cuKeeper, app.supplyKeeper, app.stakingKeeper, custodianunit.DefaultSigVerificationGasConsumer))
+	app.SetAnteHandler(custodianunit.NewAnteHandler(app.cuKeeper, app.supplyKeeper, app.stakingKeeper, app.transferKeeper, custodianunit.DefaultSigVerificationGasConsumer))
 	app.SetGasRefundHandler(custodianunit.NewGasRefundHandler(app.supplyKeeper))
 	app.SetEndBlocker(app.EndBlocker)
 
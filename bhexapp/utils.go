@@ -164,6 +164,7 @@ func GenBankGenesisState(cdc *codec.Codec, r *rand.Rand, ap simulation.AppParams
 				})
 			return v
 		}(r),
+		transfer.DefaultUtxoConsolidationParams(),
 	)
 
 	fmt.Printf("Selected randomly generated bank parameters:\n%s\n", codec.MustMarshalJSONIndent(cdc, bankGenesis))
@@ -0,0 +1,398 @@
+package chainnode
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// ErrorRateLimited is returned by Cached for calls over the rate limit of the method
+var ErrorRateLimited = errors.New("chainnode call is rate limited")
+
+// CacheConfig configures Cached
+type CacheConfig struct {
+	// TxTTL is how long a finalized transaction is cached, 0 disables the cache. A reorg of the
+	// external chain can still revert a finalized transaction, so keep it within a few blocks
+	TxTTL time.Duration
+	// GasPriceTTL is how long a gas price is cached, 0 disables the cache
+	GasPriceTTL time.Duration
+	// CacheSize is the max number of cached entries
+	CacheSize int
+	// RateLimit is the max calls per second of each method querying the external chains, 0 means unlimited
+	RateLimit float64
+	// RateLimits overrides RateLimit for the methods in it, method names are case insensitive
+	RateLimits map[string]float64
+}
+
+type cacheEntry struct {
+	value  interface{}
+	expire time.Time
+}
+
+type ttlCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]cacheEntry
+}
+
+func newTTLCache(size int) *ttlCache {
+	return &ttlCache{size: size, entries: make(map[string]cacheEntry)}
+}
+
+func (c *ttlCache) get(key string, now time.Time) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expire) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache) set(key string, value interface{}, now time.Time, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expire) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.size {
+			return
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expire: now.Add(ttl)}
+}
+
+// rateLimiter is a token bucket refilled at rate per second, holding at most burst tokens
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := math.Max(1, math.Ceil(rate))
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst}
+}
+
+func (l *rateLimiter) allow(now time.Time) bool {
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+var _ Chainnode = (*Cached)(nil)
+
+// Cached wraps a Chainnode, caches finalized transactions and gas prices, limits
+// the call rate of each method querying the external chains and reports latency
+// and errors of the calls. The methods computed locally by the chainnode, like
+// address validation and transaction decoding, are not rate limited, as they are
+// called in DeliverTx and have to answer the same on every node.
+type Cached struct {
+	cn      Chainnode
+	conf    CacheConfig
+	metrics *Metrics
+	cache   *ttlCache
+
+	mu       sync.Mutex
+	limiters map[string]*rateLimiter
+
+	now func() time.Time
+}
+
+// NewCached creates a Cached wrapping cn
+func NewCached(cn Chainnode, conf CacheConfig, metrics *Metrics) *Cached {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	rateLimits := make(map[string]float64, len(conf.RateLimits))
+	for method, rate := range conf.RateLimits {
+		rateLimits[strings.ToLower(method)] = rate
+	}
+	conf.RateLimits = rateLimits
+	return &Cached{
+		cn:       cn,
+		conf:     conf,
+		metrics:  metrics,
+		cache:    newTTLCache(conf.CacheSize),
+		limiters: make(map[string]*rateLimiter),
+		now:      time.Now,
+	}
+}
+
+func (c *Cached) allow(method, chain string) error {
+	rate, ok := c.conf.RateLimits[strings.ToLower(method)]
+	if !ok {
+		rate = c.conf.RateLimit
+	}
+	if rate <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	limiter, ok := c.limiters[method]
+	if !ok {
+		limiter = newRateLimiter(rate)
+		c.limiters[method] = limiter
+	}
+	if !limiter.allow(c.now()) {
+		c.metrics.RateLimited.With("chain", chain, "method", method).Add(1)
+		return ErrorRateLimited
+	}
+	return nil
+}
+
+func (c *Cached) observe(method, chain string, start time.Time, err error) {
+	c.metrics.CallDuration.With("chain", chain, "method", method).Observe(c.now().Sub(start).Seconds())
+	if err != nil {
+		c.metrics.CallErrors.With("chain", chain, "method", method).Add(1)
+	}
+}
+
+func (c *Cached) cached(method, chain, key string) (interface{}, bool) {
+	value, ok := c.cache.get(method+"/"+key, c.now())
+	if ok {
+		c.metrics.CacheHits.With("chain", chain, "method", method).Add(1)
+	}
+	return value, ok
+}
+
+func isTxFinalized(status uint64) bool {
+	return status == StatusSuccess || status == StatusFailed || status == StatusContractExecuteFailed
+}
+
+// copyUtxoTransaction copies tx, so callers can not modify the cached one
+func copyUtxoTransaction(tx *ExtUtxoTransaction) *ExtUtxoTransaction {
+	cp := *tx
+	cp.Vins = make([]*sdk.UtxoIn, len(tx.Vins))
+	for i, vin := range tx.Vins {
+		if vin != nil {
+			v := *vin
+			cp.Vins[i] = &v
+		}
+	}
+	cp.Vouts = make([]*sdk.UtxoOut, len(tx.Vouts))
+	for i, vout := range tx.Vouts {
+		if vout != nil {
+			v := *vout
+			cp.Vouts[i] = &v
+		}
+	}
+	return &cp
+}
+
+// copyAccountTransaction copies tx, so callers can not modify the cached one
+func copyAccountTransaction(tx *ExtAccountTransaction) *ExtAccountTransaction {
+	cp := *tx
+	return &cp
+}
+
+func (c *Cached) SupportChain(chain string) bool {
+	start := c.now()
+	ok := c.cn.SupportChain(chain)
+	c.observe("SupportChain", chain, start, nil)
+	return ok
+}
+
+func (c *Cached) ConvertAddress(chain string, pubKey []byte) (string, error) {
+	start := c.now()
+	addr, err := c.cn.ConvertAddress(chain, pubKey)
+	c.observe("ConvertAddress", chain, start, err)
+	return addr, err
+}
+
+func (c *Cached) ValidAddress(chain, symbol, address string) (bool, string) {
+	start := c.now()
+	valid, canonical := c.cn.ValidAddress(chain, symbol, address)
+	c.observe("ValidAddress", chain, start, nil)
+	return valid, canonical
+}
+
+func (c *Cached) QueryBalance(chain, symbol, address, contractAddress string, blockHeight uint64) (sdk.Int, error) {
+	if err := c.allow("QueryBalance", chain); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	start := c.now()
+	balance, err := c.cn.QueryBalance(chain, symbol, address, contractAddress, blockHeight)
+	c.observe("QueryBalance", chain, start, err)
+	return balance, err
+}
+
+func (c *Cached) QueryUtxo(chain, symbol string, vin *sdk.UtxoIn) (bool, error) {
+	if err := c.allow("QueryUtxo", chain); err != nil {
+		return false, err
+	}
+	start := c.now()
+	exist, err := c.cn.QueryUtxo(chain, symbol, vin)
+	c.observe("QueryUtxo", chain, start, err)
+	return exist, err
+}
+
+func (c *Cached) QueryNonce(chain, address string) (uint64, error) {
+	if err := c.allow("QueryNonce", chain); err != nil {
+		return 0, err
+	}
+	start := c.now()
+	nonce, err := c.cn.QueryNonce(chain, address)
+	c.observe("QueryNonce", chain, start, err)
+	return nonce, err
+}
+
+func (c *Cached) QueryGasPrice(chain string) (sdk.Int, error) {
+	if price, ok := c.cached("QueryGasPrice", chain, chain); ok {
+		return price.(sdk.Int), nil
+	}
+	if err := c.allow("QueryGasPrice", chain); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	start := c.now()
+	price, err := c.cn.QueryGasPrice(chain)
+	c.observe("QueryGasPrice", chain, start, err)
+	if err == nil {
+		c.cache.set("QueryGasPrice/"+chain, price, c.now(), c.conf.GasPriceTTL)
+	}
+	return price, err
+}
+
+func (c *Cached) QueryUtxoTransaction(chain, symbol, hash string, asynMode bool) (*ExtUtxoTransaction, error) {
+	key := fmt.Sprintf("%s/%s/%s/%t", chain, symbol, hash, asynMode)
+	if tx, ok := c.cached("QueryUtxoTransaction", chain, key); ok {
+		return copyUtxoTransaction(tx.(*ExtUtxoTransaction)), nil
+	}
+	if err := c.allow("QueryUtxoTransaction", chain); err != nil {
+		return nil, err
+	}
+	start := c.now()
+	tx, err := c.cn.QueryUtxoTransaction(chain, symbol, hash, asynMode)
+	c.observe("QueryUtxoTransaction", chain, start, err)
+	if err == nil && tx != nil && isTxFinalized(tx.Status) {
+		c.cache.set("QueryUtxoTransaction/"+key, copyUtxoTransaction(tx), c.now(), c.conf.TxTTL)
+	}
+	return tx, err
+}
+
+func (c *Cached) QueryAccountTransaction(chain, symbol, hash string, asynMode bool) (*ExtAccountTransaction, error) {
+	key := fmt.Sprintf("%s/%s/%s/%t", chain, symbol, hash, asynMode)
+	if tx, ok := c.cached("QueryAccountTransaction", chain, key); ok {
+		return copyAccountTransaction(tx.(*ExtAccountTransaction)), nil
+	}
+	if err := c.allow("QueryAccountTransaction", chain); err != nil {
+		return nil, err
+	}
+	start := c.now()
+	tx, err := c.cn.QueryAccountTransaction(chain, symbol, hash, asynMode)
+	c.observe("QueryAccountTransaction", chain, start, err)
+	if err == nil && tx != nil && isTxFinalized(tx.Status) {
+		c.cache.set("QueryAccountTransaction/"+key, copyAccountTransaction(tx), c.now(), c.conf.TxTTL)
+	}
+	return tx, err
+}
+
+func (c *Cached) CreateUtxoTransaction(chain, symbol string, transaction *ExtUtxoTransaction) ([]byte, [][]byte, error) {
+	start := c.now()
+	raw, hashes, err := c.cn.CreateUtxoTransaction(chain, symbol, transaction)
+	c.observe("CreateUtxoTransaction", chain, start, err)
+	return raw, hashes, err
+}
+
+func (c *Cached) CreateAccountTransaction(chain, symbol, contractAddress string, transaction *ExtAccountTransaction) ([]byte, []byte, error) {
+	start := c.now()
+	raw, hash, err := c.cn.CreateAccountTransaction(chain, symbol, contractAddress, transaction)
+	c.observe("CreateAccountTransaction", chain, start, err)
+	return raw, hash, err
+}
+
+func (c *Cached) CreateUtxoSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys [][]byte) ([]byte, []byte, error) {
+	start := c.now()
+	signed, hash, err := c.cn.CreateUtxoSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+	c.observe("CreateUtxoSignedTransaction", chain, start, err)
+	return signed, hash, err
+}
+
+func (c *Cached) CreateAccountSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys []byte) ([]byte, []byte, error) {
+	start := c.now()
+	signed, hash, err := c.cn.CreateAccountSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+	c.observe("CreateAccountSignedTransaction", chain, start, err)
+	return signed, hash, err
+}
+
+func (c *Cached) VerifyUtxoSignedTransaction(chain, symbol string, address []string, signedTxData []byte, vins []*sdk.UtxoIn) (bool, error) {
+	start := c.now()
+	verified, err := c.cn.VerifyUtxoSignedTransaction(chain, symbol, address, signedTxData, vins)
+	c.observe("VerifyUtxoSignedTransaction", chain, start, err)
+	return verified, err
+}
+
+func (c *Cached) VerifyAccountSignedTransaction(chain, symbol string, address string, signedTxData []byte) (bool, error) {
+	start := c.now()
+	verified, err := c.cn.VerifyAccountSignedTransaction(chain, symbol, address, signedTxData)
+	c.observe("VerifyAccountSignedTransaction", chain, start, err)
+	return verified, err
+}
+
+func (c *Cached) QueryAccountTransactionFromSignedData(chain, symbol string, signedTxData []byte) (*ExtAccountTransaction, error) {
+	start := c.now()
+	tx, err := c.cn.QueryAccountTransactionFromSignedData(chain, symbol, signedTxData)
+	c.observe("QueryAccountTransactionFromSignedData", chain, start, err)
+	return tx, err
+}
+
+func (c *Cached) QueryUtxoTransactionFromSignedData(chain, symbol string, signedTxData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, error) {
+	start := c.now()
+	tx, err := c.cn.QueryUtxoTransactionFromSignedData(chain, symbol, signedTxData, vins)
+	c.observe("QueryUtxoTransactionFromSignedData", chain, start, err)
+	return tx, err
+}
+
+func (c *Cached) QueryAccountTransactionFromData(chain, symbol string, rawData []byte) (*ExtAccountTransaction, []byte, error) {
+	start := c.now()
+	tx, hash, err := c.cn.QueryAccountTransactionFromData(chain, symbol, rawData)
+	c.observe("QueryAccountTransactionFromData", chain, start, err)
+	return tx, hash, err
+}
+
+func (c *Cached) QueryUtxoTransactionFromData(chain, symbol string, rawData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, [][]byte, error) {
+	start := c.now()
+	tx, hashes, err := c.cn.QueryUtxoTransactionFromData(chain, symbol, rawData, vins)
+	c.observe("QueryUtxoTransactionFromData", chain, start, err)
+	return tx, hashes, err
+}
+
+func (c *Cached) BroadcastTransaction(chain, symbol string, signedTxData []byte) (string, error) {
+	if err := c.allow("BroadcastTransaction", chain); err != nil {
+		return "", err
+	}
+	start := c.now()
+	hash, err := c.cn.BroadcastTransaction(chain, symbol, signedTxData)
+	c.observe("BroadcastTransaction", chain, start, err)
+	return hash, err
+}
+
+func (c *Cached) QueryUtxoInsFromData(chain, symbol string, data []byte) ([]*sdk.UtxoIn, error) {
+	start := c.now()
+	ins, err := c.cn.QueryUtxoInsFromData(chain, symbol, data)
+	c.observe("QueryUtxoInsFromData", chain, start, err)
+	return ins, err
+}
@@ -0,0 +1,107 @@
+package chainnode
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestCached(t *testing.T) {
+	cn := &MockChainnode{}
+	pending := &ExtAccountTransaction{Hash: "pending", Status: StatusPending}
+	success := &ExtAccountTransaction{Hash: "success", Status: StatusSuccess}
+	cn.On("QueryAccountTransaction", "eth", "eth", "pending", false).Return(pending, nil).Twice()
+	cn.On("QueryAccountTransaction", "eth", "eth", "success", false).Return(success, nil).Once()
+	cn.On("QueryAccountTransaction", "eth", "eth", "success", true).Return(&ExtAccountTransaction{Hash: "success", Status: StatusSuccess}, nil).Once()
+	cn.On("QueryGasPrice", "eth").Return(sdk.NewInt(1000), nil).Once()
+	cn.On("QueryGasPrice", "eth").Return(sdk.NewInt(2000), nil).Once()
+	cn.On("QueryGasPrice", "btc").Return(sdk.ZeroInt(), errors.New("query fails")).Twice()
+
+	now := time.Unix(1600000000, 0)
+	c := NewCached(cn, CacheConfig{TxTTL: time.Minute, GasPriceTTL: 10 * time.Second, CacheSize: 10}, nil)
+	c.now = func() time.Time { return now }
+
+	// pending transactions are not cached
+	for i := 0; i < 2; i++ {
+		tx, err := c.QueryAccountTransaction("eth", "eth", "pending", false)
+		require.Nil(t, err)
+		require.Equal(t, pending, tx)
+	}
+	// finalized transactions are cached
+	for i := 0; i < 3; i++ {
+		tx, err := c.QueryAccountTransaction("eth", "eth", "success", false)
+		require.Nil(t, err)
+		require.Equal(t, uint64(StatusSuccess), tx.Status)
+		// callers get copies, modifying them does not touch the cached transaction
+		tx.Status = StatusFailed
+	}
+	// asynMode is part of the cache key
+	tx, err := c.QueryAccountTransaction("eth", "eth", "success", true)
+	require.Nil(t, err)
+	require.Equal(t, uint64(StatusSuccess), tx.Status)
+
+	price, err := c.QueryGasPrice("eth")
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(1000), price)
+	now = now.Add(5 * time.Second)
+	price, _ = c.QueryGasPrice("eth")
+	require.Equal(t, sdk.NewInt(1000), price)
+	now = now.Add(5 * time.Second)
+	price, _ = c.QueryGasPrice("eth")
+	require.Equal(t, sdk.NewInt(2000), price)
+
+	// errors are not cached
+	_, err = c.QueryGasPrice("btc")
+	require.NotNil(t, err)
+	_, err = c.QueryGasPrice("btc")
+	require.NotNil(t, err)
+
+	cn.AssertExpectations(t)
+}
+
+func TestCachedRateLimit(t *testing.T) {
+	cn := &MockChainnode{}
+	cn.On("QueryNonce", "eth", "addr").Return(uint64(1), nil)
+	cn.On("BroadcastTransaction", "eth", []byte{1}).Return("hash", nil)
+	cn.On("ValidAddress", "eth", "eth", "addr").Return(true, "addr")
+	cn.On("ConvertAddressFromSerializedPubKey", "eth", []byte{1}).Return("addr", nil)
+	cn.On("SupportChain", "eth").Return(true)
+
+	now := time.Unix(1600000000, 0)
+	c := NewCached(cn, CacheConfig{RateLimit: 2, RateLimits: map[string]float64{"broadcasttransaction": 0}}, nil)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := c.QueryNonce("eth", "addr")
+		require.Nil(t, err)
+	}
+	_, err := c.QueryNonce("eth", "addr")
+	require.Equal(t, ErrorRateLimited, err)
+
+	// local methods answer the same on every node, never limited
+	for i := 0; i < 5; i++ {
+		valid, addr := c.ValidAddress("eth", "eth", "addr")
+		require.True(t, valid)
+		require.Equal(t, "addr", addr)
+		addr, err = c.ConvertAddress("eth", []byte{1})
+		require.Nil(t, err)
+		require.Equal(t, "addr", addr)
+		require.True(t, c.SupportChain("eth"))
+	}
+
+	// unlimited by the override
+	for i := 0; i < 5; i++ {
+		_, err = c.BroadcastTransaction("eth", "eth", []byte{1})
+		require.Nil(t, err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	_, err = c.QueryNonce("eth", "addr")
+	require.Nil(t, err)
+	_, err = c.QueryNonce("eth", "addr")
+	require.Equal(t, ErrorRateLimited, err)
+}
@@ -0,0 +1,278 @@
+// Package chainnodetest is the conformance suite of the chainnode.Chainnode
+// contract. Every implementation, e.g. the grpc client, an in-process adapter
+// or a fake, runs it from its own tests with a Fixture of the chains it serves:
+//
+//	func TestConformance(t *testing.T) {
+//		chainnodetest.RunSuite(t, cn, fixture)
+//	}
+package chainnodetest
+
+import (
+	"crypto/ecdsa"
+	"strings"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// Signer signs the hashes returned by Create*Transaction with one key
+type Signer interface {
+	PubKey() []byte
+	Sign(hash []byte) ([]byte, error)
+}
+
+// Secp256k1Signer signs with a secp256k1 key, the signature is [R || S || V]
+type Secp256k1Signer struct {
+	Key        *ecdsa.PrivateKey
+	Compressed bool
+}
+
+var _ Signer = Secp256k1Signer{}
+
+// NewSecp256k1Signer creates a Secp256k1Signer with a random key
+func NewSecp256k1Signer(compressed bool) Secp256k1Signer {
+	key, err := ethcrypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	return Secp256k1Signer{Key: key, Compressed: compressed}
+}
+
+func (s Secp256k1Signer) PubKey() []byte {
+	if s.Compressed {
+		return ethcrypto.CompressPubkey(&s.Key.PublicKey)
+	}
+	return ethcrypto.FromECDSAPub(&s.Key.PublicKey)
+}
+
+func (s Secp256k1Signer) Sign(hash []byte) ([]byte, error) {
+	return ethcrypto.Sign(hash, s.Key)
+}
+
+// Fixture is the chain data a Chainnode is checked against, the flows of a nil
+// chain model are skipped
+type Fixture struct {
+	// UnsupportedChain is a chain the Chainnode does not serve
+	UnsupportedChain string
+	Account          *AccountFixture
+	Utxo             *UtxoFixture
+}
+
+// AccountFixture describes an account based chain served by the Chainnode
+type AccountFixture struct {
+	Chain  string
+	Symbol string
+	// Signer owns the From address of Transaction
+	Signer           Signer
+	InvalidAddresses []string
+	// Transaction is the transaction to create, From is derived from Signer
+	Transaction chainnode.ExtAccountTransaction
+	// SkipSignature skips the signing flow, for implementations signing with a full node only
+	SkipSignature bool
+	// Statuses are the expected Status of transaction hashes
+	Statuses map[string]uint64
+}
+
+// UtxoFixture describes an utxo based chain served by the Chainnode
+type UtxoFixture struct {
+	Chain  string
+	Symbol string
+	// Signer owns the Vins of Transaction
+	Signer           Signer
+	InvalidAddresses []string
+	// Transaction is the transaction to create, Vin addresses are derived from Signer
+	Transaction chainnode.ExtUtxoTransaction
+	// SkipSignature skips the signing flow, for implementations signing with a full node only
+	SkipSignature bool
+	// Statuses are the expected Status of transaction hashes
+	Statuses map[string]uint64
+}
+
+// RunSuite checks cn against the Chainnode contract
+func RunSuite(t *testing.T, cn chainnode.Chainnode, f Fixture) {
+	t.Run("Unsupported", func(t *testing.T) { testUnsupported(t, cn, f.UnsupportedChain) })
+	t.Run("Account", func(t *testing.T) {
+		if f.Account == nil {
+			t.Skip("no account chain")
+		}
+		testAccount(t, cn, f.Account)
+	})
+	t.Run("Utxo", func(t *testing.T) {
+		if f.Utxo == nil {
+			t.Skip("no utxo chain")
+		}
+		testUtxo(t, cn, f.Utxo)
+	})
+}
+
+// IsValidStatus reports whether status is one of the chainnode Status* values
+func IsValidStatus(status uint64) bool {
+	return status <= chainnode.StatusOther
+}
+
+func testUnsupported(t *testing.T, cn chainnode.Chainnode, chain string) {
+	require.False(t, cn.SupportChain(chain))
+	valid, _ := cn.ValidAddress(chain, chain, "address")
+	require.False(t, valid)
+
+	_, err := cn.ConvertAddress(chain, NewSecp256k1Signer(true).PubKey())
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.QueryGasPrice(chain)
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.QueryNonce(chain, "address")
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.QueryBalance(chain, chain, "address", "", 0)
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.QueryAccountTransaction(chain, chain, "hash", false)
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.QueryUtxoTransaction(chain, chain, "hash", false)
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	_, err = cn.BroadcastTransaction(chain, chain, []byte("signed"))
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+}
+
+func testAddress(t *testing.T, cn chainnode.Chainnode, chain, symbol string, signer Signer, invalid []string) string {
+	require.True(t, cn.SupportChain(chain))
+
+	address, err := cn.ConvertAddress(chain, signer.PubKey())
+	require.Nil(t, err)
+	valid, canonical := cn.ValidAddress(chain, symbol, address)
+	require.True(t, valid, "converted address %s is invalid", address)
+	require.Equal(t, address, canonical)
+
+	for _, addr := range invalid {
+		valid, _ = cn.ValidAddress(chain, symbol, addr)
+		require.False(t, valid, "invalid address %s is valid", addr)
+	}
+	return address
+}
+
+func testAccount(t *testing.T, cn chainnode.Chainnode, f *AccountFixture) {
+	from := testAddress(t, cn, f.Chain, f.Symbol, f.Signer, f.InvalidAddresses)
+
+	// raw transaction round trip
+	tx := f.Transaction
+	tx.From = from
+	raw, signHash, err := cn.CreateAccountTransaction(f.Chain, f.Symbol, tx.ContractAddress, &tx)
+	require.Nil(t, err)
+	require.NotEmpty(t, signHash)
+	decoded, hash, err := cn.QueryAccountTransactionFromData(f.Chain, f.Symbol, raw)
+	require.Nil(t, err)
+	require.Equal(t, signHash, hash)
+	requireAccountTxEqual(t, &tx, decoded)
+
+	for hash, status := range f.Statuses {
+		queried, err := cn.QueryAccountTransaction(f.Chain, f.Symbol, hash, false)
+		require.Nil(t, err)
+		require.True(t, IsValidStatus(queried.Status))
+		require.Equal(t, status, queried.Status, "status of %s", hash)
+	}
+
+	if f.SkipSignature {
+		return
+	}
+	signature, err := f.Signer.Sign(signHash)
+	require.Nil(t, err)
+	signed, _, err := cn.CreateAccountSignedTransaction(f.Chain, f.Symbol, raw, signature, f.Signer.PubKey())
+	require.Nil(t, err)
+
+	verified, err := cn.VerifyAccountSignedTransaction(f.Chain, f.Symbol, from, signed)
+	require.Nil(t, err)
+	require.True(t, verified)
+	other, err := cn.ConvertAddress(f.Chain, NewSecp256k1Signer(true).PubKey())
+	require.Nil(t, err)
+	verified, err = cn.VerifyAccountSignedTransaction(f.Chain, f.Symbol, other, signed)
+	require.Nil(t, err)
+	require.False(t, verified)
+
+	decoded, err = cn.QueryAccountTransactionFromSignedData(f.Chain, f.Symbol, signed)
+	require.Nil(t, err)
+	requireAccountTxEqual(t, &tx, decoded)
+	require.True(t, strings.EqualFold(from, decoded.From))
+}
+
+func requireAccountTxEqual(t *testing.T, expected, actual *chainnode.ExtAccountTransaction) {
+	require.True(t, strings.EqualFold(expected.To, actual.To), "to %s != %s", expected.To, actual.To)
+	require.True(t, expected.Amount.Equal(actual.Amount), "amount %v != %v", expected.Amount, actual.Amount)
+	require.Equal(t, expected.Nonce, actual.Nonce)
+	require.True(t, expected.GasPrice.Equal(actual.GasPrice), "gas price %v != %v", expected.GasPrice, actual.GasPrice)
+	require.True(t, expected.GasLimit.Equal(actual.GasLimit), "gas limit %v != %v", expected.GasLimit, actual.GasLimit)
+}
+
+func testUtxo(t *testing.T, cn chainnode.Chainnode, f *UtxoFixture) {
+	from := testAddress(t, cn, f.Chain, f.Symbol, f.Signer, f.InvalidAddresses)
+
+	// raw transaction round trip
+	tx := f.Transaction
+	tx.Vins = make([]*sdk.UtxoIn, len(f.Transaction.Vins))
+	for i, vin := range f.Transaction.Vins {
+		in := *vin
+		in.Address = from
+		tx.Vins[i] = &in
+	}
+	raw, signHashes, err := cn.CreateUtxoTransaction(f.Chain, f.Symbol, &tx)
+	require.Nil(t, err)
+	require.Len(t, signHashes, len(tx.Vins))
+	decoded, hashes, err := cn.QueryUtxoTransactionFromData(f.Chain, f.Symbol, raw, tx.Vins)
+	require.Nil(t, err)
+	require.Equal(t, signHashes, hashes)
+	requireUtxoTxEqual(t, &tx, decoded)
+
+	ins, err := cn.QueryUtxoInsFromData(f.Chain, f.Symbol, raw)
+	require.Nil(t, err)
+	require.Len(t, ins, len(tx.Vins))
+	for i, in := range ins {
+		require.Equal(t, tx.Vins[i].Hash, in.Hash)
+		require.Equal(t, tx.Vins[i].Index, in.Index)
+	}
+
+	for hash, status := range f.Statuses {
+		queried, err := cn.QueryUtxoTransaction(f.Chain, f.Symbol, hash, false)
+		require.Nil(t, err)
+		require.True(t, IsValidStatus(queried.Status))
+		require.Equal(t, status, queried.Status, "status of %s", hash)
+	}
+
+	if f.SkipSignature {
+		return
+	}
+	signatures := make([][]byte, len(signHashes))
+	pubKeys := make([][]byte, len(signHashes))
+	for i, hash := range signHashes {
+		signatures[i], err = f.Signer.Sign(hash)
+		require.Nil(t, err)
+		pubKeys[i] = f.Signer.PubKey()
+	}
+	signed, _, err := cn.CreateUtxoSignedTransaction(f.Chain, f.Symbol, raw, signatures, pubKeys)
+	require.Nil(t, err)
+
+	verified, err := cn.VerifyUtxoSignedTransaction(f.Chain, f.Symbol, []string{from}, signed, tx.Vins)
+	require.Nil(t, err)
+	require.True(t, verified)
+	other, err := cn.ConvertAddress(f.Chain, NewSecp256k1Signer(true).PubKey())
+	require.Nil(t, err)
+	verified, err = cn.VerifyUtxoSignedTransaction(f.Chain, f.Symbol, []string{other}, signed, tx.Vins)
+	require.Nil(t, err)
+	require.False(t, verified)
+
+	decoded, err = cn.QueryUtxoTransactionFromSignedData(f.Chain, f.Symbol, signed, tx.Vins)
+	require.Nil(t, err)
+	requireUtxoTxEqual(t, &tx, decoded)
+}
+
+func requireUtxoTxEqual(t *testing.T, expected, actual *chainnode.ExtUtxoTransaction) {
+	require.Len(t, actual.Vins, len(expected.Vins))
+	for i, vin := range expected.Vins {
+		require.Equal(t, vin.Hash, actual.Vins[i].Hash)
+		require.Equal(t, vin.Index, actual.Vins[i].Index)
+	}
+	require.Len(t, actual.Vouts, len(expected.Vouts))
+	for i, vout := range expected.Vouts {
+		require.Equal(t, vout.Address, actual.Vouts[i].Address)
+		require.True(t, vout.Amount.Equal(actual.Vouts[i].Amount), "vout amount %v != %v", vout.Amount, actual.Vouts[i].Amount)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"time"
 
 	"github.com/hbtc-chain/bhchain/chainnode"
 	sdk "github.com/hbtc-chain/bhchain/types"
@@ -25,8 +26,44 @@ var _ chainnode.Chainnode = (*Chainnode)(nil)
 
 // Chainnode implements chainnode.Chainnode
 type Chainnode struct {
-	client *chaindispatcher.ChainDispatcher
+	client proto.ChainnodeServer
 	logger log.Logger
+
+	endpoints           []string
+	quorum              int
+	callTimeout         time.Duration
+	healthCheckInterval time.Duration
+	metrics             *Metrics
+}
+
+// Option sets an optional parameter of Chainnode
+type Option func(*Chainnode)
+
+// WithEndpoints sets the chainnode endpoints in failover order. LocalEndpoint
+// is the in-process dispatcher, any other value is a grpc address.
+func WithEndpoints(endpoints ...string) Option {
+	return func(ch *Chainnode) { ch.endpoints = endpoints }
+}
+
+// WithQuorum requires n endpoints to return the same reply for
+// QueryUtxoTransaction, QueryAccountTransaction and QueryBalance, n <= 1 disables it
+func WithQuorum(n int) Option {
+	return func(ch *Chainnode) { ch.quorum = n }
+}
+
+// WithCallTimeout bounds a single call to one endpoint
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(ch *Chainnode) { ch.callTimeout = timeout }
+}
+
+// WithHealthCheckInterval sets how often the endpoints are probed
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(ch *Chainnode) { ch.healthCheckInterval = interval }
+}
+
+// WithMetrics sets the metrics
+func WithMetrics(metrics *Metrics) Option {
+	return func(ch *Chainnode) { ch.metrics = metrics }
 }
 
 type grpcCommonReply interface {
@@ -36,6 +73,9 @@ type grpcCommonReply interface {
 
 func validateReply(reply grpcCommonReply) error {
 	if reply.GetCode() != proto.ReturnCode_SUCCESS {
+		if reply.GetMsg() == config.UnsupportedChain {
+			return chainnode.ErrorNotSupported
+		}
 		return fmt.Errorf("Reply fails for %v, msg: %v",
 			reflect.TypeOf(reply), reply.GetMsg())
 	}
@@ -43,10 +83,18 @@ func validateReply(reply grpcCommonReply) error {
 }
 
 // New creates a new instance
-func New(logger log.Logger) *Chainnode {
-	return &Chainnode{
-		logger: logger,
+func New(logger log.Logger, options ...Option) *Chainnode {
+	ch := &Chainnode{
+		logger:              logger,
+		endpoints:           []string{LocalEndpoint},
+		callTimeout:         DefaultCallTimeout,
+		healthCheckInterval: DefaultHealthCheckInterval,
+		metrics:             NopMetrics(),
+	}
+	for _, option := range options {
+		option(ch)
 	}
+	return ch
 }
 
 // Connect to Chainnode server via grpc
@@ -61,11 +109,44 @@ func (ch *Chainnode) Init(network string) error {
 	} else {
 		panic("unsupported chainnode network type: " + network)
 	}
-	ch.logger.Info("Init Chainnode", "networktype", networkType)
-	ch.client = chaindispatcher.NewLocal(networkType)
+	ch.logger.Info("Init Chainnode", "networktype", networkType, "endpoints", ch.endpoints, "quorum", ch.quorum)
+	if len(ch.endpoints) == 0 {
+		return errNoEndpoint
+	}
+	if ch.quorum > len(ch.endpoints) {
+		return fmt.Errorf("chainnode quorum %d exceeds %d endpoints", ch.quorum, len(ch.endpoints))
+	}
+
+	endpoints := make([]*endpoint, 0, len(ch.endpoints))
+	for _, addr := range ch.endpoints {
+		if addr == LocalEndpoint {
+			endpoints = append(endpoints, newEndpoint(addr, chaindispatcher.NewLocal(networkType)))
+			continue
+		}
+		e, err := dialEndpoint(addr)
+		if err != nil {
+			return err
+		}
+		endpoints = append(endpoints, e)
+	}
+	ch.initEndpoints(endpoints)
 	return nil
 }
 
+func (ch *Chainnode) initEndpoints(endpoints []*endpoint) {
+	f := &failover{
+		endpoints:   endpoints,
+		quorum:      ch.quorum,
+		callTimeout: ch.callTimeout,
+		metrics:     ch.metrics,
+		logger:      ch.logger,
+	}
+	if len(endpoints) > 1 && ch.healthCheckInterval > 0 {
+		go f.healthCheckRoutine(ch.healthCheckInterval)
+	}
+	ch.client = f
+}
+
 // SupportAsset checks if a symbol is supported
 func (ch *Chainnode) SupportChain(chain string) bool {
 	reply, err := ch.client.SupportChain(context.TODO(), &proto.SupportChainRequest{
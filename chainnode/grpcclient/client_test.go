@@ -1,10 +1,13 @@
 package grpcclient
 
 import (
+	"github.com/hbtc-chain/chainnode/config"
 	"github.com/hbtc-chain/chainnode/proto"
 	"github.com/stretchr/testify/require"
 	"testing"
 
+	"github.com/hbtc-chain/bhchain/chainnode"
+	"github.com/hbtc-chain/bhchain/chainnode/chainnodetest"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -158,3 +161,36 @@ func TestConvertProtoUtxoInsToSdkUtxoIns(t *testing.T) {
 		require.Equal(t, sdkUtxoIn.Address, protoVins[i].Address)
 	}
 }
+
+func TestConvertProtoTxStatusToChainnodeStatus(t *testing.T) {
+	expected := map[proto.TxStatus]uint64{
+		proto.TxStatus_NotFound:              chainnode.StatusNotFound,
+		proto.TxStatus_Pending:               chainnode.StatusPending,
+		proto.TxStatus_Failed:                chainnode.StatusFailed,
+		proto.TxStatus_Success:               chainnode.StatusSuccess,
+		proto.TxStatus_ContractExecuteFailed: chainnode.StatusContractExecuteFailed,
+		proto.TxStatus_Other:                 chainnode.StatusOther,
+	}
+	// every proto status is mapped
+	require.Equal(t, len(proto.TxStatus_name), len(expected))
+	for status, s := range expected {
+		require.Equal(t, s, convertProtoTxStatusToChainnodeStatus(status))
+		require.True(t, chainnodetest.IsValidStatus(s))
+
+		tx, _, err := loadExtAccountTransaction(&proto.QueryAccountTransactionReply{TxStatus: status})
+		require.NoError(t, err)
+		require.Equal(t, s, tx.Status)
+		utxoTx, _, err := loadExtUtxoTransaction(&proto.QueryUtxoTransactionReply{TxStatus: status})
+		require.NoError(t, err)
+		require.Equal(t, s, utxoTx.Status)
+	}
+}
+
+func TestValidateReplyUnsupportedChain(t *testing.T) {
+	err := validateReply(&proto.SupportChainReply{Code: proto.ReturnCode_ERROR, Msg: config.UnsupportedChain})
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+	err = validateReply(&proto.SupportChainReply{Code: proto.ReturnCode_ERROR, Msg: "other"})
+	require.NotNil(t, err)
+	require.NotEqual(t, chainnode.ErrorNotSupported, err)
+	require.Nil(t, validateReply(&proto.SupportChainReply{Code: proto.ReturnCode_SUCCESS}))
+}
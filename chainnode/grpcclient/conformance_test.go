@@ -0,0 +1,41 @@
+package grpcclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	"github.com/hbtc-chain/bhchain/chainnode/chainnodetest"
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// TestConformance runs the chainnode suite against the local dispatcher. Creating
+// eth transactions and signing btc ones need full nodes, so they are skipped.
+func TestConformance(t *testing.T) {
+	ch := New(log.NewNopLogger(), WithHealthCheckInterval(0))
+	require.Nil(t, ch.Init("testnet"))
+
+	btcTo, err := ch.ConvertAddress("btc", chainnodetest.NewSecp256k1Signer(true).PubKey())
+	require.Nil(t, err)
+
+	chainnodetest.RunSuite(t, ch, chainnodetest.Fixture{
+		UnsupportedChain: "unknown",
+		Utxo: &chainnodetest.UtxoFixture{
+			Chain:            "btc",
+			Symbol:           "btc",
+			Signer:           chainnodetest.NewSecp256k1Signer(true),
+			InvalidAddresses: []string{"", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"},
+			Transaction: chainnode.ExtUtxoTransaction{
+				Vins: []*sdk.UtxoIn{
+					{Hash: "a4b3a7b1f1f3e4e2c0f3c1a0b9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0", Index: 1, Amount: sdk.NewInt(10000)},
+				},
+				Vouts: []*sdk.UtxoOut{
+					{Address: btcTo, Amount: sdk.NewInt(9000)},
+				},
+			},
+			SkipSignature: true,
+		},
+	})
+}
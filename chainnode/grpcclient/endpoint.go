@@ -0,0 +1,145 @@
+package grpcclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hbtc-chain/chainnode/proto"
+	"google.golang.org/grpc"
+)
+
+// LocalEndpoint is the endpoint name of the in-process chain dispatcher
+const LocalEndpoint = "local"
+
+// endpoint is one chainnode server the client can talk to, either the local
+// dispatcher or a remote chainnode reached via grpc
+type endpoint struct {
+	name   string
+	server proto.ChainnodeServer
+
+	mtx     sync.RWMutex
+	healthy bool
+}
+
+func newEndpoint(name string, server proto.ChainnodeServer) *endpoint {
+	return &endpoint{
+		name:    name,
+		server:  server,
+		healthy: true,
+	}
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.healthy
+}
+
+func (e *endpoint) setHealthy(healthy bool) bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	changed := e.healthy != healthy
+	e.healthy = healthy
+	return changed
+}
+
+// dialEndpoint connects to a remote chainnode server at addr
+func dialEndpoint(addr string) (*endpoint, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return newEndpoint(addr, &remoteServer{client: proto.NewChainnodeClient(conn)}), nil
+}
+
+var _ proto.ChainnodeServer = (*remoteServer)(nil)
+
+// remoteServer adapts a grpc chainnode client to proto.ChainnodeServer, so that
+// local and remote endpoints are called the same way
+type remoteServer struct {
+	client proto.ChainnodeClient
+}
+
+func (s *remoteServer) BroadcastTransaction(ctx context.Context, req *proto.BroadcastTransactionRequest) (*proto.BroadcastTransactionReply, error) {
+	return s.client.BroadcastTransaction(ctx, req)
+}
+
+func (s *remoteServer) ConvertAddress(ctx context.Context, req *proto.ConvertAddressRequest) (*proto.ConvertAddressReply, error) {
+	return s.client.ConvertAddress(ctx, req)
+}
+
+func (s *remoteServer) SupportChain(ctx context.Context, req *proto.SupportChainRequest) (*proto.SupportChainReply, error) {
+	return s.client.SupportChain(ctx, req)
+}
+
+func (s *remoteServer) ValidAddress(ctx context.Context, req *proto.ValidAddressRequest) (*proto.ValidAddressReply, error) {
+	return s.client.ValidAddress(ctx, req)
+}
+
+func (s *remoteServer) CreateAccountSignedTransaction(ctx context.Context, req *proto.CreateAccountSignedTransactionRequest) (*proto.CreateSignedTransactionReply, error) {
+	return s.client.CreateAccountSignedTransaction(ctx, req)
+}
+
+func (s *remoteServer) CreateAccountTransaction(ctx context.Context, req *proto.CreateAccountTransactionRequest) (*proto.CreateAccountTransactionReply, error) {
+	return s.client.CreateAccountTransaction(ctx, req)
+}
+
+func (s *remoteServer) CreateUtxoSignedTransaction(ctx context.Context, req *proto.CreateUtxoSignedTransactionRequest) (*proto.CreateSignedTransactionReply, error) {
+	return s.client.CreateUtxoSignedTransaction(ctx, req)
+}
+
+func (s *remoteServer) CreateUtxoTransaction(ctx context.Context, req *proto.CreateUtxoTransactionRequest) (*proto.CreateUtxoTransactionReply, error) {
+	return s.client.CreateUtxoTransaction(ctx, req)
+}
+
+func (s *remoteServer) QueryUtxo(ctx context.Context, req *proto.QueryUtxoRequest) (*proto.QueryUtxoReply, error) {
+	return s.client.QueryUtxo(ctx, req)
+}
+
+func (s *remoteServer) QueryUtxoInsFromData(ctx context.Context, req *proto.QueryUtxoInsFromDataRequest) (*proto.QueryUtxoInsReply, error) {
+	return s.client.QueryUtxoInsFromData(ctx, req)
+}
+
+func (s *remoteServer) QueryAccountTransaction(ctx context.Context, req *proto.QueryTransactionRequest) (*proto.QueryAccountTransactionReply, error) {
+	return s.client.QueryAccountTransaction(ctx, req)
+}
+
+func (s *remoteServer) QueryUtxoTransaction(ctx context.Context, req *proto.QueryTransactionRequest) (*proto.QueryUtxoTransactionReply, error) {
+	return s.client.QueryUtxoTransaction(ctx, req)
+}
+
+func (s *remoteServer) QueryAccountTransactionFromData(ctx context.Context, req *proto.QueryTransactionFromDataRequest) (*proto.QueryAccountTransactionReply, error) {
+	return s.client.QueryAccountTransactionFromData(ctx, req)
+}
+
+func (s *remoteServer) QueryUtxoTransactionFromData(ctx context.Context, req *proto.QueryTransactionFromDataRequest) (*proto.QueryUtxoTransactionReply, error) {
+	return s.client.QueryUtxoTransactionFromData(ctx, req)
+}
+
+func (s *remoteServer) QueryAccountTransactionFromSignedData(ctx context.Context, req *proto.QueryTransactionFromSignedDataRequest) (*proto.QueryAccountTransactionReply, error) {
+	return s.client.QueryAccountTransactionFromSignedData(ctx, req)
+}
+
+func (s *remoteServer) QueryUtxoTransactionFromSignedData(ctx context.Context, req *proto.QueryTransactionFromSignedDataRequest) (*proto.QueryUtxoTransactionReply, error) {
+	return s.client.QueryUtxoTransactionFromSignedData(ctx, req)
+}
+
+func (s *remoteServer) QueryBalance(ctx context.Context, req *proto.QueryBalanceRequest) (*proto.QueryBalanceReply, error) {
+	return s.client.QueryBalance(ctx, req)
+}
+
+func (s *remoteServer) QueryGasPrice(ctx context.Context, req *proto.QueryGasPriceRequest) (*proto.QueryGasPriceReply, error) {
+	return s.client.QueryGasPrice(ctx, req)
+}
+
+func (s *remoteServer) QueryNonce(ctx context.Context, req *proto.QueryNonceRequest) (*proto.QueryNonceReply, error) {
+	return s.client.QueryNonce(ctx, req)
+}
+
+func (s *remoteServer) VerifyAccountSignedTransaction(ctx context.Context, req *proto.VerifySignedTransactionRequest) (*proto.VerifySignedTransactionReply, error) {
+	return s.client.VerifyAccountSignedTransaction(ctx, req)
+}
+
+func (s *remoteServer) VerifyUtxoSignedTransaction(ctx context.Context, req *proto.VerifySignedTransactionRequest) (*proto.VerifySignedTransactionReply, error) {
+	return s.client.VerifyUtxoSignedTransaction(ctx, req)
+}
@@ -0,0 +1,427 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	pb "github.com/golang/protobuf/proto"
+	"github.com/hbtc-chain/chainnode/proto"
+	"github.com/tendermint/tendermint/libs/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultCallTimeout bounds a single call to one endpoint, so a hung endpoint fails over
+	DefaultCallTimeout = 30 * time.Second
+	// DefaultHealthCheckInterval is how often all endpoints are probed
+	DefaultHealthCheckInterval = 10 * time.Second
+)
+
+var (
+	errNoEndpoint       = errors.New("no chainnode endpoint available")
+	errQuorumNotReached = errors.New("chainnode quorum not reached")
+)
+
+var _ proto.ChainnodeServer = (*failover)(nil)
+
+// failover serves chainnode requests from a list of endpoints. Calls go to the
+// first healthy endpoint and fall through to the next one on a transport error.
+// With quorum > 1, QueryUtxoTransaction, QueryAccountTransaction and QueryBalance
+// are sent to all endpoints and only succeed if quorum of them return the same reply.
+type failover struct {
+	endpoints   []*endpoint
+	quorum      int
+	callTimeout time.Duration
+	metrics     *Metrics
+	logger      log.Logger
+}
+
+type endpointCall func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error)
+
+func (f *failover) context() (context.Context, context.CancelFunc) {
+	if f.callTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), f.callTimeout)
+}
+
+// ordered returns the healthy endpoints in configured order, followed by the
+// unhealthy ones as a last resort
+func (f *failover) ordered() []*endpoint {
+	healthy := make([]*endpoint, 0, len(f.endpoints))
+	var unhealthy []*endpoint
+	for _, e := range f.endpoints {
+		if e.isHealthy() {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (f *failover) callEndpoint(e *endpoint, fn endpointCall) (interface{}, error) {
+	ctx, cancel := f.context()
+	defer cancel()
+	reply, err := fn(ctx, e.server)
+	if isTransportError(err) {
+		f.metrics.EndpointFailures.With("endpoint", e.name).Add(1)
+		f.markHealthy(e, false, err)
+		return nil, err
+	}
+	f.markHealthy(e, true, nil)
+	return reply, err
+}
+
+// isTransportError tells if err is from reaching the endpoint rather than from the endpoint serving the call,
+// only such errors make the endpoint unhealthy and the call fail over
+func isTransportError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f *failover) markHealthy(e *endpoint, healthy bool, err error) {
+	if !e.setHealthy(healthy) {
+		return
+	}
+	if healthy {
+		f.logger.Info("chainnode endpoint recovered", "endpoint", e.name)
+	} else {
+		f.logger.Error("chainnode endpoint unhealthy", "endpoint", e.name, "err", err)
+	}
+	unhealthy := 0
+	for _, e := range f.endpoints {
+		if !e.isHealthy() {
+			unhealthy++
+		}
+	}
+	f.metrics.UnhealthyEndpoints.Set(float64(unhealthy))
+}
+
+func (f *failover) call(method string, fn endpointCall) (interface{}, error) {
+	err := errNoEndpoint
+	for i, e := range f.ordered() {
+		if i > 0 {
+			f.metrics.Failovers.With("method", method).Add(1)
+			f.logger.Info("chainnode call fails over", "method", method, "endpoint", e.name)
+		}
+		var reply interface{}
+		reply, err = f.callEndpoint(e, fn)
+		if !isTransportError(err) {
+			return reply, err
+		}
+	}
+	return nil, err
+}
+
+type quorumGroup struct {
+	reply     interface{}
+	endpoints []string
+}
+
+// quorumCall sends the request to all endpoints and returns the reply at least
+// quorum of them agree on
+func (f *failover) quorumCall(method string, fn endpointCall) (interface{}, error) {
+	if f.quorum <= 1 {
+		return f.call(method, fn)
+	}
+
+	replies := make([]interface{}, len(f.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range f.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			// an endpoint failing the call does not vote, its reply is a typed nil
+			if reply, err := f.callEndpoint(e, fn); err == nil && !isNilReply(reply) {
+				replies[i] = reply
+			}
+		}(i, e)
+	}
+	wg.Wait()
+
+	var groups []*quorumGroup
+	var best *quorumGroup
+	for i, reply := range replies {
+		if reply == nil {
+			continue
+		}
+		var group *quorumGroup
+		for _, g := range groups {
+			if pb.Equal(g.reply.(pb.Message), reply.(pb.Message)) {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &quorumGroup{reply: reply}
+			groups = append(groups, group)
+		}
+		group.endpoints = append(group.endpoints, f.endpoints[i].name)
+		if best == nil || len(group.endpoints) > len(best.endpoints) {
+			best = group
+		}
+	}
+
+	if len(groups) > 1 {
+		f.metrics.QuorumDisagreements.With("method", method).Add(1)
+		for _, g := range groups {
+			f.logger.Error("chainnode endpoints disagree", "method", method, "endpoints", g.endpoints, "reply", g.reply)
+		}
+	}
+	if best == nil || len(best.endpoints) < f.quorum {
+		f.metrics.QuorumFailures.With("method", method).Add(1)
+		agreed := 0
+		if best != nil {
+			agreed = len(best.endpoints)
+		}
+		return nil, fmt.Errorf("%w for %s: %d of %d endpoints agree, need %d",
+			errQuorumNotReached, method, agreed, len(f.endpoints), f.quorum)
+	}
+	return best.reply, nil
+}
+
+func isNilReply(reply interface{}) bool {
+	if reply == nil {
+		return true
+	}
+	v := reflect.ValueOf(reply)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// healthCheck probes every endpoint with a SupportChain call, any reply
+// without transport error counts as healthy
+func (f *failover) healthCheck() {
+	for _, e := range f.endpoints {
+		f.callEndpoint(e, func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+			return s.SupportChain(ctx, &proto.SupportChainRequest{})
+		})
+	}
+}
+
+func (f *failover) healthCheckRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		f.healthCheck()
+	}
+}
+
+func (f *failover) BroadcastTransaction(_ context.Context, req *proto.BroadcastTransactionRequest) (*proto.BroadcastTransactionReply, error) {
+	reply, err := f.call("BroadcastTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.BroadcastTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.BroadcastTransactionReply), nil
+}
+
+func (f *failover) ConvertAddress(_ context.Context, req *proto.ConvertAddressRequest) (*proto.ConvertAddressReply, error) {
+	reply, err := f.call("ConvertAddress", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.ConvertAddress(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.ConvertAddressReply), nil
+}
+
+func (f *failover) SupportChain(_ context.Context, req *proto.SupportChainRequest) (*proto.SupportChainReply, error) {
+	reply, err := f.call("SupportChain", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.SupportChain(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.SupportChainReply), nil
+}
+
+func (f *failover) ValidAddress(_ context.Context, req *proto.ValidAddressRequest) (*proto.ValidAddressReply, error) {
+	reply, err := f.call("ValidAddress", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.ValidAddress(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.ValidAddressReply), nil
+}
+
+func (f *failover) CreateAccountSignedTransaction(_ context.Context, req *proto.CreateAccountSignedTransactionRequest) (*proto.CreateSignedTransactionReply, error) {
+	reply, err := f.call("CreateAccountSignedTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.CreateAccountSignedTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.CreateSignedTransactionReply), nil
+}
+
+func (f *failover) CreateAccountTransaction(_ context.Context, req *proto.CreateAccountTransactionRequest) (*proto.CreateAccountTransactionReply, error) {
+	reply, err := f.call("CreateAccountTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.CreateAccountTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.CreateAccountTransactionReply), nil
+}
+
+func (f *failover) CreateUtxoSignedTransaction(_ context.Context, req *proto.CreateUtxoSignedTransactionRequest) (*proto.CreateSignedTransactionReply, error) {
+	reply, err := f.call("CreateUtxoSignedTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.CreateUtxoSignedTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.CreateSignedTransactionReply), nil
+}
+
+func (f *failover) CreateUtxoTransaction(_ context.Context, req *proto.CreateUtxoTransactionRequest) (*proto.CreateUtxoTransactionReply, error) {
+	reply, err := f.call("CreateUtxoTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.CreateUtxoTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.CreateUtxoTransactionReply), nil
+}
+
+func (f *failover) QueryUtxo(_ context.Context, req *proto.QueryUtxoRequest) (*proto.QueryUtxoReply, error) {
+	reply, err := f.call("QueryUtxo", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryUtxo(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryUtxoReply), nil
+}
+
+func (f *failover) QueryUtxoInsFromData(_ context.Context, req *proto.QueryUtxoInsFromDataRequest) (*proto.QueryUtxoInsReply, error) {
+	reply, err := f.call("QueryUtxoInsFromData", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryUtxoInsFromData(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryUtxoInsReply), nil
+}
+
+func (f *failover) QueryAccountTransaction(_ context.Context, req *proto.QueryTransactionRequest) (*proto.QueryAccountTransactionReply, error) {
+	reply, err := f.quorumCall("QueryAccountTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryAccountTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryAccountTransactionReply), nil
+}
+
+func (f *failover) QueryUtxoTransaction(_ context.Context, req *proto.QueryTransactionRequest) (*proto.QueryUtxoTransactionReply, error) {
+	reply, err := f.quorumCall("QueryUtxoTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryUtxoTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryUtxoTransactionReply), nil
+}
+
+func (f *failover) QueryAccountTransactionFromData(_ context.Context, req *proto.QueryTransactionFromDataRequest) (*proto.QueryAccountTransactionReply, error) {
+	reply, err := f.call("QueryAccountTransactionFromData", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryAccountTransactionFromData(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryAccountTransactionReply), nil
+}
+
+func (f *failover) QueryUtxoTransactionFromData(_ context.Context, req *proto.QueryTransactionFromDataRequest) (*proto.QueryUtxoTransactionReply, error) {
+	reply, err := f.call("QueryUtxoTransactionFromData", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryUtxoTransactionFromData(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryUtxoTransactionReply), nil
+}
+
+func (f *failover) QueryAccountTransactionFromSignedData(_ context.Context, req *proto.QueryTransactionFromSignedDataRequest) (*proto.QueryAccountTransactionReply, error) {
+	reply, err := f.call("QueryAccountTransactionFromSignedData", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryAccountTransactionFromSignedData(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryAccountTransactionReply), nil
+}
+
+func (f *failover) QueryUtxoTransactionFromSignedData(_ context.Context, req *proto.QueryTransactionFromSignedDataRequest) (*proto.QueryUtxoTransactionReply, error) {
+	reply, err := f.call("QueryUtxoTransactionFromSignedData", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryUtxoTransactionFromSignedData(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryUtxoTransactionReply), nil
+}
+
+func (f *failover) QueryBalance(_ context.Context, req *proto.QueryBalanceRequest) (*proto.QueryBalanceReply, error) {
+	reply, err := f.quorumCall("QueryBalance", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryBalance(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryBalanceReply), nil
+}
+
+func (f *failover) QueryGasPrice(_ context.Context, req *proto.QueryGasPriceRequest) (*proto.QueryGasPriceReply, error) {
+	reply, err := f.call("QueryGasPrice", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryGasPrice(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryGasPriceReply), nil
+}
+
+func (f *failover) QueryNonce(_ context.Context, req *proto.QueryNonceRequest) (*proto.QueryNonceReply, error) {
+	reply, err := f.call("QueryNonce", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.QueryNonce(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.QueryNonceReply), nil
+}
+
+func (f *failover) VerifyAccountSignedTransaction(_ context.Context, req *proto.VerifySignedTransactionRequest) (*proto.VerifySignedTransactionReply, error) {
+	reply, err := f.call("VerifyAccountSignedTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.VerifyAccountSignedTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.VerifySignedTransactionReply), nil
+}
+
+func (f *failover) VerifyUtxoSignedTransaction(_ context.Context, req *proto.VerifySignedTransactionRequest) (*proto.VerifySignedTransactionReply, error) {
+	reply, err := f.call("VerifyUtxoSignedTransaction", func(ctx context.Context, s proto.ChainnodeServer) (interface{}, error) {
+		return s.VerifyUtxoSignedTransaction(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*proto.VerifySignedTransactionReply), nil
+}
@@ -0,0 +1,155 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/chainnode/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errEndpointDown = status.Error(codes.Unavailable, "endpoint down")
+	errQueryFailed  = errors.New("query failed")
+)
+
+type fakeServer struct {
+	proto.UnimplementedChainnodeServer
+	down    bool
+	failed  bool
+	balance string
+	calls   int
+}
+
+func (s *fakeServer) SupportChain(_ context.Context, req *proto.SupportChainRequest) (*proto.SupportChainReply, error) {
+	if s.down {
+		return nil, errEndpointDown
+	}
+	return &proto.SupportChainReply{Code: proto.ReturnCode_SUCCESS, Support: true}, nil
+}
+
+func (s *fakeServer) QueryBalance(_ context.Context, req *proto.QueryBalanceRequest) (*proto.QueryBalanceReply, error) {
+	s.calls++
+	if s.down {
+		return nil, errEndpointDown
+	}
+	if s.failed {
+		return nil, errQueryFailed
+	}
+	return &proto.QueryBalanceReply{Code: proto.ReturnCode_SUCCESS, Balance: s.balance}, nil
+}
+
+func newTestChainnode(quorum int, servers ...*fakeServer) (*Chainnode, []*endpoint) {
+	endpoints := make([]*endpoint, len(servers))
+	for i, s := range servers {
+		endpoints[i] = newEndpoint(string(rune('a'+i)), s)
+	}
+	ch := New(log.NewNopLogger(), WithQuorum(quorum), WithHealthCheckInterval(0))
+	ch.initEndpoints(endpoints)
+	return ch, endpoints
+}
+
+func TestFailover(t *testing.T) {
+	primary := &fakeServer{down: true, balance: "1"}
+	backup := &fakeServer{balance: "2"}
+	ch, endpoints := newTestChainnode(0, primary, backup)
+
+	balance, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(2), balance)
+	assert.False(t, endpoints[0].isHealthy())
+	assert.True(t, endpoints[1].isHealthy())
+
+	// unhealthy endpoint is skipped
+	_, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, backup.calls)
+
+	// unhealthy endpoints are the last resort
+	backup.down = true
+	_, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	assert.Equal(t, errEndpointDown, err)
+	assert.Equal(t, 2, primary.calls)
+
+	// health check restores the endpoint
+	primary.down = false
+	ch.client.(*failover).healthCheck()
+	assert.True(t, endpoints[0].isHealthy())
+	assert.False(t, endpoints[1].isHealthy())
+	balance, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(1), balance)
+}
+
+func TestFailoverApplicationError(t *testing.T) {
+	primary := &fakeServer{failed: true, balance: "1"}
+	backup := &fakeServer{balance: "2"}
+	ch, endpoints := newTestChainnode(0, primary, backup)
+
+	// the endpoint answers, the error is returned without failover
+	_, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	assert.Equal(t, errQueryFailed, err)
+	assert.True(t, endpoints[0].isHealthy())
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 0, backup.calls)
+
+	primary.failed = false
+	balance, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(1), balance)
+}
+
+func TestQuorum(t *testing.T) {
+	a := &fakeServer{balance: "10"}
+	b := &fakeServer{balance: "11"}
+	c := &fakeServer{balance: "10"}
+	ch, _ := newTestChainnode(2, a, b, c)
+
+	balance, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(10), balance)
+	assert.Equal(t, 1, b.calls)
+
+	c.balance = "12"
+	_, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	assert.Error(t, err)
+
+	c.balance = "10"
+	b.down = true
+	balance, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(10), balance)
+
+	c.down = true
+	_, err = ch.QueryBalance("eth", "eth", "addr", "", 0)
+	assert.Error(t, err)
+}
+
+func TestQuorumApplicationError(t *testing.T) {
+	a := &fakeServer{failed: true, balance: "10"}
+	b := &fakeServer{failed: true, balance: "10"}
+	c := &fakeServer{balance: "10"}
+	ch, endpoints := newTestChainnode(2, a, b, c)
+
+	// failed replies do not agree with each other
+	_, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	assert.True(t, errors.Is(err, errQuorumNotReached))
+	assert.True(t, endpoints[0].isHealthy())
+
+	b.failed = false
+	balance, err := ch.QueryBalance("eth", "eth", "addr", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewInt(10), balance)
+}
+
+func TestInitQuorumExceedsEndpoints(t *testing.T) {
+	ch := New(log.NewNopLogger(), WithEndpoints(LocalEndpoint), WithQuorum(2))
+	assert.Error(t, ch.Init("testnet"))
+}
@@ -0,0 +1,87 @@
+package grpcclient
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "chainnode"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of failed calls, labeled by endpoint.
+	EndpointFailures metrics.Counter
+	// Number of endpoints currently marked unhealthy.
+	UnhealthyEndpoints metrics.Gauge
+	// Number of calls served by another endpoint after a failure, labeled by method.
+	Failovers metrics.Counter
+	// Number of quorum reads where endpoints returned different replies, labeled by method.
+	QuorumDisagreements metrics.Counter
+	// Number of quorum reads where no reply reached the quorum, labeled by method.
+	QuorumFailures metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		EndpointFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "endpoint_failures",
+			Help:      "Number of failed calls to a chainnode endpoint.",
+		}, withLabel(labels, "endpoint")).With(labelsAndValues...),
+		UnhealthyEndpoints: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "unhealthy_endpoints",
+			Help:      "Number of chainnode endpoints marked unhealthy.",
+		}, labels).With(labelsAndValues...),
+		Failovers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "failovers",
+			Help:      "Number of calls failed over to another chainnode endpoint.",
+		}, withLabel(labels, "method")).With(labelsAndValues...),
+		QuorumDisagreements: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "quorum_disagreements",
+			Help:      "Number of quorum reads with disagreeing chainnode replies.",
+		}, withLabel(labels, "method")).With(labelsAndValues...),
+		QuorumFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "quorum_failures",
+			Help:      "Number of quorum reads without enough agreeing chainnode replies.",
+		}, withLabel(labels, "method")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		EndpointFailures:    discard.NewCounter(),
+		UnhealthyEndpoints:  discard.NewGauge(),
+		Failovers:           discard.NewCounter(),
+		QuorumDisagreements: discard.NewCounter(),
+		QuorumFailures:      discard.NewCounter(),
+	}
+}
+
+// withLabel returns a copy of labels with label appended, so the label
+// slices of different metrics never share a backing array.
+func withLabel(labels []string, label string) []string {
+	return append(append(make([]string, 0, len(labels)+1), labels...), label)
+}
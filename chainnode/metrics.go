@@ -0,0 +1,74 @@
+package chainnode
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "chainnode"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Latency of chainnode calls in seconds, labeled by chain and method.
+	CallDuration metrics.Histogram
+	// Number of failed chainnode calls, labeled by chain and method.
+	CallErrors metrics.Counter
+	// Number of calls served from the cache, labeled by chain and method.
+	CacheHits metrics.Counter
+	// Number of calls rejected by the rate limit, labeled by chain and method.
+	RateLimited metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	labels = append(labels, "chain", "method")
+	return &Metrics{
+		CallDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "call_duration_seconds",
+			Help:      "Latency of chainnode calls in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.005, 2, 14),
+		}, labels).With(labelsAndValues...),
+		CallErrors: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "call_errors",
+			Help:      "Number of failed chainnode calls.",
+		}, labels).With(labelsAndValues...),
+		CacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "cache_hits",
+			Help:      "Number of chainnode calls served from the cache.",
+		}, labels).With(labelsAndValues...),
+		RateLimited: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rate_limited",
+			Help:      "Number of chainnode calls rejected by the rate limit.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		CallDuration: discard.NewHistogram(),
+		CallErrors:   discard.NewCounter(),
+		CacheHits:    discard.NewCounter(),
+		RateLimited:  discard.NewCounter(),
+	}
+}
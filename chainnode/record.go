@@ -0,0 +1,386 @@
+package chainnode
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// Record is a request to a Chainnode and its response, written as one JSON line by Recorder
+type Record struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// ErrorNotRecorded is returned by Replayer for requests missing in the records
+var ErrorNotRecorded = errors.New("request is not recorded")
+
+// recordedErrors are the sentinel errors restored by Replayer, so callers comparing errors behave as with the live Chainnode
+var recordedErrors = []error{ErrorNotSupported, ErrorInvalidSignature, ErrorInvalidInput, ErrorRateLimited}
+
+var _ Chainnode = (*Recorder)(nil)
+
+// Recorder wraps a Chainnode and records every request and response to w, which can be served back by Replayer
+type Recorder struct {
+	cn Chainnode
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewRecorder creates a Recorder wrapping cn
+func NewRecorder(cn Chainnode, w io.Writer) *Recorder {
+	return &Recorder{cn: cn, w: w}
+}
+
+func (r *Recorder) record(method string, request []interface{}, err error, response ...interface{}) {
+	rec := Record{Method: method}
+	rec.Request, _ = json.Marshal(request)
+	rec.Response, _ = json.Marshal(response)
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	bz, _ := json.Marshal(rec)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.w.Write(append(bz, '\n'))
+}
+
+func (r *Recorder) SupportChain(chain string) bool {
+	ok := r.cn.SupportChain(chain)
+	r.record("SupportChain", []interface{}{chain}, nil, ok)
+	return ok
+}
+
+func (r *Recorder) ConvertAddress(chain string, pubKey []byte) (string, error) {
+	addr, err := r.cn.ConvertAddress(chain, pubKey)
+	r.record("ConvertAddress", []interface{}{chain, pubKey}, err, addr)
+	return addr, err
+}
+
+func (r *Recorder) ValidAddress(chain, symbol, address string) (bool, string) {
+	valid, canonical := r.cn.ValidAddress(chain, symbol, address)
+	r.record("ValidAddress", []interface{}{chain, symbol, address}, nil, valid, canonical)
+	return valid, canonical
+}
+
+func (r *Recorder) QueryBalance(chain, symbol, address, contractAddress string, blockHeight uint64) (sdk.Int, error) {
+	balance, err := r.cn.QueryBalance(chain, symbol, address, contractAddress, blockHeight)
+	r.record("QueryBalance", []interface{}{chain, symbol, address, contractAddress, blockHeight}, err, balance)
+	return balance, err
+}
+
+func (r *Recorder) QueryUtxo(chain, symbol string, vin *sdk.UtxoIn) (bool, error) {
+	exist, err := r.cn.QueryUtxo(chain, symbol, vin)
+	r.record("QueryUtxo", []interface{}{chain, symbol, vin}, err, exist)
+	return exist, err
+}
+
+func (r *Recorder) QueryNonce(chain, address string) (uint64, error) {
+	nonce, err := r.cn.QueryNonce(chain, address)
+	r.record("QueryNonce", []interface{}{chain, address}, err, nonce)
+	return nonce, err
+}
+
+func (r *Recorder) QueryGasPrice(chain string) (sdk.Int, error) {
+	price, err := r.cn.QueryGasPrice(chain)
+	r.record("QueryGasPrice", []interface{}{chain}, err, price)
+	return price, err
+}
+
+func (r *Recorder) QueryUtxoTransaction(chain, symbol, hash string, asynMode bool) (*ExtUtxoTransaction, error) {
+	tx, err := r.cn.QueryUtxoTransaction(chain, symbol, hash, asynMode)
+	r.record("QueryUtxoTransaction", []interface{}{chain, symbol, hash, asynMode}, err, tx)
+	return tx, err
+}
+
+func (r *Recorder) QueryAccountTransaction(chain, symbol, hash string, asynMode bool) (*ExtAccountTransaction, error) {
+	tx, err := r.cn.QueryAccountTransaction(chain, symbol, hash, asynMode)
+	r.record("QueryAccountTransaction", []interface{}{chain, symbol, hash, asynMode}, err, tx)
+	return tx, err
+}
+
+func (r *Recorder) CreateUtxoTransaction(chain, symbol string, transaction *ExtUtxoTransaction) ([]byte, [][]byte, error) {
+	raw, hashes, err := r.cn.CreateUtxoTransaction(chain, symbol, transaction)
+	r.record("CreateUtxoTransaction", []interface{}{chain, symbol, transaction}, err, raw, hashes)
+	return raw, hashes, err
+}
+
+func (r *Recorder) CreateAccountTransaction(chain, symbol, contractAddress string, transaction *ExtAccountTransaction) ([]byte, []byte, error) {
+	raw, hash, err := r.cn.CreateAccountTransaction(chain, symbol, contractAddress, transaction)
+	r.record("CreateAccountTransaction", []interface{}{chain, symbol, contractAddress, transaction}, err, raw, hash)
+	return raw, hash, err
+}
+
+func (r *Recorder) CreateUtxoSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys [][]byte) ([]byte, []byte, error) {
+	signed, hash, err := r.cn.CreateUtxoSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+	r.record("CreateUtxoSignedTransaction", []interface{}{chain, symbol, raw, signatures, pubKeys}, err, signed, hash)
+	return signed, hash, err
+}
+
+func (r *Recorder) CreateAccountSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys []byte) ([]byte, []byte, error) {
+	signed, hash, err := r.cn.CreateAccountSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+	r.record("CreateAccountSignedTransaction", []interface{}{chain, symbol, raw, signatures, pubKeys}, err, signed, hash)
+	return signed, hash, err
+}
+
+func (r *Recorder) VerifyUtxoSignedTransaction(chain, symbol string, address []string, signedTxData []byte, vins []*sdk.UtxoIn) (bool, error) {
+	verified, err := r.cn.VerifyUtxoSignedTransaction(chain, symbol, address, signedTxData, vins)
+	r.record("VerifyUtxoSignedTransaction", []interface{}{chain, symbol, address, signedTxData, vins}, err, verified)
+	return verified, err
+}
+
+func (r *Recorder) VerifyAccountSignedTransaction(chain, symbol string, address string, signedTxData []byte) (bool, error) {
+	verified, err := r.cn.VerifyAccountSignedTransaction(chain, symbol, address, signedTxData)
+	r.record("VerifyAccountSignedTransaction", []interface{}{chain, symbol, address, signedTxData}, err, verified)
+	return verified, err
+}
+
+func (r *Recorder) QueryAccountTransactionFromSignedData(chain, symbol string, signedTxData []byte) (*ExtAccountTransaction, error) {
+	tx, err := r.cn.QueryAccountTransactionFromSignedData(chain, symbol, signedTxData)
+	r.record("QueryAccountTransactionFromSignedData", []interface{}{chain, symbol, signedTxData}, err, tx)
+	return tx, err
+}
+
+func (r *Recorder) QueryUtxoTransactionFromSignedData(chain, symbol string, signedTxData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, error) {
+	tx, err := r.cn.QueryUtxoTransactionFromSignedData(chain, symbol, signedTxData, vins)
+	r.record("QueryUtxoTransactionFromSignedData", []interface{}{chain, symbol, signedTxData, vins}, err, tx)
+	return tx, err
+}
+
+func (r *Recorder) QueryAccountTransactionFromData(chain, symbol string, rawData []byte) (*ExtAccountTransaction, []byte, error) {
+	tx, hash, err := r.cn.QueryAccountTransactionFromData(chain, symbol, rawData)
+	r.record("QueryAccountTransactionFromData", []interface{}{chain, symbol, rawData}, err, tx, hash)
+	return tx, hash, err
+}
+
+func (r *Recorder) QueryUtxoTransactionFromData(chain, symbol string, rawData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, [][]byte, error) {
+	tx, hashes, err := r.cn.QueryUtxoTransactionFromData(chain, symbol, rawData, vins)
+	r.record("QueryUtxoTransactionFromData", []interface{}{chain, symbol, rawData, vins}, err, tx, hashes)
+	return tx, hashes, err
+}
+
+func (r *Recorder) BroadcastTransaction(chain, symbol string, signedTxData []byte) (string, error) {
+	hash, err := r.cn.BroadcastTransaction(chain, symbol, signedTxData)
+	r.record("BroadcastTransaction", []interface{}{chain, symbol, signedTxData}, err, hash)
+	return hash, err
+}
+
+func (r *Recorder) QueryUtxoInsFromData(chain, symbol string, data []byte) ([]*sdk.UtxoIn, error) {
+	vins, err := r.cn.QueryUtxoInsFromData(chain, symbol, data)
+	r.record("QueryUtxoInsFromData", []interface{}{chain, symbol, data}, err, vins)
+	return vins, err
+}
+
+var _ Chainnode = (*Replayer)(nil)
+
+// Replayer serves the records of Recorder back without any external chain. Responses to the same request are
+// served in the recorded order, and the last one is repeated once they run out. Requests never recorded fail
+// with ErrorNotRecorded.
+type Replayer struct {
+	mu      sync.Mutex
+	records map[string][]Record
+}
+
+// NewReplayer creates a Replayer from the records read from r
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{records: make(map[string][]Record)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid record at line %d: %v", line, err)
+		}
+		key := replayKey(rec.Method, rec.Request)
+		rp.records[key] = append(rp.records[key], rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+func replayKey(method string, request json.RawMessage) string {
+	return method + string(request)
+}
+
+// replay decodes the response to the request into response, and returns the recorded error
+func (rp *Replayer) replay(method string, request []interface{}, response ...interface{}) error {
+	bz, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	key := replayKey(method, bz)
+
+	rp.mu.Lock()
+	recs := rp.records[key]
+	if len(recs) == 0 {
+		rp.mu.Unlock()
+		return fmt.Errorf("%v %v: %w", method, string(bz), ErrorNotRecorded)
+	}
+	rec := recs[0]
+	if len(recs) > 1 {
+		rp.records[key] = recs[1:]
+	}
+	rp.mu.Unlock()
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(rec.Response, &values); err != nil {
+		return err
+	}
+	for i := 0; i < len(values) && i < len(response); i++ {
+		if err := json.Unmarshal(values[i], response[i]); err != nil {
+			return err
+		}
+	}
+	if rec.Error != "" {
+		for _, e := range recordedErrors {
+			if e.Error() == rec.Error {
+				return e
+			}
+		}
+		return errors.New(rec.Error)
+	}
+	return nil
+}
+
+func (rp *Replayer) SupportChain(chain string) bool {
+	var ok bool
+	_ = rp.replay("SupportChain", []interface{}{chain}, &ok)
+	return ok
+}
+
+func (rp *Replayer) ConvertAddress(chain string, pubKey []byte) (string, error) {
+	var addr string
+	err := rp.replay("ConvertAddress", []interface{}{chain, pubKey}, &addr)
+	return addr, err
+}
+
+func (rp *Replayer) ValidAddress(chain, symbol, address string) (bool, string) {
+	var valid bool
+	var canonical string
+	_ = rp.replay("ValidAddress", []interface{}{chain, symbol, address}, &valid, &canonical)
+	return valid, canonical
+}
+
+func (rp *Replayer) QueryBalance(chain, symbol, address, contractAddress string, blockHeight uint64) (sdk.Int, error) {
+	balance := sdk.ZeroInt()
+	err := rp.replay("QueryBalance", []interface{}{chain, symbol, address, contractAddress, blockHeight}, &balance)
+	return balance, err
+}
+
+func (rp *Replayer) QueryUtxo(chain, symbol string, vin *sdk.UtxoIn) (bool, error) {
+	var exist bool
+	err := rp.replay("QueryUtxo", []interface{}{chain, symbol, vin}, &exist)
+	return exist, err
+}
+
+func (rp *Replayer) QueryNonce(chain, address string) (uint64, error) {
+	var nonce uint64
+	err := rp.replay("QueryNonce", []interface{}{chain, address}, &nonce)
+	return nonce, err
+}
+
+func (rp *Replayer) QueryGasPrice(chain string) (sdk.Int, error) {
+	price := sdk.ZeroInt()
+	err := rp.replay("QueryGasPrice", []interface{}{chain}, &price)
+	return price, err
+}
+
+func (rp *Replayer) QueryUtxoTransaction(chain, symbol, hash string, asynMode bool) (*ExtUtxoTransaction, error) {
+	var tx *ExtUtxoTransaction
+	err := rp.replay("QueryUtxoTransaction", []interface{}{chain, symbol, hash, asynMode}, &tx)
+	return tx, err
+}
+
+func (rp *Replayer) QueryAccountTransaction(chain, symbol, hash string, asynMode bool) (*ExtAccountTransaction, error) {
+	var tx *ExtAccountTransaction
+	err := rp.replay("QueryAccountTransaction", []interface{}{chain, symbol, hash, asynMode}, &tx)
+	return tx, err
+}
+
+func (rp *Replayer) CreateUtxoTransaction(chain, symbol string, transaction *ExtUtxoTransaction) ([]byte, [][]byte, error) {
+	var raw []byte
+	var hashes [][]byte
+	err := rp.replay("CreateUtxoTransaction", []interface{}{chain, symbol, transaction}, &raw, &hashes)
+	return raw, hashes, err
+}
+
+func (rp *Replayer) CreateAccountTransaction(chain, symbol, contractAddress string, transaction *ExtAccountTransaction) ([]byte, []byte, error) {
+	var raw, hash []byte
+	err := rp.replay("CreateAccountTransaction", []interface{}{chain, symbol, contractAddress, transaction}, &raw, &hash)
+	return raw, hash, err
+}
+
+func (rp *Replayer) CreateUtxoSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys [][]byte) ([]byte, []byte, error) {
+	var signed, hash []byte
+	err := rp.replay("CreateUtxoSignedTransaction", []interface{}{chain, symbol, raw, signatures, pubKeys}, &signed, &hash)
+	return signed, hash, err
+}
+
+func (rp *Replayer) CreateAccountSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys []byte) ([]byte, []byte, error) {
+	var signed, hash []byte
+	err := rp.replay("CreateAccountSignedTransaction", []interface{}{chain, symbol, raw, signatures, pubKeys}, &signed, &hash)
+	return signed, hash, err
+}
+
+func (rp *Replayer) VerifyUtxoSignedTransaction(chain, symbol string, address []string, signedTxData []byte, vins []*sdk.UtxoIn) (bool, error) {
+	var verified bool
+	err := rp.replay("VerifyUtxoSignedTransaction", []interface{}{chain, symbol, address, signedTxData, vins}, &verified)
+	return verified, err
+}
+
+func (rp *Replayer) VerifyAccountSignedTransaction(chain, symbol string, address string, signedTxData []byte) (bool, error) {
+	var verified bool
+	err := rp.replay("VerifyAccountSignedTransaction", []interface{}{chain, symbol, address, signedTxData}, &verified)
+	return verified, err
+}
+
+func (rp *Replayer) QueryAccountTransactionFromSignedData(chain, symbol string, signedTxData []byte) (*ExtAccountTransaction, error) {
+	var tx *ExtAccountTransaction
+	err := rp.replay("QueryAccountTransactionFromSignedData", []interface{}{chain, symbol, signedTxData}, &tx)
+	return tx, err
+}
+
+func (rp *Replayer) QueryUtxoTransactionFromSignedData(chain, symbol string, signedTxData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, error) {
+	var tx *ExtUtxoTransaction
+	err := rp.replay("QueryUtxoTransactionFromSignedData", []interface{}{chain, symbol, signedTxData, vins}, &tx)
+	return tx, err
+}
+
+func (rp *Replayer) QueryAccountTransactionFromData(chain, symbol string, rawData []byte) (*ExtAccountTransaction, []byte, error) {
+	var tx *ExtAccountTransaction
+	var hash []byte
+	err := rp.replay("QueryAccountTransactionFromData", []interface{}{chain, symbol, rawData}, &tx, &hash)
+	return tx, hash, err
+}
+
+func (rp *Replayer) QueryUtxoTransactionFromData(chain, symbol string, rawData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, [][]byte, error) {
+	var tx *ExtUtxoTransaction
+	var hashes [][]byte
+	err := rp.replay("QueryUtxoTransactionFromData", []interface{}{chain, symbol, rawData, vins}, &tx, &hashes)
+	return tx, hashes, err
+}
+
+func (rp *Replayer) BroadcastTransaction(chain, symbol string, signedTxData []byte) (string, error) {
+	var hash string
+	err := rp.replay("BroadcastTransaction", []interface{}{chain, symbol, signedTxData}, &hash)
+	return hash, err
+}
+
+func (rp *Replayer) QueryUtxoInsFromData(chain, symbol string, data []byte) ([]*sdk.UtxoIn, error) {
+	var vins []*sdk.UtxoIn
+	err := rp.replay("QueryUtxoInsFromData", []interface{}{chain, symbol, data}, &vins)
+	return vins, err
+}
@@ -0,0 +1,78 @@
+package chainnode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestRecordReplay(t *testing.T) {
+	cn := &MockChainnode{}
+	vin := sdk.NewUtxoIn("hash", 1, sdk.NewInt(100), "addr")
+	tx := &ExtUtxoTransaction{
+		Hash:    "hash",
+		Status:  StatusSuccess,
+		Vins:    []*sdk.UtxoIn{&vin},
+		Vouts:   []*sdk.UtxoOut{{Address: "to", Amount: sdk.NewInt(90)}},
+		CostFee: sdk.NewInt(10),
+	}
+	cn.On("ValidAddress", "eth", "eth", "0xabc").Return(true, "0xABC")
+	cn.On("QueryGasPrice", "eth").Return(sdk.NewInt(1000), nil).Once()
+	cn.On("QueryGasPrice", "eth").Return(sdk.NewInt(2000), nil).Once()
+	cn.On("QueryUtxoTransaction", "btc", "btc", "hash", false).Return(tx, nil)
+	cn.On("QueryUtxoTransactionFromData", "btc", "btc", []byte{1, 2}, []*sdk.UtxoIn{&vin}).Return(tx, [][]byte{{3}}, nil)
+	cn.On("BroadcastTransaction", "btc", []byte{4}).Return("", errors.New("broadcast fails"))
+	cn.On("QueryNonce", "eth", "0xabc").Return(uint64(0), ErrorInvalidInput)
+
+	var buf bytes.Buffer
+	r := NewRecorder(cn, &buf)
+	valid, canonical := r.ValidAddress("eth", "eth", "0xabc")
+	price1, _ := r.QueryGasPrice("eth")
+	price2, _ := r.QueryGasPrice("eth")
+	queried, _ := r.QueryUtxoTransaction("btc", "btc", "hash", false)
+	fromData, hashes, _ := r.QueryUtxoTransactionFromData("btc", "btc", []byte{1, 2}, []*sdk.UtxoIn{&vin})
+	_, broadcastErr := r.BroadcastTransaction("btc", "btc", []byte{4})
+	require.NotNil(t, broadcastErr)
+	_, nonceErr := r.QueryNonce("eth", "0xabc")
+	require.Equal(t, ErrorInvalidInput, nonceErr)
+
+	rp, err := NewReplayer(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+
+	valid2, canonical2 := rp.ValidAddress("eth", "eth", "0xabc")
+	require.Equal(t, valid, valid2)
+	require.Equal(t, canonical, canonical2)
+
+	// responses to the same request are served in order, and the last one is repeated
+	price, err := rp.QueryGasPrice("eth")
+	require.Nil(t, err)
+	require.Equal(t, price1, price)
+	price, _ = rp.QueryGasPrice("eth")
+	require.Equal(t, price2, price)
+	price, _ = rp.QueryGasPrice("eth")
+	require.Equal(t, price2, price)
+
+	queried2, err := rp.QueryUtxoTransaction("btc", "btc", "hash", false)
+	require.Nil(t, err)
+	require.Equal(t, queried, queried2)
+	fromData2, hashes2, err := rp.QueryUtxoTransactionFromData("btc", "btc", []byte{1, 2}, []*sdk.UtxoIn{&vin})
+	require.Nil(t, err)
+	require.Equal(t, fromData, fromData2)
+	require.Equal(t, hashes, hashes2)
+
+	_, err = rp.BroadcastTransaction("btc", "btc", []byte{4})
+	require.Equal(t, broadcastErr.Error(), err.Error())
+	// sentinel errors are restored
+	_, err = rp.QueryNonce("eth", "0xabc")
+	require.Equal(t, ErrorInvalidInput, err)
+
+	// not recorded
+	valid, _ = rp.ValidAddress("eth", "eth", "0xdef")
+	require.False(t, valid)
+	_, err = rp.QueryGasPrice("btc")
+	require.True(t, errors.Is(err, ErrorNotRecorded))
+}
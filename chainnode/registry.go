@@ -0,0 +1,281 @@
+package chainnode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// An Adapter is an in-process Chainnode serving one or more external chains, it
+// is registered per chain name and called by Registry for that chain only.
+//
+// An adapter must follow the Chainnode contract:
+//   - SupportChain returns true for the chains it is registered for.
+//   - ValidAddress returns the canonical form of a valid address, ConvertAddress
+//     derives the address of a public key, which must be valid.
+//   - Methods not applicable to the chain model (utxo or account) return ErrorNotSupported.
+//   - A transaction created by Create*Transaction decodes back to the same
+//     transaction by Query*TransactionFromData, along with the hashes to sign.
+//   - A transaction signed by Create*SignedTransaction is verified by
+//     Verify*SignedTransaction for the signer's address only, and decodes back
+//     by Query*TransactionFromSignedData.
+//   - Query*Transaction reports the Status* of a transaction, StatusNotFound
+//     for unknown hashes.
+//
+// chainnodetest.RunSuite checks an adapter against this contract.
+type Adapter interface {
+	Chainnode
+}
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = make(map[string]Adapter)
+)
+
+// RegisterAdapter makes an adapter available for chain to every Registry created
+// afterwards. It panics if an adapter is already registered for chain.
+func RegisterAdapter(chain string, adapter Adapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	chain = strings.ToLower(chain)
+	if adapter == nil {
+		panic("chainnode: RegisterAdapter adapter is nil")
+	}
+	if _, dup := adapters[chain]; dup {
+		panic("chainnode: RegisterAdapter called twice for chain " + chain)
+	}
+	adapters[chain] = adapter
+}
+
+// RegisteredChains returns the sorted chains with a registered adapter
+func RegisteredChains() []string {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	chains := make([]string, 0, len(adapters))
+	for chain := range adapters {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
+
+var _ Chainnode = (*Registry)(nil)
+
+// Registry dispatches every call to the adapter registered for its chain, and
+// to the fallback Chainnode for chains without adapter
+type Registry struct {
+	mu       sync.RWMutex
+	adapters map[string]Adapter
+	fallback Chainnode
+}
+
+// NewRegistry creates a Registry with the adapters registered by RegisterAdapter.
+// fallback may be nil, calls for chains without adapter then fail with ErrorNotSupported.
+func NewRegistry(fallback Chainnode) *Registry {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	r := &Registry{
+		adapters: make(map[string]Adapter, len(adapters)),
+		fallback: fallback,
+	}
+	for chain, adapter := range adapters {
+		r.adapters[chain] = adapter
+	}
+	return r
+}
+
+// Register adds an adapter for chain to this registry only
+func (r *Registry) Register(chain string, adapter Adapter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chain = strings.ToLower(chain)
+	if _, dup := r.adapters[chain]; dup {
+		return fmt.Errorf("adapter for chain %s is already registered", chain)
+	}
+	r.adapters[chain] = adapter
+	return nil
+}
+
+func (r *Registry) route(chain string) (Chainnode, error) {
+	r.mu.RLock()
+	adapter, ok := r.adapters[strings.ToLower(chain)]
+	r.mu.RUnlock()
+	if ok {
+		return adapter, nil
+	}
+	if r.fallback == nil {
+		return nil, ErrorNotSupported
+	}
+	return r.fallback, nil
+}
+
+func (r *Registry) SupportChain(chain string) bool {
+	cn, err := r.route(chain)
+	if err != nil {
+		return false
+	}
+	return cn.SupportChain(chain)
+}
+
+func (r *Registry) ConvertAddress(chain string, pubKey []byte) (string, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return "", err
+	}
+	return cn.ConvertAddress(chain, pubKey)
+}
+
+func (r *Registry) ValidAddress(chain, symbol, address string) (bool, string) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return false, ""
+	}
+	return cn.ValidAddress(chain, symbol, address)
+}
+
+func (r *Registry) QueryBalance(chain, symbol, address, contractAddress string, blockHeight uint64) (sdk.Int, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return sdk.ZeroInt(), err
+	}
+	return cn.QueryBalance(chain, symbol, address, contractAddress, blockHeight)
+}
+
+func (r *Registry) QueryUtxo(chain, symbol string, vin *sdk.UtxoIn) (bool, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return false, err
+	}
+	return cn.QueryUtxo(chain, symbol, vin)
+}
+
+func (r *Registry) QueryNonce(chain, address string) (uint64, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return 0, err
+	}
+	return cn.QueryNonce(chain, address)
+}
+
+func (r *Registry) QueryGasPrice(chain string) (sdk.Int, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return sdk.ZeroInt(), err
+	}
+	return cn.QueryGasPrice(chain)
+}
+
+func (r *Registry) QueryUtxoTransaction(chain, symbol, hash string, asynMode bool) (*ExtUtxoTransaction, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, err
+	}
+	return cn.QueryUtxoTransaction(chain, symbol, hash, asynMode)
+}
+
+func (r *Registry) QueryAccountTransaction(chain, symbol, hash string, asynMode bool) (*ExtAccountTransaction, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, err
+	}
+	return cn.QueryAccountTransaction(chain, symbol, hash, asynMode)
+}
+
+func (r *Registry) CreateUtxoTransaction(chain, symbol string, transaction *ExtUtxoTransaction) ([]byte, [][]byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.CreateUtxoTransaction(chain, symbol, transaction)
+}
+
+func (r *Registry) CreateAccountTransaction(chain, symbol, contractAddress string, transaction *ExtAccountTransaction) ([]byte, []byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.CreateAccountTransaction(chain, symbol, contractAddress, transaction)
+}
+
+func (r *Registry) CreateUtxoSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys [][]byte) ([]byte, []byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.CreateUtxoSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+}
+
+func (r *Registry) CreateAccountSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys []byte) ([]byte, []byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.CreateAccountSignedTransaction(chain, symbol, raw, signatures, pubKeys)
+}
+
+func (r *Registry) VerifyUtxoSignedTransaction(chain, symbol string, address []string, signedTxData []byte, vins []*sdk.UtxoIn) (bool, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return false, err
+	}
+	return cn.VerifyUtxoSignedTransaction(chain, symbol, address, signedTxData, vins)
+}
+
+func (r *Registry) VerifyAccountSignedTransaction(chain, symbol string, address string, signedTxData []byte) (bool, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return false, err
+	}
+	return cn.VerifyAccountSignedTransaction(chain, symbol, address, signedTxData)
+}
+
+func (r *Registry) QueryAccountTransactionFromSignedData(chain, symbol string, signedTxData []byte) (*ExtAccountTransaction, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, err
+	}
+	return cn.QueryAccountTransactionFromSignedData(chain, symbol, signedTxData)
+}
+
+func (r *Registry) QueryUtxoTransactionFromSignedData(chain, symbol string, signedTxData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, err
+	}
+	return cn.QueryUtxoTransactionFromSignedData(chain, symbol, signedTxData, vins)
+}
+
+func (r *Registry) QueryAccountTransactionFromData(chain, symbol string, rawData []byte) (*ExtAccountTransaction, []byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.QueryAccountTransactionFromData(chain, symbol, rawData)
+}
+
+func (r *Registry) QueryUtxoTransactionFromData(chain, symbol string, rawData []byte, vins []*sdk.UtxoIn) (*ExtUtxoTransaction, [][]byte, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cn.QueryUtxoTransactionFromData(chain, symbol, rawData, vins)
+}
+
+func (r *Registry) BroadcastTransaction(chain, symbol string, signedTxData []byte) (string, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return "", err
+	}
+	return cn.BroadcastTransaction(chain, symbol, signedTxData)
+}
+
+func (r *Registry) QueryUtxoInsFromData(chain, symbol string, data []byte) ([]*sdk.UtxoIn, error) {
+	cn, err := r.route(chain)
+	if err != nil {
+		return nil, err
+	}
+	return cn.QueryUtxoInsFromData(chain, symbol, data)
+}
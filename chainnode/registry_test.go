@@ -0,0 +1,55 @@
+package chainnode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestRegistry(t *testing.T) {
+	adapter := &MockChainnode{}
+	adapter.On("SupportChain", "foo").Return(true)
+	adapter.On("QueryGasPrice", "foo").Return(sdk.NewInt(10), nil)
+	fallback := &MockChainnode{}
+	fallback.On("SupportChain", "eth").Return(true)
+	fallback.On("QueryGasPrice", "eth").Return(sdk.NewInt(20), nil)
+
+	RegisterAdapter("Foo", adapter)
+	defer func() {
+		adaptersMu.Lock()
+		delete(adapters, "foo")
+		adaptersMu.Unlock()
+	}()
+	require.Equal(t, []string{"foo"}, RegisteredChains())
+	require.Panics(t, func() { RegisterAdapter("foo", adapter) })
+
+	r := NewRegistry(fallback)
+	require.True(t, r.SupportChain("foo"))
+	require.True(t, r.SupportChain("eth"))
+	price, err := r.QueryGasPrice("foo")
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(10), price)
+	price, err = r.QueryGasPrice("eth")
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(20), price)
+
+	bar := &MockChainnode{}
+	bar.On("QueryNonce", "bar", "addr").Return(uint64(3), nil)
+	require.Nil(t, r.Register("bar", bar))
+	require.NotNil(t, r.Register("bar", bar))
+	nonce, err := r.QueryNonce("bar", "addr")
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), nonce)
+
+	// without fallback, chains without adapter are not supported
+	r = NewRegistry(nil)
+	require.True(t, r.SupportChain("foo"))
+	require.False(t, r.SupportChain("eth"))
+	_, err = r.QueryGasPrice("eth")
+	require.Equal(t, ErrorNotSupported, err)
+
+	adapter.AssertExpectations(t)
+	fallback.AssertExpectations(t)
+}
@@ -0,0 +1,369 @@
+// Package testchain is a reference chainnode.Adapter for a simple account
+// based chain kept in memory. Transactions are JSON encoded, signed with
+// secp256k1 over the sha256 hash of the raw transaction, and applied to the
+// ledger when broadcasted.
+package testchain
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+const (
+	// Chain is the chain name and native symbol of the test chain
+	Chain = "testchain"
+
+	addressPrefix = "tc"
+	addressLength = 20
+)
+
+// Errors
+var (
+	ErrInvalidNonce        = errors.New("invalid nonce")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+var _ chainnode.Adapter = (*TestChain)(nil)
+
+type rawTx struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Amount   sdk.Int `json:"amount"`
+	Nonce    uint64  `json:"nonce"`
+	GasPrice sdk.Int `json:"gas_price"`
+	GasLimit sdk.Int `json:"gas_limit"`
+	Memo     string  `json:"memo"`
+}
+
+type signedTx struct {
+	Raw       []byte `json:"raw"`
+	Signature []byte `json:"signature"`
+	PubKey    []byte `json:"pub_key"`
+}
+
+// TestChain implements chainnode.Adapter for the test chain
+type TestChain struct {
+	mu       sync.Mutex
+	gasPrice sdk.Int
+	height   uint64
+	balances map[string]sdk.Int
+	nonces   map[string]uint64
+	txs      map[string]*chainnode.ExtAccountTransaction
+}
+
+// New creates an empty test chain with a fixed gas price
+func New(gasPrice sdk.Int) *TestChain {
+	return &TestChain{
+		gasPrice: gasPrice,
+		balances: make(map[string]sdk.Int),
+		nonces:   make(map[string]uint64),
+		txs:      make(map[string]*chainnode.ExtAccountTransaction),
+	}
+}
+
+// Fund adds amount to the balance of address
+func (c *TestChain) Fund(address string, amount sdk.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances[address] = c.balanceOf(address).Add(amount)
+}
+
+func (c *TestChain) balanceOf(address string) sdk.Int {
+	balance, ok := c.balances[address]
+	if !ok {
+		return sdk.ZeroInt()
+	}
+	return balance
+}
+
+func checkChain(chain, symbol, contractAddress string) error {
+	if chain != Chain || symbol != Chain || contractAddress != "" {
+		return chainnode.ErrorNotSupported
+	}
+	return nil
+}
+
+func canonicalAddress(address string) (string, bool) {
+	address = strings.ToLower(address)
+	if !strings.HasPrefix(address, addressPrefix) {
+		return "", false
+	}
+	bz, err := hex.DecodeString(address[len(addressPrefix):])
+	if err != nil || len(bz) != addressLength {
+		return "", false
+	}
+	return address, true
+}
+
+func pubKeyToAddress(pubKey []byte) (string, error) {
+	var pk *ecdsa.PublicKey
+	var err error
+	if len(pubKey) == 33 {
+		pk, err = ethcrypto.DecompressPubkey(pubKey)
+	} else {
+		pk, err = ethcrypto.UnmarshalPubkey(pubKey)
+	}
+	if err != nil {
+		return "", chainnode.ErrorInvalidInput
+	}
+	hash := sha256.Sum256(ethcrypto.CompressPubkey(pk))
+	return addressPrefix + hex.EncodeToString(hash[:addressLength]), nil
+}
+
+func decodeRawTx(raw []byte) (*rawTx, []byte, error) {
+	var tx rawTx
+	if err := json.Unmarshal(raw, &tx); err != nil {
+		return nil, nil, chainnode.ErrorInvalidInput
+	}
+	hash := sha256.Sum256(raw)
+	return &tx, hash[:], nil
+}
+
+func decodeSignedTx(signed []byte) (*signedTx, *rawTx, error) {
+	var stx signedTx
+	if err := json.Unmarshal(signed, &stx); err != nil {
+		return nil, nil, chainnode.ErrorInvalidInput
+	}
+	tx, signHash, err := decodeRawTx(stx.Raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stx.Signature) < 64 || !ethcrypto.VerifySignature(stx.PubKey, signHash, stx.Signature[:64]) {
+		return nil, nil, chainnode.ErrorInvalidSignature
+	}
+	return &stx, tx, nil
+}
+
+func txHash(signed []byte) string {
+	hash := sha256.Sum256(signed)
+	return hex.EncodeToString(hash[:])
+}
+
+func (tx *rawTx) toExt(hash string, status uint64) *chainnode.ExtAccountTransaction {
+	return &chainnode.ExtAccountTransaction{
+		Hash:     hash,
+		Status:   status,
+		From:     tx.From,
+		To:       tx.To,
+		Amount:   tx.Amount,
+		Memo:     tx.Memo,
+		Nonce:    tx.Nonce,
+		GasLimit: tx.GasLimit,
+		GasPrice: tx.GasPrice,
+		CostFee:  tx.GasPrice.Mul(tx.GasLimit),
+	}
+}
+
+func (c *TestChain) SupportChain(chain string) bool {
+	return chain == Chain
+}
+
+func (c *TestChain) ConvertAddress(chain string, pubKey []byte) (string, error) {
+	if chain != Chain {
+		return "", chainnode.ErrorNotSupported
+	}
+	return pubKeyToAddress(pubKey)
+}
+
+func (c *TestChain) ValidAddress(chain, symbol, address string) (bool, string) {
+	if checkChain(chain, symbol, "") != nil {
+		return false, ""
+	}
+	canonical, ok := canonicalAddress(address)
+	return ok, canonical
+}
+
+func (c *TestChain) QueryBalance(chain, symbol, address, contractAddress string, blockHeight uint64) (sdk.Int, error) {
+	if err := checkChain(chain, symbol, contractAddress); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.balanceOf(address), nil
+}
+
+func (c *TestChain) QueryUtxo(chain, symbol string, vin *sdk.UtxoIn) (bool, error) {
+	return false, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) QueryNonce(chain, address string) (uint64, error) {
+	if chain != Chain {
+		return 0, chainnode.ErrorNotSupported
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nonces[address], nil
+}
+
+func (c *TestChain) QueryGasPrice(chain string) (sdk.Int, error) {
+	if chain != Chain {
+		return sdk.ZeroInt(), chainnode.ErrorNotSupported
+	}
+	return c.gasPrice, nil
+}
+
+func (c *TestChain) QueryUtxoTransaction(chain, symbol, hash string, asynMode bool) (*chainnode.ExtUtxoTransaction, error) {
+	return nil, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) QueryAccountTransaction(chain, symbol, hash string, asynMode bool) (*chainnode.ExtAccountTransaction, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx, ok := c.txs[hash]
+	if !ok {
+		return &chainnode.ExtAccountTransaction{Hash: hash, Status: chainnode.StatusNotFound}, nil
+	}
+	copied := *tx
+	return &copied, nil
+}
+
+func (c *TestChain) CreateUtxoTransaction(chain, symbol string, transaction *chainnode.ExtUtxoTransaction) ([]byte, [][]byte, error) {
+	return nil, nil, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) CreateAccountTransaction(chain, symbol, contractAddress string, transaction *chainnode.ExtAccountTransaction) ([]byte, []byte, error) {
+	if err := checkChain(chain, symbol, contractAddress); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := canonicalAddress(transaction.From); !ok {
+		return nil, nil, chainnode.ErrorInvalidInput
+	}
+	if _, ok := canonicalAddress(transaction.To); !ok {
+		return nil, nil, chainnode.ErrorInvalidInput
+	}
+	raw, err := json.Marshal(rawTx{
+		From:     transaction.From,
+		To:       transaction.To,
+		Amount:   transaction.Amount,
+		Nonce:    transaction.Nonce,
+		GasPrice: transaction.GasPrice,
+		GasLimit: transaction.GasLimit,
+		Memo:     transaction.Memo,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	_, signHash, err := decodeRawTx(raw)
+	return raw, signHash, err
+}
+
+func (c *TestChain) CreateUtxoSignedTransaction(chain, symbol string, raw []byte, signatures, pubKeys [][]byte) ([]byte, []byte, error) {
+	return nil, nil, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) CreateAccountSignedTransaction(chain, symbol string, raw []byte, signature, pubKey []byte) ([]byte, []byte, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return nil, nil, err
+	}
+	signed, err := json.Marshal(signedTx{Raw: raw, Signature: signature, PubKey: pubKey})
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, err = decodeSignedTx(signed); err != nil {
+		return nil, nil, err
+	}
+	hash := sha256.Sum256(signed)
+	return signed, hash[:], nil
+}
+
+func (c *TestChain) VerifyUtxoSignedTransaction(chain, symbol string, address []string, signedTxData []byte, vins []*sdk.UtxoIn) (bool, error) {
+	return false, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) VerifyAccountSignedTransaction(chain, symbol string, address string, signedTxData []byte) (bool, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return false, err
+	}
+	stx, tx, err := decodeSignedTx(signedTxData)
+	if err == chainnode.ErrorInvalidSignature {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	signer, err := pubKeyToAddress(stx.PubKey)
+	if err != nil {
+		return false, nil
+	}
+	return signer == address && tx.From == address, nil
+}
+
+func (c *TestChain) QueryAccountTransactionFromSignedData(chain, symbol string, signedTxData []byte) (*chainnode.ExtAccountTransaction, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return nil, err
+	}
+	_, tx, err := decodeSignedTx(signedTxData)
+	if err != nil {
+		return nil, err
+	}
+	return tx.toExt(txHash(signedTxData), chainnode.StatusPending), nil
+}
+
+func (c *TestChain) QueryUtxoTransactionFromSignedData(chain, symbol string, signedTxData []byte, vins []*sdk.UtxoIn) (*chainnode.ExtUtxoTransaction, error) {
+	return nil, chainnode.ErrorNotSupported
+}
+
+func (c *TestChain) QueryAccountTransactionFromData(chain, symbol string, rawData []byte) (*chainnode.ExtAccountTransaction, []byte, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return nil, nil, err
+	}
+	tx, signHash, err := decodeRawTx(rawData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx.toExt("", chainnode.StatusNotFound), signHash, nil
+}
+
+func (c *TestChain) QueryUtxoTransactionFromData(chain, symbol string, rawData []byte, vins []*sdk.UtxoIn) (*chainnode.ExtUtxoTransaction, [][]byte, error) {
+	return nil, nil, chainnode.ErrorNotSupported
+}
+
+// BroadcastTransaction applies a signed transaction to the ledger
+func (c *TestChain) BroadcastTransaction(chain, symbol string, signedTxData []byte) (string, error) {
+	if err := checkChain(chain, symbol, ""); err != nil {
+		return "", err
+	}
+	stx, tx, err := decodeSignedTx(signedTxData)
+	if err != nil {
+		return "", err
+	}
+	if signer, err := pubKeyToAddress(stx.PubKey); err != nil || signer != tx.From {
+		return "", chainnode.ErrorInvalidSignature
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tx.Nonce != c.nonces[tx.From] {
+		return "", ErrInvalidNonce
+	}
+	ext := tx.toExt(txHash(signedTxData), chainnode.StatusSuccess)
+	balance := c.balanceOf(tx.From)
+	if balance.LT(tx.Amount.Add(ext.CostFee)) {
+		return "", ErrInsufficientBalance
+	}
+
+	c.height++
+	ext.BlockHeight = c.height
+	c.balances[tx.From] = balance.Sub(tx.Amount).Sub(ext.CostFee)
+	c.balances[tx.To] = c.balanceOf(tx.To).Add(tx.Amount)
+	c.nonces[tx.From]++
+	c.txs[ext.Hash] = ext
+	return ext.Hash, nil
+}
+
+func (c *TestChain) QueryUtxoInsFromData(chain, symbol string, data []byte) ([]*sdk.UtxoIn, error) {
+	return nil, chainnode.ErrorNotSupported
+}
@@ -0,0 +1,105 @@
+package testchain
+
+import (
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	"github.com/hbtc-chain/bhchain/chainnode/chainnodetest"
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestTransfer(t *testing.T) {
+	tc := New(sdk.NewInt(2))
+	r := chainnode.NewRegistry(nil)
+	require.Nil(t, r.Register(Chain, tc))
+	require.True(t, r.SupportChain(Chain))
+
+	key, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+	pubKey := ethcrypto.CompressPubkey(&key.PublicKey)
+	from, err := r.ConvertAddress(Chain, pubKey)
+	require.Nil(t, err)
+	valid, canonical := r.ValidAddress(Chain, Chain, from)
+	require.True(t, valid)
+	require.Equal(t, from, canonical)
+	to := addressPrefix + "0102030405060708090a0b0c0d0e0f1011121314"
+	valid, _ = r.ValidAddress(Chain, Chain, "tc0102")
+	require.False(t, valid)
+
+	tc.Fund(from, sdk.NewInt(1000))
+	raw, signHash, err := r.CreateAccountTransaction(Chain, Chain, "", &chainnode.ExtAccountTransaction{
+		From:     from,
+		To:       to,
+		Amount:   sdk.NewInt(100),
+		GasPrice: sdk.NewInt(2),
+		GasLimit: sdk.NewInt(21),
+	})
+	require.Nil(t, err)
+	tx, hash, err := r.QueryAccountTransactionFromData(Chain, Chain, raw)
+	require.Nil(t, err)
+	require.Equal(t, signHash, hash)
+	require.Equal(t, sdk.NewInt(100), tx.Amount)
+
+	sig, err := ethcrypto.Sign(signHash, key)
+	require.Nil(t, err)
+	signed, _, err := r.CreateAccountSignedTransaction(Chain, Chain, raw, sig, pubKey)
+	require.Nil(t, err)
+	ok, err := r.VerifyAccountSignedTransaction(Chain, Chain, from, signed)
+	require.Nil(t, err)
+	require.True(t, ok)
+	ok, err = r.VerifyAccountSignedTransaction(Chain, Chain, to, signed)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	txHash, err := r.BroadcastTransaction(Chain, Chain, signed)
+	require.Nil(t, err)
+	_, err = r.BroadcastTransaction(Chain, Chain, signed)
+	require.Equal(t, ErrInvalidNonce, err)
+
+	tx, err = r.QueryAccountTransaction(Chain, Chain, txHash, false)
+	require.Nil(t, err)
+	require.Equal(t, uint64(chainnode.StatusSuccess), tx.Status)
+	require.Equal(t, sdk.NewInt(42), tx.CostFee)
+	balance, err := r.QueryBalance(Chain, Chain, from, "", 0)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(858), balance)
+	balance, _ = r.QueryBalance(Chain, Chain, to, "", 0)
+	require.Equal(t, sdk.NewInt(100), balance)
+	nonce, _ := r.QueryNonce(Chain, from)
+	require.Equal(t, uint64(1), nonce)
+
+	tx, err = r.QueryAccountTransaction(Chain, Chain, "unknown", false)
+	require.Nil(t, err)
+	require.Equal(t, uint64(chainnode.StatusNotFound), tx.Status)
+	_, err = r.QueryUtxoTransaction(Chain, Chain, txHash, false)
+	require.Equal(t, chainnode.ErrorNotSupported, err)
+}
+
+func TestConformance(t *testing.T) {
+	fixture := chainnodetest.Fixture{
+		UnsupportedChain: "eth",
+		Account: &chainnodetest.AccountFixture{
+			Chain:            Chain,
+			Symbol:           Chain,
+			Signer:           chainnodetest.NewSecp256k1Signer(true),
+			InvalidAddresses: []string{"", "tc0102", "0x0102030405060708090a0b0c0d0e0f1011121314"},
+			Transaction: chainnode.ExtAccountTransaction{
+				To:       addressPrefix + "0102030405060708090a0b0c0d0e0f1011121314",
+				Amount:   sdk.NewInt(100),
+				Nonce:    1,
+				GasPrice: sdk.NewInt(2),
+				GasLimit: sdk.NewInt(21),
+				Memo:     "memo",
+			},
+			Statuses: map[string]uint64{"unknown": chainnode.StatusNotFound},
+		},
+	}
+	chainnodetest.RunSuite(t, New(sdk.NewInt(2)), fixture)
+
+	r := chainnode.NewRegistry(nil)
+	require.Nil(t, r.Register(Chain, New(sdk.NewInt(2))))
+	chainnodetest.RunSuite(t, r, fixture)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hbtc-chain/bhchain/chainnode"
 	"github.com/hbtc-chain/bhchain/chainnode/grpcclient"
 	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,6 +22,7 @@ import (
 	"github.com/hbtc-chain/bhchain/client"
 	"github.com/hbtc-chain/bhchain/client/flags"
 	"github.com/hbtc-chain/bhchain/server"
+	srvconfig "github.com/hbtc-chain/bhchain/server/config"
 	"github.com/hbtc-chain/bhchain/store"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/x/genaccounts"
@@ -106,10 +108,56 @@ func exportAppStateAndTMValidators(
 }
 
 func getChainnode(logger log.Logger, network string) chainnode.Chainnode {
-	cn := grpcclient.New(logger)
-	logger.Info("start init local chainnode", "networktype", network)
-	if err := cn.Init(network); err != nil {
-		panic(fmt.Sprintf("Failed to init local chainnode err: %v", err))
+	if replayFile := viper.GetString(server.FlagChainnodeReplay); replayFile != "" {
+		logger.Info("start replay chainnode", "file", replayFile)
+		f, err := os.Open(replayFile)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to open chainnode records err: %v", err))
+		}
+		defer f.Close()
+		rp, err := chainnode.NewReplayer(f)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to load chainnode records err: %v", err))
+		}
+		return rp
+	}
+
+	options := []grpcclient.Option{grpcclient.WithQuorum(viper.GetInt(server.FlagChainnodeQuorum))}
+	if endpoints := viper.GetStringSlice(server.FlagChainnodeEndpoints); len(endpoints) > 0 {
+		options = append(options, grpcclient.WithEndpoints(endpoints...))
+	}
+	metrics := chainnode.NopMetrics()
+	if viper.GetBool("instrumentation.prometheus") {
+		namespace := viper.GetString("instrumentation.namespace")
+		options = append(options, grpcclient.WithMetrics(grpcclient.PrometheusMetrics(namespace)))
+		metrics = chainnode.PrometheusMetrics(namespace)
+	}
+	client := grpcclient.New(logger, options...)
+	logger.Info("start init chainnode", "networktype", network)
+	if err := client.Init(network); err != nil {
+		panic(fmt.Sprintf("Failed to init chainnode err: %v", err))
+	}
+
+	srvConf, err := srvconfig.ParseConfig()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse chainnode config err: %v", err))
+	}
+	// in-process adapters registered by chainnode.RegisterAdapter serve their chains, grpc client the rest
+	var cn chainnode.Chainnode = chainnode.NewCached(chainnode.NewRegistry(client), chainnode.CacheConfig{
+		TxTTL:       srvConf.Chainnode.TxCacheTTL,
+		GasPriceTTL: srvConf.Chainnode.GasPriceCacheTTL,
+		CacheSize:   srvConf.Chainnode.CacheSize,
+		RateLimit:   srvConf.Chainnode.RateLimit,
+		RateLimits:  srvConf.Chainnode.RateLimits,
+	}, metrics)
+
+	if recordFile := viper.GetString(server.FlagChainnodeRecord); recordFile != "" {
+		logger.Info("record chainnode", "file", recordFile)
+		f, err := os.OpenFile(recordFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to open chainnode records err: %v", err))
+		}
+		return chainnode.NewRecorder(cn, f)
 	}
 	return cn
 }
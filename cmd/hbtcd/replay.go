@@ -39,6 +39,10 @@ func replayCmd() *cobra.Command {
 		server.FlagChainnodeNetwork, "testnet",
 		"Network type of chainnode; available value: mainnet, testnet, regtest",
 	)
+	command.Flags().String(
+		server.FlagChainnodeReplay, "",
+		"Serve chainnode offline with the records of a node started with --"+server.FlagChainnodeRecord,
+	)
 	return &command
 }
 
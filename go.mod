@@ -11,6 +11,7 @@ require (
 	github.com/cosmos/ledger-cosmos-go v0.11.1
 	github.com/emirpasic/gods v1.12.0
 	github.com/ethereum/go-ethereum v1.9.15
+	github.com/go-kit/kit v0.9.0
 	github.com/gogo/protobuf v1.3.1
 	github.com/golang/mock v1.3.1
 	github.com/golang/protobuf v1.4.0
@@ -20,6 +21,7 @@ require (
 	github.com/otiai10/copy v1.0.2
 	github.com/pelletier/go-toml v1.6.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.4.1
 	github.com/rakyll/statik v0.1.6
 	github.com/satori/go.uuid v1.2.0
 	github.com/spf13/cobra v1.0.0
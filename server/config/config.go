@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
 )
@@ -36,9 +37,34 @@ type BaseConfig struct {
 	ChainnodeNetwork string `mapstructure:"chainnode-network"`
 }
 
+// ChainnodeConfig defines the caching and rate limiting of chainnode calls
+type ChainnodeConfig struct {
+	// TxCacheTTL is how long a finalized transaction queried from chainnode is
+	// cached, 0 disables the cache. A reorg of the external chain can revert a
+	// finalized transaction, so it should not exceed a few block times.
+	TxCacheTTL time.Duration `mapstructure:"tx-cache-ttl"`
+
+	// GasPriceCacheTTL is how long a gas price queried from chainnode is cached,
+	// 0 disables the cache.
+	GasPriceCacheTTL time.Duration `mapstructure:"gas-price-cache-ttl"`
+
+	// CacheSize is the max number of cached chainnode replies.
+	CacheSize int `mapstructure:"cache-size"`
+
+	// RateLimit is the max calls per second of each chainnode method querying
+	// the external chains, 0 means unlimited. Methods computed locally by the
+	// chainnode, like ValidAddress, are never limited.
+	RateLimit float64 `mapstructure:"rate-limit"`
+
+	// RateLimits overrides RateLimit for the chainnode methods in it.
+	RateLimits map[string]float64 `mapstructure:"rate-limits"`
+}
+
 // Config defines the server's top level configuration
 type Config struct {
 	BaseConfig `mapstructure:",squash"`
+
+	Chainnode ChainnodeConfig `mapstructure:"chainnode"`
 }
 
 // SetMinGasPrices sets the validator's minimum gas prices.
@@ -71,9 +97,15 @@ func (c *Config) GetMinGasPrices() sdk.DecCoins {
 // DefaultConfig returns server's default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		BaseConfig{
+		BaseConfig: BaseConfig{
 			MinGasPrices:     defaultMinGasPrices,
 			ChainnodeNetwork: "testnet",
 		},
+		Chainnode: ChainnodeConfig{
+			TxCacheTTL:       10 * time.Second,
+			GasPriceCacheTTL: 10 * time.Second,
+			CacheSize:        10000,
+			RateLimits:       map[string]float64{},
+		},
 	}
 }
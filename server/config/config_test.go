@@ -1,8 +1,13 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
@@ -18,3 +23,28 @@ func TestSetMinimumFees(t *testing.T) {
 	cfg.SetMinGasPrices(sdk.DecCoins{sdk.NewInt64DecCoin("foo", 5)})
 	require.Equal(t, "5.000000000000000000foo", cfg.MinGasPrices)
 }
+
+func TestChainnodeConfigRoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chainnode.RateLimit = 5
+	cfg.Chainnode.RateLimits = map[string]float64{"BroadcastTransaction": 0.5}
+
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.toml")
+	WriteConfigFile(path, cfg)
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(path)
+	require.NoError(t, viper.ReadInConfig())
+	parsed, err := ParseConfig()
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, parsed.Chainnode.TxCacheTTL)
+	require.Equal(t, 10*time.Second, parsed.Chainnode.GasPriceCacheTTL)
+	require.Equal(t, 10000, parsed.Chainnode.CacheSize)
+	require.Equal(t, float64(5), parsed.Chainnode.RateLimit)
+	// viper keys are case insensitive
+	require.Equal(t, map[string]float64{"broadcasttransaction": 0.5}, parsed.Chainnode.RateLimits)
+}
@@ -36,7 +36,28 @@ halt-time = {{ .BaseConfig.HaltTime }}
 # chainnode-network config the network type of chainnode; available value: 
 # mainnet, testnet, regtest
 chainnode-network = "{{ .BaseConfig.ChainnodeNetwork }}"
-`
+
+##### chainnode caching and rate limiting options #####
+[chainnode]
+
+# How long a finalized transaction queried from chainnode is cached, 0 disables the cache.
+# A reorg of the external chain can revert a finalized transaction, keep it within a few block times.
+tx-cache-ttl = "{{ .Chainnode.TxCacheTTL }}"
+
+# How long a gas price queried from chainnode is cached, 0 disables the cache.
+gas-price-cache-ttl = "{{ .Chainnode.GasPriceCacheTTL }}"
+
+# The max number of cached chainnode replies.
+cache-size = {{ .Chainnode.CacheSize }}
+
+# The max calls per second of each chainnode method querying the external chains, 0 means unlimited.
+# Methods computed locally by the chainnode, like ValidAddress, are never limited.
+rate-limit = {{ .Chainnode.RateLimit }}
+
+# Overrides rate-limit for the listed chainnode methods, e.g. BroadcastTransaction = 10
+[chainnode.rate-limits]
+{{ range $method, $rate := .Chainnode.RateLimits }}{{ $method }} = {{ $rate }}
+{{ end }}`
 
 var configTemplate *template.Template
 
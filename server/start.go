@@ -35,6 +35,10 @@ const (
 	FlagHaltHeight         = "halt-height"
 	FlagHaltTime           = "halt-time"
 	FlagChainnodeNetwork   = "chainnode-network"
+	FlagChainnodeRecord    = "chainnode-record"
+	FlagChainnodeReplay    = "chainnode-replay"
+	FlagChainnodeEndpoints = "chainnode-endpoints"
+	FlagChainnodeQuorum    = "chainnode-quorum"
 	FlagUnsafeSkipUpgrades = "unsafe-skip-upgrades"
 )
 
@@ -88,6 +92,12 @@ which accepts a path for the resulting pprof file.
 		FlagChainnodeNetwork, "testnet",
 		"Network type of chainnode; available value: mainnet, testnet, regtest",
 	)
+	cmd.Flags().StringSlice(
+		FlagChainnodeEndpoints, []string{"local"},
+		"Chainnode endpoints in failover order; \"local\" is the in-process chainnode, others are grpc addresses",
+	)
+	cmd.Flags().Int(FlagChainnodeQuorum, 0, "Number of chainnode endpoints that must agree on transaction and balance queries, 0 to disable")
+	cmd.Flags().String(FlagChainnodeRecord, "", "Record the requests to chainnode and responses to the provided file, which can be served back by replay")
 	cmd.Flags().String(flagP2PServer, ":26659", "P2P server address for settle")
 	cmd.Flags().Uint64(FlagHaltHeight, 0, "Height at which to gracefully halt the chain and shutdown the node")
 	cmd.Flags().Uint64(FlagHaltTime, 0, "Minimum block time (in Unix seconds) at which to gracefully halt the chain and shutdown the node")
@@ -12,6 +12,10 @@ type Asset struct {
 	EnableSendTx bool `json:"enable_sendtx" yaml:"enable_sendtx"`
 
 	GasRemained Int `json:"gas_remained"`
+
+	// DepositOnly marks an additional deposit address of a user CU, it never
+	// replaces the address of the epoch and can be retired once collected.
+	DepositOnly bool `json:"deposit_only" yaml:"deposit_only"`
 }
 
 var NilAsset = Asset{}
@@ -12,11 +12,15 @@ type DepositItemStatus uint16
 // state transform flow:
 //               CollectWaitSign                CollectFinish
 // UnCollected -------------------> InProcess -----------------> Confirmed
+//
+// deposits below the deposit threshold stay Dust until they are aggregated into a collect:
+// Dust --------> WaitCollect
 const (
 	DepositItemStatusUnCollected DepositItemStatus = 0x0
 	DepositItemStatusWaitCollect DepositItemStatus = 0x1
 	DepositItemStatusConfirmed   DepositItemStatus = 0x2
 	DepositItemStatusInProcess   DepositItemStatus = 0x3
+	DepositItemStatusDust        DepositItemStatus = 0x4
 )
 
 // In check if current status is in the list
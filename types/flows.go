@@ -35,6 +35,9 @@ const (
 	CategoryTypeOpenswap          CategoryType = 0xB
 	CategoryTypeQuickSwap         CategoryType = 0xC
 	CategoryTypeHrc10             CategoryType = 0xD
+	CategoryTypeUtxoConsolidation CategoryType = 0xE
+	CategoryTypeHTLC              CategoryType = 0xF
+	CategoryTypeScheduledTransfer CategoryType = 0x10
 )
 
 const (
@@ -115,6 +118,92 @@ type DepositConfirmedFlow struct {
 	InValidOrderIDs []string
 }
 
+type DepositInvalidatedFlow struct {
+	OrderID   string
+	CuAddress string
+	Symbol    string
+	Txhash    string
+	Index     uint64
+	Amount    Int
+	// HeldAmount is the credited amount moved back into hold
+	HeldAmount Int
+	// Deficit is the credited amount which has already been spent by the CU
+	Deficit Int
+}
+
+// DepositRoutedFlow moves a confirmed deposit credited to the deposit address owner to the CU its memo
+// routes to, or into the suspense balance of the owner if the memo is unknown
+type DepositRoutedFlow struct {
+	OrderID  string
+	FromCU   string
+	ToCU     string
+	Memo     string
+	Symbol   string
+	Amount   Int
+	Suspense bool
+}
+
+// SuspenseReassignedFlow moves suspense balance of the owner to a CU
+type SuspenseReassignedFlow struct {
+	FromCU string
+	ToCU   string
+	Symbol string
+	Amount Int
+}
+
+// HTLCCreatedFlow locks Amount of Sender under Hashlock until ExpireHeight
+type HTLCCreatedFlow struct {
+	Hashlock     string
+	Sender       string
+	Receiver     string
+	Amount       Coins
+	ExpireHeight uint64
+}
+
+// HTLCClaimedFlow pays the locked amount to Receiver with the preimage of Hashlock
+type HTLCClaimedFlow struct {
+	Hashlock string
+	Preimage string
+	Sender   string
+	Receiver string
+	Amount   Coins
+}
+
+// HTLCRefundedFlow unlocks the amount of an expired HTLC to Sender
+type HTLCRefundedFlow struct {
+	Hashlock string
+	Sender   string
+	Amount   Coins
+}
+
+// ScheduledTransferCreatedFlow reserves Amount for each of the Times executions of a scheduled transfer
+type ScheduledTransferCreatedFlow struct {
+	ID       uint64
+	FromCU   string
+	ToCU     string
+	Amount   Coins
+	ByTime   bool
+	Start    uint64
+	Interval uint64
+	Times    uint64
+}
+
+// ScheduledTransferExecutedFlow pays Amount of a scheduled transfer to ToCU, Remaining executions are left
+type ScheduledTransferExecutedFlow struct {
+	ID        uint64
+	FromCU    string
+	ToCU      string
+	Amount    Coins
+	Remaining uint64
+}
+
+// ScheduledTransferCancelledFlow unlocks the Refund reserved for the remaining executions to FromCU
+type ScheduledTransferCancelledFlow struct {
+	ID     uint64
+	FromCU string
+	Refund Coins
+}
+
 type CollectWaitSignFlow struct {
 	OrderIDs []string
 	RawData  []byte
@@ -158,9 +247,24 @@ type WithdrawalSignFinishFlow struct {
 }
 
 type WithdrawalFinishFlow struct {
+	OrderIDs  []string
+	CostFee   Int
+	Valid     bool
+	BumpedFee Int
+}
+
+// BatchWithdrawalFlow links the withdrawal orders created for the outputs of a batch
+type BatchWithdrawalFlow struct {
+	BatchID  string
 	OrderIDs []string
-	CostFee  Int
-	Valid    bool
+}
+
+type WithdrawalFeeBumpFlow struct {
+	OrderIDs       []string
+	ReplacedTxHash string
+	RawData        []byte
+	CostFee        Int
+	BumpedFee      Int
 }
 
 type OpcuAssetTransferFlow struct {
@@ -187,6 +291,21 @@ type OpcuAssetTransferFinishFlow struct {
 	CostFee Int
 }
 
+type UtxoConsolidationWaitSignFlow struct {
+	OrderID string
+	RawData []byte
+}
+
+type UtxoConsolidationSignFinishFlow struct {
+	OrderID  string
+	SignedTx []byte
+}
+
+type UtxoConsolidationFinishFlow struct {
+	OrderID string
+	CostFee Int
+}
+
 type SysTransferFlow struct {
 	OrderID  string
 	FromCU   string
@@ -19,6 +19,7 @@ const (
 	OrderTypeDeposit           OrderType = 0x4
 	OrderTypeSysTransfer       OrderType = 0x5
 	OrderTypeOpcuAssetTransfer OrderType = 0x6
+	OrderTypeUtxoConsolidation OrderType = 0x7
 )
 
 const (
@@ -49,6 +50,8 @@ const (
 	DepositUnconfirm   = 0
 	DepositWaitConfirm = 1
 	DepositConfirmed   = 2
+	// DepositInvalidated marks a confirmed deposit whose external tx vanished by a chain reorg
+	DepositInvalidated = 3
 )
 
 type WithdrawStatus int
@@ -165,6 +168,11 @@ type OrderKeyGen struct {
 	MultiSignAddress string      `json:"multi_sign_address"`
 	Pubkey           []byte      `json:"pubkey"`
 	Epoch            uint64      `json:"epoch"`
+	DepositAddress   bool        `json:"deposit_address"`
+	// StageHeight is the height the order entered its current status, the order height if 0
+	StageHeight uint64 `json:"stage_height"`
+	// Retries is the number of times the order was reassigned after a timeout
+	Retries uint64 `json:"retries"`
 }
 
 // DeepCopy OrderKeygen
@@ -177,6 +185,9 @@ func (o *OrderKeyGen) DeepCopy() Order {
 		To:               o.To,
 		OpenFee:          o.OpenFee,
 		MultiSignAddress: o.MultiSignAddress,
+		DepositAddress:   o.DepositAddress,
+		StageHeight:      o.StageHeight,
+		Retries:          o.Retries,
 	}
 	copy(newOrder.KeyNodes, o.KeyNodes)
 	return newOrder
@@ -219,6 +230,10 @@ type OrderCollect struct {
 	// external chain collect tx hash
 	ExtTxHash     string `json:"ext_txhash"`
 	DepositStatus uint16 `json:"deposit_status"`
+	// CreditedTo is the CU the deposit is credited to by its deposit route, CollectFromCU if empty
+	CreditedTo CUAddress `json:"credited_to"`
+	// CreditedToSuspense is true if the deposit is credited to the suspense balance of CreditedTo
+	CreditedToSuspense bool `json:"credited_to_suspense"`
 }
 
 func (o *OrderCollect) GetRawdata() []byte {
@@ -246,6 +261,8 @@ func (o *OrderCollect) DeepCopy() Order {
 		SignedTx:           make([]byte, len(o.SignedTx)),
 		DepositStatus:      o.DepositStatus,
 		ExtTxHash:          o.ExtTxHash,
+		CreditedTo:         o.CreditedTo,
+		CreditedToSuspense: o.CreditedToSuspense,
 	}
 	copy(newOrder.RawData, o.RawData)
 	copy(newOrder.SignedTx, o.SignedTx)
@@ -267,10 +284,12 @@ func (o *OrderCollect) String() string {
         RawData:%x
 		SignedTx:%x
         DepositStatus:%x
-        ExtTxHash:%v`,
+        ExtTxHash:%v
+        CreditedTo:%v
+        CreditedToSuspense:%v`,
 		o.CollectFromCU, o.CollectFromAddress, o.CollectToCU, o.Amount,
 		o.GasPrice, o.GasLimit, o.Txhash, o.Index, o.RawData, o.SignedTx,
-		o.DepositStatus, o.ExtTxHash))
+		o.DepositStatus, o.ExtTxHash, o.CreditedTo, o.CreditedToSuspense))
 	return build.String()
 }
 
@@ -305,6 +324,8 @@ func (o *OrderCollect) MarshalYAML() (interface{}, error) {
 		SignedTx           string
 		ExtTxHash          string
 		DepositStatus      uint16
+		CreditedTo         CUAddress
+		CreditedToSuspense bool
 	}{
 
 		CUAddress:          o.CUAddress,
@@ -325,6 +346,8 @@ func (o *OrderCollect) MarshalYAML() (interface{}, error) {
 		SignedTx:           signedTx,
 		DepositStatus:      o.DepositStatus,
 		ExtTxHash:          o.ExtTxHash,
+		CreditedTo:         o.CreditedTo,
+		CreditedToSuspense: o.CreditedToSuspense,
 	})
 	if err != nil {
 		return nil, err
@@ -349,6 +372,14 @@ type OrderWithdrawal struct {
 	RawData           []byte         `json:"raw_data"`
 	SignedTx          []byte         `json:"signed_tx"`
 	WithdrawStatus    WithdrawStatus `json:"withdraw_status"`
+	// BumpedFee is the extra cost fee spent by fee bumps, only valid when ReplacedTxHashes is not empty
+	BumpedFee Int `json:"bumped_fee"`
+	// ReplacedTxHashes are the external tx hashes replaced by fee bumps, in bumping order
+	ReplacedTxHashes []string `json:"replaced_tx_hashes"`
+	// BatchID is the id of the batch withdrawal the order is an output of, empty for a single withdrawal
+	BatchID string `json:"batch_id"`
+	// ReplacedSignedTxs are the signed txs of ReplacedTxHashes, a replaced tx may still be packed
+	ReplacedSignedTxs [][]byte `json:"replaced_signed_txs"`
 }
 
 func (o *OrderWithdrawal) GetRawdata() []byte {
@@ -358,6 +389,24 @@ func (o *OrderWithdrawal) GetSignedTx() []byte {
 	return o.SignedTx
 }
 
+// GetReplacedSignedTx returns the signed tx of a tx hash replaced by fee bumps, nil if txHash is not replaced
+func (o *OrderWithdrawal) GetReplacedSignedTx(txHash string) []byte {
+	for i, hash := range o.ReplacedTxHashes {
+		if hash == txHash && i < len(o.ReplacedSignedTxs) {
+			return o.ReplacedSignedTxs[i]
+		}
+	}
+	return nil
+}
+
+// GetBumpedFee returns the extra cost fee spent by fee bumps, zero if the order has never been bumped
+func (o *OrderWithdrawal) GetBumpedFee() Int {
+	if len(o.ReplacedTxHashes) == 0 {
+		return ZeroInt()
+	}
+	return o.BumpedFee
+}
+
 // DeepCopy OrderWithdrawal
 func (o *OrderWithdrawal) DeepCopy() Order {
 	ob := o.OrderBase.DeepCopy().(*OrderBase)
@@ -373,9 +422,22 @@ func (o *OrderWithdrawal) DeepCopy() Order {
 		UtxoInNum:         o.UtxoInNum,
 		RawData:           make([]byte, len(o.RawData)),
 		SignedTx:          make([]byte, len(o.SignedTx)),
+		BumpedFee:         o.BumpedFee,
+		BatchID:           o.BatchID,
 	}
 	copy(newOrder.RawData, o.RawData)
 	copy(newOrder.SignedTx, o.SignedTx)
+	if o.ReplacedTxHashes != nil {
+		newOrder.ReplacedTxHashes = make([]string, len(o.ReplacedTxHashes))
+		copy(newOrder.ReplacedTxHashes, o.ReplacedTxHashes)
+	}
+	if o.ReplacedSignedTxs != nil {
+		newOrder.ReplacedSignedTxs = make([][]byte, len(o.ReplacedSignedTxs))
+		for i, signedTx := range o.ReplacedSignedTxs {
+			newOrder.ReplacedSignedTxs[i] = make([]byte, len(signedTx))
+			copy(newOrder.ReplacedSignedTxs[i], signedTx)
+		}
+	}
 	return newOrder
 }
 
@@ -391,9 +453,12 @@ func (o *OrderWithdrawal) String() string {
         TxHash:%v
         UtxoInNum:%v
         RawData:%x
-		SignedTx:%x`,
+		SignedTx:%x
+        BumpedFee:%v
+        ReplacedTxHashes:%v
+        BatchID:%v`,
 		o.Amount, o.GasFee, o.CostFee, o.WithdrawToAddress, o.FromAddress, o.Txhash, o.UtxoInNum,
-		hex.EncodeToString(o.RawData), hex.EncodeToString(o.SignedTx)))
+		hex.EncodeToString(o.RawData), hex.EncodeToString(o.SignedTx), o.GetBumpedFee(), o.ReplacedTxHashes, o.BatchID))
 	return build.String()
 }
 
@@ -425,6 +490,9 @@ func (o *OrderWithdrawal) MarshalYAML() (interface{}, error) {
 		UtxoInNum         int
 		RawData           string
 		SignedTx          string
+		BumpedFee         Int
+		ReplacedTxHashes  []string
+		BatchID           string
 	}{
 
 		CUAddress:         o.CUAddress,
@@ -442,6 +510,9 @@ func (o *OrderWithdrawal) MarshalYAML() (interface{}, error) {
 		UtxoInNum:         o.UtxoInNum,
 		RawData:           rawData,
 		SignedTx:          signedTx,
+		BumpedFee:         o.GetBumpedFee(),
+		ReplacedTxHashes:  o.ReplacedTxHashes,
+		BatchID:           o.BatchID,
 	})
 	if err != nil {
 		return nil, err
@@ -571,12 +642,21 @@ type OrderOpcuAssetTransfer struct {
 	SignedTx       []byte         `json:"signed_tx"`
 	Txhash         string         `json:"tx_hash"`
 	CostFee        Int            `json:"cost_fee"`
+	// ToCUAddress is the OPCU receiving a rebalancing between the hot and cold tiers, empty for a migration
+	ToCUAddress CUAddress `json:"to_cu_address"`
+	// FromEpoch is the epoch of the address a rebalancing is sent from, 0 for a migration
+	FromEpoch uint64 `json:"from_epoch"`
 }
 
 func (o *OrderOpcuAssetTransfer) GetRawdata() []byte {
 	return o.RawData
 }
 
+// IsRebalance returns whether the order moves assets between two OPCUs rather than migrating an OPCU's assets
+func (o *OrderOpcuAssetTransfer) IsRebalance() bool {
+	return !o.ToCUAddress.Empty()
+}
+
 func (o *OrderOpcuAssetTransfer) GetSignedTx() []byte {
 	return o.SignedTx
 }
@@ -596,6 +676,8 @@ func (o *OrderOpcuAssetTransfer) DeepCopy() Order {
 		RawData:        make([]byte, len(o.RawData)),
 		SignedTx:       make([]byte, len(o.SignedTx)),
 		Txhash:         o.Txhash,
+		ToCUAddress:    o.ToCUAddress,
+		FromEpoch:      o.FromEpoch,
 	}
 	copy(newOrder.RawData, o.RawData)
 	copy(newOrder.SignedTx, o.SignedTx)
@@ -612,9 +694,11 @@ func (o *OrderOpcuAssetTransfer) String() string {
         ToAddress:%v
         RawData:%x
 		SignedTx:%x
-        Txhash:%x`,
+        Txhash:%x
+        ToCUAddress:%v
+        FromEpoch:%v`,
 		o.TransfertItems, o.CostFee, o.ToAddr,
-		hex.EncodeToString(o.RawData), hex.EncodeToString(o.SignedTx), o.Txhash))
+		hex.EncodeToString(o.RawData), hex.EncodeToString(o.SignedTx), o.Txhash, o.ToCUAddress, o.FromEpoch))
 	return build.String()
 }
 
@@ -642,6 +726,8 @@ func (o *OrderOpcuAssetTransfer) MarshalYAML() (interface{}, error) {
 		RawData       string
 		SignedTx      string
 		Txhash        string
+		ToCUAddress   CUAddress
+		FromEpoch     uint64
 	}{
 
 		CUAddress:     o.CUAddress,
@@ -655,6 +741,116 @@ func (o *OrderOpcuAssetTransfer) MarshalYAML() (interface{}, error) {
 		RawData:       rawData,
 		SignedTx:      signedTx,
 		Txhash:        o.Txhash,
+		ToCUAddress:   o.ToCUAddress,
+		FromEpoch:     o.FromEpoch,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return string(orderStr), err
+}
+
+var _ Order = (*OrderUtxoConsolidation)(nil)
+
+// OrderUtxoConsolidation is a self transfer scheduled by chain to merge the utxos of an OPCU
+type OrderUtxoConsolidation struct {
+	OrderBase
+	ConsolidateItems []TransferItem `json:"consolidate_items"`
+	Address          string         `json:"address"`
+	RawData          []byte         `json:"raw_data"`
+	SignedTx         []byte         `json:"signed_tx"`
+	Txhash           string         `json:"tx_hash"`
+	CostFee          Int            `json:"cost_fee"`
+	// StageHeight is the height the order entered its current status, the order height if 0
+	StageHeight uint64 `json:"stage_height"`
+}
+
+func (o *OrderUtxoConsolidation) GetRawdata() []byte {
+	return o.RawData
+}
+
+func (o *OrderUtxoConsolidation) GetSignedTx() []byte {
+	return o.SignedTx
+}
+
+func (o *OrderUtxoConsolidation) GetTxHash() string {
+	return o.Txhash
+}
+
+// DeepCopy OrderUtxoConsolidation
+func (o *OrderUtxoConsolidation) DeepCopy() Order {
+	ob := o.OrderBase.DeepCopy().(*OrderBase)
+	newOrder := &OrderUtxoConsolidation{
+		OrderBase:        *ob,
+		ConsolidateItems: make([]TransferItem, len(o.ConsolidateItems)),
+		Address:          o.Address,
+		RawData:          make([]byte, len(o.RawData)),
+		SignedTx:         make([]byte, len(o.SignedTx)),
+		Txhash:           o.Txhash,
+		CostFee:          o.CostFee,
+		StageHeight:      o.StageHeight,
+	}
+	copy(newOrder.RawData, o.RawData)
+	copy(newOrder.SignedTx, o.SignedTx)
+	copy(newOrder.ConsolidateItems, o.ConsolidateItems)
+	return newOrder
+}
+
+func (o *OrderUtxoConsolidation) String() string {
+	var build strings.Builder
+	build.WriteString(o.OrderBase.String())
+	build.WriteString(fmt.Sprintf(`
+		ConsolidateItems:%x
+        CostFee:%v
+        Address:%v
+        RawData:%x
+		SignedTx:%x
+        Txhash:%x
+        StageHeight:%v`,
+		o.ConsolidateItems, o.CostFee, o.Address,
+		hex.EncodeToString(o.RawData), hex.EncodeToString(o.SignedTx), o.Txhash, o.StageHeight))
+	return build.String()
+}
+
+// MarshalYAML returns the YAML representation of an utxo consolidation order.
+func (o *OrderUtxoConsolidation) MarshalYAML() (interface{}, error) {
+	var orderStr []byte
+	var err error
+	var rawData string
+	var signedTx string
+	if o.RawData != nil {
+		rawData = hex.EncodeToString(o.RawData)
+	}
+	if o.SignedTx != nil {
+		signedTx = hex.EncodeToString(o.SignedTx)
+	}
+	orderStr, err = yaml.Marshal(struct {
+		CUAddress        CUAddress
+		ID               string
+		OrderType        OrderType
+		Symbol           string
+		Status           OrderStatus
+		ConsolidateItems []TransferItem
+		CostFee          Int
+		Address          string
+		RawData          string
+		SignedTx         string
+		Txhash           string
+		StageHeight      uint64
+	}{
+		CUAddress:        o.CUAddress,
+		ID:               o.ID,
+		OrderType:        o.OrderType,
+		Symbol:           o.Symbol,
+		Status:           o.Status,
+		ConsolidateItems: o.ConsolidateItems,
+		CostFee:          o.CostFee,
+		Address:          o.Address,
+		RawData:          rawData,
+		SignedTx:         signedTx,
+		Txhash:           o.Txhash,
+		StageHeight:      o.StageHeight,
 	})
 	if err != nil {
 		return nil, err
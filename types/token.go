@@ -13,23 +13,31 @@ const (
 	NativeDefiTokenDecimal = 8
 
 	GasPriceBucketWindow uint64 = 10
+
+	// MinKeyRotationInterval is the minimum non-zero key rotation interval of a chain, in blocks
+	MinKeyRotationInterval uint64 = 1000
+	// MaxKeyNodeThreshold is the maximum key node threshold of a chain, in percent of the key nodes
+	MaxKeyNodeThreshold uint64 = 100
 )
 
 var (
-	KeySendEnabled        = "send_enabled"
-	KeyDepositEnabled     = "deposit_enabled"
-	KeyWithdrawalEnabled  = "withdrawal_enabled"
-	KeyCollectThreshold   = "collect_threshold"
-	KeyDepositThreshold   = "deposit_threshold"
-	KeyOpenFee            = "open_fee"
-	KeySysOpenFee         = "sys_open_fee"
-	KeyWithdrawalFeeRate  = "withdrawal_fee_rate"
-	KeyMaxOpCUNumber      = "max_op_cu_number"
-	KeySysTransferNum     = "systransfer_num"
-	KeyOpCUSysTransferNum = "op_cu_systransfer_num"
-	KeyGasLimit           = "gas_limit"
-	KeyConfirmations      = "confirmations"
-	KeyNeedCollectFee     = "need_collect_fee"
+	KeySendEnabled         = "send_enabled"
+	KeyDepositEnabled      = "deposit_enabled"
+	KeyWithdrawalEnabled   = "withdrawal_enabled"
+	KeyCollectThreshold    = "collect_threshold"
+	KeyDepositThreshold    = "deposit_threshold"
+	KeyOpenFee             = "open_fee"
+	KeySysOpenFee          = "sys_open_fee"
+	KeyWithdrawalFeeRate   = "withdrawal_fee_rate"
+	KeyMaxOpCUNumber       = "max_op_cu_number"
+	KeySysTransferNum      = "systransfer_num"
+	KeyOpCUSysTransferNum  = "op_cu_systransfer_num"
+	KeyGasLimit            = "gas_limit"
+	KeyConfirmations       = "confirmations"
+	KeyNeedCollectFee      = "need_collect_fee"
+	KeyKeyRotationInterval = "key_rotation_interval"
+	KeyKeyNodeThreshold    = "key_node_threshold"
+	KeyOpCUTiers           = "op_cu_tiers"
 )
 
 var (
@@ -176,6 +184,17 @@ type IBCToken struct {
 	Confirmations      uint64    `json:"confirmations" yaml:"confirmations"` //confirmation of chain
 	IsNonceBased       bool      `json:"is_nonce_based" yaml:"is_nonce_based"`
 	NeedCollectFee     bool      `json:"need_collect_fee" yaml:"need_collect_fee"`
+	// KeyRotationInterval is the number of blocks after which the OPCU keys of the chain are rotated,
+	// 0 disables the rotation. It is set on the chain's main token only, and is the same for all chains
+	// rotating their keys, as the keys of all chains are rotated together.
+	KeyRotationInterval uint64 `json:"key_rotation_interval" yaml:"key_rotation_interval"`
+	// KeyNodeThreshold is the percentage of the key nodes required to sign for the keys of the chain,
+	// 0 defaults to the 2/3 majority. It is set on the chain's main token only. It applies to the keys
+	// generated afterwards, the existing keys of the chain are rotated once it is raised above theirs,
+	// until then the cold OPCUs of the chain do not sign.
+	KeyNodeThreshold uint64 `json:"key_node_threshold" yaml:"key_node_threshold"`
+	// OpCUTiers splits the OPCUs of the token into a hot and a cold tier, nil if all OPCUs are hot
+	OpCUTiers *OpCUTierParams `json:"op_cu_tiers" yaml:"op_cu_tiers"`
 }
 
 func (t *IBCToken) String() string {
@@ -204,10 +223,90 @@ func (t *IBCToken) String() string {
 	Confirmations:%v
 	IsNonceBased:%v
 	NeedCollectFee:%v
+	KeyRotationInterval:%v
+	KeyNodeThreshold:%v
+	OpCUTiers:%v
 	`, t.Name, t.Symbol, t.Issuer, t.Chain, t.TokenType, t.SendEnabled, t.DepositEnabled,
 		t.WithdrawalEnabled, t.Decimals, t.TotalSupply, t.Weight, t.CollectThreshold, t.DepositThreshold,
 		t.OpenFee, t.SysOpenFee, t.WithdrawalFeeRate, t.MaxOpCUNumber, t.SysTransferNum,
-		t.OpCUSysTransferNum, t.GasLimit, t.GasPrice, t.Confirmations, t.IsNonceBased, t.NeedCollectFee)
+		t.OpCUSysTransferNum, t.GasLimit, t.GasPrice, t.Confirmations, t.IsNonceBased, t.NeedCollectFee, t.KeyRotationInterval,
+		t.KeyNodeThreshold, t.OpCUTiers)
+}
+
+// GetKeyNodeThreshold returns the number of key nodes out of keyNodeNum required to sign, which is never below the 2/3 majority
+func (t *IBCToken) GetKeyNodeThreshold(keyNodeNum int) int {
+	threshold := Majority23(keyNodeNum)
+	if n := (keyNodeNum*int(t.KeyNodeThreshold) + 99) / 100; n > threshold {
+		threshold = n
+	}
+	return threshold
+}
+
+// IsColdOpCU returns whether the OPCU is in the cold tier of the token
+func (t *IBCToken) IsColdOpCU(opCUAddr CUAddress) bool {
+	return t.OpCUTiers != nil && t.OpCUTiers.IsColdOpCU(opCUAddr)
+}
+
+// OpCUTierParams are the hot/cold tier params of a token's OPCUs.
+// Cold OPCUs only take part in withdrawals of at least ColdWithdrawalThreshold and in rebalancing,
+// funds are moved between the tiers once the balance of a hot OPCU crosses the watermarks.
+type OpCUTierParams struct {
+	ColdOpCUs []CUAddress `json:"cold_op_cus" yaml:"cold_op_cus"`
+	// ColdWithdrawalThreshold is the minimum withdrawal amount of cold OPCUs, 0 if they do not take part in withdrawals
+	ColdWithdrawalThreshold Int `json:"cold_withdrawal_threshold" yaml:"cold_withdrawal_threshold"`
+	// HotHighWatermark is the balance of a hot OPCU above which funds are moved to the cold tier, 0 disables it
+	HotHighWatermark Int `json:"hot_high_watermark" yaml:"hot_high_watermark"`
+	// HotLowWatermark is the balance of a hot OPCU below which funds are moved from the cold tier, 0 disables it
+	HotLowWatermark Int `json:"hot_low_watermark" yaml:"hot_low_watermark"`
+}
+
+func (p *OpCUTierParams) String() string {
+	return fmt.Sprintf(`{ColdOpCUs:%v ColdWithdrawalThreshold:%v HotHighWatermark:%v HotLowWatermark:%v}`,
+		p.ColdOpCUs, p.ColdWithdrawalThreshold, p.HotHighWatermark, p.HotLowWatermark)
+}
+
+func (p *OpCUTierParams) IsValid() bool {
+	if p.ColdWithdrawalThreshold.i == nil || p.HotHighWatermark.i == nil || p.HotLowWatermark.i == nil {
+		return false
+	}
+	if p.ColdWithdrawalThreshold.IsNegative() || p.HotHighWatermark.IsNegative() || p.HotLowWatermark.IsNegative() {
+		return false
+	}
+	if p.HotHighWatermark.IsPositive() && p.HotLowWatermark.GT(p.HotHighWatermark) {
+		return false
+	}
+	seen := make(map[string]bool)
+	for _, addr := range p.ColdOpCUs {
+		if addr.Empty() || seen[addr.String()] {
+			return false
+		}
+		seen[addr.String()] = true
+	}
+	return true
+}
+
+// IsColdOpCU returns whether the OPCU is in the cold tier
+func (p *OpCUTierParams) IsColdOpCU(opCUAddr CUAddress) bool {
+	for _, addr := range p.ColdOpCUs {
+		if addr.Equals(opCUAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanWithdraw returns whether a cold OPCU may take part in a withdrawal of amount
+func (p *OpCUTierParams) CanWithdraw(amount Int) bool {
+	return p.ColdWithdrawalThreshold.IsPositive() && amount.GTE(p.ColdWithdrawalThreshold)
+}
+
+// RebalanceTarget returns the balance a hot OPCU is rebalanced to, which is the middle of the watermarks,
+// or the low watermark if the high one is not set
+func (p *OpCUTierParams) RebalanceTarget() Int {
+	if p.HotHighWatermark.IsZero() {
+		return p.HotLowWatermark
+	}
+	return p.HotLowWatermark.Add(p.HotHighWatermark).QuoRaw(2)
 }
 
 func (t *IBCToken) IsValid() bool {
@@ -257,6 +356,19 @@ func (t *IBCToken) WithdrawalFee() Coin {
 	return NewCoin(t.Chain.String(), withdrawalFeeAmt)
 }
 
+// BatchWithdrawalFee returns the withdrawal fee of a batch with outputNum outputs. Outputs of UTXO based token
+// share one transaction, while those of other tokens are sent in one transaction each.
+func (t *IBCToken) BatchWithdrawalFee(outputNum int) Coin {
+	var baseGasFee Int
+	if t.TokenType == UtxoBased {
+		baseGasFee = EstimateSignedUtxoTxSize(1, outputNum+1).Mul(t.GasPrice).QuoRaw(KiloBytes)
+	} else {
+		baseGasFee = t.GasPrice.Mul(t.GasLimit).MulRaw(int64(outputNum))
+	}
+	withdrawalFeeAmt := t.WithdrawalFeeRate.Mul(NewDecFromInt(baseGasFee)).TruncateInt()
+	return NewCoin(t.Chain.String(), withdrawalFeeAmt)
+}
+
 func (t *IBCToken) CollectFee() Coin {
 	if !t.NeedCollectFee {
 		return NewCoin(t.Chain.String(), ZeroInt())
@@ -276,6 +388,17 @@ func (t *IBCToken) CollectFee() Coin {
 	}
 }
 
+// DustCollectFee returns the collect fee charged for a deposit below the deposit threshold, which is
+// the collect fee in proportion to amount over the deposit threshold.
+func (t *IBCToken) DustCollectFee(amount Int) Coin {
+	fee := t.CollectFee()
+	if !t.DepositThreshold.IsPositive() || amount.GTE(t.DepositThreshold) {
+		return fee
+	}
+	fee.Amount = fee.Amount.Mul(amount).Quo(t.DepositThreshold)
+	return fee
+}
+
 type TokensGasPrice struct {
 	Chain    string `json:"chain" yaml:"chain"`
 	GasPrice Int    `json:"gas_price" yaml:"gas_price"`
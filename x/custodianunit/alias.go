@@ -25,28 +25,31 @@ const (
 
 var (
 	// functions aliases
-	NewBaseCU             = types.NewBaseCU
-	ProtoBaseCU           = types.ProtoBaseCU
-	NewBaseCUWithAddress  = types.NewBaseCUWithAddress
-	RegisterCodec         = types.RegisterCodec
-	NewGenesisState       = types.NewGenesisState
-	DefaultGenesisState   = types.DefaultGenesisState
-	ValidateGenesis       = types.ValidateGenesis
-	AddressStoreKey       = types.AddressStoreKey
-	NewParams             = types.NewParams
-	ParamKeyTable         = types.ParamKeyTable
-	DefaultParams         = types.DefaultParams
-	NewQueryAccountParams = types.NewQueryCUParams
-	NewStdTx              = types.NewStdTx
-	CountSubKeys          = types.CountSubKeys
-	NewStdFee             = types.NewStdFee
-	StdSignBytes          = types.StdSignBytes
-	DefaultTxDecoder      = types.DefaultTxDecoder
-	DefaultTxEncoder      = types.DefaultTxEncoder
-	NewTxBuilder          = types.NewTxBuilder
-	NewTxBuilderFromCLI   = types.NewTxBuilderFromCLI
-	MakeSignature         = types.MakeSignature
-	NewAccountRetriever   = types.NewCURetriever
+	NewBaseCU              = types.NewBaseCU
+	ProtoBaseCU            = types.ProtoBaseCU
+	NewBaseCUWithAddress   = types.NewBaseCUWithAddress
+	NewContinuousVestingCU = types.NewContinuousVestingCU
+	NewDelayedVestingCU    = types.NewDelayedVestingCU
+	NewPeriodicVestingCU   = types.NewPeriodicVestingCU
+	RegisterCodec          = types.RegisterCodec
+	NewGenesisState        = types.NewGenesisState
+	DefaultGenesisState    = types.DefaultGenesisState
+	ValidateGenesis        = types.ValidateGenesis
+	AddressStoreKey        = types.AddressStoreKey
+	NewParams              = types.NewParams
+	ParamKeyTable          = types.ParamKeyTable
+	DefaultParams          = types.DefaultParams
+	NewQueryAccountParams  = types.NewQueryCUParams
+	NewStdTx               = types.NewStdTx
+	CountSubKeys           = types.CountSubKeys
+	NewStdFee              = types.NewStdFee
+	StdSignBytes           = types.StdSignBytes
+	DefaultTxDecoder       = types.DefaultTxDecoder
+	DefaultTxEncoder       = types.DefaultTxEncoder
+	NewTxBuilder           = types.NewTxBuilder
+	NewTxBuilderFromCLI    = types.NewTxBuilderFromCLI
+	MakeSignature          = types.MakeSignature
+	NewAccountRetriever    = types.NewCURetriever
 
 	// variable aliases
 	ModuleCdc                 = types.ModuleCdc
@@ -62,16 +65,22 @@ var (
 )
 
 type (
-	CU                 = exported.CustodianUnit
-	VestingAccount     = exported.VestingCU
-	BaseCU             = types.BaseCU
-	GenesisState       = types.GenesisState
-	Params             = types.Params
-	QueryAccountParams = types.QueryCUParams
-	StdSignMsg         = types.StdSignMsg
-	StdTx              = types.StdTx
-	StdFee             = types.StdFee
-	StdSignDoc         = types.StdSignDoc
-	StdSignature       = types.StdSignature
-	TxBuilder          = types.TxBuilder
+	CU                  = exported.CustodianUnit
+	VestingAccount      = exported.VestingCU
+	BaseCU              = types.BaseCU
+	BaseVestingCU       = types.BaseVestingCU
+	ContinuousVestingCU = types.ContinuousVestingCU
+	DelayedVestingCU    = types.DelayedVestingCU
+	PeriodicVestingCU   = types.PeriodicVestingCU
+	VestingPeriod       = types.VestingPeriod
+	VestingPeriods      = types.VestingPeriods
+	GenesisState        = types.GenesisState
+	Params              = types.Params
+	QueryAccountParams  = types.QueryCUParams
+	StdSignMsg          = types.StdSignMsg
+	StdTx               = types.StdTx
+	StdFee              = types.StdFee
+	StdSignDoc          = types.StdSignDoc
+	StdSignature        = types.StdSignature
+	TxBuilder           = types.TxBuilder
 )
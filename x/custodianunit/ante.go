@@ -39,7 +39,8 @@ type SignatureVerificationGasConsumer = func(meter sdk.GasMeter, sig []byte, pub
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
 // numbers, checks signatures & CU numbers, and deducts fees from the first
 // signer.
-func NewAnteHandler(ck CUKeeper, supplyKeeper internal.SupplyKeeper, stakingKeeper internal.StakingKeeper, sigGasConsumer SignatureVerificationGasConsumer) sdk.AnteHandler {
+func NewAnteHandler(ck CUKeeper, supplyKeeper internal.SupplyKeeper, stakingKeeper internal.StakingKeeper,
+	frozenKeeper internal.FrozenAddressKeeper, sigGasConsumer SignatureVerificationGasConsumer) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx sdk.Tx, simulate bool,
 	) (newCtx sdk.Context, res sdk.Result, abort bool) {
@@ -120,6 +121,14 @@ func NewAnteHandler(ck CUKeeper, supplyKeeper internal.SupplyKeeper, stakingKeep
 			return newCtx, res, true
 		}
 
+		// Check the signers of spending msgs are not frozen
+		if frozenKeeper != nil {
+			res = CheckFrozenCU(newCtx, frozenKeeper, stdTx.GetMsgs())
+			if !res.IsOK() {
+				return newCtx, res, true
+			}
+		}
+
 		// fetch first signer, who's going to pay the fees
 		signerAccs[0] = ck.GetOrNewCU(ctx, sdk.CUTypeUser, signerAddrs[0])
 
@@ -212,6 +221,22 @@ func CheckCUType(ctx sdk.Context, ck CUKeeper, signers []sdk.CUAddress) sdk.Resu
 	return sdk.Result{}
 }
 
+// CheckFrozenCU rejects the msgs restricted for frozen CUs if any of their signers is frozen by governance
+func CheckFrozenCU(ctx sdk.Context, fk internal.FrozenAddressKeeper, msgs []sdk.Msg) sdk.Result {
+	for _, msg := range msgs {
+		if !fk.IsFrozenRestrictedMsg(msg) {
+			continue
+		}
+		for _, s := range msg.GetSigners() {
+			if fk.IsAddressFrozen(ctx, s) {
+				return sdk.ErrUnauthorized(fmt.Sprintf("frozen CU %s can not sign %s message", s, msg.Type())).Result()
+			}
+		}
+	}
+
+	return sdk.Result{}
+}
+
 // ValidateSigCount validates that the transaction has a valid cumulative total
 // amount of signatures.
 func ValidateSigCount(stdTx StdTx, params Params) sdk.Result {
@@ -47,6 +47,8 @@ type VestingCU interface {
 
 	GetVestedCoins(blockTime time.Time) sdk.Coins
 	GetVestingCoins(blockTime time.Time) sdk.Coins
+	// LockedCoins returns the vesting coins which are not delegated, they can not be spent
+	LockedCoins(blockTime time.Time) sdk.Coins
 
 	GetStartTime() int64
 	GetEndTime() int64
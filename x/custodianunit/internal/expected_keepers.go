@@ -17,6 +17,12 @@ type StakingKeeper interface {
 	IsActiveKeyNode(ctx sdk.Context, addr sdk.CUAddress) (bool, int)
 }
 
+// FrozenAddressKeeper defines the expected keeper of the addresses frozen by governance (noalias)
+type FrozenAddressKeeper interface {
+	IsAddressFrozen(ctx sdk.Context, addr sdk.CUAddress) bool
+	IsFrozenRestrictedMsg(msg sdk.Msg) bool
+}
+
 type TransferKeeper interface {
 	SendCoins(ctx sdk.Context, from, to sdk.CUAddress, amt sdk.Coins) (sdk.Result, []sdk.Flow, sdk.Error)
 }
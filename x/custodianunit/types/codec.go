@@ -11,6 +11,10 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(&BaseCU{}, "hbtcchain/CustodianUnit", nil)
 	cdc.RegisterConcrete(&BaseCUs{}, "hbtcchain/CustodianUnits", nil)
 	cdc.RegisterInterface((*exported.VestingCU)(nil), nil)
+	cdc.RegisterConcrete(&BaseVestingCU{}, "hbtcchain/BaseVestingCU", nil)
+	cdc.RegisterConcrete(&ContinuousVestingCU{}, "hbtcchain/ContinuousVestingCU", nil)
+	cdc.RegisterConcrete(&DelayedVestingCU{}, "hbtcchain/DelayedVestingCU", nil)
+	cdc.RegisterConcrete(&PeriodicVestingCU{}, "hbtcchain/PeriodicVestingCU", nil)
 	cdc.RegisterConcrete(StdTx{}, "hbtcchain/StdTx", nil)
 }
 
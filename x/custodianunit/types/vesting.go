@@ -0,0 +1,481 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit/exported"
+)
+
+// Compile-time type assertions
+var (
+	_ exported.VestingCU = (*ContinuousVestingCU)(nil)
+	_ exported.VestingCU = (*DelayedVestingCU)(nil)
+	_ exported.VestingCU = (*PeriodicVestingCU)(nil)
+)
+
+// BaseVestingCU implements the delegation tracking shared by all vesting CUs.
+// The balances of a CU are kept by the transfer keeper, a vesting CU only tracks how much of them are locked.
+type BaseVestingCU struct {
+	*BaseCU
+
+	OriginalVesting  sdk.Coins `json:"original_vesting" yaml:"original_vesting"`   // coins in the CU upon initialization
+	DelegatedFree    sdk.Coins `json:"delegated_free" yaml:"delegated_free"`       // coins that are vested and delegated
+	DelegatedVesting sdk.Coins `json:"delegated_vesting" yaml:"delegated_vesting"` // coins that vesting and delegated
+	EndTime          int64     `json:"end_time" yaml:"end_time"`                   // when the coins become unlocked
+}
+
+// NewBaseVestingCU creates a new BaseVestingCU object
+func NewBaseVestingCU(bcu *BaseCU, originalVesting sdk.Coins, endTime int64) *BaseVestingCU {
+	return &BaseVestingCU{
+		BaseCU:           bcu,
+		OriginalVesting:  originalVesting,
+		DelegatedFree:    sdk.NewCoins(),
+		DelegatedVesting: sdk.NewCoins(),
+		EndTime:          endTime,
+	}
+}
+
+// lockedCoins returns the part of vestingCoins which is neither vested nor delegated
+func (bva BaseVestingCU) lockedCoins(vestingCoins sdk.Coins) sdk.Coins {
+	var locked sdk.Coins
+	for _, coin := range vestingCoins {
+		amt := coin.Amount.Sub(bva.DelegatedVesting.AmountOf(coin.Denom))
+		if amt.IsPositive() {
+			locked = append(locked, sdk.NewCoin(coin.Denom, amt))
+		}
+	}
+	return locked
+}
+
+// trackDelegation tracks a delegation amount for any given vesting CU type
+// given the amount of coins currently vesting.
+//
+// CONTRACT: The CU's balance must cover amount.
+func (bva *BaseVestingCU) trackDelegation(vestingCoins, amount sdk.Coins) {
+	for _, coin := range amount {
+		vestingAmt := vestingCoins.AmountOf(coin.Denom)
+		delVestingAmt := bva.DelegatedVesting.AmountOf(coin.Denom)
+
+		// compute x and y per the specification, where:
+		// X := min(max(V - DV, 0), D)
+		// Y := D - X
+		x := sdk.MinInt(sdk.MaxInt(vestingAmt.Sub(delVestingAmt), sdk.ZeroInt()), coin.Amount)
+		y := coin.Amount.Sub(x)
+
+		if !x.IsZero() {
+			bva.DelegatedVesting = bva.DelegatedVesting.Add(sdk.NewCoins(sdk.NewCoin(coin.Denom, x)))
+		}
+		if !y.IsZero() {
+			bva.DelegatedFree = bva.DelegatedFree.Add(sdk.NewCoins(sdk.NewCoin(coin.Denom, y)))
+		}
+	}
+}
+
+// TrackUndelegation tracks an undelegation amount by setting the necessary
+// values by which delegated vesting and delegated vesting need to decrease and
+// by which amount the base coins need to increase. The resulting base coins are
+// returned.
+//
+// NOTE: The undelegation (bond refund) amount may exceed the delegated
+// vesting (bond) amount due to the way undelegation truncates the bond refund,
+// which can increase the validator's exchange rate (tokens/shares) slightly if
+// the undelegated tokens are non-integral.
+func (bva *BaseVestingCU) TrackUndelegation(amount sdk.Coins) {
+	for _, coin := range amount {
+		delegatedFree := bva.DelegatedFree.AmountOf(coin.Denom)
+		delegatedVesting := bva.DelegatedVesting.AmountOf(coin.Denom)
+
+		// compute x and y per the specification, where:
+		// X := min(DF, D)
+		// Y := min(DV, D - X)
+		x := sdk.MinInt(delegatedFree, coin.Amount)
+		y := sdk.MinInt(delegatedVesting, coin.Amount.Sub(x))
+
+		if !x.IsZero() {
+			bva.DelegatedFree = bva.DelegatedFree.Sub(sdk.NewCoins(sdk.NewCoin(coin.Denom, x)))
+		}
+		if !y.IsZero() {
+			bva.DelegatedVesting = bva.DelegatedVesting.Sub(sdk.NewCoins(sdk.NewCoin(coin.Denom, y)))
+		}
+	}
+}
+
+// GetOriginalVesting returns a vesting CU's original vesting amount
+func (bva BaseVestingCU) GetOriginalVesting() sdk.Coins {
+	return bva.OriginalVesting
+}
+
+// GetDelegatedFree returns a vesting CU's delegation amount that is not
+// vesting.
+func (bva BaseVestingCU) GetDelegatedFree() sdk.Coins {
+	return bva.DelegatedFree
+}
+
+// GetDelegatedVesting returns a vesting CU's delegation amount that is
+// still vesting.
+func (bva BaseVestingCU) GetDelegatedVesting() sdk.Coins {
+	return bva.DelegatedVesting
+}
+
+// GetEndTime returns a vesting CU's end time
+func (bva BaseVestingCU) GetEndTime() int64 {
+	return bva.EndTime
+}
+
+// Validate checks for errors on the CU fields
+func (bva BaseVestingCU) Validate() error {
+	if bva.BaseCU == nil {
+		return errors.New("nil base CU")
+	}
+	if bva.GetCUType() != sdk.CUTypeUser {
+		return errors.New("vesting CU must be a user CU")
+	}
+	if !bva.OriginalVesting.IsValid() || bva.OriginalVesting.Empty() {
+		return fmt.Errorf("invalid original vesting %v", bva.OriginalVesting)
+	}
+	return bva.BaseCU.Validate()
+}
+
+func (bva BaseVestingCU) String() string {
+	return fmt.Sprintf(`%s
+  Original Vesting:  %s
+  Delegated Free:    %s
+  Delegated Vesting: %s
+  EndTime:           %d`,
+		bva.BaseCU.String(), bva.OriginalVesting, bva.DelegatedFree, bva.DelegatedVesting, bva.EndTime)
+}
+
+type vestingCUYAML struct {
+	Type             sdk.CUType
+	Address          sdk.CUAddress
+	PubKey           string
+	Sequence         uint64
+	OriginalVesting  sdk.Coins
+	DelegatedFree    sdk.Coins
+	DelegatedVesting sdk.Coins
+	StartTime        int64          `yaml:",omitempty"`
+	EndTime          int64
+	VestingPeriods   VestingPeriods `yaml:",omitempty"`
+}
+
+func (bva BaseVestingCU) marshalYAML(startTime int64, periods VestingPeriods) (interface{}, error) {
+	var pubkey string
+	if bva.PubKey != nil {
+		pubkey = sdk.PubkeyToString(bva.PubKey)
+	}
+
+	bs, err := yaml.Marshal(vestingCUYAML{
+		Type:             bva.Type,
+		Address:          bva.Address,
+		PubKey:           pubkey,
+		Sequence:         bva.Sequence,
+		OriginalVesting:  bva.OriginalVesting,
+		DelegatedFree:    bva.DelegatedFree,
+		DelegatedVesting: bva.DelegatedVesting,
+		StartTime:        startTime,
+		EndTime:          bva.EndTime,
+		VestingPeriods:   periods,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bs), err
+}
+
+//-----------------------------------------------------------------------------
+// Continuous Vesting CU
+
+// ContinuousVestingCU implements the VestingCU interface. It
+// continuously vests by unlocking coins linearly with respect to time.
+type ContinuousVestingCU struct {
+	*BaseVestingCU
+
+	StartTime int64 `json:"start_time" yaml:"start_time"` // when the coins start to vest
+}
+
+// NewContinuousVestingCU returns a new ContinuousVestingCU
+func NewContinuousVestingCU(bcu *BaseCU, originalVesting sdk.Coins, startTime, endTime int64) *ContinuousVestingCU {
+	return &ContinuousVestingCU{
+		BaseVestingCU: NewBaseVestingCU(bcu, originalVesting, endTime),
+		StartTime:     startTime,
+	}
+}
+
+// GetVestedCoins returns the total number of vested coins. If no coins are vested,
+// nil is returned.
+func (cva ContinuousVestingCU) GetVestedCoins(blockTime time.Time) sdk.Coins {
+	var vestedCoins sdk.Coins
+
+	// We must handle the case where the start time for a vesting CU has
+	// been set into the future or when the start of the chain is not exactly
+	// known.
+	if blockTime.Unix() <= cva.StartTime {
+		return vestedCoins
+	} else if blockTime.Unix() >= cva.EndTime {
+		return cva.OriginalVesting
+	}
+
+	// calculate the vesting scalar
+	x := blockTime.Unix() - cva.StartTime
+	y := cva.EndTime - cva.StartTime
+	s := sdk.NewDec(x).Quo(sdk.NewDec(y))
+
+	for _, ovc := range cva.OriginalVesting {
+		vestedAmt := ovc.Amount.ToDec().Mul(s).RoundInt()
+		if vestedAmt.IsPositive() {
+			vestedCoins = append(vestedCoins, sdk.NewCoin(ovc.Denom, vestedAmt))
+		}
+	}
+
+	return vestedCoins
+}
+
+// GetVestingCoins returns the total number of vesting coins. If no coins are
+// vesting, nil is returned.
+func (cva ContinuousVestingCU) GetVestingCoins(blockTime time.Time) sdk.Coins {
+	return cva.OriginalVesting.Sub(cva.GetVestedCoins(blockTime))
+}
+
+// LockedCoins returns the coins of the CU which can not be spent at blockTime
+func (cva ContinuousVestingCU) LockedCoins(blockTime time.Time) sdk.Coins {
+	return cva.lockedCoins(cva.GetVestingCoins(blockTime))
+}
+
+// TrackDelegation tracks a desired delegation amount by setting the appropriate
+// values for the amount of delegated vesting, delegated free, and reducing the
+// overall amount of base coins.
+func (cva *ContinuousVestingCU) TrackDelegation(blockTime time.Time, amount sdk.Coins) {
+	cva.trackDelegation(cva.GetVestingCoins(blockTime), amount)
+}
+
+// GetStartTime returns the time when vesting starts for a continuous vesting
+// CU.
+func (cva ContinuousVestingCU) GetStartTime() int64 {
+	return cva.StartTime
+}
+
+// Validate checks for errors on the CU fields
+func (cva ContinuousVestingCU) Validate() error {
+	if cva.BaseVestingCU == nil {
+		return errors.New("nil base vesting CU")
+	}
+	if cva.StartTime >= cva.EndTime {
+		return errors.New("vesting start-time cannot be after end-time")
+	}
+	return cva.BaseVestingCU.Validate()
+}
+
+// MarshalYAML returns the YAML representation of a ContinuousVestingCU.
+func (cva ContinuousVestingCU) MarshalYAML() (interface{}, error) {
+	return cva.marshalYAML(cva.StartTime, nil)
+}
+
+func (cva ContinuousVestingCU) String() string {
+	return fmt.Sprintf(`%s
+  StartTime:         %d`, cva.BaseVestingCU.String(), cva.StartTime)
+}
+
+//-----------------------------------------------------------------------------
+// Delayed Vesting CU
+
+// DelayedVestingCU implements the VestingCU interface. It vests all
+// coins after a specific time, but non prior. In other words, it keeps them
+// locked until a specified time.
+type DelayedVestingCU struct {
+	*BaseVestingCU
+}
+
+// NewDelayedVestingCU returns a DelayedVestingCU
+func NewDelayedVestingCU(bcu *BaseCU, originalVesting sdk.Coins, endTime int64) *DelayedVestingCU {
+	return &DelayedVestingCU{
+		BaseVestingCU: NewBaseVestingCU(bcu, originalVesting, endTime),
+	}
+}
+
+// GetVestedCoins returns the total amount of vested coins for a delayed vesting
+// CU. All coins are only vested once the schedule has elapsed.
+func (dva DelayedVestingCU) GetVestedCoins(blockTime time.Time) sdk.Coins {
+	if blockTime.Unix() >= dva.EndTime {
+		return dva.OriginalVesting
+	}
+
+	return nil
+}
+
+// GetVestingCoins returns the total number of vesting coins for a delayed
+// vesting CU.
+func (dva DelayedVestingCU) GetVestingCoins(blockTime time.Time) sdk.Coins {
+	return dva.OriginalVesting.Sub(dva.GetVestedCoins(blockTime))
+}
+
+// LockedCoins returns the coins of the CU which can not be spent at blockTime
+func (dva DelayedVestingCU) LockedCoins(blockTime time.Time) sdk.Coins {
+	return dva.lockedCoins(dva.GetVestingCoins(blockTime))
+}
+
+// TrackDelegation tracks a desired delegation amount by setting the appropriate
+// values for the amount of delegated vesting, delegated free, and reducing the
+// overall amount of base coins.
+func (dva *DelayedVestingCU) TrackDelegation(blockTime time.Time, amount sdk.Coins) {
+	dva.trackDelegation(dva.GetVestingCoins(blockTime), amount)
+}
+
+// GetStartTime returns zero since a delayed vesting CU has no start time.
+func (dva DelayedVestingCU) GetStartTime() int64 {
+	return 0
+}
+
+// Validate checks for errors on the CU fields
+func (dva DelayedVestingCU) Validate() error {
+	if dva.BaseVestingCU == nil {
+		return errors.New("nil base vesting CU")
+	}
+	return dva.BaseVestingCU.Validate()
+}
+
+// MarshalYAML returns the YAML representation of a DelayedVestingCU.
+func (dva DelayedVestingCU) MarshalYAML() (interface{}, error) {
+	return dva.marshalYAML(0, nil)
+}
+
+//-----------------------------------------------------------------------------
+// Periodic Vesting CU
+
+// VestingPeriod defines a length of time, in seconds, and the amount of coins vested at its end
+type VestingPeriod struct {
+	Length int64     `json:"length" yaml:"length"`
+	Amount sdk.Coins `json:"amount" yaml:"amount"`
+}
+
+// VestingPeriods are the consecutive periods of a periodic vesting CU
+type VestingPeriods []VestingPeriod
+
+// TotalLength returns the sum of the lengths of the periods
+func (vp VestingPeriods) TotalLength() int64 {
+	var total int64
+	for _, period := range vp {
+		total += period.Length
+	}
+	return total
+}
+
+// TotalAmount returns the sum of the amounts of the periods
+func (vp VestingPeriods) TotalAmount() sdk.Coins {
+	total := sdk.NewCoins()
+	for _, period := range vp {
+		total = total.Add(period.Amount)
+	}
+	return total
+}
+
+// PeriodicVestingCU implements the VestingCU interface. It vests the amount
+// of each period at the end of it.
+type PeriodicVestingCU struct {
+	*BaseVestingCU
+
+	StartTime      int64          `json:"start_time" yaml:"start_time"`           // when the coins start to vest
+	VestingPeriods VestingPeriods `json:"vesting_periods" yaml:"vesting_periods"` // the periods of vesting
+}
+
+// NewPeriodicVestingCU returns a new PeriodicVestingCU, its original vesting is the sum of the periods
+func NewPeriodicVestingCU(bcu *BaseCU, startTime int64, periods VestingPeriods) *PeriodicVestingCU {
+	return &PeriodicVestingCU{
+		BaseVestingCU:  NewBaseVestingCU(bcu, periods.TotalAmount(), startTime+periods.TotalLength()),
+		StartTime:      startTime,
+		VestingPeriods: periods,
+	}
+}
+
+// GetVestedCoins returns the total number of vested coins. If no coins are vested,
+// nil is returned.
+func (pva PeriodicVestingCU) GetVestedCoins(blockTime time.Time) sdk.Coins {
+	var vestedCoins sdk.Coins
+
+	// We must handle the case where the start time for a vesting CU has
+	// been set into the future or when the start of the chain is not exactly
+	// known.
+	if blockTime.Unix() <= pva.StartTime {
+		return vestedCoins
+	} else if blockTime.Unix() >= pva.EndTime {
+		return pva.OriginalVesting
+	}
+
+	// track the start time of the next period
+	currentPeriodStartTime := pva.StartTime
+	for _, period := range pva.VestingPeriods {
+		x := blockTime.Unix() - currentPeriodStartTime
+		if x < period.Length {
+			break
+		}
+		vestedCoins = vestedCoins.Add(period.Amount)
+		currentPeriodStartTime += period.Length
+	}
+
+	return vestedCoins
+}
+
+// GetVestingCoins returns the total number of vesting coins. If no coins are
+// vesting, nil is returned.
+func (pva PeriodicVestingCU) GetVestingCoins(blockTime time.Time) sdk.Coins {
+	return pva.OriginalVesting.Sub(pva.GetVestedCoins(blockTime))
+}
+
+// LockedCoins returns the coins of the CU which can not be spent at blockTime
+func (pva PeriodicVestingCU) LockedCoins(blockTime time.Time) sdk.Coins {
+	return pva.lockedCoins(pva.GetVestingCoins(blockTime))
+}
+
+// TrackDelegation tracks a desired delegation amount by setting the appropriate
+// values for the amount of delegated vesting, delegated free, and reducing the
+// overall amount of base coins.
+func (pva *PeriodicVestingCU) TrackDelegation(blockTime time.Time, amount sdk.Coins) {
+	pva.trackDelegation(pva.GetVestingCoins(blockTime), amount)
+}
+
+// GetStartTime returns the time when vesting starts for a periodic vesting
+// CU.
+func (pva PeriodicVestingCU) GetStartTime() int64 {
+	return pva.StartTime
+}
+
+// Validate checks for errors on the CU fields
+func (pva PeriodicVestingCU) Validate() error {
+	if pva.BaseVestingCU == nil {
+		return errors.New("nil base vesting CU")
+	}
+	if len(pva.VestingPeriods) == 0 {
+		return errors.New("no vesting periods")
+	}
+	for _, period := range pva.VestingPeriods {
+		if period.Length <= 0 {
+			return fmt.Errorf("invalid vesting period length %v", period.Length)
+		}
+		if !period.Amount.IsValid() {
+			return fmt.Errorf("invalid vesting period amount %v", period.Amount)
+		}
+	}
+	if pva.EndTime != pva.StartTime+pva.VestingPeriods.TotalLength() {
+		return errors.New("vesting end-time does not match the total length of periods")
+	}
+	if !pva.OriginalVesting.IsEqual(pva.VestingPeriods.TotalAmount()) {
+		return errors.New("original vesting does not match the total amount of periods")
+	}
+	return pva.BaseVestingCU.Validate()
+}
+
+// MarshalYAML returns the YAML representation of a PeriodicVestingCU.
+func (pva PeriodicVestingCU) MarshalYAML() (interface{}, error) {
+	return pva.marshalYAML(pva.StartTime, pva.VestingPeriods)
+}
+
+func (pva PeriodicVestingCU) String() string {
+	return fmt.Sprintf(`%s
+  StartTime:         %d
+  Vesting Periods:   %v`, pva.BaseVestingCU.String(), pva.StartTime, pva.VestingPeriods)
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestContinuousVestingCU(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	endTime := now.Add(24 * time.Hour)
+	_, _, addr := KeyTestPubAddr()
+	origCoins := sdk.NewCoins(sdk.NewInt64Coin(EthToken, 100), sdk.NewInt64Coin(stakeDenom, 100))
+
+	cva := NewContinuousVestingCU(NewBaseCU(sdk.CUTypeUser, addr, nil, 0), origCoins, now.Unix(), endTime.Unix())
+	require.NoError(t, cva.Validate())
+
+	require.Nil(t, cva.GetVestedCoins(now))
+	require.Equal(t, origCoins, cva.GetVestingCoins(now))
+	require.Equal(t, origCoins, cva.LockedCoins(now))
+
+	halfCoins := sdk.NewCoins(sdk.NewInt64Coin(EthToken, 50), sdk.NewInt64Coin(stakeDenom, 50))
+	require.Equal(t, halfCoins, cva.GetVestedCoins(now.Add(12*time.Hour)))
+	require.Equal(t, halfCoins, cva.LockedCoins(now.Add(12*time.Hour)))
+
+	require.Equal(t, origCoins, cva.GetVestedCoins(endTime))
+	require.True(t, cva.LockedCoins(endTime).IsZero())
+
+	cva.StartTime = endTime.Unix()
+	require.Error(t, cva.Validate())
+}
+
+func TestDelayedVestingCU(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	endTime := now.Add(24 * time.Hour)
+	_, _, addr := KeyTestPubAddr()
+	origCoins := sdk.NewCoins(sdk.NewInt64Coin(EthToken, 100))
+
+	dva := NewDelayedVestingCU(NewBaseCU(sdk.CUTypeUser, addr, nil, 0), origCoins, endTime.Unix())
+	require.NoError(t, dva.Validate())
+
+	require.Nil(t, dva.GetVestedCoins(now.Add(12*time.Hour)))
+	require.Equal(t, origCoins, dva.LockedCoins(endTime.Add(-time.Second)))
+	require.Equal(t, origCoins, dva.GetVestedCoins(endTime))
+	require.True(t, dva.LockedCoins(endTime).IsZero())
+}
+
+func TestPeriodicVestingCU(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	_, _, addr := KeyTestPubAddr()
+	periods := VestingPeriods{
+		{Length: 3600, Amount: sdk.NewCoins(sdk.NewInt64Coin(EthToken, 50))},
+		{Length: 1800, Amount: sdk.NewCoins(sdk.NewInt64Coin(EthToken, 25))},
+		{Length: 1800, Amount: sdk.NewCoins(sdk.NewInt64Coin(EthToken, 25))},
+	}
+
+	pva := NewPeriodicVestingCU(NewBaseCU(sdk.CUTypeUser, addr, nil, 0), now.Unix(), periods)
+	require.NoError(t, pva.Validate())
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(EthToken, 100)), pva.GetOriginalVesting())
+	require.Equal(t, now.Unix()+7200, pva.GetEndTime())
+
+	require.Nil(t, pva.GetVestedCoins(now.Add(3599*time.Second)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(EthToken, 50)), pva.GetVestedCoins(now.Add(time.Hour)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(EthToken, 75)), pva.GetVestedCoins(now.Add(90*time.Minute)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(EthToken, 25)), pva.LockedCoins(now.Add(90*time.Minute)))
+	require.True(t, pva.LockedCoins(now.Add(2*time.Hour)).IsZero())
+}
+
+func TestVestingCUTrackDelegation(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	endTime := now.Add(24 * time.Hour)
+	_, _, addr := KeyTestPubAddr()
+	origCoins := sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 100))
+
+	// delegating vesting coins unlocks them
+	cva := NewContinuousVestingCU(NewBaseCU(sdk.CUTypeUser, addr, nil, 0), origCoins, now.Unix(), endTime.Unix())
+	cva.TrackDelegation(now, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 60)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 60)), cva.GetDelegatedVesting())
+	require.True(t, cva.GetDelegatedFree().IsZero())
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 40)), cva.LockedCoins(now))
+
+	// vested coins are delegated free
+	cva.TrackDelegation(endTime, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 20)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 20)), cva.GetDelegatedFree())
+
+	// undelegation releases the delegated free coins first
+	cva.TrackUndelegation(sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 50)))
+	require.True(t, cva.GetDelegatedFree().IsZero())
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 30)), cva.GetDelegatedVesting())
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(stakeDenom, 70)), cva.LockedCoins(now))
+}
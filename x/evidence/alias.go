@@ -12,6 +12,8 @@ const (
 	StoreKey          = types.StoreKey
 	DefaultParamspace = types.DefaultParamspace
 	QuerierRoute      = types.QuerierRoute
+
+	BehaviourParamsUpgrade = types.BehaviourParamsUpgrade
 )
 
 var (
@@ -22,8 +24,9 @@ var (
 	DefaultGenesisState = types.DefaultGenesisState
 
 	// variable aliases
-	ModuleCdc         = types.ModuleCdc
-	DsignBehaviourKey = types.DsignBehaviourKey
+	ModuleCdc          = types.ModuleCdc
+	DsignBehaviourKey  = types.DsignBehaviourKey
+	KeyGenBehaviourKey = types.KeyGenBehaviourKey
 )
 
 type (
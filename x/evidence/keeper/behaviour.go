@@ -9,6 +9,7 @@ func (k Keeper) HandleBehaviour(ctx sdk.Context, behaviourKey string, validator
 	// check if validator is not unboned
 	// fetch behavior info
 	validatorBehavior := k.GetValidatorBehaviour(ctx, behaviourKey, validator)
+	k.recordEpochBehaviour(ctx, behaviourKey, validator, height, normal)
 
 	// this is a relative index, so it counts blocks the validator *should* have signed
 	// will use the 0-value default signing info if not present, except for start height
@@ -46,6 +47,47 @@ func (k Keeper) HandleBehaviour(ctx sdk.Context, behaviourKey string, validator
 	k.SetValidatorBehaviour(ctx, behaviourKey, validator, validatorBehavior)
 }
 
+// recordEpochBehaviour counts the behaviour in the epoch of height. Unlike the window, the counters
+// of an epoch are never reset, so they stay queryable after the epoch ends.
+func (k Keeper) recordEpochBehaviour(ctx sdk.Context, behaviourKey string, validator sdk.ValAddress, height uint64, normal bool) {
+	epoch := k.stakingKeeper.GetEpochByHeight(ctx, height).Index
+	eb := k.GetEpochBehaviour(ctx, epoch, behaviourKey, validator)
+	eb.Recorded++
+	if !normal {
+		eb.MisbehaviourCounter++
+	}
+	k.SetEpochBehaviour(ctx, epoch, behaviourKey, validator, eb)
+}
+
+// GetValidatorBehaviourStats returns the behaviour of validator within the windows of all behaviours
+// and within the given epoch
+func (k Keeper) GetValidatorBehaviourStats(ctx sdk.Context, validator sdk.ValAddress, epoch uint64) types.ValidatorBehaviourStats {
+	stats := types.ValidatorBehaviourStats{Validator: validator, Epoch: epoch}
+	for _, key := range types.AllBehaviourKeys {
+		vb := k.GetValidatorBehaviour(ctx, key, validator)
+		stats.Behaviours = append(stats.Behaviours, types.NewBehaviourStats(key, k.BehaviourWindow(ctx, key), k.MaxMisbehaviourCount(ctx, key), vb))
+		eb := k.GetEpochBehaviour(ctx, epoch, key, validator)
+		stats.EpochBehaviours = append(stats.EpochBehaviours, types.NewEpochBehaviourStats(key, eb))
+	}
+	return stats
+}
+
+func (k Keeper) GetEpochBehaviour(ctx sdk.Context, epoch uint64, behaviourName string, address sdk.ValAddress) (epochBehaviour types.EpochBehaviour) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetEpochBehaviourKey(epoch, behaviourName, address))
+	if bz == nil {
+		return types.EpochBehaviour{}
+	}
+	k.cdc.MustUnmarshalBinaryBare(bz, &epochBehaviour)
+	return
+}
+
+func (k Keeper) SetEpochBehaviour(ctx sdk.Context, epoch uint64, behaviourName string, address sdk.ValAddress, epochBehaviour types.EpochBehaviour) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryBare(&epochBehaviour)
+	store.Set(types.GetEpochBehaviourKey(epoch, behaviourName, address), bz)
+}
+
 func (k Keeper) GetValidatorBehaviour(ctx sdk.Context, behaviourName string, address sdk.ValAddress) (validatorBehaviour types.ValidatorBehaviour) {
 	store := ctx.KVStore(k.storeKey)
 	bz := store.Get(types.GetValidatorBehaviourKey(behaviourName, address))
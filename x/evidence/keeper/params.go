@@ -28,3 +28,15 @@ func (k Keeper) GetBehaviourParams(ctx sdk.Context, behaviourName string) (param
 func (k Keeper) SetBehaviourParams(ctx sdk.Context, behaviourName string, params types.BehaviourParams) {
 	k.paramSubSpace.SetParamSetWithSubkey(ctx, []byte(behaviourName), &params)
 }
+
+// SetMissingBehaviourParams sets the default params of the behaviours without params, which are introduced
+// after the genesis, like KeyGenBehaviourKey. It is run by the upgrade named BehaviourParamsUpgrade.
+func (k Keeper) SetMissingBehaviourParams(ctx sdk.Context) {
+	for _, behaviourName := range types.AllBehaviourKeys {
+		var window int64
+		k.paramSubSpace.GetWithSubkeyIfExists(ctx, types.KeyBehaviourWindow, []byte(behaviourName), &window)
+		if window == 0 {
+			k.SetBehaviourParams(ctx, behaviourName, types.DefaultBehaviourParams())
+		}
+	}
+}
@@ -23,6 +23,9 @@ func NewQuerier(k Keeper) sdk.Querier {
 		case types.QueryAllEvidence:
 			res, err = queryAllEvidence(ctx, req, k)
 
+		case types.QueryValidatorBehaviours:
+			res, err = queryValidatorBehaviours(ctx, req, k)
+
 		default:
 			err = sdk.ErrUnknownRequest(fmt.Sprintf("unknown %s query endpoint: %s", types.ModuleName, path[0]))
 		}
@@ -81,3 +84,28 @@ func queryAllEvidence(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte,
 
 	return res, nil
 }
+
+func queryValidatorBehaviours(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorBehavioursParams
+
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	epoch := params.Epoch
+	if epoch == 0 {
+		epoch = k.stakingKeeper.GetCurrentEpoch(ctx).Index
+	}
+	stats := make([]types.ValidatorBehaviourStats, 0, len(params.Validators))
+	for _, validator := range params.Validators {
+		stats = append(stats, k.GetValidatorBehaviourStats(ctx, validator, epoch))
+	}
+
+	res, err := codec.MarshalJSONIndent(k.cdc, stats)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+
+	return res, nil
+}
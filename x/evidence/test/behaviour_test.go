@@ -32,6 +32,7 @@ type testEnv struct {
 func TestKeeperHandleBehaviourAllMis(t *testing.T) {
 	startBlock := 5
 	env := setupUnitTestEnv()
+	env.stakingKeeper.On("GetEpochByHeight", mock.Anything, mock.Anything).Return(sdk.Epoch{Index: 1})
 	max := int(env.keeper.MaxMisbehaviourCount(env.ctx, types.VoteBehaviourKey))
 
 	env.stakingKeeper.On("JailByOperator", mock.Anything, env.validators[0].OperatorAddress)
@@ -49,6 +50,7 @@ func TestKeeperHandleBehaviourRevert(t *testing.T) {
 	startBlock := 5
 
 	env := setupUnitTestEnv()
+	env.stakingKeeper.On("GetEpochByHeight", mock.Anything, mock.Anything).Return(sdk.Epoch{Index: 1})
 
 	window := int(env.keeper.BehaviourWindow(env.ctx, types.VoteBehaviourKey))
 
@@ -69,6 +71,108 @@ func TestKeeperHandleBehaviourRevert(t *testing.T) {
 	env.stakingKeeper.AssertNumberOfCalls(t, "JailByOperator", 0)
 }
 
+func TestValidatorBehaviourStats(t *testing.T) {
+	env := setupUnitTestEnv()
+	val := env.validators[0].OperatorAddress
+	// the epoch 2 starts at height 10
+	env.stakingKeeper.On("GetEpochByHeight", mock.Anything, mock.Anything).Return(func(_ sdk.Context, height uint64) sdk.Epoch {
+		if height < 10 {
+			return sdk.Epoch{Index: 1}
+		}
+		return sdk.Epoch{Index: 2, StartBlockNum: 10}
+	})
+	env.stakingKeeper.On("GetCurrentEpoch", mock.Anything).Return(sdk.Epoch{Index: 2, StartBlockNum: 10})
+
+	// nothing recorded yet
+	stats := env.keeper.GetValidatorBehaviourStats(env.ctx, val, 1)
+	require.Equal(t, val, stats.Validator)
+	require.EqualValues(t, 1, stats.Epoch)
+	require.Len(t, stats.Behaviours, len(types.AllBehaviourKeys))
+	for _, behaviour := range stats.Behaviours {
+		require.EqualValues(t, 0, behaviour.Recorded)
+		require.Equal(t, sdk.OneDec(), behaviour.ParticipationRate)
+	}
+	require.Len(t, stats.EpochBehaviours, len(types.AllBehaviourKeys))
+	for _, behaviour := range stats.EpochBehaviours {
+		require.EqualValues(t, 0, behaviour.Recorded)
+		require.Equal(t, sdk.OneDec(), behaviour.ParticipationRate)
+	}
+
+	for i := 0; i < 4; i++ {
+		env.keeper.HandleBehaviour(env.ctx, types.KeyGenBehaviourKey, val, uint64(i), i != 0)
+	}
+	env.keeper.HandleBehaviour(env.ctx, types.DsignBehaviourKey, val, 1, false)
+	env.keeper.HandleBehaviour(env.ctx, types.KeyGenBehaviourKey, val, 10, false)
+
+	queryStats := func(epoch uint64) []types.ValidatorBehaviourStats {
+		query := abci.RequestQuery{
+			Path: "/custom/evidence/" + types.QueryValidatorBehaviours,
+			Data: types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorBehavioursParams([]sdk.ValAddress{val, env.validators[1].OperatorAddress}, epoch)),
+		}
+		res, err := keeper.NewQuerier(env.keeper)(env.ctx, []string{types.QueryValidatorBehaviours}, query)
+		require.Nil(t, err)
+		var allStats []types.ValidatorBehaviourStats
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(res, &allStats))
+		require.Len(t, allStats, 2)
+		require.Equal(t, env.validators[1].OperatorAddress, allStats[1].Validator)
+		return allStats
+	}
+
+	// the windows cover both epochs
+	allStats := queryStats(1)
+	behaviours := make(map[string]types.BehaviourStats)
+	for _, behaviour := range allStats[0].Behaviours {
+		behaviours[behaviour.Behaviour] = behaviour
+	}
+	require.EqualValues(t, 5, behaviours[types.KeyGenBehaviourKey].Recorded)
+	require.EqualValues(t, 2, behaviours[types.KeyGenBehaviourKey].MisbehaviourCounter)
+	require.Equal(t, sdk.NewDecWithPrec(6, 1), behaviours[types.KeyGenBehaviourKey].ParticipationRate)
+	require.Equal(t, sdk.ZeroDec(), behaviours[types.DsignBehaviourKey].ParticipationRate)
+	require.Equal(t, sdk.OneDec(), behaviours[types.VoteBehaviourKey].ParticipationRate)
+
+	require.EqualValues(t, 1, allStats[0].Epoch)
+	epochBehaviours := make(map[string]types.EpochBehaviourStats)
+	for _, behaviour := range allStats[0].EpochBehaviours {
+		epochBehaviours[behaviour.Behaviour] = behaviour
+	}
+	require.EqualValues(t, 4, epochBehaviours[types.KeyGenBehaviourKey].Recorded)
+	require.EqualValues(t, 1, epochBehaviours[types.KeyGenBehaviourKey].MisbehaviourCounter)
+	require.Equal(t, sdk.NewDecWithPrec(75, 2), epochBehaviours[types.KeyGenBehaviourKey].ParticipationRate)
+	require.EqualValues(t, 1, epochBehaviours[types.DsignBehaviourKey].MisbehaviourCounter)
+	require.Equal(t, sdk.ZeroDec(), epochBehaviours[types.DsignBehaviourKey].ParticipationRate)
+
+	// the current epoch by default
+	allStats = queryStats(0)
+	require.EqualValues(t, 2, allStats[0].Epoch)
+	epochBehaviours = make(map[string]types.EpochBehaviourStats)
+	for _, behaviour := range allStats[0].EpochBehaviours {
+		epochBehaviours[behaviour.Behaviour] = behaviour
+	}
+	require.EqualValues(t, 1, epochBehaviours[types.KeyGenBehaviourKey].Recorded)
+	require.EqualValues(t, 1, epochBehaviours[types.KeyGenBehaviourKey].MisbehaviourCounter)
+	require.EqualValues(t, 0, epochBehaviours[types.DsignBehaviourKey].Recorded)
+	require.Equal(t, sdk.OneDec(), epochBehaviours[types.DsignBehaviourKey].ParticipationRate)
+}
+
+func TestSetMissingBehaviourParams(t *testing.T) {
+	env := setupUnitTestEnv()
+	voteParams := types.NewBehaviourParams(100, 10, sdk.NewDecWithPrec(5, 2))
+	env.keeper.SetBehaviourParams(env.ctx, types.VoteBehaviourKey, voteParams)
+
+	// a chain upgraded from before the keygen behaviour misses its params
+	genesis := types.DefaultGenesisState()
+	delete(genesis.BehaviourParams, types.KeyGenBehaviourKey)
+	upgraded := setupUnitTestEnv(genesis)
+	require.Panics(t, func() { upgraded.keeper.BehaviourWindow(upgraded.ctx, types.KeyGenBehaviourKey) })
+
+	for _, e := range []*testEnv{env, upgraded} {
+		e.keeper.SetMissingBehaviourParams(e.ctx)
+		require.Equal(t, types.DefaultBehaviourParams(), e.keeper.GetBehaviourParams(e.ctx, types.KeyGenBehaviourKey))
+	}
+	// the set params are kept
+	require.Equal(t, voteParams, env.keeper.GetBehaviourParams(env.ctx, types.VoteBehaviourKey))
+}
+
 var (
 	priv1 = secp256k1.GenPrivKey()
 	addr1 = sdk.CUAddress(priv1.PubKey().Address())
@@ -80,7 +184,7 @@ var (
 	addr4 = sdk.CUAddress(priv4.PubKey().Address())
 )
 
-func setupUnitTestEnv() *testEnv {
+func setupUnitTestEnv(genesis ...types.GenesisState) *testEnv {
 	validators := []stakingtypes.Validator{
 		{
 			OperatorAddress: sdk.ValAddress(addr1),
@@ -132,7 +236,11 @@ func setupUnitTestEnv() *testEnv {
 	keeper := keeper.NewKeeper(cdc, keeperKey, paramSpace, stakingKeeper)
 
 	// init genesis state
-	evidence.InitGenesis(ctx, keeper, types.DefaultGenesisState())
+	genesisState := types.DefaultGenesisState()
+	if len(genesis) > 0 {
+		genesisState = genesis[0]
+	}
+	evidence.InitGenesis(ctx, keeper, genesisState)
 
 	return &testEnv{
 		ctx:           ctx,
@@ -1,13 +1,19 @@
 package types
 
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
 var (
-	VoteBehaviourKey  = "Vote"
-	DsignBehaviourKey = "dsign"
+	VoteBehaviourKey   = "Vote"
+	DsignBehaviourKey  = "dsign"
+	KeyGenBehaviourKey = "keygen"
 )
 
 var AllBehaviourKeys = []string{
 	VoteBehaviourKey,
 	DsignBehaviourKey,
+	KeyGenBehaviourKey,
 }
 
 // Signing info for a validator
@@ -15,3 +21,71 @@ type ValidatorBehaviour struct {
 	IndexOffset         int64 `json:"index_offset" yaml:"index_offset"`                   // index offset into signed block bit array
 	MisbehaviourCounter int64 `json:"missed_blocks_counter" yaml:"missed_blocks_counter"` // missed blocks counter (to avoid scanning the array every time)
 }
+
+// BehaviourStats is the behaviour of a validator within the window of a behaviour
+type BehaviourStats struct {
+	Behaviour string `json:"behaviour" yaml:"behaviour"`
+	Window    int64  `json:"window" yaml:"window"`
+	// Recorded is the number of behaviours recorded in the window
+	Recorded             int64 `json:"recorded" yaml:"recorded"`
+	MisbehaviourCounter  int64 `json:"misbehaviour_counter" yaml:"misbehaviour_counter"`
+	MaxMisbehaviourCount int64 `json:"max_misbehaviour_count" yaml:"max_misbehaviour_count"`
+	// ParticipationRate is the ratio of normal behaviours in the recorded ones, 1 if none is recorded
+	ParticipationRate sdk.Dec `json:"participation_rate" yaml:"participation_rate"`
+}
+
+func NewBehaviourStats(behaviour string, window, maxMisbehaviourCount int64, vb ValidatorBehaviour) BehaviourStats {
+	recorded := vb.IndexOffset
+	if recorded > window {
+		recorded = window
+	}
+	rate := sdk.OneDec()
+	if recorded > 0 {
+		rate = sdk.NewDec(recorded - vb.MisbehaviourCounter).QuoInt64(recorded)
+	}
+	return BehaviourStats{
+		Behaviour:            behaviour,
+		Window:               window,
+		Recorded:             recorded,
+		MisbehaviourCounter:  vb.MisbehaviourCounter,
+		MaxMisbehaviourCount: maxMisbehaviourCount,
+		ParticipationRate:    rate,
+	}
+}
+
+// EpochBehaviour counts the behaviours of a validator recorded within an epoch
+type EpochBehaviour struct {
+	Recorded            int64 `json:"recorded" yaml:"recorded"`
+	MisbehaviourCounter int64 `json:"misbehaviour_counter" yaml:"misbehaviour_counter"`
+}
+
+// EpochBehaviourStats is the behaviour of a validator within an epoch
+type EpochBehaviourStats struct {
+	Behaviour           string `json:"behaviour" yaml:"behaviour"`
+	Recorded            int64  `json:"recorded" yaml:"recorded"`
+	MisbehaviourCounter int64  `json:"misbehaviour_counter" yaml:"misbehaviour_counter"`
+	// ParticipationRate is the ratio of normal behaviours in the recorded ones, 1 if none is recorded
+	ParticipationRate sdk.Dec `json:"participation_rate" yaml:"participation_rate"`
+}
+
+func NewEpochBehaviourStats(behaviour string, eb EpochBehaviour) EpochBehaviourStats {
+	rate := sdk.OneDec()
+	if eb.Recorded > 0 {
+		rate = sdk.NewDec(eb.Recorded - eb.MisbehaviourCounter).QuoInt64(eb.Recorded)
+	}
+	return EpochBehaviourStats{
+		Behaviour:           behaviour,
+		Recorded:            eb.Recorded,
+		MisbehaviourCounter: eb.MisbehaviourCounter,
+		ParticipationRate:   rate,
+	}
+}
+
+// ValidatorBehaviourStats is the behaviour of a validator within the windows of all behaviours,
+// and within the epoch Epoch
+type ValidatorBehaviourStats struct {
+	Validator       sdk.ValAddress        `json:"validator" yaml:"validator"`
+	Behaviours      []BehaviourStats      `json:"behaviours" yaml:"behaviours"`
+	Epoch           uint64                `json:"epoch" yaml:"epoch"`
+	EpochBehaviours []EpochBehaviourStats `json:"epoch_behaviours" yaml:"epoch_behaviours"`
+}
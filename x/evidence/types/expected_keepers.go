@@ -12,6 +12,7 @@ type ParamSubspace interface {
 	GetParamSet(ctx sdk.Context, ps params.ParamSet)
 	SetParamSet(ctx sdk.Context, ps params.ParamSet)
 	GetWithSubkey(ctx sdk.Context, key, subkey []byte, ptr interface{})
+	GetWithSubkeyIfExists(ctx sdk.Context, key, subkey []byte, ptr interface{})
 	GetParamSetWithSubkey(ctx sdk.Context, key []byte, ps params.ParamSet)
 	SetParamSetWithSubkey(ctx sdk.Context, key []byte, ps params.ParamSet)
 }
@@ -18,6 +18,10 @@ const (
 
 	// QuerierRoute to be used for querierer msgs
 	QuerierRoute = ModuleName
+
+	// BehaviourParamsUpgrade is the name of the upgrade setting the default params of the behaviours
+	// introduced after the genesis
+	BehaviourParamsUpgrade = "behaviour-params"
 )
 
 var (
@@ -26,6 +30,7 @@ var (
 	ValidatorBehaviourBitArrayKey = []byte{0x02}
 	VoteBoxKey                    = []byte{0x03}
 	ConfirmedVoteKey              = []byte{0x04}
+	EpochBehaviourKey             = []byte{0x05}
 )
 
 func GetValidatorBehaviourKey(behaviourName string, v sdk.ValAddress) []byte {
@@ -43,6 +48,13 @@ func GetValidatorBehaviourBitArrayKey(behaviourName string, v sdk.ValAddress, i
 	return append(GetValidatorBehaviourBitArrayPrefixKey(behaviourName, v), b...)
 }
 
+// GetEpochBehaviourKey returns the key of the behaviour counters of a validator within an epoch
+func GetEpochBehaviourKey(epoch uint64, behaviourName string, v sdk.ValAddress) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, epoch)
+	return append(EpochBehaviourKey, append(b, append([]byte(behaviourName), v.Bytes()...)...)...)
+}
+
 func GetVoteBoxKey(voteID string) []byte {
 	return append(VoteBoxKey, voteID...)
 }
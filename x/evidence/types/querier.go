@@ -1,9 +1,15 @@
 package types
 
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
 // Querier routes for the evidence module
 const (
 	QueryEvidence    = "evidence"
 	QueryAllEvidence = "all_evidence"
+
+	QueryValidatorBehaviours = "validator_behaviours"
 )
 
 // QueryEvidenceParams defines the parameters necessary for querying Evidence.
@@ -24,3 +30,14 @@ type QueryAllEvidenceParams struct {
 func NewQueryAllEvidenceParams(page, limit int) QueryAllEvidenceParams {
 	return QueryAllEvidenceParams{Page: page, Limit: limit}
 }
+
+// QueryValidatorBehavioursParams defines the parameters necessary for querying the behaviours of validators.
+// The behaviours within the epoch Epoch are returned too, the current one if Epoch is 0.
+type QueryValidatorBehavioursParams struct {
+	Validators []sdk.ValAddress `json:"validators" yaml:"validators"`
+	Epoch      uint64           `json:"epoch" yaml:"epoch"`
+}
+
+func NewQueryValidatorBehavioursParams(validators []sdk.ValAddress, epoch uint64) QueryValidatorBehavioursParams {
+	return QueryValidatorBehavioursParams{Validators: validators, Epoch: epoch}
+}
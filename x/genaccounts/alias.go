@@ -16,6 +16,7 @@ var (
 	// functions aliases
 	NewGenesisCURaw             = types.NewGenesisCURaw
 	NewGenesisCU                = types.NewGenesisCU
+	NewVestingGenesisCU         = types.NewVestingGenesisCU
 	NewGenesisCUI               = types.NewGenesisCUI
 	GetGenesisStateFromAppState = types.GetGenesisStateFromAppState
 	SetGenesisStateInAppState   = types.SetGenesisStateInAppState
@@ -53,8 +53,20 @@ func AddGenesisAccountCmd(ctx *server.Context, cdc *codec.Codec,
 				return err
 			}
 
-			genAcc := genaccounts.NewGenesisCURaw(sdk.CUTypeUser, nil, nil, addr, coins, sdk.NewCoins(),
-				sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), []sdk.Asset{}, "", "")
+			vestingStart := viper.GetInt64(flagVestingStart)
+			vestingEnd := viper.GetInt64(flagVestingEnd)
+			vestingAmt, err := sdk.ParseCoins(viper.GetString(flagVestingAmt))
+			if err != nil {
+				return fmt.Errorf("failed to parse vesting amount: %w", err)
+			}
+
+			var genAcc genaccounts.GenesisCU
+			if !vestingAmt.IsZero() {
+				genAcc = genaccounts.NewVestingGenesisCU(addr, coins, vestingAmt, vestingStart, vestingEnd)
+			} else {
+				genAcc = genaccounts.NewGenesisCURaw(sdk.CUTypeUser, nil, nil, addr, coins, sdk.NewCoins(),
+					sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), []sdk.Asset{}, "", "")
+			}
 
 			if err := genAcc.Validate(); err != nil {
 				return err
@@ -96,7 +108,7 @@ func AddGenesisAccountCmd(ctx *server.Context, cdc *codec.Codec,
 	cmd.Flags().String(cli.HomeFlag, defaultNodeHome, "node's home directory")
 	cmd.Flags().String(flagClientHome, defaultClientHome, "client's home directory")
 	cmd.Flags().String(flagVestingAmt, "", "amount of coins for vesting accounts")
-	cmd.Flags().Uint64(flagVestingStart, 0, "schedule start time (unix epoch) for vesting accounts")
-	cmd.Flags().Uint64(flagVestingEnd, 0, "schedule end time (unix epoch) for vesting accounts")
+	cmd.Flags().Int64(flagVestingStart, 0, "schedule start time (unix epoch) for vesting accounts, 0 for a delayed vesting CU")
+	cmd.Flags().Int64(flagVestingEnd, 0, "schedule end time (unix epoch) for vesting accounts")
 	return cmd
 }
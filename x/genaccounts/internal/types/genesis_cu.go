@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/hbtc-chain/bhchain/x/supply"
@@ -29,6 +30,14 @@ type GenesisCU struct {
 	Coins          sdk.Coins     `json:"coins" yaml:"coins"`
 	Sequence       uint64        `json:"sequence_number" yaml:"sequence_number"`
 
+	// vesting CustodianUnit fields
+	OriginalVesting  sdk.Coins                    `json:"original_vesting" yaml:"original_vesting"`   // total vesting coins upon initialization
+	DelegatedFree    sdk.Coins                    `json:"delegated_free" yaml:"delegated_free"`       // delegated vested coins at time of delegation
+	DelegatedVesting sdk.Coins                    `json:"delegated_vesting" yaml:"delegated_vesting"` // delegated vesting coins at time of delegation
+	StartTime        int64                        `json:"start_time" yaml:"start_time"`               // vesting start time (UNIX Epoch time)
+	EndTime          int64                        `json:"end_time" yaml:"end_time"`                   // vesting end time (UNIX Epoch time)
+	VestingPeriods   custodianunit.VestingPeriods `json:"vesting_periods" yaml:"vesting_periods"`     // vesting periods of a periodic vesting CU
+
 	// module CustodianUnit fields
 	ModuleName        string   `json:"module_name" yaml:"module_name"`               // name of the module CustodianUnit
 	ModulePermissions []string `json:"module_permissions" yaml:"module_permissions"` // permissions of module CustodianUnit
@@ -41,6 +50,30 @@ func (ga GenesisCU) Validate() error {
 		return errors.New("module CustodianUnit name cannot be blank")
 	}
 
+	if len(ga.VestingPeriods) > 0 {
+		if !ga.OriginalVesting.IsEqual(ga.VestingPeriods.TotalAmount()) {
+			return errors.New("vesting amount does not match the total amount of vesting periods")
+		}
+		if ga.EndTime != ga.StartTime+ga.VestingPeriods.TotalLength() {
+			return errors.New("vesting end-time does not match the total length of vesting periods")
+		}
+	}
+
+	if !ga.OriginalVesting.IsZero() {
+		if ga.ModuleName != "" {
+			return errors.New("module CustodianUnit can not be vesting")
+		}
+		if ga.OriginalVesting.IsAnyGT(ga.Coins) {
+			return errors.New("vesting amount cannot be greater than total amount")
+		}
+		if ga.StartTime >= ga.EndTime && (ga.StartTime != 0 || len(ga.VestingPeriods) > 0) {
+			return errors.New("vesting start-time cannot be after end-time")
+		}
+		if ga.EndTime <= 0 {
+			return fmt.Errorf("invalid vesting end-time %v", ga.EndTime)
+		}
+	}
+
 	return nil
 }
 
@@ -69,6 +102,17 @@ func NewGenesisCURaw(cutype sdk.CUType, pubkey crypto.PubKey, assetpubkey []byte
 	}
 }
 
+// NewVestingGenesisCU creates a GenesisCU of a vesting CU with coins, among which vestingAmt vests
+// continuously from startTime to endTime, or at endTime if startTime is zero.
+func NewVestingGenesisCU(address sdk.CUAddress, coins, vestingAmt sdk.Coins, startTime, endTime int64) GenesisCU {
+	gcu := NewGenesisCURaw(sdk.CUTypeUser, nil, nil, address, coins, sdk.NewCoins(),
+		sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), []sdk.Asset{}, "")
+	gcu.OriginalVesting = vestingAmt
+	gcu.StartTime = startTime
+	gcu.EndTime = endTime
+	return gcu
+}
+
 // NewGenesisCU creates a GenesisCU instance from a BaseCU.
 func NewGenesisCU(cu *custodianunit.BaseCU) GenesisCU {
 	return GenesisCU{
@@ -89,6 +133,15 @@ func NewGenesisCUI(cu cuexported.CustodianUnit) (GenesisCU, error) {
 	}
 
 	switch acc := cu.(type) {
+	case cuexported.VestingCU:
+		gcu.OriginalVesting = acc.GetOriginalVesting()
+		gcu.DelegatedFree = acc.GetDelegatedFree()
+		gcu.DelegatedVesting = acc.GetDelegatedVesting()
+		gcu.StartTime = acc.GetStartTime()
+		gcu.EndTime = acc.GetEndTime()
+		if pcu, ok := acc.(*custodianunit.PeriodicVestingCU); ok {
+			gcu.VestingPeriods = pcu.VestingPeriods
+		}
 
 	case supplyexported.ModuleAccountI:
 		gcu.ModuleName = acc.GetName()
@@ -102,6 +155,30 @@ func NewGenesisCUI(cu cuexported.CustodianUnit) (GenesisCU, error) {
 func (ga *GenesisCU) ToCU() custodianunit.CU {
 	bcu := custodianunit.NewBaseCU(sdk.CUTypeUser, ga.Address, ga.PubKey, ga.Sequence)
 
+	// vesting accounts
+	if !ga.OriginalVesting.IsZero() {
+		var bvcu *custodianunit.BaseVestingCU
+		var vcu custodianunit.CU
+		switch {
+		case len(ga.VestingPeriods) > 0:
+			pcu := custodianunit.NewPeriodicVestingCU(bcu, ga.StartTime, ga.VestingPeriods)
+			bvcu, vcu = pcu.BaseVestingCU, pcu
+		case ga.StartTime != 0:
+			ccu := custodianunit.NewContinuousVestingCU(bcu, ga.OriginalVesting, ga.StartTime, ga.EndTime)
+			bvcu, vcu = ccu.BaseVestingCU, ccu
+		default:
+			dcu := custodianunit.NewDelayedVestingCU(bcu, ga.OriginalVesting, ga.EndTime)
+			bvcu, vcu = dcu.BaseVestingCU, dcu
+		}
+		if !ga.DelegatedFree.Empty() {
+			bvcu.DelegatedFree = ga.DelegatedFree
+		}
+		if !ga.DelegatedVesting.Empty() {
+			bvcu.DelegatedVesting = ga.DelegatedVesting
+		}
+		return vcu
+	}
+
 	// module accounts
 	if ga.ModuleName != "" {
 		return supply.NewModuleAccount(bcu, ga.ModuleName, ga.ModulePermissions...)
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
 	"github.com/hbtc-chain/bhchain/x/supply"
 )
 
@@ -38,6 +39,21 @@ func TestGenesisAccountValidate(t *testing.T) {
 				sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), sdk.NewCoins(), []sdk.Asset{}, " ", supply.Minter),
 			errors.New("module CustodianUnit name cannot be blank"),
 		},
+		{
+			"valid vesting CU",
+			NewVestingGenesisCU(addr, sdk.NewCoins(sdk.NewInt64Coin("hbc", 100)), sdk.NewCoins(sdk.NewInt64Coin("hbc", 50)), 1000, 2000),
+			nil,
+		},
+		{
+			"vesting amount greater than total amount",
+			NewVestingGenesisCU(addr, sdk.NewCoins(sdk.NewInt64Coin("hbc", 100)), sdk.NewCoins(sdk.NewInt64Coin("hbc", 150)), 1000, 2000),
+			errors.New("vesting amount cannot be greater than total amount"),
+		},
+		{
+			"vesting start-time after end-time",
+			NewVestingGenesisCU(addr, sdk.NewCoins(sdk.NewInt64Coin("hbc", 100)), sdk.NewCoins(sdk.NewInt64Coin("hbc", 50)), 2000, 1000),
+			errors.New("vesting start-time cannot be after end-time"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,3 +62,35 @@ func TestGenesisAccountValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenesisVestingCUToCU(t *testing.T) {
+	addr := sdk.CUAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewInt64Coin("hbc", 100))
+	vesting := sdk.NewCoins(sdk.NewInt64Coin("hbc", 60))
+
+	gcu := NewVestingGenesisCU(addr, coins, vesting, 1000, 2000)
+	ccu, ok := gcu.ToCU().(*custodianunit.ContinuousVestingCU)
+	require.True(t, ok)
+	require.Equal(t, vesting, ccu.GetOriginalVesting())
+	exported, err := NewGenesisCUI(ccu)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), exported.StartTime)
+	require.Equal(t, int64(2000), exported.EndTime)
+
+	gcu = NewVestingGenesisCU(addr, coins, vesting, 0, 2000)
+	_, ok = gcu.ToCU().(*custodianunit.DelayedVestingCU)
+	require.True(t, ok)
+
+	gcu.StartTime = 1000
+	gcu.VestingPeriods = custodianunit.VestingPeriods{
+		{Length: 500, Amount: sdk.NewCoins(sdk.NewInt64Coin("hbc", 30))},
+		{Length: 500, Amount: sdk.NewCoins(sdk.NewInt64Coin("hbc", 30))},
+	}
+	require.NoError(t, gcu.Validate())
+	pcu, ok := gcu.ToCU().(*custodianunit.PeriodicVestingCU)
+	require.True(t, ok)
+	require.Equal(t, int64(2000), pcu.GetEndTime())
+	exported, err = NewGenesisCUI(pcu)
+	require.NoError(t, err)
+	require.Equal(t, gcu.VestingPeriods, exported.VestingPeriods)
+}
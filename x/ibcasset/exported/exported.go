@@ -24,6 +24,10 @@ type CUIBCAsset interface {
 
 	SetAssetAddress(denom, address string, epoch uint64) error
 
+	AddDepositAddress(denom, address string, epoch uint64) error
+	GetDepositAddresses(denom string) []string
+	RemoveDepositAddress(denom, address string) error
+
 	GetAssetCoinsHold() sdk.Coins
 	AddAssetCoinsHold(coins sdk.Coins) sdk.Coins
 	SubAssetCoinsHold(coins sdk.Coins) sdk.Coins
@@ -35,6 +39,8 @@ type CUIBCAsset interface {
 	GetAssetPubkey(epoch uint64) []byte
 	SetAssetPubkey(pubkey []byte, epoch uint64) error
 	GetAssetPubkeyEpoch() uint64
+	GetAssetPubkeyThreshold() uint64
+	SetAssetPubkeyThreshold(threshold uint64)
 
 	GetGasUsed() sdk.Coins
 	AddGasUsed(coins sdk.Coins) sdk.Coins
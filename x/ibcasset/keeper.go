@@ -140,6 +140,25 @@ func (keeper Keeper) GetPendingDepositList(ctx sdk.Context, address sdk.CUAddres
 
 }
 
+// GetDepositListByExtAddress returns the deposit items of all symbols which are sent to extAddress
+func (keeper Keeper) GetDepositListByExtAddress(ctx sdk.Context, address sdk.CUAddress, extAddress string) map[string]sdk.DepositList {
+	store := ctx.KVStore(keeper.key)
+	iterator := sdk.KVStorePrefixIterator(store, types.DepositStorePrefixKeyWithAddr(address))
+	defer iterator.Close()
+
+	deposits := make(map[string]sdk.DepositList)
+	for ; iterator.Valid(); iterator.Next() {
+		bz := iterator.Value()
+		var dl sdk.DepositItem
+		keeper.cdc.UnmarshalBinaryBare(bz, &dl)
+		if dl.ExtAddress == extAddress {
+			token := types.DecodeSymbolFromDepositListKey(iterator.Key())
+			deposits[token] = append(deposits[token], dl)
+		}
+	}
+	return deposits
+}
+
 // ----------------------------------------------------------------------
 // DepositList funcs
 func (keeper Keeper) GetDepositList(ctx sdk.Context, symbol string, address sdk.CUAddress) sdk.DepositList {
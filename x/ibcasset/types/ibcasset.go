@@ -20,6 +20,9 @@ type CUIBCAsset struct {
 	GasUsed          sdk.Coins           `json:"gas_used" yaml:"gas_used"`
 	GasReceived      sdk.Coins           `json:"gas_received" yaml:"gas_received"`
 	MigrationStatus  sdk.MigrationStatus `json:"migration_status" yaml:"migration_status"`
+	// AssetPubkeyThreshold is the number of key nodes required to sign with AssetPubkey, 0 for the keys generated
+	// before it was recorded, which require the 2/3 majority of the key nodes
+	AssetPubkeyThreshold uint64 `json:"asset_pubkey_threshold" yaml:"asset_pubkey_threshold"`
 }
 
 func ProtoBaseCUIBCAsset() exported.CUIBCAsset {
@@ -77,7 +80,7 @@ func (cuAst *CUIBCAsset) GetAssets() []sdk.Asset {
 
 func (cuAst *CUIBCAsset) GetAsset(denom string, epoch uint64) sdk.Asset {
 	for _, asset := range cuAst.Assets {
-		if asset.Denom == denom && asset.Epoch == epoch {
+		if asset.Denom == denom && asset.Epoch == epoch && !asset.DepositOnly {
 			return asset
 		}
 	}
@@ -126,9 +129,20 @@ func (cuAst *CUIBCAsset) SetAssetPubkey(pubkey []byte, epoch uint64) error {
 	return nil
 }
 
+func (cuAst *CUIBCAsset) GetAssetPubkeyThreshold() uint64 {
+	return cuAst.AssetPubkeyThreshold
+}
+
+func (cuAst *CUIBCAsset) SetAssetPubkeyThreshold(threshold uint64) {
+	cuAst.AssetPubkeyThreshold = threshold
+}
+
 func (cuAst *CUIBCAsset) AddAsset(denom, address string, epoch uint64) error {
 	for i := 0; i < len(cuAst.Assets); i++ {
 		asset := cuAst.Assets[i]
+		if asset.DepositOnly {
+			continue
+		}
 		if asset.Denom == denom && asset.Epoch == epoch {
 			return errors.New("asset already exist")
 		}
@@ -158,7 +172,7 @@ func (cuAst *CUIBCAsset) AddAsset(denom, address string, epoch uint64) error {
 
 func (cuAst *CUIBCAsset) SetAssetAddress(denom, address string, epoch uint64) error {
 	for i, asset := range cuAst.Assets {
-		if asset.Denom == denom && asset.Epoch == 0 {
+		if asset.Denom == denom && asset.Epoch == 0 && !asset.DepositOnly {
 			cuAst.Assets[i].Address = address
 			cuAst.Assets[i].Epoch = epoch
 			return nil
@@ -167,6 +181,45 @@ func (cuAst *CUIBCAsset) SetAssetAddress(denom, address string, epoch uint64) er
 	return cuAst.AddAsset(denom, address, epoch)
 }
 
+// AddDepositAddress adds an additional deposit address of denom, deposits to it are accepted
+// and collected like the ones to the address of the epoch.
+func (cuAst *CUIBCAsset) AddDepositAddress(denom, address string, epoch uint64) error {
+	for _, asset := range cuAst.Assets {
+		if asset.Denom == denom && asset.Address == address {
+			return errors.New("address already exist")
+		}
+	}
+	asset := sdk.NewAsset(denom, address, epoch, true)
+	asset.DepositOnly = true
+	cuAst.Assets = append(cuAst.Assets, asset)
+	return nil
+}
+
+func (cuAst *CUIBCAsset) GetDepositAddresses(denom string) []string {
+	var addrs []string
+	for _, asset := range cuAst.Assets {
+		if asset.Denom == denom && asset.DepositOnly {
+			addrs = append(addrs, asset.Address)
+		}
+	}
+	return addrs
+}
+
+// RemoveDepositAddress removes an additional deposit address of denom, the gas remained
+// on it is counted as used.
+func (cuAst *CUIBCAsset) RemoveDepositAddress(denom, address string) error {
+	for i, asset := range cuAst.Assets {
+		if asset.Denom == denom && asset.Address == address && asset.DepositOnly {
+			if !asset.GasRemained.IsZero() {
+				cuAst.AddGasUsed(sdk.NewCoins(sdk.NewCoin(asset.Denom, asset.GasRemained)))
+			}
+			cuAst.Assets = append(cuAst.Assets[:i], cuAst.Assets[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("deposit address does not exist")
+}
+
 func (cuAst *CUIBCAsset) GetNonce(denom string, addr string) uint64 {
 	for _, asset := range cuAst.Assets {
 		if asset.Denom == denom && asset.Address == addr {
@@ -275,27 +328,29 @@ func (cuAst *CUIBCAsset) MarshalYAML() (interface{}, error) {
 		assetPubkey = base58.Encode(cuAst.AssetPubkey)
 	}
 	bs, err = yaml.Marshal(struct {
-		Address          sdk.CUAddress
-		Type             sdk.CUType
-		Assets           []sdk.Asset
-		AssetCoins       sdk.Coins
-		AssetCoinsHold   sdk.Coins
-		AssetPubkey      string
-		AssetPubkeyEpoch uint64
-		GasUsed          sdk.Coins
-		GasReceived      sdk.Coins
-		MigrationStatu   sdk.MigrationStatus
+		Address              sdk.CUAddress
+		Type                 sdk.CUType
+		Assets               []sdk.Asset
+		AssetCoins           sdk.Coins
+		AssetCoinsHold       sdk.Coins
+		AssetPubkey          string
+		AssetPubkeyEpoch     uint64
+		AssetPubkeyThreshold uint64
+		GasUsed              sdk.Coins
+		GasReceived          sdk.Coins
+		MigrationStatu       sdk.MigrationStatus
 	}{
-		Address:          cuAst.Address,
-		Type:             cuAst.Type,
-		AssetCoins:       cuAst.AssetCoins,
-		AssetCoinsHold:   cuAst.AssetCoinsHold,
-		GasUsed:          cuAst.GasUsed,
-		GasReceived:      cuAst.GasReceived,
-		Assets:           cuAst.Assets,
-		AssetPubkey:      assetPubkey,
-		AssetPubkeyEpoch: cuAst.AssetPubkeyEpoch,
-		MigrationStatu:   cuAst.MigrationStatus,
+		Address:              cuAst.Address,
+		Type:                 cuAst.Type,
+		AssetCoins:           cuAst.AssetCoins,
+		AssetCoinsHold:       cuAst.AssetCoinsHold,
+		GasUsed:              cuAst.GasUsed,
+		GasReceived:          cuAst.GasReceived,
+		Assets:               cuAst.Assets,
+		AssetPubkey:          assetPubkey,
+		AssetPubkeyEpoch:     cuAst.AssetPubkeyEpoch,
+		AssetPubkeyThreshold: cuAst.AssetPubkeyThreshold,
+		MigrationStatu:       cuAst.MigrationStatus,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,15 @@
+package keygen
+
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+)
+
+// EndBlocker reassigns the timed out keygen orders and rotates the OPCU keys on schedule
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	k.handleKeyGenTimeouts(ctx)
+
+	if ctx.BlockHeight()%types.KeyRotationCheckInterval == 0 {
+		k.rotateKeys(ctx)
+	}
+}
@@ -15,6 +15,7 @@ const (
 	MaxWaitAssignKeyOrders = types.MaxWaitAssignKeyOrders
 	MaxPreKeyGenOrders     = types.MaxPreKeyGenOrders
 	MaxKeyNodeHeartbeat    = types.MaxKeyNodeHeartbeat
+	MaxDepositAddresses    = types.MaxDepositAddresses
 )
 
 var (
@@ -23,14 +24,20 @@ var (
 	HandleMsgNewOpCUForTest        = handleMsgNewOpCU
 	HandleMsgKeyGenForTest         = handleMsgKeyGen
 	HandleMsgKeyGenWaitSignForTest = handleMsgKeyGenWaitSign
+	HandleMsgKeyGenFinishForTest   = handleMsgKeyGenFinish
+
+	HandleMsgNewDepositAddressForTest    = handleMsgNewDepositAddress
+	HandleMsgRetireDepositAddressForTest = handleMsgRetireDepositAddress
 )
 
 type (
-	MsgKeyGen              = types.MsgKeyGen
-	MsgKeyGenWaitSign      = types.MsgKeyGenWaitSign
-	MsgPreKeyGen           = types.MsgPreKeyGen
-	MsgKeyGenFinish        = types.MsgKeyGenFinish
-	MsgOpcuMigrationKeyGen = types.MsgOpcuMigrationKeyGen
-	MsgNewOpCU             = types.MsgNewOpCU
-	CustodianUnit          = exported.CustodianUnit
+	MsgKeyGen               = types.MsgKeyGen
+	MsgKeyGenWaitSign       = types.MsgKeyGenWaitSign
+	MsgPreKeyGen            = types.MsgPreKeyGen
+	MsgKeyGenFinish         = types.MsgKeyGenFinish
+	MsgOpcuMigrationKeyGen  = types.MsgOpcuMigrationKeyGen
+	MsgNewOpCU              = types.MsgNewOpCU
+	MsgNewDepositAddress    = types.MsgNewDepositAddress
+	MsgRetireDepositAddress = types.MsgRetireDepositAddress
+	CustodianUnit           = exported.CustodianUnit
 )
@@ -1,11 +1,59 @@
 package cli
 
 import (
-	"github.com/hbtc-chain/bhchain/codec"
+	"fmt"
+
 	"github.com/spf13/cobra"
+
+	"github.com/hbtc-chain/bhchain/client"
+	"github.com/hbtc-chain/bhchain/client/context"
+	"github.com/hbtc-chain/bhchain/codec"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
 )
 
 // GetQueryCmd returns the cli query commands for this module
 func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{}
+	keygenQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the keygen module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	keygenQueryCmd.AddCommand(
+		client.GetCommands(
+			GetCmdQueryKeyRotation(queryRoute, cdc),
+		)...,
+	)
+
+	return keygenQueryCmd
+}
+
+func GetCmdQueryKeyRotation(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "key-rotation [chain]",
+		Short: "Query the OPCU key rotation status of a chain, or of all chains with OPCUs",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			var chain string
+			if len(args) > 0 {
+				chain = args[0]
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryKeyRotationParams(chain))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryKeyRotation)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
 }
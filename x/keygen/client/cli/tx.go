@@ -30,6 +30,8 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		GetCmdKeyGen(cdc),
 		GetCmdNewOpCU(cdc),
+		GetCmdNewDepositAddress(cdc),
+		GetCmdRetireDepositAddress(cdc),
 	)...)
 
 	return txCmd
@@ -110,3 +112,63 @@ func GetCmdNewOpCU(cdc *codec.Codec) *cobra.Command {
 	}
 	return cmd
 }
+
+func GetCmdNewDepositAddress(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "newdepositaddress [from_key_or_address] [chain]",
+		Short: "generate an additional deposit address of the chain",
+		Long:  ` Example: newdepositaddress alice btc`,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := ctypes.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			chain := sdk.Symbol(args[1])
+			if !chain.IsValid() {
+				return fmt.Errorf("Invalid chain:%v", args[1])
+			}
+
+			orderID := viper.GetString(flagOrderID)
+			if len(orderID) == 0 {
+				orderID = uuid.NewV4().String()
+			}
+
+			msg := types.NewMsgNewDepositAddress(orderID, chain, sdk.CUAddress(cliCtx.GetFromAddress()))
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String(flagOrderID, "", "order ID of keygen is a uuid string. e.g. 'fc9ffd98-c99f-4a7c-b3ab-a517fed807c4'")
+	return cmd
+}
+
+func GetCmdRetireDepositAddress(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retiredepositaddress [from_key_or_address] [chain] [address]",
+		Short: "retire an additional deposit address whose deposits are all collected",
+		Long:  ` Example: retiredepositaddress alice btc mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg`,
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := ctypes.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			chain := sdk.Symbol(args[1])
+			if !chain.IsValid() {
+				return fmt.Errorf("Invalid chain:%v", args[1])
+			}
+
+			msg := types.NewMsgRetireDepositAddress(chain, args[2], sdk.CUAddress(cliCtx.GetFromAddress()))
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	return cmd
+}
@@ -23,6 +23,18 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		"/keygen/wait_assign",
 		waitAssignHandlerFn(cliCtx),
 	).Methods("GET")
+
+	// Query the key rotation status of all chains
+	r.HandleFunc(
+		"/keygen/key_rotation",
+		keyRotationHandlerFn(cliCtx),
+	).Methods("GET")
+
+	// Query the key rotation status of a chain
+	r.HandleFunc(
+		"/keygen/key_rotation/{chain}",
+		keyRotationHandlerFn(cliCtx),
+	).Methods("GET")
 }
 
 func waitAssignHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
@@ -41,3 +53,25 @@ func waitAssignHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+func keyRotationHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(types.NewQueryKeyRotationParams(mux.Vars(r)["chain"]))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryKeyRotation), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
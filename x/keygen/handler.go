@@ -28,6 +28,10 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgOpcuMigrationKeyGen(ctx, keeper, msg)
 		case MsgNewOpCU:
 			return handleMsgNewOpCU(ctx, keeper, msg)
+		case MsgNewDepositAddress:
+			return handleMsgNewDepositAddress(ctx, keeper, msg)
+		case MsgRetireDepositAddress:
+			return handleMsgRetireDepositAddress(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized token Msg type: %v", msg.Type())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -129,7 +133,8 @@ func handleMsgKeyGen(ctx sdk.Context, keeper Keeper, msg MsgKeyGen) sdk.Result {
 		}
 	}
 
-	// 先从预生成公钥中绑定
+	// 先从预生成公钥中绑定, 预生成公钥的门限需满足链的门限
+	threshold := keeper.getSignThreshold(ctx, symbol.String(), len(curEpoch.KeyNodeSet))
 	waitAssignKeyGens := keeper.GetWaitAssignKeyGenOrderIDs(ctx)
 	for _, orderID := range waitAssignKeyGens {
 		order := keeper.ok.GetOrder(ctx, orderID)
@@ -139,6 +144,9 @@ func handleMsgKeyGen(ctx sdk.Context, keeper Keeper, msg MsgKeyGen) sdk.Result {
 			continue
 		}
 		keygenOrder := order.(*sdk.OrderKeyGen)
+		if keygenOrder.SignThreshold < uint64(threshold) {
+			continue
+		}
 		// 向 chainnode 获取 Address
 		addr, err := keeper.cn.ConvertAddress(ti.Chain.String(), keygenOrder.Pubkey)
 		if err != nil {
@@ -147,7 +155,7 @@ func handleMsgKeyGen(ctx sdk.Context, keeper Keeper, msg MsgKeyGen) sdk.Result {
 			continue
 		}
 		// multisignaddress 写入to CU，PubKey 写入cu.AssetPubkey
-		if result := setAddressAndPubkeyToCU(ctx, toCUAst, keeper, keygenOrder.Pubkey, addr, symbol.String(), ti.Chain.String(), curEpoch.Index); !result.IsOK() {
+		if result := setAddressAndPubkeyToCU(ctx, toCUAst, keeper, keygenOrder.Pubkey, keygenOrder.SignThreshold, addr, symbol.String(), ti.Chain.String(), curEpoch.Index); !result.IsOK() {
 			return result
 		}
 		// 更新 order 状态
@@ -207,7 +215,7 @@ func handleMsgKeyGen(ctx sdk.Context, keeper Keeper, msg MsgKeyGen) sdk.Result {
 	keeper.ik.SetCUIBCAsset(ctx, toCUAst)
 
 	//8、生成KeyGenOrder， ID为msg.orderID
-	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet)
+	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet, threshold)
 	keynodes := make([]sdk.CUAddress, 0, len(curEpoch.KeyNodeSet))
 	for _, val := range curEpoch.KeyNodeSet {
 		if !val.Equals(excludedKeyNode) {
@@ -217,7 +225,7 @@ func handleMsgKeyGen(ctx sdk.Context, keeper Keeper, msg MsgKeyGen) sdk.Result {
 	if len(keynodes) == 0 {
 		return sdk.ErrInsufficientValidatorNumForKeyGen("empty keynode list").Result()
 	}
-	order := keeper.ok.NewOrderKeyGen(ctx, fromAddr, msg.OrderID, symbol.String(), keynodes, uint64(sdk.Majority23(len(curEpoch.KeyNodeSet))), toAddr, feeCoin)
+	order := keeper.ok.NewOrderKeyGen(ctx, fromAddr, msg.OrderID, symbol.String(), keynodes, uint64(threshold), toAddr, feeCoin)
 	keeper.ok.SetOrder(ctx, order)
 
 	//9. generate orderflow, keygenflow, balanceFlows
@@ -282,7 +290,7 @@ func handleMsgKeyGenWaitSign(ctx sdk.Context, keeper Keeper, msg MsgKeyGenWaitSi
 		}
 
 		//4、检查tocu.symbol对应的address是否已经存在，
-		if toCUAst.GetAssetAddress(symbol, msg.Epoch) != "" {
+		if !keyGenOrder.DepositAddress && toCUAst.GetAssetAddress(symbol, msg.Epoch) != "" {
 			return sdk.ErrInvalidTx("cu asset address already exist").Result()
 		}
 	} else {
@@ -294,12 +302,13 @@ func handleMsgKeyGenWaitSign(ctx sdk.Context, keeper Keeper, msg MsgKeyGenWaitSi
 
 	// 6、验签
 	signMsg := types.NewMsgKeyGenWaitSign(msg.From, msg.OrderID, msg.PubKey, msg.KeyNodes, []cutypes.StdSignature{}, msg.Epoch)
-	if result := checkKeyNodesSigns(signMsg.GetSignBytes(), msg.KeySigs, keyGenOrder.KeyNodes); !result.IsOK() {
+	if result := checkKeyNodesSigns(signMsg.GetSignBytes(), msg.KeySigs, keyGenOrder.KeyNodes, keyGenOrder.SignThreshold); !result.IsOK() {
 		return result
 	}
 
 	//7、修改KeyGenOrder等待验证签名，记录MultiSignAddress，生成KeyGenFinishFlow.
 	keyGenOrder.Epoch = msg.Epoch
+	keyGenOrder.StageHeight = uint64(ctx.BlockHeight())
 	keyGenOrder.SetOrderStatus(sdk.OrderStatusWaitSign)
 	keyGenOrder.Pubkey = msg.PubKey
 	keeper.ok.SetOrder(ctx, order)
@@ -367,7 +376,12 @@ func handleMsgKeyGenFinish(ctx sdk.Context, keeper Keeper, msg MsgKeyGenFinish)
 			return sdk.ErrInvalidTx(fmt.Sprintf("Convert address error, chain:%v, pubKey:%v, err:%v", ti.Chain.String(), keyGenOrder.Pubkey, err)).Result()
 
 		}
-		if result := setAddressAndPubkeyToCU(ctx, toCUAst, keeper, keyGenOrder.Pubkey, addr, symbol, ti.Chain.String(), keyGenOrder.Epoch); !result.IsOK() {
+		if keyGenOrder.DepositAddress {
+			if result := addDepositAddressToCU(ctx, toCUAst, keeper, addr, ti.Chain.String()); !result.IsOK() {
+				return result
+			}
+			keyGenOrder.MultiSignAddress = addr
+		} else if result := setAddressAndPubkeyToCU(ctx, toCUAst, keeper, keyGenOrder.Pubkey, keyGenOrder.SignThreshold, addr, symbol, ti.Chain.String(), keyGenOrder.Epoch); !result.IsOK() {
 			return result
 		}
 
@@ -453,7 +467,8 @@ func handleMsgPreKeyGen(ctx sdk.Context, keeper Keeper, msg MsgPreKeyGen) sdk.Re
 	}
 
 	// 6.生成KeyGenOrder, orderflow, keygenflow
-	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet)
+	threshold := sdk.Majority23(len(curEpoch.KeyNodeSet))
+	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet, threshold)
 	keynodes := make([]sdk.CUAddress, 0, len(curEpoch.KeyNodeSet))
 	for _, val := range curEpoch.KeyNodeSet {
 		if !val.Equals(excludedKeyNode) {
@@ -466,7 +481,7 @@ func handleMsgPreKeyGen(ctx sdk.Context, keeper Keeper, msg MsgPreKeyGen) sdk.Re
 	flows := make([]sdk.Flow, 0, 2*len(msg.OrderIDs))
 	for _, orderID := range msg.OrderIDs {
 		zeroFee := sdk.NewCoin(sdk.NativeToken, sdk.ZeroInt())
-		order := keeper.ok.NewOrderKeyGen(ctx, msg.From, orderID, "", keynodes, uint64(sdk.Majority23(len(curEpoch.KeyNodeSet))), nil, zeroFee)
+		order := keeper.ok.NewOrderKeyGen(ctx, msg.From, orderID, "", keynodes, uint64(threshold), nil, zeroFee)
 		keeper.ok.SetOrder(ctx, order)
 		orderFlow := keeper.rk.NewOrderFlow("", nil, orderID, sdk.OrderTypeKeyGen, sdk.OrderStatusBegin)
 		keyGenFlow := sdk.KeyGenFlow{OrderID: orderID, Symbol: "", From: msg.From, To: nil, IsPreKeyGen: true, ExcludedKeyNode: excludedKeyNode}
@@ -506,7 +521,7 @@ func handleMsgOpcuMigrationKeyGen(ctx sdk.Context, keeper Keeper, msg MsgOpcuMig
 		}
 	}
 
-	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet)
+	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet, sdk.Majority23(len(curEpoch.KeyNodeSet)))
 	keynodes := make([]sdk.CUAddress, 0, len(curEpoch.KeyNodeSet))
 	for _, val := range curEpoch.KeyNodeSet {
 		if !val.Equals(excludedKeyNode) {
@@ -517,7 +532,6 @@ func handleMsgOpcuMigrationKeyGen(ctx sdk.Context, keeper Keeper, msg MsgOpcuMig
 		return sdk.ErrInsufficientValidatorNumForKeyGen("empty keynode list").Result()
 	}
 
-	threshold := uint64(sdk.Majority23(len(curEpoch.KeyNodeSet)))
 	zeroFee := sdk.NewCoin(sdk.NativeToken, sdk.ZeroInt())
 
 	opcus := keeper.ck.GetOpCUs(ctx, "")
@@ -536,10 +550,16 @@ func handleMsgOpcuMigrationKeyGen(ctx sdk.Context, keeper Keeper, msg MsgOpcuMig
 		if exist, _ := checkCuKeyGenOrder(ctx, keeper, processOrderList, opcu.GetAddress()); exist {
 			continue
 		}
-		order := keeper.ok.NewOrderKeyGen(ctx, msg.From, msg.OrderIDs[i], opcu.GetSymbol(), keynodes, threshold, opcu.GetAddress(), zeroFee)
+		// chains with a stricter threshold keep all key nodes if the excluded one is needed to reach it
+		threshold := keeper.getSignThreshold(ctx, opcu.GetSymbol(), len(curEpoch.KeyNodeSet))
+		orderKeyNodes, orderExcludedKeyNode := keynodes, excludedKeyNode
+		if len(keynodes) < threshold {
+			orderKeyNodes, orderExcludedKeyNode = curEpoch.KeyNodeSet, nil
+		}
+		order := keeper.ok.NewOrderKeyGen(ctx, msg.From, msg.OrderIDs[i], opcu.GetSymbol(), orderKeyNodes, uint64(threshold), opcu.GetAddress(), zeroFee)
 		keeper.ok.SetOrder(ctx, order)
 		orderFlow := keeper.rk.NewOrderFlow(sdk.Symbol(opcu.GetSymbol()), opcu.GetAddress(), msg.OrderIDs[i], sdk.OrderTypeKeyGen, sdk.OrderStatusBegin)
-		keyGenFlow := sdk.KeyGenFlow{OrderID: msg.OrderIDs[i], Symbol: sdk.Symbol(opcu.GetSymbol()), From: msg.From, To: opcu.GetAddress(), IsPreKeyGen: false, ExcludedKeyNode: excludedKeyNode}
+		keyGenFlow := sdk.KeyGenFlow{OrderID: msg.OrderIDs[i], Symbol: sdk.Symbol(opcu.GetSymbol()), From: msg.From, To: opcu.GetAddress(), IsPreKeyGen: false, ExcludedKeyNode: orderExcludedKeyNode}
 		flows = append(flows, orderFlow, keyGenFlow)
 	}
 	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeKeyGen, flows)
@@ -586,7 +606,10 @@ func isValidator(vals []sdk.CUAddress, cuAddress sdk.CUAddress) bool {
 	return false
 }
 
-func checkKeyNodesSigns(msg []byte, sigs []cutypes.StdSignature, keyNodes []sdk.CUAddress) sdk.Result {
+func checkKeyNodesSigns(msg []byte, sigs []cutypes.StdSignature, keyNodes []sdk.CUAddress, threshold uint64) sdk.Result {
+	if uint64(len(keyNodes)) < threshold {
+		return sdk.ErrInsufficientValidatorNumForKeyGen(fmt.Sprintf("%d key nodes cannot reach sign threshold %d", len(keyNodes), threshold)).Result()
+	}
 	nodes := make(map[string]bool, len(keyNodes))
 	signedNodes := make(map[string]bool, len(keyNodes))
 	for _, node := range keyNodes {
@@ -633,7 +656,7 @@ func getFeeCoin(cutype sdk.CUType, ti *sdk.IBCToken) sdk.Coin {
 }
 
 func setAddressAndPubkeyToCU(ctx sdk.Context, cuAst exported.CUIBCAsset, keeper Keeper,
-	pubkey []byte, address string, symbol, chain string, epoch uint64) sdk.Result {
+	pubkey []byte, threshold uint64, address string, symbol, chain string, epoch uint64) sdk.Result {
 	if err := cuAst.SetAssetAddress(symbol, address, epoch); err != nil {
 		return sdk.ErrInternal(fmt.Sprintf("Set asset address error: %v", err)).Result()
 	}
@@ -646,6 +669,7 @@ func setAddressAndPubkeyToCU(ctx sdk.Context, cuAst exported.CUIBCAsset, keeper
 	if err := cuAst.SetAssetPubkey(pubkey, epoch); err != nil {
 		return sdk.ErrInternal(fmt.Sprintf("Set asset public key error: %v", err)).Result()
 	}
+	cuAst.SetAssetPubkeyThreshold(threshold)
 	keeper.ck.SetExtAddressWithCU(ctx, chain, address, cuAst.GetAddress())
 	keeper.ik.SetCUIBCAsset(ctx, cuAst)
 
@@ -709,3 +733,216 @@ func handleMsgNewOpCU(ctx sdk.Context, keeper Keeper, msg MsgNewOpCU) sdk.Result
 
 	return sdk.Result{}
 }
+
+func addDepositAddressToCU(ctx sdk.Context, cuAst exported.CUIBCAsset, keeper Keeper, address, chain string) sdk.Result {
+	if err := cuAst.AddDepositAddress(chain, address, cuAst.GetAssetPubkeyEpoch()); err != nil {
+		return sdk.ErrInternal(fmt.Sprintf("Add deposit address error: %v", err)).Result()
+	}
+	keeper.ck.SetExtAddressWithCU(ctx, chain, address, cuAst.GetAddress())
+	keeper.ik.SetCUIBCAsset(ctx, cuAst)
+
+	return sdk.Result{Code: sdk.CodeOK}
+}
+
+func handleMsgNewDepositAddress(ctx sdk.Context, keeper Keeper, msg MsgNewDepositAddress) sdk.Result {
+	ctx.Logger().Info("handleMsgNewDepositAddress", "msg", msg)
+	chain, fromAddr := msg.Chain, msg.From
+	ti := keeper.tk.GetIBCToken(ctx, chain)
+	if result := checkSymbol(chain, ti, keeper); !result.IsOK() {
+		return result
+	}
+	if ti.Chain != chain {
+		return sdk.ErrInvalidSymbol(fmt.Sprintf("%v is not a chain", chain)).Result()
+	}
+
+	cuAst := keeper.ik.GetCUIBCAsset(ctx, fromAddr)
+	if cuAst == nil || cuAst.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("CU %s is not a user CU", fromAddr.String())).Result()
+	}
+	epoch := cuAst.GetAssetPubkeyEpoch()
+	if cuAst.GetAssetAddress(chain.String(), epoch) == "" {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("CU %s does not have %v address", fromAddr.String(), chain)).Result()
+	}
+	if len(cuAst.GetDepositAddresses(chain.String())) >= MaxDepositAddresses {
+		return sdk.ErrInvalidTx(fmt.Sprintf("CU %s already has %d %v deposit addresses", fromAddr.String(), MaxDepositAddresses, chain)).Result()
+	}
+
+	if result := checkOrderID(ctx, msg.OrderID, keeper); !result.IsOK() {
+		return result
+	}
+	processOrderList := keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeKeyGen)
+	if exist, orderID := checkCuKeyGenOrder(ctx, keeper, processOrderList, fromAddr); exist {
+		return sdk.ErrInvalidTx(fmt.Sprintf("CU %s has unfinished keygen order %s", fromAddr.String(), orderID)).Result()
+	}
+
+	feeCoin := getFeeCoin(sdk.CUTypeUser, ti)
+	have := keeper.trk.GetBalance(ctx, fromAddr, feeCoin.Denom)
+	if have.LT(feeCoin.Amount) {
+		return sdk.ErrInsufficientFee(fmt.Sprintf("From CU %s does not have enough fee. has:%v, need:%v", fromAddr.String(), have, feeCoin.Amount)).Result()
+	}
+
+	// 先从预生成公钥中绑定, 预生成公钥的门限需满足链的门限
+	curEpoch := keeper.vk.GetCurrentEpoch(ctx)
+	threshold := keeper.getSignThreshold(ctx, chain.String(), len(curEpoch.KeyNodeSet))
+	waitAssignKeyGens := keeper.GetWaitAssignKeyGenOrderIDs(ctx)
+	for _, orderID := range waitAssignKeyGens {
+		order := keeper.ok.GetOrder(ctx, orderID)
+		if order.GetOrderType() != sdk.OrderTypeKeyGen || order.GetOrderStatus() != sdk.OrderStatusSignFinish {
+			ctx.Logger().Error("Unexpected order", "type", order.GetOrderType(), "status", order.GetOrderStatus())
+			keeper.DelWaitAssignKeyGenOrderID(ctx, orderID)
+			continue
+		}
+		keygenOrder := order.(*sdk.OrderKeyGen)
+		if keygenOrder.SignThreshold < uint64(threshold) {
+			continue
+		}
+		addr, err := keeper.cn.ConvertAddress(chain.String(), keygenOrder.Pubkey)
+		if err != nil {
+			ctx.Logger().Error("Convert address error", "chain", chain.String(), "pubkey", keygenOrder.Pubkey, "err", err)
+			keeper.DelWaitAssignKeyGenOrderID(ctx, orderID)
+			continue
+		}
+		if result := addDepositAddressToCU(ctx, cuAst, keeper, addr, chain.String()); !result.IsOK() {
+			return result
+		}
+		keygenOrder.CUAddress = fromAddr
+		keygenOrder.Symbol = chain.String()
+		keygenOrder.To = fromAddr
+		keygenOrder.OpenFee = feeCoin
+		keygenOrder.Status = sdk.OrderStatusFinish
+		keygenOrder.MultiSignAddress = addr
+		keygenOrder.DepositAddress = true
+		keeper.ok.SetOrder(ctx, keygenOrder)
+		keeper.DelWaitAssignKeyGenOrderID(ctx, orderID)
+
+		flows := make([]sdk.Flow, 0, 3)
+		orderflow := keeper.rk.NewOrderFlow(chain, fromAddr, orderID, sdk.OrderTypeKeyGen, sdk.OrderStatusFinish)
+		keyGenFinishFlow := sdk.KeyGenFinishFlow{OrderID: orderID, ToAddr: fromAddr.String(), IsPreKeyGen: true}
+		flows = append(flows, orderflow, keyGenFinishFlow)
+
+		if feeCoin.Amount.IsPositive() {
+			_, flow, err := keeper.trk.SubCoin(ctx, fromAddr, feeCoin)
+			if err != nil {
+				return err.Result()
+			}
+			flows = append(flows, flow)
+			keeper.dk.AddToFeePool(ctx, sdk.NewDecCoins(sdk.NewCoins(feeCoin)))
+		}
+
+		receipt := keeper.rk.NewReceipt(sdk.CategoryTypeKeyGen, flows)
+		result := sdk.Result{}
+		keeper.rk.SaveReceiptToResult(receipt, &result)
+		ctx.EventManager().EmitEvents(sdk.Events{
+			sdk.NewEvent(
+				types.EventTypeNewDepositAddress,
+				sdk.NewAttribute(types.AttributeKeyFrom, fromAddr.String()),
+				sdk.NewAttribute(types.AttributeKeySymbol, chain.String()),
+				sdk.NewAttribute(types.AttributeKeyOrderID, orderID),
+				sdk.NewAttribute(types.AttributeKeyAddress, addr),
+			),
+		})
+
+		result.Events = append(result.Events, ctx.EventManager().Events()...)
+		return result
+	}
+
+	// 没有预生成的公钥则直接 keygen
+	transferFlows, err := keeper.trk.LockCoin(ctx, fromAddr, feeCoin)
+	if err != nil {
+		return err.Result()
+	}
+
+	excludedKeyNode := keeper.getExcludedKeyNode(ctx, curEpoch.KeyNodeSet, threshold)
+	keynodes := make([]sdk.CUAddress, 0, len(curEpoch.KeyNodeSet))
+	for _, val := range curEpoch.KeyNodeSet {
+		if !val.Equals(excludedKeyNode) {
+			keynodes = append(keynodes, val)
+		}
+	}
+	if len(keynodes) == 0 {
+		return sdk.ErrInsufficientValidatorNumForKeyGen("empty keynode list").Result()
+	}
+	order := keeper.ok.NewOrderKeyGen(ctx, fromAddr, msg.OrderID, chain.String(), keynodes, uint64(threshold), fromAddr, feeCoin)
+	order.DepositAddress = true
+	keeper.ok.SetOrder(ctx, order)
+
+	flows := make([]sdk.Flow, 0, 3)
+	orderFlow := keeper.rk.NewOrderFlow(chain, fromAddr, msg.OrderID, sdk.OrderTypeKeyGen, sdk.OrderStatusBegin)
+	keyGenFlow := sdk.KeyGenFlow{OrderID: msg.OrderID, Symbol: chain, From: fromAddr, To: fromAddr, IsPreKeyGen: false, ExcludedKeyNode: excludedKeyNode}
+	flows = append(flows, orderFlow, keyGenFlow)
+	flows = append(flows, transferFlows...)
+
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeKeyGen, flows)
+	result := sdk.Result{}
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeKeyGen,
+			sdk.NewAttribute(types.AttributeKeyFrom, fromAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyTo, fromAddr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, chain.String()),
+			sdk.NewAttribute(types.AttributeKeyOrderID, msg.OrderID),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgRetireDepositAddress(ctx sdk.Context, keeper Keeper, msg MsgRetireDepositAddress) sdk.Result {
+	ctx.Logger().Info("handleMsgRetireDepositAddress", "msg", msg)
+	chain, fromAddr := msg.Chain, msg.From
+	ti := keeper.tk.GetIBCToken(ctx, chain)
+	if result := checkSymbol(chain, ti, keeper); !result.IsOK() {
+		return result
+	}
+
+	cuAst := keeper.ik.GetCUIBCAsset(ctx, fromAddr)
+	if cuAst == nil || cuAst.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("CU %s is not a user CU", fromAddr.String())).Result()
+	}
+	if sdk.StringsIndex(cuAst.GetDepositAddresses(chain.String()), msg.Address) < 0 {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("%s is not a deposit address of CU %s", msg.Address, fromAddr.String())).Result()
+	}
+	if !cuAst.IsEnabledSendTx(chain.String(), msg.Address) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("%s is being collected", msg.Address)).Result()
+	}
+
+	// deposits are confirmed once collected, others are still on the address
+	deposits := keeper.ik.GetDepositListByExtAddress(ctx, fromAddr, msg.Address)
+	for symbol, dls := range deposits {
+		for _, item := range dls {
+			if item.Status != sdk.DepositItemStatusConfirmed {
+				return sdk.ErrInvalidTx(fmt.Sprintf("%v deposit %v-%v to %s is not collected", symbol, item.Hash, item.Index, msg.Address)).Result()
+			}
+		}
+	}
+
+	// tokens on the chain share the address with the chain
+	assets := append([]sdk.Asset{}, cuAst.GetAssets()...)
+	for _, asset := range assets {
+		if !asset.DepositOnly || asset.Address != msg.Address {
+			continue
+		}
+		if asset.Denom != chain.String() {
+			if token := keeper.tk.GetIBCToken(ctx, sdk.Symbol(asset.Denom)); token == nil || token.Chain != chain {
+				continue
+			}
+		}
+		if err := cuAst.RemoveDepositAddress(asset.Denom, msg.Address); err != nil {
+			return sdk.ErrInternal(fmt.Sprintf("Remove deposit address error: %v", err)).Result()
+		}
+	}
+	keeper.ik.SetCUIBCAsset(ctx, cuAst)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeRetireDepositAddress,
+			sdk.NewAttribute(types.AttributeKeyFrom, fromAddr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, chain.String()),
+			sdk.NewAttribute(types.AttributeKeyAddress, msg.Address),
+		),
+	})
+
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
@@ -52,6 +52,7 @@ type IBCAssetKeeper interface {
 	GetCUIBCAsset(context sdk.Context, addresses sdk.CUAddress) ibcexported.CUIBCAsset
 	NewCUIBCAssetWithAddress(ctx sdk.Context, cuType sdk.CUType, cuaddr sdk.CUAddress) ibcexported.CUIBCAsset
 	SetCUIBCAsset(ctx sdk.Context, cuAst ibcexported.CUIBCAsset)
+	GetDepositListByExtAddress(ctx sdk.Context, address sdk.CUAddress, extAddress string) map[string]sdk.DepositList
 }
 
 type ReceiptKeeper interface {
@@ -65,8 +66,13 @@ type ReceiptKeeper interface {
 	NewBalanceFlow(cuAddress sdk.CUAddress, symbol sdk.Symbol, orderID string, previousBalance,
 		balanceChange, previousBalanceOnHold, balanceOnHoldChange sdk.Int) sdk.BalanceFlow
 
+	// NewOrderRetryFlow creates a new order retry flow
+	NewOrderRetryFlow(orderIDs []string, excludedKeyNode sdk.CUAddress) sdk.OrderRetryFlow
+
 	// SaveReceiptToResult saves the receipt into a result.
 	SaveReceiptToResult(receipt *sdk.Receipt, result *sdk.Result) *sdk.Result
+	// SaveReceiptToEvents emits the receipt as an event.
+	SaveReceiptToEvents(receipt *sdk.Receipt, em *sdk.EventManager)
 
 	GetReceiptFromResult(result *sdk.Result) (*sdk.Receipt, error)
 }
@@ -75,6 +81,8 @@ type StakingKeeper interface {
 	GetValidator(ctx sdk.Context, addr sdk.ValAddress) (validator types.Validator, found bool)
 	GetEpochByHeight(ctx sdk.Context, height uint64) sdk.Epoch
 	GetCurrentEpoch(ctx sdk.Context) sdk.Epoch
+	StartNewEpoch(ctx sdk.Context, vals []sdk.CUAddress) sdk.Epoch
+	AfterNewEpoch(ctx sdk.Context, epoch sdk.Epoch)
 }
 
 type DistributionKeeper interface {
@@ -86,4 +94,9 @@ type TransferKeeper interface {
 	SubCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) (sdk.Coin, sdk.Flow, sdk.Error)
 	SubCoinHold(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) (sdk.Coin, sdk.Flow, sdk.Error)
 	LockCoin(ctx sdk.Context, addr sdk.CUAddress, amt sdk.Coin) ([]sdk.Flow, sdk.Error)
+	UnlockCoin(ctx sdk.Context, addr sdk.CUAddress, amt sdk.Coin) ([]sdk.Flow, sdk.Error)
+}
+
+type EvidenceKeeper interface {
+	HandleBehaviour(ctx sdk.Context, behaviourKey string, validator sdk.ValAddress, height uint64, normal bool)
 }
@@ -19,6 +19,7 @@ type Keeper struct {
 	vk       internal.StakingKeeper
 	dk       internal.DistributionKeeper
 	trk      internal.TransferKeeper
+	ek       internal.EvidenceKeeper
 	cn       chainnode.Chainnode
 }
 
@@ -39,6 +40,10 @@ func NewKeeper(store sdk.StoreKey, cdc *codec.Codec, tk internal.TokenKeeper, ck
 	}
 }
 
+func (k *Keeper) SetEvidenceKeeper(evidenceKeeper internal.EvidenceKeeper) {
+	k.ek = evidenceKeeper
+}
+
 func (k *Keeper) GetWaitAssignKeyGenOrderIDs(ctx sdk.Context) []string {
 	orderIDs := []string{}
 	store := ctx.KVStore(k.storeKey)
@@ -97,7 +102,6 @@ func (k *Keeper) resetKeyGenOrders(ctx sdk.Context, epoch sdk.Epoch) {
 	for i, val := range epoch.KeyNodeSet {
 		keyNodes[i] = val
 	}
-	threshold := uint64(sdk.Majority23(len(keyNodes)))
 	for _, orderID := range k.ok.GetProcessOrderList(ctx) {
 		order := k.ok.GetOrder(ctx, orderID)
 		if order == nil || order.GetOrderType() != sdk.OrderTypeKeyGen {
@@ -108,13 +112,18 @@ func (k *Keeper) resetKeyGenOrders(ctx sdk.Context, epoch sdk.Epoch) {
 		}
 		keygenOrder := order.(*sdk.OrderKeyGen)
 		keygenOrder.KeyNodes = keyNodes
-		keygenOrder.SignThreshold = threshold
+		keygenOrder.SignThreshold = uint64(k.getSignThreshold(ctx, keygenOrder.Symbol, len(keyNodes)))
+		keygenOrder.StageHeight = uint64(ctx.BlockHeight())
 		keygenOrder.SetOrderStatus(sdk.OrderStatusBegin)
 		k.ok.SetOrder(ctx, keygenOrder)
 	}
 }
 
-func (k *Keeper) getExcludedKeyNode(ctx sdk.Context, keyNodes []sdk.CUAddress) sdk.CUAddress {
+// getExcludedKeyNode returns the key node without heartbeat for the longest time, unless the others would not reach threshold
+func (k *Keeper) getExcludedKeyNode(ctx sdk.Context, keyNodes []sdk.CUAddress, threshold int) sdk.CUAddress {
+	if len(keyNodes) <= threshold {
+		return nil
+	}
 	var excluded sdk.CUAddress
 	var longest int64
 	blkHeight := ctx.BlockHeight()
@@ -131,6 +140,16 @@ func (k *Keeper) getExcludedKeyNode(ctx sdk.Context, keyNodes []sdk.CUAddress) s
 	return nil
 }
 
+// getSignThreshold returns the number of key nodes out of keyNodeNum required to sign for the keys of symbol's chain
+func (k *Keeper) getSignThreshold(ctx sdk.Context, symbol string, keyNodeNum int) int {
+	if ti := k.tk.GetIBCToken(ctx, sdk.Symbol(symbol)); ti != nil {
+		if chainToken := k.tk.GetIBCToken(ctx, ti.Chain); chainToken != nil {
+			return chainToken.GetKeyNodeThreshold(keyNodeNum)
+		}
+	}
+	return sdk.Majority23(keyNodeNum)
+}
+
 func waitAssignKey() []byte {
 	return types.WaitAssignKey
 }
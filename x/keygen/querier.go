@@ -1,6 +1,8 @@
 package keygen
 
 import (
+	"fmt"
+
 	"github.com/hbtc-chain/bhchain/codec"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/x/keygen/types"
@@ -13,6 +15,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case types.QueryWaitAssignKeys:
 			return queryWaitAssignKeys(ctx, req, keeper)
+		case types.QueryKeyRotation:
+			return queryKeyRotation(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown order query endpoint")
 		}
@@ -27,3 +31,26 @@ func queryWaitAssignKeys(ctx sdk.Context, req abci.RequestQuery, keeper Keeper)
 	}
 	return res, nil
 }
+
+func queryKeyRotation(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryKeyRotationParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	var statuses []types.KeyRotationStatus
+	if params.Chain == "" {
+		statuses = keeper.GetKeyRotationStatuses(ctx)
+	} else {
+		status, found := keeper.GetKeyRotationStatus(ctx, params.Chain)
+		if !found {
+			return nil, sdk.ErrInvalidSymbol(fmt.Sprintf("%s is not a chain", params.Chain))
+		}
+		statuses = append(statuses, status)
+	}
+	res, err := codec.MarshalJSONIndent(keeper.cdc, statuses)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+	return res, nil
+}
@@ -0,0 +1,188 @@
+package keygen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+)
+
+// OPCU keys are generated for an epoch, the keys of every chain are rotated by starting
+// a new epoch with the same key nodes, which is then migrated like a key node change:
+// MsgOpcuMigrationKeyGen generates the new keys and OpcuAssetTransfer sweeps the assets.
+// As the epoch is shared, the keys of all chains are migrated together, so the chains
+// rotating their keys share a single interval, checked by the token params proposal, and
+// the schedule of all of them restarts from the height of the rotation.
+// The keys of a chain are also rotated once its key node threshold is raised above the
+// threshold they were generated with, until then the keys of hot OPCUs keep signing with their own
+// threshold, while cold OPCUs, which hold the large withdrawals, do not sign.
+
+// rotateKeys rotates the OPCU keys once the key rotation interval of a chain is elapsed,
+// or the key node threshold of a chain is raised
+func (k Keeper) rotateKeys(ctx sdk.Context) {
+	statuses := k.GetKeyRotationStatuses(ctx)
+	k.trackKeyRotationHeights(ctx, statuses)
+
+	curEpoch := k.vk.GetCurrentEpoch(ctx)
+	if !curEpoch.MigrationFinished {
+		return
+	}
+
+	var chains []string
+	for _, status := range statuses {
+		due := status.NextRotationHeight != 0 && uint64(ctx.BlockHeight()) >= status.NextRotationHeight
+		if due || k.hasKeyBelowThreshold(ctx, status, len(curEpoch.KeyNodeSet)) {
+			chains = append(chains, status.Chain)
+		}
+	}
+	if len(chains) == 0 {
+		return
+	}
+
+	epoch := k.vk.StartNewEpoch(ctx, curEpoch.KeyNodeSet)
+	k.vk.AfterNewEpoch(ctx, epoch)
+	for _, status := range statuses {
+		if status.Interval != 0 {
+			k.setLastKeyRotationHeight(ctx, status.Chain, uint64(ctx.BlockHeight()))
+		}
+	}
+	ctx.Logger().Info("rotate opcu keys", "chains", chains, "epoch", epoch.Index)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeKeyRotation,
+			sdk.NewAttribute(types.AttributeKeyChain, strings.Join(chains, ",")),
+			sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", epoch.Index)),
+		),
+	)
+}
+
+// GetKeyRotationStatus returns the key rotation status of chain
+func (k Keeper) GetKeyRotationStatus(ctx sdk.Context, chain string) (types.KeyRotationStatus, bool) {
+	ti := k.tk.GetIBCToken(ctx, sdk.Symbol(chain))
+	if ti == nil || ti.Symbol != ti.Chain {
+		return types.KeyRotationStatus{}, false
+	}
+	status := k.newKeyRotationStatus(ctx, ti)
+	for _, opCU := range k.ck.GetOpCUs(ctx, "") {
+		symbol := opCU.GetSymbol()
+		if opCUToken := k.tk.GetIBCToken(ctx, sdk.Symbol(symbol)); opCUToken == nil || opCUToken.Chain != ti.Chain {
+			continue
+		}
+		status.AddOpCU(k.opCUMigrationStatus(ctx, opCU.GetAddress(), symbol))
+	}
+	return status, true
+}
+
+// GetKeyRotationStatuses returns the key rotation status of the chains with OPCUs, sorted by chain
+func (k Keeper) GetKeyRotationStatuses(ctx sdk.Context) []types.KeyRotationStatus {
+	statuses := make(map[string]*types.KeyRotationStatus)
+	for _, opCU := range k.ck.GetOpCUs(ctx, "") {
+		symbol := opCU.GetSymbol()
+		ti := k.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+		if ti == nil {
+			continue
+		}
+		chain := ti.Chain.String()
+		status, ok := statuses[chain]
+		if !ok {
+			chainToken := k.tk.GetIBCToken(ctx, ti.Chain)
+			if chainToken == nil {
+				continue
+			}
+			s := k.newKeyRotationStatus(ctx, chainToken)
+			status = &s
+			statuses[chain] = status
+		}
+		status.AddOpCU(k.opCUMigrationStatus(ctx, opCU.GetAddress(), symbol))
+	}
+
+	res := make([]types.KeyRotationStatus, 0, len(statuses))
+	for _, status := range statuses {
+		res = append(res, *status)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Chain < res[j].Chain })
+	return res
+}
+
+func (k Keeper) newKeyRotationStatus(ctx sdk.Context, chainToken *sdk.IBCToken) types.KeyRotationStatus {
+	curEpoch := k.vk.GetCurrentEpoch(ctx)
+	status := types.KeyRotationStatus{
+		Chain:              chainToken.Chain.String(),
+		Interval:           chainToken.KeyRotationInterval,
+		Epoch:              curEpoch.Index,
+		LastRotationHeight: curEpoch.StartBlockNum,
+		MigrationFinished:  true,
+	}
+	if height, ok := k.getLastKeyRotationHeight(ctx, status.Chain); ok {
+		status.LastRotationHeight = height
+	}
+	if status.Interval != 0 {
+		status.NextRotationHeight = status.LastRotationHeight + status.Interval
+	}
+	return status
+}
+
+// trackKeyRotationHeights keeps the schedule of the chains whose rotation is enabled, starting from the height
+// the current keys are generated at, and drops the schedule of the chains whose rotation is disabled
+func (k Keeper) trackKeyRotationHeights(ctx sdk.Context, statuses []types.KeyRotationStatus) {
+	for _, status := range statuses {
+		_, ok := k.getLastKeyRotationHeight(ctx, status.Chain)
+		if status.Interval == 0 && ok {
+			ctx.KVStore(k.storeKey).Delete(types.KeyRotationHeightKey(status.Chain))
+		} else if status.Interval != 0 && !ok {
+			k.setLastKeyRotationHeight(ctx, status.Chain, status.LastRotationHeight)
+		}
+	}
+}
+
+// hasKeyBelowThreshold returns whether an OPCU key of the chain is generated with a threshold below the
+// current key node threshold of the chain. Keys generated before their threshold was recorded have
+// a threshold of 0, which stands for the 2/3 majority of the key nodes.
+func (k Keeper) hasKeyBelowThreshold(ctx sdk.Context, status types.KeyRotationStatus, keyNodeNum int) bool {
+	chainToken := k.tk.GetIBCToken(ctx, sdk.Symbol(status.Chain))
+	if chainToken == nil {
+		return false
+	}
+	required := uint64(chainToken.GetKeyNodeThreshold(keyNodeNum))
+	for _, opCU := range status.OpCUs {
+		opCUAst := k.ik.GetCUIBCAsset(ctx, opCU.Address)
+		if opCUAst == nil || len(opCUAst.GetAssetPubkey(opCUAst.GetAssetPubkeyEpoch())) == 0 {
+			continue
+		}
+		threshold := opCUAst.GetAssetPubkeyThreshold()
+		if threshold == 0 {
+			threshold = uint64(sdk.Majority23(keyNodeNum))
+		}
+		if threshold < required {
+			return true
+		}
+	}
+	return false
+}
+
+func (k Keeper) getLastKeyRotationHeight(ctx sdk.Context, chain string) (uint64, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.KeyRotationHeightKey(chain))
+	if len(bz) == 0 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(bz), true
+}
+
+func (k Keeper) setLastKeyRotationHeight(ctx sdk.Context, chain string, height uint64) {
+	ctx.KVStore(k.storeKey).Set(types.KeyRotationHeightKey(chain), sdk.Uint64ToBigEndian(height))
+}
+
+func (k Keeper) opCUMigrationStatus(ctx sdk.Context, addr sdk.CUAddress, symbol string) types.OpCUMigrationStatus {
+	status := types.OpCUMigrationStatus{
+		Address:         addr,
+		Symbol:          symbol,
+		MigrationStatus: sdk.MigrationFinish,
+	}
+	if opCUAst := k.ik.GetCUIBCAsset(ctx, addr); opCUAst != nil {
+		status.MigrationStatus = opCUAst.GetMigrationStatus()
+	}
+	return status
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -18,6 +19,7 @@ import (
 	"github.com/hbtc-chain/bhchain/x/custodianunit/exported"
 	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
 	"github.com/hbtc-chain/bhchain/x/distribution"
+	"github.com/hbtc-chain/bhchain/x/evidence"
 	"github.com/hbtc-chain/bhchain/x/ibcasset"
 	"github.com/hbtc-chain/bhchain/x/keygen"
 	"github.com/hbtc-chain/bhchain/x/keygen/types"
@@ -330,7 +332,8 @@ func TestHandleMsgKeyGen(t *testing.T) {
 			OrderType: sdk.OrderTypeKeyGen,
 			Status:    sdk.OrderStatusSignFinish,
 		},
-		Pubkey: pubkey.Bytes(),
+		SignThreshold: 2,
+		Pubkey:        pubkey.Bytes(),
 	}
 	input.Ok.SetOrder(ctx, preKeyGenOrder)
 	keygenkeeper.AddWaitAssignKeyGenOrderID(ctx, preKeyGenOrderID)
@@ -569,6 +572,109 @@ func TestHandleMsgKeyGenFinish(t *testing.T) {
 	assert.True(t, originHold.Equal(trk.GetAllHoldBalance(ctx, cu.GetAddress()).AmountOf(sdk.NativeToken)))
 }
 
+func TestHandleMsgNewDepositAddress(t *testing.T) {
+	input := SetupTestInput()
+	ctx := input.Ctx
+	cuKeeper := input.Ck.(custodianunit.CUKeeper)
+	keygenkeeper := input.Kk
+	trk := input.Trk
+	ik := input.Ik
+
+	userAddr := sdk.NewCUAddress()
+	cuKeeper.SetCU(ctx, cuKeeper.NewCUWithAddress(ctx, sdk.CUTypeUser, userAddr))
+	cuAsset := ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeUser, userAddr)
+	ik.SetCUIBCAsset(ctx, cuAsset)
+	epoch := input.Sk.GetCurrentEpoch(ctx).Index
+
+	// no address of the chain yet
+	msg := types.NewMsgNewDepositAddress(uuid.NewV4().String(), sdk.Symbol(ethToken), userAddr)
+	res := keygen.HandleMsgNewDepositAddressForTest(ctx, keygenkeeper, msg)
+	assert.False(t, res.IsOK())
+
+	cuAsset.SetAssetAddress(ethToken, "0xprimary", epoch)
+	cuAsset.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), epoch)
+	ik.SetCUIBCAsset(ctx, cuAsset)
+
+	// not a chain
+	msg = types.NewMsgNewDepositAddress(uuid.NewV4().String(), sdk.Symbol(usdtToken), userAddr)
+	res = keygen.HandleMsgNewDepositAddressForTest(ctx, keygenkeeper, msg)
+	assert.False(t, res.IsOK())
+
+	// insufficient open fee
+	msg = types.NewMsgNewDepositAddress(uuid.NewV4().String(), sdk.Symbol(ethToken), userAddr)
+	res = keygen.HandleMsgNewDepositAddressForTest(ctx, keygenkeeper, msg)
+	assert.False(t, res.IsOK())
+
+	trk.AddCoins(ctx, userAddr, sdk.NewCoins(sdk.NewCoin(sdk.NativeToken, sdk.NewIntWithDecimal(1, 20))))
+	originCoin := trk.GetAllBalance(ctx, userAddr).AmountOf(sdk.NativeToken)
+	openFee := input.Tk.GetIBCToken(ctx, sdk.Symbol(ethToken)).OpenFee
+	res = keygen.HandleMsgNewDepositAddressForTest(ctx, keygenkeeper, msg)
+	assert.True(t, res.IsOK())
+	assert.Equal(t, openFee, trk.GetAllHoldBalance(ctx, userAddr).AmountOf(sdk.NativeToken))
+	keygenOrder := input.Ok.GetOrder(ctx, msg.OrderID).(*sdk.OrderKeyGen)
+	assert.Equal(t, sdk.OrderStatusBegin, keygenOrder.GetOrderStatus())
+	assert.True(t, keygenOrder.DepositAddress)
+	assert.Equal(t, userAddr, keygenOrder.To)
+
+	// only one keygen order at a time
+	res = keygen.HandleMsgNewDepositAddressForTest(ctx, keygenkeeper, types.NewMsgNewDepositAddress(uuid.NewV4().String(), sdk.Symbol(ethToken), userAddr))
+	assert.False(t, res.IsOK())
+
+	// wait sign, the primary address does not block it
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	pubkey := crypto.FromECDSAPub(&key.PublicKey)
+	keyNodes := []sdk.CUAddress{validatorAddr1, validatorAddr2}
+	signMsg := types.NewMsgKeyGenWaitSign(validatorAddr1, msg.OrderID, pubkey, keyNodes, []cutypes.StdSignature{}, epoch)
+	sig1, _ := validatorPriv1.Sign(signMsg.GetSignBytes())
+	sig2, _ := validatorPriv2.Sign(signMsg.GetSignBytes())
+	waitSignMsg := types.NewMsgKeyGenWaitSign(validatorAddr1, msg.OrderID, pubkey, keyNodes,
+		[]cutypes.StdSignature{{Signature: sig1, PubKey: validatorPriv1.PubKey()}, {Signature: sig2, PubKey: validatorPriv2.PubKey()}}, epoch)
+	res = keygen.HandleMsgKeyGenWaitSignForTest(ctx, keygenkeeper, waitSignMsg)
+	assert.True(t, res.IsOK())
+
+	// finish
+	input.ChainNode.On("ConvertAddressFromSerializedPubKey", ethToken, pubkey).Return("0xextra", nil)
+	sig, err := crypto.Sign(sdk.BytesToHash(pubkey).Bytes(), key)
+	assert.Nil(t, err)
+	res = keygen.HandleMsgKeyGenFinishForTest(ctx, keygenkeeper, *types.NewMsgKeyGenFinish(msg.OrderID, sig, validatorAddr1))
+	assert.True(t, res.IsOK())
+	keygenOrder = input.Ok.GetOrder(ctx, msg.OrderID).(*sdk.OrderKeyGen)
+	assert.Equal(t, sdk.OrderStatusFinish, keygenOrder.GetOrderStatus())
+	assert.Equal(t, "0xextra", keygenOrder.MultiSignAddress)
+	assert.True(t, trk.GetAllHoldBalance(ctx, userAddr).AmountOf(sdk.NativeToken).IsZero())
+	assert.Equal(t, originCoin.Sub(openFee), trk.GetAllBalance(ctx, userAddr).AmountOf(sdk.NativeToken))
+
+	cuAsset = ik.GetCUIBCAsset(ctx, userAddr)
+	assert.Equal(t, "0xprimary", cuAsset.GetAssetAddress(ethToken, epoch))
+	assert.Equal(t, []string{"0xextra"}, cuAsset.GetDepositAddresses(ethToken))
+	assert.True(t, cuAsset.GetAssetByAddr(ethToken, "0xextra").DepositOnly)
+	assert.Equal(t, cuAsset.GetAssetPubkeyEpoch(), cuAsset.GetAssetByAddr(ethToken, "0xextra").Epoch)
+	extCU, err := cuKeeper.GetCUFromExtAddress(ctx, ethToken, "0xextra")
+	assert.Nil(t, err)
+	assert.Equal(t, userAddr, extCU)
+
+	// the address of the epoch can not be retired
+	res = keygen.HandleMsgRetireDepositAddressForTest(ctx, keygenkeeper, types.NewMsgRetireDepositAddress(sdk.Symbol(ethToken), "0xprimary", userAddr))
+	assert.False(t, res.IsOK())
+
+	// deposit is not collected
+	item, err := sdk.NewDepositItem("depositHash", 0, sdk.NewInt(100), "0xextra", "", sdk.DepositItemStatusWaitCollect)
+	assert.Nil(t, err)
+	ik.SaveDeposit(ctx, usdtToken, userAddr, item)
+	retireMsg := types.NewMsgRetireDepositAddress(sdk.Symbol(ethToken), "0xextra", userAddr)
+	res = keygen.HandleMsgRetireDepositAddressForTest(ctx, keygenkeeper, retireMsg)
+	assert.False(t, res.IsOK())
+
+	ik.SetDepositStatus(ctx, usdtToken, userAddr, "depositHash", 0, sdk.DepositItemStatusConfirmed)
+	res = keygen.HandleMsgRetireDepositAddressForTest(ctx, keygenkeeper, retireMsg)
+	assert.True(t, res.IsOK())
+	cuAsset = ik.GetCUIBCAsset(ctx, userAddr)
+	assert.Equal(t, sdk.NilAsset, cuAsset.GetAssetByAddr(ethToken, "0xextra"))
+	assert.Equal(t, 0, len(cuAsset.GetDepositAddresses(ethToken)))
+	assert.Equal(t, "0xprimary", cuAsset.GetAssetAddress(ethToken, epoch))
+}
+
 func newTestKeyGenOrder(input testInput, msg types.MsgKeyGenWaitSign, to sdk.CUAddress) *sdk.OrderKeyGen {
 	ordBase := sdk.OrderBase{
 		CUAddress: keygenFromAddr,
@@ -582,7 +688,7 @@ func newTestKeyGenOrder(input testInput, msg types.MsgKeyGenWaitSign, to sdk.CUA
 	orderKeyGen := sdk.OrderKeyGen{
 		OrderBase:        ordBase,
 		KeyNodes:         msg.KeyNodes,
-		SignThreshold:    3,
+		SignThreshold:    uint64(sdk.Majority23(len(msg.KeyNodes))),
 		To:               to,
 		MultiSignAddress: "",
 		OpenFee:          sdk.NewCoin(sdk.NativeToken, tokenInfo.OpenFee),
@@ -618,6 +724,7 @@ func SetupTestInput() testInput {
 	transferKey := sdk.NewKVStoreKey(transfer.StoreKey)
 	distrKey := sdk.NewKVStoreKey(distribution.StoreKey)
 	keyIbcAsset := sdk.NewKVStoreKey(ibcasset.StoreKey)
+	evidenceKey := sdk.NewKVStoreKey(evidence.StoreKey)
 
 	pk := params.NewKeeper(cdc, keyParams, tkeyParams, params.DefaultCodespace)
 
@@ -634,6 +741,7 @@ func SetupTestInput() testInput {
 	ms.MountStoreWithDB(distrKey, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyIbcAsset, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(transferKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(evidenceKey, sdk.StoreTypeIAVL, db)
 	ms.LoadLatestVersion()
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "test-chain-id"}, false, log.NewNopLogger())
@@ -678,6 +786,9 @@ func SetupTestInput() testInput {
 		custodianunit.FeeCollectorName, nil)
 
 	kk := keygen.NewKeeper(keygenKey, cdc, &tk, ck, ik, &ok, rk, stakingK, distrKeeper, transferK, chainnode)
+	evidenceKeeper := evidence.NewKeeper(cdc, evidenceKey, pk.Subspace(evidence.DefaultParamspace), &stakingK)
+	evidence.InitGenesis(ctx, evidenceKeeper, evidence.DefaultGenesisState())
+	kk.SetEvidenceKeeper(evidenceKeeper)
 
 	ck.SetParams(ctx, cutypes.DefaultParams())
 	//init token info
@@ -700,7 +811,7 @@ func SetupTestInput() testInput {
 	feePool.CommunityPool = sdk.DecCoins{}
 	distrKeeper.SetFeePool(ctx, feePool)
 
-	return testInput{Cdc: cdc, Ctx: ctx, Ck: ck, Kk: kk, Tk: tk, Ok: ok, Rk: *rk, Sk: stakingK, Dk: distrKeeper, Trk: transferK, Ik: ik, ChainNode: chainnode}
+	return testInput{Cdc: cdc, Ctx: ctx, Ck: ck, Kk: kk, Tk: tk, Ok: ok, Rk: *rk, Sk: stakingK, Dk: distrKeeper, Trk: transferK, Ik: ik, Ek: evidenceKeeper, ChainNode: chainnode}
 }
 
 type testInput struct {
@@ -715,5 +826,6 @@ type testInput struct {
 	Dk        distribution.Keeper
 	Trk       transfer.Keeper
 	Ik        ibcasset.Keeper
+	Ek        evidence.Keeper
 	ChainNode *chainnode.MockChainnode
 }
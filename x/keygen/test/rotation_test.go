@@ -0,0 +1,148 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/keygen"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+)
+
+func TestKeyRotation(t *testing.T) {
+	input := SetupTestInput()
+	ctx := input.Ctx
+	kk := input.Kk
+
+	for _, symbol := range []string{ethToken, usdtToken, btcToken} {
+		opCU := input.Ck.NewOpCUWithAddress(ctx, symbol, sdk.NewCUAddress())
+		input.Ck.SetCU(ctx, opCU)
+		opCUAst := input.Ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeOp, opCU.GetAddress())
+		opCUAst.SetMigrationStatus(sdk.MigrationFinish)
+		input.Ik.SetCUIBCAsset(ctx, opCUAst)
+	}
+
+	statuses := kk.GetKeyRotationStatuses(ctx)
+	require.Len(t, statuses, 2)
+	require.Equal(t, btcToken, statuses[0].Chain)
+	require.Equal(t, ethToken, statuses[1].Chain)
+	require.Len(t, statuses[1].OpCUs, 2)
+	require.Equal(t, uint64(0), statuses[1].NextRotationHeight)
+
+	// rotation disabled
+	keygen.EndBlocker(ctx.WithBlockHeight(1000), kk)
+	require.Equal(t, uint64(1), input.Sk.GetCurrentEpoch(ctx.WithBlockHeight(1001)).Index)
+
+	ethInfo := input.Tk.GetIBCToken(ctx, sdk.Symbol(ethToken))
+	ethInfo.KeyRotationInterval = 1000
+	input.Tk.SetToken(ctx, ethInfo)
+	status, found := kk.GetKeyRotationStatus(ctx, ethToken)
+	require.True(t, found)
+	require.Equal(t, uint64(1), status.LastRotationHeight)
+	require.Equal(t, uint64(1001), status.NextRotationHeight)
+	require.Len(t, status.OpCUs, 2)
+	_, found = kk.GetKeyRotationStatus(ctx, usdtToken)
+	require.False(t, found)
+
+	// btc shares the interval
+	btcInfo := input.Tk.GetIBCToken(ctx, sdk.Symbol(btcToken))
+	btcInfo.KeyRotationInterval = 1000
+	input.Tk.SetToken(ctx, btcInfo)
+
+	// not due yet
+	keygen.EndBlocker(ctx.WithBlockHeight(1000), kk)
+	require.Equal(t, uint64(1), input.Sk.GetCurrentEpoch(ctx.WithBlockHeight(1001)).Index)
+
+	ctx = ctx.WithBlockHeight(1100).WithEventManager(sdk.NewEventManager())
+	keygen.EndBlocker(ctx, kk)
+	require.Len(t, ctx.EventManager().Events(), 1)
+	require.Equal(t, types.EventTypeKeyRotation, ctx.EventManager().Events()[0].Type)
+
+	ctx = ctx.WithBlockHeight(1101)
+	epoch := input.Sk.GetCurrentEpoch(ctx)
+	require.Equal(t, uint64(2), epoch.Index)
+	require.False(t, epoch.MigrationFinished)
+	require.Equal(t, []sdk.CUAddress{validatorAddr1, validatorAddr2}, epoch.KeyNodeSet)
+	// the new epoch starts the migration of all OPCUs
+	for _, opCU := range input.Ck.GetOpCUs(ctx, "") {
+		opCUAst := input.Ik.GetCUIBCAsset(ctx, opCU.GetAddress())
+		opCUAst.SetMigrationStatus(sdk.MigrationBegin)
+		input.Ik.SetCUIBCAsset(ctx, opCUAst)
+	}
+	status, _ = kk.GetKeyRotationStatus(ctx, ethToken)
+	require.Equal(t, uint64(2), status.Epoch)
+	require.Equal(t, uint64(1100), status.LastRotationHeight)
+	require.Equal(t, uint64(2100), status.NextRotationHeight)
+	require.False(t, status.MigrationFinished)
+	// the keys of all chains are rotated, so is their schedule
+	btcStatus, _ := kk.GetKeyRotationStatus(ctx, btcToken)
+	require.Equal(t, uint64(1100), btcStatus.LastRotationHeight)
+	require.Equal(t, uint64(2100), btcStatus.NextRotationHeight)
+	require.False(t, btcStatus.MigrationFinished)
+
+	// the migration status is of the chain's OPCUs
+	btcOpCUAst := input.Ik.GetCUIBCAsset(ctx, btcStatus.OpCUs[0].Address)
+	btcOpCUAst.SetMigrationStatus(sdk.MigrationFinish)
+	input.Ik.SetCUIBCAsset(ctx, btcOpCUAst)
+	btcStatus, _ = kk.GetKeyRotationStatus(ctx, btcToken)
+	require.True(t, btcStatus.MigrationFinished)
+	status, _ = kk.GetKeyRotationStatus(ctx, ethToken)
+	require.False(t, status.MigrationFinished)
+
+	// no rotation during a migration
+	keygen.EndBlocker(ctx.WithBlockHeight(2200), kk)
+	require.Equal(t, uint64(2), input.Sk.GetCurrentEpoch(ctx.WithBlockHeight(2201)).Index)
+
+	querier := keygen.NewQuerier(kk)
+	req := abci.RequestQuery{Data: input.Cdc.MustMarshalJSON(types.NewQueryKeyRotationParams(ethToken))}
+	bz, err := querier(ctx, []string{types.QueryKeyRotation}, req)
+	require.Nil(t, err)
+	var res []types.KeyRotationStatus
+	input.Cdc.MustUnmarshalJSON(bz, &res)
+	require.Equal(t, []types.KeyRotationStatus{status}, res)
+
+	req.Data = input.Cdc.MustMarshalJSON(types.NewQueryKeyRotationParams(usdtToken))
+	_, err = querier(ctx, []string{types.QueryKeyRotation}, req)
+	require.NotNil(t, err)
+}
+
+func TestKeyRotationOnThresholdRaise(t *testing.T) {
+	input := SetupTestInput()
+	ctx := input.Ctx
+	kk := input.Kk
+
+	keyNodes := []sdk.CUAddress{validatorAddr1, validatorAddr2,
+		sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address()), sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())}
+	input.Sk.StartNewEpoch(ctx.WithBlockHeight(0), keyNodes)
+
+	opCU := input.Ck.NewOpCUWithAddress(ctx, ethToken, sdk.NewCUAddress())
+	input.Ck.SetCU(ctx, opCU)
+	opCUAst := input.Ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeOp, opCU.GetAddress())
+	opCUAst.SetMigrationStatus(sdk.MigrationFinish)
+	require.Nil(t, opCUAst.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), 1))
+	opCUAst.SetAssetPubkeyThreshold(4)
+	input.Ik.SetCUIBCAsset(ctx, opCUAst)
+
+	ethInfo := input.Tk.GetIBCToken(ctx, sdk.Symbol(ethToken))
+	ethInfo.KeyNodeThreshold = 100
+	input.Tk.SetToken(ctx, ethInfo)
+
+	// the key already meets the threshold
+	keygen.EndBlocker(ctx.WithBlockHeight(100), kk)
+	require.Equal(t, uint64(1), input.Sk.GetCurrentEpoch(ctx.WithBlockHeight(101)).Index)
+
+	// a key without recorded threshold has the 2/3 majority
+	opCUAst.SetAssetPubkeyThreshold(0)
+	input.Ik.SetCUIBCAsset(ctx, opCUAst)
+	ctx = ctx.WithBlockHeight(200).WithEventManager(sdk.NewEventManager())
+	keygen.EndBlocker(ctx, kk)
+	require.Len(t, ctx.EventManager().Events(), 1)
+	require.Equal(t, types.AttributeKeyChain, string(ctx.EventManager().Events()[0].Attributes[0].Key))
+	require.Equal(t, ethToken, string(ctx.EventManager().Events()[0].Attributes[0].Value))
+	epoch := input.Sk.GetCurrentEpoch(ctx.WithBlockHeight(201))
+	require.Equal(t, uint64(2), epoch.Index)
+	require.Equal(t, keyNodes, epoch.KeyNodeSet)
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
+	"github.com/hbtc-chain/bhchain/x/keygen"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+	stakingtypes "github.com/hbtc-chain/bhchain/x/staking/types"
+)
+
+func TestKeyNodeThreshold(t *testing.T) {
+	input := SetupTestInput()
+	ctx := input.Ctx
+	kk := input.Kk
+
+	keyNodes := []sdk.CUAddress{validatorAddr1, validatorAddr2,
+		sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address()), sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())}
+	for _, addr := range keyNodes {
+		input.Sk.SetValidator(ctx, stakingtypes.NewValidator(sdk.ValAddress(addr), ed25519.GenPrivKey().PubKey(), stakingtypes.Description{}))
+	}
+	epoch := input.Sk.StartNewEpoch(ctx, keyNodes)
+	ctx = ctx.WithBlockHeight(2)
+
+	ethInfo := input.Tk.GetIBCToken(ctx, sdk.Symbol(ethToken))
+	ethInfo.KeyNodeThreshold = 100
+	input.Tk.SetToken(ctx, ethInfo)
+
+	order := newTestKeyGenOrder(input, types.MsgKeyGenWaitSign{OrderID: "order", KeyNodes: keyNodes[:3]}, sdk.NewCUAddress())
+	order.Height = 1
+	input.Ok.SetOrder(ctx, order)
+
+	// the key nodes cannot reach the threshold of the chain
+	msg := types.NewMsgKeyGenWaitSign(validatorAddr1, order.ID, []byte("pubkey"), keyNodes[:3], []cutypes.StdSignature{}, epoch.Index)
+	order.SignThreshold = 4
+	input.Ok.SetOrder(ctx, order)
+	res := keygen.HandleMsgKeyGenWaitSignForTest(ctx, kk, msg)
+	require.Equal(t, sdk.CodeInsufficientValidtorNumberForKeyGen, res.Code)
+
+	// a reassigned order keeps all key nodes, even non-responsive ones, to reach the threshold
+	keygen.EndBlocker(ctx.WithBlockHeight(201), kk)
+	order = input.Ok.GetOrder(ctx, "order").(*sdk.OrderKeyGen)
+	require.Equal(t, uint64(1), order.Retries)
+	require.Equal(t, uint64(4), order.SignThreshold)
+	require.Equal(t, keyNodes, order.KeyNodes)
+
+	// other chains keep the 2/3 majority
+	order.Symbol = btcToken
+	input.Ok.SetOrder(ctx, order)
+	keygen.EndBlocker(ctx.WithBlockHeight(401), kk)
+	order = input.Ok.GetOrder(ctx, "order").(*sdk.OrderKeyGen)
+	require.Equal(t, uint64(2), order.Retries)
+	require.Equal(t, uint64(3), order.SignThreshold)
+	require.Len(t, order.KeyNodes, 3)
+}
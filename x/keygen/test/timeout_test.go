@@ -0,0 +1,117 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/evidence"
+	"github.com/hbtc-chain/bhchain/x/keygen"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+	stakingtypes "github.com/hbtc-chain/bhchain/x/staking/types"
+)
+
+func TestKeyGenTimeout(t *testing.T) {
+	input := SetupTestInput()
+	ctx := input.Ctx
+	kk := input.Kk
+
+	validatorAddr3 := sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())
+	validatorAddr4 := sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())
+	heartbeats := map[string]uint64{
+		validatorAddr1.String(): 150,
+		validatorAddr2.String(): 0,
+		validatorAddr3.String(): 150,
+		validatorAddr4.String(): 0,
+	}
+	keyNodes := []sdk.CUAddress{validatorAddr1, validatorAddr2, validatorAddr3, validatorAddr4}
+	for _, addr := range keyNodes {
+		val := stakingtypes.NewValidator(sdk.ValAddress(addr), ed25519.GenPrivKey().PubKey(), stakingtypes.Description{})
+		val.LastKeyNodeHeartbeatHeight = heartbeats[addr.String()]
+		input.Sk.SetValidator(ctx, val)
+	}
+	input.Sk.StartNewEpoch(ctx, keyNodes)
+
+	fee := sdk.NewCoin(sdk.NativeToken, input.Tk.GetIBCToken(ctx, sdk.Symbol(ethToken)).OpenFee)
+	_, _, err := input.Trk.AddCoins(ctx, keygenFromAddr, sdk.NewCoins(fee))
+	require.Nil(t, err)
+	_, err = input.Trk.LockCoin(ctx, keygenFromAddr, fee)
+	require.Nil(t, err)
+
+	userOrder := newTestKeyGenOrder(input, types.MsgKeyGenWaitSign{OrderID: "user", KeyNodes: keyNodes[:3]}, sdk.NewCUAddress())
+	userOrder.Height = 1
+	input.Ok.SetOrder(ctx, userOrder)
+
+	opCU := input.Ck.NewOpCUWithAddress(ctx, ethToken, sdk.NewCUAddress())
+	input.Ck.SetCU(ctx, opCU)
+	input.Ik.SetCUIBCAsset(ctx, input.Ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeOp, opCU.GetAddress()))
+	opCUOrder := newTestKeyGenOrder(input, types.MsgKeyGenWaitSign{OrderID: "opcu", KeyNodes: keyNodes[:3]}, opCU.GetAddress())
+	opCUOrder.Height = 1
+	opCUOrder.OpenFee = sdk.NewCoin(sdk.NativeToken, sdk.ZeroInt())
+	input.Ok.SetOrder(ctx, opCUOrder)
+
+	// not timed out yet
+	keygen.EndBlocker(ctx.WithBlockHeight(200), kk)
+	order := input.Ok.GetOrder(ctx, "user").(*sdk.OrderKeyGen)
+	require.Equal(t, uint64(0), order.Retries)
+
+	ctx = ctx.WithBlockHeight(201).WithEventManager(sdk.NewEventManager())
+	keygen.EndBlocker(ctx, kk)
+	require.Len(t, ctx.EventManager().Events(), 4)
+	require.Equal(t, types.EventTypeKeyGenTimeout, ctx.EventManager().Events()[0].Type)
+	// the reassignment is recorded like an order retry
+	receipts, rerr := input.Rk.GetReceiptsFromEvents(ctx.EventManager().Events())
+	require.Nil(t, rerr)
+	require.Len(t, receipts, 2)
+	require.Equal(t, sdk.CategoryTypeOrderRetry, receipts[0].Category)
+	require.Equal(t, []string{"user"}, receipts[1].Flows[1].(sdk.OrderRetryFlow).OrderIDs)
+	require.Equal(t, validatorAddr2, receipts[1].Flows[1].(sdk.OrderRetryFlow).ExcludedKeyNode)
+
+	// the non-responsive key node is excluded, the previously excluded one included again
+	order = input.Ok.GetOrder(ctx, "user").(*sdk.OrderKeyGen)
+	require.Equal(t, sdk.OrderStatusBegin, order.Status)
+	require.Equal(t, uint64(1), order.Retries)
+	require.Equal(t, uint64(201), order.StageHeight)
+	require.Equal(t, uint64(3), order.SignThreshold)
+	require.Equal(t, []sdk.CUAddress{validatorAddr1, validatorAddr3, validatorAddr4}, order.KeyNodes)
+
+	// both orders recorded the misbehaviour of validatorAddr2
+	require.Equal(t, int64(2), input.Ek.GetValidatorBehaviour(ctx, evidence.KeyGenBehaviourKey, sdk.ValAddress(validatorAddr2)).MisbehaviourCounter)
+	require.Equal(t, int64(0), input.Ek.GetValidatorBehaviour(ctx, evidence.KeyGenBehaviourKey, sdk.ValAddress(validatorAddr1)).MisbehaviourCounter)
+	require.Equal(t, int64(0), input.Ek.GetValidatorBehaviour(ctx, evidence.KeyGenBehaviourKey, sdk.ValAddress(validatorAddr4)).MisbehaviourCounter)
+
+	// a waiting sign order times out earlier
+	order.SetOrderStatus(sdk.OrderStatusWaitSign)
+	order.StageHeight = 250
+	input.Ok.SetOrder(ctx, order)
+	keygen.EndBlocker(ctx.WithBlockHeight(350), kk)
+	order = input.Ok.GetOrder(ctx, "user").(*sdk.OrderKeyGen)
+	require.Equal(t, sdk.OrderStatusBegin, order.Status)
+	require.Equal(t, uint64(2), order.Retries)
+
+	for height := int64(550); order.Retries <= types.MaxKeyGenRetries; height += types.KeyGenTimeout {
+		ctx = ctx.WithBlockHeight(height).WithEventManager(sdk.NewEventManager())
+		keygen.EndBlocker(ctx, kk)
+		order = input.Ok.GetOrder(ctx, "user").(*sdk.OrderKeyGen)
+	}
+	receipts, rerr = input.Rk.GetReceiptsFromEvents(ctx.EventManager().Events())
+	require.Nil(t, rerr)
+	// the cancellation of the user order follows the retry of the OPCU order
+	require.Len(t, receipts, 2)
+	require.Equal(t, sdk.CategoryTypeKeyGen, receipts[1].Category)
+	require.Equal(t, sdk.OrderStatusCancel, receipts[1].Flows[0].(sdk.OrderFlow).OrderStatus)
+
+	// the user order is cancelled and the open fee refunded
+	require.Equal(t, sdk.OrderStatusCancel, order.Status)
+	require.Equal(t, fee.Amount, input.Trk.GetBalance(ctx, keygenFromAddr, sdk.NativeToken))
+	require.True(t, input.Trk.GetHoldBalance(ctx, keygenFromAddr, sdk.NativeToken).IsZero())
+	require.Equal(t, []string{"opcu"}, input.Ok.GetProcessOrderListByType(ctx, sdk.OrderTypeKeyGen))
+
+	// the OPCU order is retried beyond MaxKeyGenRetries
+	keygen.EndBlocker(ctx.WithBlockHeight(950), kk)
+	order = input.Ok.GetOrder(ctx, "opcu").(*sdk.OrderKeyGen)
+	require.Equal(t, sdk.OrderStatusBegin, order.Status)
+	require.Equal(t, uint64(types.MaxKeyGenRetries+1), order.Retries)
+}
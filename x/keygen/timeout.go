@@ -0,0 +1,164 @@
+package keygen
+
+import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/evidence"
+	"github.com/hbtc-chain/bhchain/x/keygen/types"
+)
+
+// handleKeyGenTimeouts reassigns the keygen orders which did not progress within the timeout of their status.
+// The key nodes without heartbeat since the order entered its status are recorded as misbehaving.
+// A reassigned order excludes another key node, after MaxKeyGenRetries reassignments it is cancelled and
+// the open fee refunded. Orders of OPCUs are never cancelled.
+func (k Keeper) handleKeyGenTimeouts(ctx sdk.Context) {
+	height := uint64(ctx.BlockHeight())
+	for _, orderID := range k.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeKeyGen) {
+		keyGenOrder, ok := k.ok.GetOrder(ctx, orderID).(*sdk.OrderKeyGen)
+		if !ok {
+			continue
+		}
+		var timeout uint64
+		switch keyGenOrder.GetOrderStatus() {
+		case sdk.OrderStatusBegin:
+			timeout = types.KeyGenTimeout
+		case sdk.OrderStatusWaitSign:
+			timeout = types.KeyGenWaitSignTimeout
+		default:
+			continue
+		}
+		stageHeight := keyGenOrder.StageHeight
+		if stageHeight == 0 {
+			stageHeight = keyGenOrder.Height
+		}
+		if height < stageHeight+timeout {
+			continue
+		}
+		k.handleKeyGenTimeout(ctx, keyGenOrder, stageHeight)
+	}
+}
+
+func (k Keeper) handleKeyGenTimeout(ctx sdk.Context, order *sdk.OrderKeyGen, stageHeight uint64) {
+	ctx.Logger().Info("keygen order timeout", "order", order.ID, "status", order.Status, "retries", order.Retries)
+	nonResponsive := k.recordKeyGenBehaviour(ctx, order.KeyNodes, stageHeight)
+
+	order.Retries++
+	if order.Retries > types.MaxKeyGenRetries && !k.isOpCU(ctx, order.To) {
+		k.cancelKeyGenOrder(ctx, order)
+		return
+	}
+
+	curEpoch := k.vk.GetCurrentEpoch(ctx)
+	threshold := k.getSignThreshold(ctx, order.Symbol, len(curEpoch.KeyNodeSet))
+	var excluded sdk.CUAddress
+	if len(curEpoch.KeyNodeSet) > threshold {
+		excluded = k.getTimeoutExcludedKeyNode(ctx, curEpoch.KeyNodeSet, order.KeyNodes, nonResponsive)
+	}
+	keyNodes := make([]sdk.CUAddress, 0, len(curEpoch.KeyNodeSet))
+	for _, val := range curEpoch.KeyNodeSet {
+		if !val.Equals(excluded) {
+			keyNodes = append(keyNodes, val)
+		}
+	}
+
+	order.KeyNodes = keyNodes
+	order.SignThreshold = uint64(threshold)
+	order.Pubkey = nil
+	order.StageHeight = uint64(ctx.BlockHeight())
+	order.SetOrderStatus(sdk.OrderStatusBegin)
+	k.ok.SetOrder(ctx, order)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeKeyGenTimeout,
+			sdk.NewAttribute(types.AttributeKeyOrderID, order.ID),
+			sdk.NewAttribute(types.AttributeKeyStatus, fmt.Sprintf("%d", order.Status)),
+			sdk.NewAttribute(types.AttributeKeyExcluded, excluded.String()),
+			sdk.NewAttribute(types.AttributeKeyRetries, fmt.Sprintf("%d", order.Retries)),
+		),
+	)
+	flows := []sdk.Flow{
+		k.rk.NewOrderFlow(sdk.Symbol(order.Symbol), order.CUAddress, order.ID, sdk.OrderTypeKeyGen, sdk.OrderStatusBegin),
+		k.rk.NewOrderRetryFlow([]string{order.ID}, excluded),
+	}
+	receipt := k.rk.NewReceipt(sdk.CategoryTypeOrderRetry, flows)
+	k.rk.SaveReceiptToEvents(receipt, ctx.EventManager())
+}
+
+// recordKeyGenBehaviour records the key nodes without heartbeat since stageHeight as misbehaving
+// and returns them
+func (k Keeper) recordKeyGenBehaviour(ctx sdk.Context, keyNodes []sdk.CUAddress, stageHeight uint64) []sdk.CUAddress {
+	var nonResponsive []sdk.CUAddress
+	for _, keyNode := range keyNodes {
+		val, found := k.vk.GetValidator(ctx, sdk.ValAddress(keyNode))
+		responsive := found && val.LastKeyNodeHeartbeatHeight >= stageHeight
+		if !responsive {
+			nonResponsive = append(nonResponsive, keyNode)
+		}
+		k.ek.HandleBehaviour(ctx, evidence.KeyGenBehaviourKey, sdk.ValAddress(keyNode), uint64(ctx.BlockHeight()), responsive)
+	}
+	return nonResponsive
+}
+
+// getTimeoutExcludedKeyNode returns the key node to exclude from a timed out order, which is one of the
+// order's key nodes so that the previously excluded one is included again. Non-responsive key nodes are
+// excluded first, the one with the oldest heartbeat otherwise.
+func (k Keeper) getTimeoutExcludedKeyNode(ctx sdk.Context, epochKeyNodes, orderKeyNodes, nonResponsive []sdk.CUAddress) sdk.CUAddress {
+	candidates := make([]sdk.CUAddress, 0, len(orderKeyNodes))
+	for _, keyNode := range epochKeyNodes {
+		if isValidator(orderKeyNodes, keyNode) && isValidator(nonResponsive, keyNode) {
+			candidates = append(candidates, keyNode)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, keyNode := range epochKeyNodes {
+			if isValidator(orderKeyNodes, keyNode) {
+				candidates = append(candidates, keyNode)
+			}
+		}
+	}
+
+	var excluded sdk.CUAddress
+	var oldest uint64
+	for _, keyNode := range candidates {
+		val, _ := k.vk.GetValidator(ctx, sdk.ValAddress(keyNode))
+		if excluded == nil || val.LastKeyNodeHeartbeatHeight < oldest {
+			excluded = keyNode
+			oldest = val.LastKeyNodeHeartbeatHeight
+		}
+	}
+	return excluded
+}
+
+func (k Keeper) cancelKeyGenOrder(ctx sdk.Context, order *sdk.OrderKeyGen) {
+	flows := []sdk.Flow{k.rk.NewOrderFlow(sdk.Symbol(order.Symbol), order.CUAddress, order.ID, sdk.OrderTypeKeyGen, sdk.OrderStatusCancel)}
+	if order.OpenFee.IsPositive() {
+		refundFlows, err := k.trk.UnlockCoin(ctx, order.CUAddress, order.OpenFee)
+		if err != nil {
+			ctx.Logger().Error("fail to refund keygen open fee", "order", order.ID, "err", err)
+		}
+		flows = append(flows, refundFlows...)
+	}
+	order.SetOrderStatus(sdk.OrderStatusCancel)
+	k.ok.SetOrder(ctx, order)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeKeyGenTimeout,
+			sdk.NewAttribute(types.AttributeKeyOrderID, order.ID),
+			sdk.NewAttribute(types.AttributeKeyStatus, fmt.Sprintf("%d", order.Status)),
+			sdk.NewAttribute(types.AttributeKeyRetries, fmt.Sprintf("%d", order.Retries)),
+		),
+	)
+	receipt := k.rk.NewReceipt(sdk.CategoryTypeKeyGen, flows)
+	k.rk.SaveReceiptToEvents(receipt, ctx.EventManager())
+}
+
+func (k Keeper) isOpCU(ctx sdk.Context, addr sdk.CUAddress) bool {
+	if addr == nil {
+		return false
+	}
+	cuAst := k.ik.GetCUIBCAsset(ctx, addr)
+	return cuAst != nil && cuAst.GetCUType() == sdk.CUTypeOp
+}
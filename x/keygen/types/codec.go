@@ -16,6 +16,8 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgPreKeyGen{}, "hbtcchain/keygen/MsgPreKeyGen", nil)
 	cdc.RegisterConcrete(MsgOpcuMigrationKeyGen{}, "hbtcchain/keygen/MsgOpcuMigrationKeyGen", nil)
 	cdc.RegisterConcrete(MsgNewOpCU{}, "hbtcchain/keygen/MsgNewOpCU", nil)
+	cdc.RegisterConcrete(MsgNewDepositAddress{}, "hbtcchain/keygen/MsgNewDepositAddress", nil)
+	cdc.RegisterConcrete(MsgRetireDepositAddress{}, "hbtcchain/keygen/MsgRetireDepositAddress", nil)
 }
 
 func init() {
@@ -17,13 +17,18 @@ const (
 	DefaultParamspace = ModuleName
 
 	QueryWaitAssignKeys = "waitAssign"
+	QueryKeyRotation    = "keyRotation"
 
-	EventTypeKeyGen              = "key_gen"
-	EventTypeKeyGenWaitSign      = "key_gen_waitsign"
-	EventTypeKeyGenFinish        = "key_gen_finish"
-	EventTypePreKeyGen           = "pre_key_gen"
-	EventTypeOpcuMigrationKeyGen = "opcu_migration_key_gen"
-	EventTypeKeyNewOPCU          = "new_opcu"
+	EventTypeKeyGen               = "key_gen"
+	EventTypeKeyGenWaitSign       = "key_gen_waitsign"
+	EventTypeKeyGenFinish         = "key_gen_finish"
+	EventTypePreKeyGen            = "pre_key_gen"
+	EventTypeOpcuMigrationKeyGen  = "opcu_migration_key_gen"
+	EventTypeKeyNewOPCU           = "new_opcu"
+	EventTypeNewDepositAddress    = "new_deposit_address"
+	EventTypeRetireDepositAddress = "retire_deposit_address"
+	EventTypeKeyRotation          = "key_rotation"
+	EventTypeKeyGenTimeout        = "key_gen_timeout"
 
 	// in keygenfinish 'sender' is the validator, which send the keygenfinish tx.
 	AttributeKeySender   = "sender"
@@ -32,12 +37,36 @@ const (
 	AttributeKeySymbol   = "symbol"
 	AttributeKeyOrderID  = "order_id"
 	AttributeKeyOrderIDs = "order_ids"
+	AttributeKeyAddress  = "address"
+	AttributeKeyChain    = "chain"
+	AttributeKeyEpoch    = "epoch"
+	AttributeKeyStatus   = "status"
+	AttributeKeyExcluded = "excluded_key_node"
+	AttributeKeyRetries  = "retries"
 
 	MaxWaitAssignKeyOrders = 32
 	MaxPreKeyGenOrders     = 5
 	MaxKeyNodeHeartbeat    = 1000
+	MaxDepositAddresses    = 16
+
+	// KeyGenTimeout is the number of blocks a keygen order waits for MsgKeyGenWaitSign
+	KeyGenTimeout = 200
+	// KeyGenWaitSignTimeout is the number of blocks a keygen order waits for MsgKeyGenFinish
+	KeyGenWaitSignTimeout = 100
+	// MaxKeyGenRetries is the number of reassignments after which a keygen order is cancelled
+	MaxKeyGenRetries = 3
+
+	// KeyRotationCheckInterval is the number of blocks between two checks of the key rotation schedules
+	KeyRotationCheckInterval = 100
 )
 
 var (
 	WaitAssignKey = []byte("waitAssign")
+
+	KeyRotationHeightKeyPrefix = []byte("keyRotationHeight/")
 )
+
+// KeyRotationHeightKey: prefix + chain
+func KeyRotationHeightKey(chain string) []byte {
+	return append(KeyRotationHeightKeyPrefix, []byte(chain)...)
+}
@@ -325,3 +325,95 @@ func (msg MsgNewOpCU) GetSignBytes() []byte {
 func (msg MsgNewOpCU) GetSigners() []sdk.CUAddress {
 	return []sdk.CUAddress{sdk.CUAddress(msg.From)}
 }
+
+const (
+	TypeMsgNewDepositAddress    = "new_deposit_address"
+	TypeMsgRetireDepositAddress = "retire_deposit_address"
+)
+
+//========MsgNewDepositAddress
+type MsgNewDepositAddress struct {
+	OrderID string        `json:"order_id"`
+	Chain   sdk.Symbol    `json:"chain"`
+	From    sdk.CUAddress `json:"from"`
+}
+
+//NewMsgNewDepositAddress is a constructor function for MsgNewDepositAddress
+func NewMsgNewDepositAddress(orderID string, chain sdk.Symbol, from sdk.CUAddress) MsgNewDepositAddress {
+	return MsgNewDepositAddress{
+		OrderID: orderID,
+		Chain:   chain,
+		From:    from,
+	}
+}
+
+func (msg MsgNewDepositAddress) Route() string { return RouterKey }
+func (msg MsgNewDepositAddress) Type() string  { return TypeMsgNewDepositAddress }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgNewDepositAddress) ValidateBasic() sdk.Error {
+	if sdk.IsIllegalOrderID(msg.OrderID) {
+		return sdk.ErrInvalidTx("OrderID is invalid")
+	}
+	if msg.Chain.String() == "" || !msg.Chain.IsValid() {
+		return sdk.ErrInvalidSymbol(fmt.Sprintf("Invalid chain %s", msg.Chain))
+	}
+	if msg.Chain.String() == sdk.NativeToken {
+		return sdk.ErrInvalidTx("No need to generate address for native token")
+	}
+	if msg.From == nil || !msg.From.IsValidAddr() {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("From CU address: %s is invalid", msg.From.String()))
+	}
+	return nil
+}
+
+func (msg MsgNewDepositAddress) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func (msg MsgNewDepositAddress) GetSigners() []sdk.CUAddress {
+	return []sdk.CUAddress{msg.From}
+}
+
+//========MsgRetireDepositAddress
+type MsgRetireDepositAddress struct {
+	Chain   sdk.Symbol    `json:"chain"`
+	Address string        `json:"address"`
+	From    sdk.CUAddress `json:"from"`
+}
+
+//NewMsgRetireDepositAddress is a constructor function for MsgRetireDepositAddress
+func NewMsgRetireDepositAddress(chain sdk.Symbol, address string, from sdk.CUAddress) MsgRetireDepositAddress {
+	return MsgRetireDepositAddress{
+		Chain:   chain,
+		Address: address,
+		From:    from,
+	}
+}
+
+func (msg MsgRetireDepositAddress) Route() string { return RouterKey }
+func (msg MsgRetireDepositAddress) Type() string  { return TypeMsgRetireDepositAddress }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgRetireDepositAddress) ValidateBasic() sdk.Error {
+	if msg.Chain.String() == "" || !msg.Chain.IsValid() {
+		return sdk.ErrInvalidSymbol(fmt.Sprintf("Invalid chain %s", msg.Chain))
+	}
+	if msg.Address == "" {
+		return sdk.ErrInvalidAddr("Deposit address is empty")
+	}
+	if msg.From == nil || !msg.From.IsValidAddr() {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("From CU address: %s is invalid", msg.From.String()))
+	}
+	return nil
+}
+
+func (msg MsgRetireDepositAddress) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func (msg MsgRetireDepositAddress) GetSigners() []sdk.CUAddress {
+	return []sdk.CUAddress{msg.From}
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// QueryKeyRotationParams defines the params of QueryKeyRotation, an empty Chain queries all chains with OPCUs
+type QueryKeyRotationParams struct {
+	Chain string
+}
+
+func NewQueryKeyRotationParams(chain string) QueryKeyRotationParams {
+	return QueryKeyRotationParams{
+		Chain: chain,
+	}
+}
+
+// KeyRotationStatus is the key rotation schedule of a chain and the migration status of its OPCUs
+type KeyRotationStatus struct {
+	Chain string `json:"chain"`
+	// Interval is the key rotation interval of the chain in blocks, 0 if the rotation is disabled
+	Interval uint64 `json:"interval"`
+	// Epoch is the index of the epoch the current OPCU keys are generated for
+	Epoch uint64 `json:"epoch"`
+	// LastRotationHeight is the height the keys of the chain were last rotated at. Before the first rotation,
+	// it's the start height of the epoch the keys are generated for when the rotation is enabled.
+	LastRotationHeight uint64 `json:"last_rotation_height"`
+	// NextRotationHeight is the height from which the keys are rotated, 0 if the rotation is disabled
+	NextRotationHeight uint64 `json:"next_rotation_height"`
+	// MigrationFinished is set once all OPCUs of the chain swept their assets to the keys of Epoch
+	MigrationFinished bool                  `json:"migration_finished"`
+	OpCUs             []OpCUMigrationStatus `json:"opcus"`
+}
+
+// AddOpCU adds the migration status of an OPCU of the chain
+func (s *KeyRotationStatus) AddOpCU(opCU OpCUMigrationStatus) {
+	s.OpCUs = append(s.OpCUs, opCU)
+	s.MigrationFinished = s.MigrationFinished && opCU.MigrationStatus == sdk.MigrationFinish
+}
+
+// OpCUMigrationStatus is the migration status of an OPCU
+type OpCUMigrationStatus struct {
+	Address         sdk.CUAddress       `json:"address"`
+	Symbol          string              `json:"symbol"`
+	MigrationStatus sdk.MigrationStatus `json:"migration_status"`
+}
@@ -87,7 +87,7 @@ func NewApp() *App {
 	// Initialize the app. The chainers and blockers can be overwritten before
 	// calling complete setup.
 	app.SetInitChainer(app.InitChainer)
-	app.SetAnteHandler(custodianunit.NewAnteHandler(app.CUKeeper, supplyKeeper, nil, custodianunit.DefaultSigVerificationGasConsumer))
+	app.SetAnteHandler(custodianunit.NewAnteHandler(app.CUKeeper, supplyKeeper, nil, nil, custodianunit.DefaultSigVerificationGasConsumer))
 	app.SetGasRefundHandler(custodianunit.NewGasRefundHandler(supplyKeeper))
 	// Not sealing for custom extension
 
@@ -41,6 +41,9 @@ func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, tokenKeeper types.TokenKeeper
 func (k Keeper) AddLiquidity(ctx sdk.Context, from sdk.CUAddress, dexID uint32, tokenA, tokenB sdk.Symbol,
 	maxTokenAAmount, maxTokenBAmount sdk.Int) sdk.Result {
 
+	if k.tk.IsAddressFrozen(ctx, from) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("address %s is frozen", from)).Result()
+	}
 	pair := k.GetTradingPair(ctx, dexID, tokenA, tokenB)
 	if pair == nil && dexID != 0 {
 		return sdk.ErrInvalidTx(fmt.Sprintf("%s-%s trading pair does not exist in dex %d",
@@ -176,6 +179,9 @@ func (k Keeper) RemoveLiquidity(ctx sdk.Context, from sdk.CUAddress, dexID uint3
 func (k Keeper) SwapExactIn(ctx sdk.Context, dexID uint32, from, referer, receiver sdk.CUAddress, amountIn, minAmountOut sdk.Int,
 	path []sdk.Symbol) sdk.Result {
 
+	if k.tk.IsAddressFrozen(ctx, from) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("address %s is frozen", from)).Result()
+	}
 	amountOut, err := k.getAmountOut(ctx, dexID, amountIn, path)
 	if err != nil {
 		return sdk.ErrInvalidTx(err.Error()).Result()
@@ -190,6 +196,9 @@ func (k Keeper) SwapExactIn(ctx sdk.Context, dexID uint32, from, referer, receiv
 func (k Keeper) SwapExactOut(ctx sdk.Context, dexID uint32, from, referer, receiver sdk.CUAddress, amountOut, maxAmountIn sdk.Int,
 	path []sdk.Symbol) sdk.Result {
 
+	if k.tk.IsAddressFrozen(ctx, from) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("address %s is frozen", from)).Result()
+	}
 	amountIn, err := k.getAmountIn(ctx, dexID, amountOut, path)
 	if err != nil {
 		return sdk.ErrInvalidTx(err.Error()).Result()
@@ -271,6 +280,9 @@ func (k Keeper) directSwap(ctx sdk.Context, dexID uint32, from, referer, receive
 func (k Keeper) LimitSwap(ctx sdk.Context, dexID uint32, orderID string, from, referer, receiver sdk.CUAddress, amountIn sdk.Int,
 	price sdk.Dec, baseSymbol, quoteSymbol sdk.Symbol, side int, expiredAt int64) sdk.Result {
 
+	if k.tk.IsAddressFrozen(ctx, from) {
+		return sdk.ErrUnauthorized(fmt.Sprintf("address %s is frozen", from)).Result()
+	}
 	pair := k.GetTradingPair(ctx, dexID, baseSymbol, quoteSymbol)
 	if pair == nil {
 		return sdk.ErrInvalidTx(fmt.Sprintf("%s-%s trading pair does not exist in dex %d", baseSymbol, quoteSymbol, dexID)).Result()
@@ -28,4 +28,5 @@ import "github.com/hbtc-chain/bhchain/x/order/types"
 const (
 	StoreKey          = types.StoreKey
 	DefaultParamspace = types.DefaultParamspace
+	OrderIndexUpgrade = types.OrderIndexUpgrade
 )
@@ -8,11 +8,24 @@ import (
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/version"
 	"github.com/hbtc-chain/bhchain/x/order/types"
+	"github.com/hbtc-chain/bhchain/types/rest"
 	"github.com/satori/go.uuid"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"strings"
 )
 
+const (
+	flagCUAddress   = "cu"
+	flagSymbol      = "symbol"
+	flagOrderType   = "order-type"
+	flagStatus      = "status"
+	flagStartHeight = "start-height"
+	flagEndHeight   = "end-height"
+	flagPage        = "page"
+	flagLimit       = "limit"
+)
+
 // GetQueryCmd returns the cli query commands for this module
 func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	QueryCmd := &cobra.Command{
@@ -24,6 +37,7 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 	QueryCmd.AddCommand(client.GetCommands(
 		GetCmdQueryOrder(cdc),
+		GetCmdQueryOrderList(cdc),
 	)...)
 	return QueryCmd
 
@@ -71,3 +85,65 @@ $ %s query order order	123e4567-e89b-12d3-a456-426655440000`,
 		},
 	}
 }
+
+// GetCmdQueryOrderList implements the order list query command.
+func GetCmdQueryOrderList(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orders",
+		Short: "Query orders by CU, symbol, type, status and height range",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the order history matching all the given filters, newest first.
+Order types: 1 keygen, 2 withdrawal, 3 collect, 4 deposit, 5 sys transfer, 6 opcu asset transfer, 7 utxo consolidation.
+Order status: 1 begin, 2 wait sign, 3 sign finish, 4 finish, 5 cancel, 6 failed.
+
+Example:
+$ %s query order orders --cu HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy --symbol btc --order-type 2 --page 1 --limit 10`,
+				version.ClientName,
+			),
+		),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			var cuAddr sdk.CUAddress
+			if cu := viper.GetString(flagCUAddress); cu != "" {
+				var err error
+				cuAddr, err = sdk.CUAddressFromBase58(cu)
+				if err != nil {
+					return err
+				}
+			}
+
+			params := types.NewQueryOrderListParams(cuAddr, viper.GetString(flagSymbol),
+				sdk.OrderType(viper.GetInt(flagOrderType)), sdk.OrderStatus(viper.GetInt(flagStatus)),
+				viper.GetUint64(flagStartHeight), viper.GetUint64(flagEndHeight), viper.GetInt(flagPage), viper.GetInt(flagLimit))
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryOrderList)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			var orders types.QueryResOrderList
+			if err = cdc.UnmarshalJSON(res, &orders); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(orders)
+		},
+	}
+
+	cmd.Flags().String(flagCUAddress, "", "CU address of the orders")
+	cmd.Flags().String(flagSymbol, "", "symbol of the orders")
+	cmd.Flags().Int(flagOrderType, 0, "order type of the orders")
+	cmd.Flags().Int(flagStatus, 0, "status of the orders")
+	cmd.Flags().Uint64(flagStartHeight, 0, "lowest height the orders created at")
+	cmd.Flags().Uint64(flagEndHeight, 0, "highest height the orders created at")
+	cmd.Flags().Int(flagPage, rest.DefaultPage, "Query a specific page of paginated results")
+	cmd.Flags().Int(flagLimit, types.DefaultOrderListLimit, "Query number of orders per page returned")
+	return cmd
+}
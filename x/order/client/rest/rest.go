@@ -3,8 +3,10 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/hbtc-chain/bhchain/client/context"
+	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/types/rest"
 	"github.com/hbtc-chain/bhchain/x/order/types"
 	"github.com/gorilla/mux"
@@ -20,6 +22,7 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	// Query the information of an order
 	r.HandleFunc("/order/info/{orderId}", orderInfoHandlerFn(cliCtx)).Methods("GET")
 	r.HandleFunc("/order/process_list", processListHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/order/list", orderListHandlerFn(cliCtx)).Methods("GET")
 }
 
 // HTTP request handler to query the information of an order.
@@ -66,3 +69,59 @@ func processListHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+// HTTP request handler to query the order history,
+// filters: cu_address, symbol, order_type, status, start_height, end_height, page, limit.
+func orderListHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, page, limit, err := rest.ParseHTTPArgsWithLimit(r, types.DefaultOrderListLimit)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		var cuAddr sdk.CUAddress
+		if cu := r.FormValue("cu_address"); cu != "" {
+			cuAddr, err = sdk.CUAddressFromBase58(cu)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		var numbers [4]uint64
+		for i, key := range []string{"order_type", "status", "start_height", "end_height"} {
+			value := r.FormValue(key)
+			if value == "" {
+				continue
+			}
+			numbers[i], err = strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", key, value))
+				return
+			}
+		}
+
+		params := types.NewQueryOrderListParams(cuAddr, r.FormValue("symbol"), sdk.OrderType(numbers[0]),
+			sdk.OrderStatus(numbers[1]), numbers[2], numbers[3], page, limit)
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryOrderList), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
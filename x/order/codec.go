@@ -21,5 +21,6 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(&sdk.OrderKeyGen{}, "hbtcchain/order/OrderKeyGen", nil)
 	cdc.RegisterConcrete(&sdk.OrderSysTransfer{}, "hbtcchain/order/OrderSysTransfer", nil)
 	cdc.RegisterConcrete(&sdk.OrderOpcuAssetTransfer{}, "hbtcchain/order/OrderOpcuAssetTransfer", nil)
+	cdc.RegisterConcrete(&sdk.OrderUtxoConsolidation{}, "hbtcchain/order/OrderUtxoConsolidation", nil)
 	cdc.RegisterConcrete(&sdk.TxFinishNodeData{}, "hbtcchain/order/TxFinishNodeData", nil)
 }
@@ -0,0 +1,210 @@
+package order
+
+import (
+	"encoding/binary"
+	"sort"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/order/types"
+)
+
+// setOrderIndexes maintains the secondary indexes of an order, prevOrder is the stored order before this update
+// or nil, whose index entries not matching the updated order are removed.
+func (k *Keeper) setOrderIndexes(ctx sdk.Context, order sdk.Order, prevOrder sdk.Order) {
+	store := ctx.KVStore(k.key)
+	keys := orderIndexKeys(order)
+	if prevOrder != nil {
+		current := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			current[string(key)] = true
+		}
+		for _, key := range orderIndexKeys(prevOrder) {
+			if !current[string(key)] {
+				store.Delete(key)
+			}
+		}
+	}
+	for _, key := range keys {
+		store.Set(key, []byte{0})
+	}
+}
+
+func (k *Keeper) deleteOrderIndexes(ctx sdk.Context, order sdk.Order) {
+	store := ctx.KVStore(k.key)
+	for _, key := range orderIndexKeys(order) {
+		store.Delete(key)
+	}
+}
+
+// BuildOrderIndexes indexes all the stored orders. Orders are indexed when they are set, the orders stored before
+// the indexes were introduced are only listed by GetOrderList after this migration, which is run by the upgrade
+// named OrderIndexUpgrade. It is safe to run it again.
+func (k *Keeper) BuildOrderIndexes(ctx sdk.Context) {
+	var orders []sdk.Order
+	k.iterateOrders(ctx, types.OrderStoreKeyPrefix, func(order sdk.Order) bool {
+		orders = append(orders, order)
+		return false
+	})
+	for _, order := range orders {
+		k.setOrderIndexes(ctx, order, nil)
+	}
+}
+
+// GetOrderList returns the orders matching all the given filters within [startHeight, endHeight], newest first.
+// Zero value of a filter means no filter on it, endHeight 0 means no upper bound.
+func (k *Keeper) GetOrderList(ctx sdk.Context, cuAddr sdk.CUAddress, symbol string, orderType sdk.OrderType,
+	status sdk.OrderStatus, startHeight, endHeight uint64) []sdk.Order {
+	orderIDs := k.GetOrderIDList(ctx, cuAddr, symbol, orderType, status, startHeight, endHeight)
+	orders := make([]sdk.Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if order := k.GetOrder(ctx, orderID); order != nil {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+// GetOrderIDList is GetOrderList without decoding the orders.
+func (k *Keeper) GetOrderIDList(ctx sdk.Context, cuAddr sdk.CUAddress, symbol string, orderType sdk.OrderType,
+	status sdk.OrderStatus, startHeight, endHeight uint64) []string {
+	// the first filter given is the index to iterate, the others are checked against their own index
+	var prefixes [][]byte
+	if !cuAddr.Empty() {
+		prefixes = append(prefixes, cuOrderIndexPrefixKey(cuAddr))
+	}
+	if symbol != "" {
+		prefixes = append(prefixes, symbolOrderIndexPrefixKey(symbol))
+	}
+	if orderType != 0 {
+		prefixes = append(prefixes, typeOrderIndexPrefixKey(orderType))
+	}
+	if status != 0 {
+		prefixes = append(prefixes, statusOrderIndexPrefixKey(status))
+	}
+
+	store := ctx.KVStore(k.key)
+	if len(prefixes) == 0 {
+		return k.getAllOrderIDList(ctx, startHeight, endHeight)
+	}
+
+	orderIDs := []string{}
+	start := orderIndexHeightKey(prefixes[0], startHeight)
+	end := sdk.PrefixEndBytes(prefixes[0])
+	if endHeight != 0 {
+		end = orderIndexHeightKey(prefixes[0], endHeight+1)
+	}
+	iter := store.ReverseIterator(start, end)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		height, orderID := decodeOrderIndexKey(prefixes[0], iter.Key())
+		matched := true
+		for _, prefix := range prefixes[1:] {
+			if !store.Has(orderIndexKey(prefix, height, orderID)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+	return orderIDs
+}
+
+// getAllOrderIDList walks the order type index, every order has exactly one entry in it.
+func (k *Keeper) getAllOrderIDList(ctx sdk.Context, startHeight, endHeight uint64) []string {
+	type indexEntry struct {
+		height  uint64
+		orderID string
+	}
+	var entries []indexEntry
+	store := ctx.KVStore(k.key)
+	iter := sdk.KVStorePrefixIterator(store, types.TypeOrderIndexKeyPrefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		height, orderID := decodeOrderIndexKey(typeOrderIndexPrefixKey(0), iter.Key())
+		if height < startHeight || (endHeight != 0 && height > endHeight) {
+			continue
+		}
+		entries = append(entries, indexEntry{height, orderID})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].height > entries[j].height
+	})
+	orderIDs := make([]string, len(entries))
+	for i, entry := range entries {
+		orderIDs[i] = entry.orderID
+	}
+	return orderIDs
+}
+
+func orderIndexKeys(order sdk.Order) [][]byte {
+	prefixes := orderIndexPrefixes(order)
+	keys := make([][]byte, len(prefixes))
+	for i, prefix := range prefixes {
+		keys[i] = orderIndexKey(prefix, order.GetHeight(), order.GetID())
+	}
+	return keys
+}
+
+func orderIndexPrefixes(order sdk.Order) [][]byte {
+	return [][]byte{
+		cuOrderIndexPrefixKey(order.GetCUAddress()),
+		symbolOrderIndexPrefixKey(order.GetSymbol()),
+		typeOrderIndexPrefixKey(order.GetOrderType()),
+		statusOrderIndexPrefixKey(order.GetOrderStatus()),
+	}
+}
+
+// prefix + len(cuAddress) + cuAddress
+func cuOrderIndexPrefixKey(cuAddr sdk.CUAddress) []byte {
+	return lengthPrefixedKey(types.CUOrderIndexKeyPrefix, cuAddr)
+}
+
+// prefix + len(symbol) + symbol
+func symbolOrderIndexPrefixKey(symbol string) []byte {
+	return lengthPrefixedKey(types.SymbolOrderIndexKeyPrefix, []byte(symbol))
+}
+
+// prefix + orderType
+func typeOrderIndexPrefixKey(orderType sdk.OrderType) []byte {
+	return uint16PrefixedKey(types.TypeOrderIndexKeyPrefix, uint16(orderType))
+}
+
+// prefix + status
+func statusOrderIndexPrefixKey(status sdk.OrderStatus) []byte {
+	return uint16PrefixedKey(types.StatusOrderIndexKeyPrefix, uint16(status))
+}
+
+// index prefix + height + orderID
+func orderIndexKey(prefix []byte, height uint64, orderID string) []byte {
+	return append(orderIndexHeightKey(prefix, height), []byte(orderID)...)
+}
+
+func orderIndexHeightKey(prefix []byte, height uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], height)
+	return key
+}
+
+func decodeOrderIndexKey(prefix, bz []byte) (height uint64, orderID string) {
+	height = binary.BigEndian.Uint64(bz[len(prefix) : len(prefix)+8])
+	orderID = string(bz[len(prefix)+8:])
+	return
+}
+
+func lengthPrefixedKey(prefix, value []byte) []byte {
+	key := make([]byte, 0, len(prefix)+1+len(value))
+	key = append(key, prefix...)
+	key = append(key, byte(len(value)))
+	return append(key, value...)
+}
+
+func uint16PrefixedKey(prefix []byte, value uint16) []byte {
+	key := make([]byte, len(prefix)+2)
+	copy(key, prefix)
+	binary.BigEndian.PutUint16(key[len(prefix):], value)
+	return key
+}
@@ -215,6 +215,28 @@ func (k *Keeper) NewOrderOpcuAssetTransfer(ctx sdk.Context, opcu sdk.CUAddress,
 	return (order).(*sdk.OrderOpcuAssetTransfer)
 }
 
+func (k *Keeper) NewOrderUtxoConsolidation(ctx sdk.Context, opcu sdk.CUAddress, orderID string, symbol string,
+	items []sdk.TransferItem, addr string) *sdk.OrderUtxoConsolidation {
+	ordBase := sdk.OrderBase{
+		CUAddress: opcu,
+		ID:        orderID,
+		OrderType: sdk.OrderTypeUtxoConsolidation,
+		Symbol:    symbol,
+		Height:    uint64(ctx.BlockHeight()),
+	}
+	neworder := sdk.OrderUtxoConsolidation{
+		OrderBase:        ordBase,
+		ConsolidateItems: make([]sdk.TransferItem, len(items)),
+		Address:          addr,
+	}
+	copy(neworder.ConsolidateItems, items)
+	order := k.NewOrder(ctx, &neworder)
+	if order == nil {
+		return &sdk.OrderUtxoConsolidation{}
+	}
+	return (order).(*sdk.OrderUtxoConsolidation)
+}
+
 func (k *Keeper) GetOrder(ctx sdk.Context, orderID string) sdk.Order {
 	store := ctx.KVStore(k.key)
 	bz := store.Get(orderKey(orderID))
@@ -244,10 +266,13 @@ func (k *Keeper) SetOrder(ctx sdk.Context, order sdk.Order) {
 		return
 	}
 
+	prevOrder := k.GetOrder(ctx, order.GetID())
+
 	store := ctx.KVStore(k.key)
 	bz := k.cdc.MustMarshalBinaryBare(order)
 
 	store.Set(orderKey(order.GetID()), bz)
+	k.setOrderIndexes(ctx, order, prevOrder)
 
 	if order.GetOrderStatus().Terminated() {
 		k.RemoveProcessOrder(ctx, order.GetOrderType(), order.GetID())
@@ -258,6 +283,9 @@ func (k *Keeper) DeleteOrder(ctx sdk.Context, order sdk.Order) {
 	if order == nil || !order.GetCUAddress().IsValidAddr() {
 		return
 	}
+	if stored := k.GetOrder(ctx, order.GetID()); stored != nil {
+		k.deleteOrderIndexes(ctx, stored)
+	}
 	store := ctx.KVStore(k.key)
 	store.Delete(orderKey(order.GetID()))
 }
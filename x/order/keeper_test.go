@@ -61,6 +61,83 @@ func TestKeeper_NewOrders(t *testing.T) {
 	assert.Equal(t, sdk.OrderTypeSysTransfer, orderGot.GetOrderType())
 }
 
+func TestKeeper_GetOrderList(t *testing.T) {
+	input := setupTestInput()
+	ctx := input.ctx
+	ok := input.ook
+	user1, user2 := sdk.NewCUAddress(), sdk.NewCUAddress()
+
+	newWithdrawal := func(height int64, from sdk.CUAddress, symbol string) *sdk.OrderWithdrawal {
+		order := ok.NewOrderWithdrawal(ctx.WithBlockHeight(height), from, uuid.NewV4().String(), symbol,
+			sdk.NewInt(3), sdk.NewInt(3), sdk.ZeroInt(), "0x1322cabc2334098", "", "")
+		ok.SetOrder(ctx, order)
+		return order
+	}
+	w1 := newWithdrawal(10, user1, "eth")
+	w2 := newWithdrawal(20, user1, "btc")
+	w3 := newWithdrawal(30, user2, "eth")
+	c1 := ok.NewOrderCollect(ctx.WithBlockHeight(40), user1, uuid.NewV4().String(), "eth",
+		sdk.NewCUAddress(), "0x12b3c42a12fe9", sdk.NewInt(3), sdk.NewInt(3), sdk.NewInt(3), "txhash", 0, "")
+	ok.SetOrder(ctx, c1)
+
+	ids := func(orders []sdk.Order) []string {
+		var res []string
+		for _, o := range orders {
+			res = append(res, o.GetID())
+		}
+		return res
+	}
+
+	// newest first
+	assert.Equal(t, []string{c1.ID, w3.ID, w2.ID, w1.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{c1.ID, w2.ID, w1.ID}, ids(ok.GetOrderList(ctx, user1, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{w2.ID, w1.ID}, ids(ok.GetOrderList(ctx, user1, "", sdk.OrderTypeWithdrawal, 0, 0, 0)))
+	assert.Equal(t, []string{c1.ID, w1.ID}, ids(ok.GetOrderList(ctx, user1, "eth", 0, 0, 0, 0)))
+	assert.Equal(t, []string{w3.ID, w1.ID}, ids(ok.GetOrderList(ctx, nil, "eth", sdk.OrderTypeWithdrawal, 0, 0, 0)))
+
+	// height range is inclusive
+	assert.Equal(t, []string{w3.ID, w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 20, 30)))
+	assert.Equal(t, []string{w2.ID}, ids(ok.GetOrderList(ctx, user1, "", sdk.OrderTypeWithdrawal, 0, 11, 39)))
+
+	// status index follows the order
+	w2.SetOrderStatus(sdk.OrderStatusFinish)
+	ok.SetOrder(ctx, w2)
+	assert.Equal(t, []string{w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, sdk.OrderStatusFinish, 0, 0)))
+	assert.Equal(t, []string{c1.ID, w1.ID}, ids(ok.GetOrderList(ctx, user1, "", 0, sdk.OrderStatusBegin, 0, 0)))
+
+	ok.DeleteOrder(ctx, w1)
+	assert.Equal(t, []string{c1.ID, w2.ID}, ids(ok.GetOrderList(ctx, user1, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{c1.ID, w3.ID, w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 0, 0)))
+
+	// all the index entries follow the height of the order
+	w3.Height = 50
+	w3.SetOrderStatus(sdk.OrderStatusWaitSign)
+	ok.SetOrder(ctx, w3)
+	assert.Equal(t, []string{w3.ID, c1.ID, w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{w3.ID}, ids(ok.GetOrderList(ctx, user2, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{w3.ID}, ids(ok.GetOrderList(ctx, nil, "eth", sdk.OrderTypeWithdrawal, sdk.OrderStatusWaitSign, 0, 0)))
+	assert.Empty(t, ok.GetOrderList(ctx, nil, "", 0, 0, 30, 30))
+	assert.Empty(t, ok.GetOrderList(ctx, nil, "", 0, sdk.OrderStatusBegin, 50, 50))
+
+	// orders stored without indexes are indexed by the migration
+	ok.deleteOrderIndexes(ctx, c1)
+	assert.Equal(t, []string{w3.ID, w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 0, 0)))
+	ok.BuildOrderIndexes(ctx)
+	assert.Equal(t, []string{w3.ID, c1.ID, w2.ID}, ids(ok.GetOrderList(ctx, nil, "", 0, 0, 0, 0)))
+	assert.Equal(t, []string{c1.ID, w2.ID}, ids(ok.GetOrderList(ctx, user1, "", 0, 0, 0, 0)))
+
+	// paginated query
+	querier := NewQuerier(ok)
+	bz, err := input.cdc.MarshalJSON(types.NewQueryOrderListParams(nil, "", 0, 0, 0, 0, 2, 2))
+	assert.Nil(t, err)
+	res, sdkErr := querier(ctx, []string{types.QueryOrderList}, abci.RequestQuery{Data: bz})
+	assert.Nil(t, sdkErr)
+	var resOrders types.QueryResOrderList
+	assert.Nil(t, input.cdc.UnmarshalJSON(res, &resOrders))
+	assert.Equal(t, 3, resOrders.Total)
+	assert.Equal(t, []string{w2.ID}, ids(resOrders.Orders))
+}
+
 type testInput struct {
 	cdc *codec.Codec
 	ctx sdk.Context
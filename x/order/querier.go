@@ -3,6 +3,7 @@ package order
 import (
 	"fmt"
 
+	"github.com/hbtc-chain/bhchain/client"
 	"github.com/hbtc-chain/bhchain/codec"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/x/order/types"
@@ -17,6 +18,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryOrder(ctx, req, keeper)
 		case types.QueryProcessList:
 			return queryProcessList(ctx, req, keeper)
+		case types.QueryOrderList:
+			return queryOrderList(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown order query endpoint")
 		}
@@ -49,3 +52,31 @@ func queryProcessList(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]
 	}
 	return res, nil
 }
+
+// nolint: unparam
+func queryOrderList(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params types.QueryOrderListParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	if params.EndHeight != 0 && params.EndHeight < params.StartHeight {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("invalid height range [%v, %v]", params.StartHeight, params.EndHeight))
+	}
+
+	orderIDs := keeper.GetOrderIDList(ctx, params.CUAddress, params.Symbol, params.OrderType, params.Status,
+		params.StartHeight, params.EndHeight)
+	resOrders := types.QueryResOrderList{Total: len(orderIDs), Orders: []sdk.Order{}}
+
+	start, end := client.Paginate(len(orderIDs), params.Page, params.Limit, types.DefaultOrderListLimit)
+	if start >= 0 && end >= 0 {
+		for _, orderID := range orderIDs[start:end] {
+			resOrders.Orders = append(resOrders.Orders, keeper.GetOrder(ctx, orderID))
+		}
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, resOrders)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+	return res, nil
+}
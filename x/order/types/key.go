@@ -1,5 +1,12 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
 const (
 	// module name
 	ModuleName = "order"
@@ -16,6 +23,13 @@ const (
 	// query endpoints supported by the nameservice Querier
 	QueryOrder       = "order"
 	QueryProcessList = "processList"
+	QueryOrderList   = "orderList"
+
+	// DefaultOrderListLimit is the default page size of order list query
+	DefaultOrderListLimit = 30
+
+	// OrderIndexUpgrade is the name of the upgrade indexing the orders stored before the order indexes
+	OrderIndexUpgrade = "order-index"
 )
 
 var (
@@ -30,6 +44,12 @@ var (
 	ProcessOrderStoreKeyPrefix = []byte{0x03, 0x04}
 	// OrderNumber Key : OrderIDKey + OrderStoreKeyPrefix + cuaddress
 	OrderIDKey = []byte("orderIdKey")
+
+	// order index key : index prefix + index value + height + orderID
+	CUOrderIndexKeyPrefix     = []byte{0x05, 0x01}
+	SymbolOrderIndexKeyPrefix = []byte{0x05, 0x02}
+	TypeOrderIndexKeyPrefix   = []byte{0x05, 0x03}
+	StatusOrderIndexKeyPrefix = []byte{0x05, 0x04}
 )
 
 type QueryOrderParams struct {
@@ -39,3 +59,44 @@ type QueryOrderParams struct {
 func NewQueryOrderParams(orderID string) QueryOrderParams {
 	return QueryOrderParams{OrderID: orderID}
 }
+
+// QueryOrderListParams filters the order list, zero value of a field means no filter on it.
+// EndHeight is inclusive.
+type QueryOrderListParams struct {
+	CUAddress   sdk.CUAddress   `json:"cu_address"`
+	Symbol      string          `json:"symbol"`
+	OrderType   sdk.OrderType   `json:"order_type"`
+	Status      sdk.OrderStatus `json:"status"`
+	StartHeight uint64          `json:"start_height"`
+	EndHeight   uint64          `json:"end_height"`
+	Page        int             `json:"page"`
+	Limit       int             `json:"limit"`
+}
+
+func NewQueryOrderListParams(cuAddress sdk.CUAddress, symbol string, orderType sdk.OrderType, status sdk.OrderStatus,
+	startHeight, endHeight uint64, page, limit int) QueryOrderListParams {
+	return QueryOrderListParams{
+		CUAddress:   cuAddress,
+		Symbol:      symbol,
+		OrderType:   orderType,
+		Status:      status,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Page:        page,
+		Limit:       limit,
+	}
+}
+
+// QueryResOrderList is one page of the order list, newest first
+type QueryResOrderList struct {
+	Total  int         `json:"total"`
+	Orders []sdk.Order `json:"orders"`
+}
+
+func (r QueryResOrderList) String() string {
+	out := fmt.Sprintf("Total:%v\n", r.Total)
+	for _, order := range r.Orders {
+		out += order.String() + "\n"
+	}
+	return strings.TrimSpace(out)
+}
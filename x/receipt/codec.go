@@ -20,6 +20,15 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(DepositFlow{}, "hbtcchain/receipt/DepositFlow", nil)
 	cdc.RegisterConcrete(MappingBalanceFlow{}, "hbtcchain/receipt/MappingBalanceFlow", nil)
 	cdc.RegisterConcrete(sdk.DepositConfirmedFlow{}, "hbtcchain/receipt/DepositConfrimedFlow", nil)
+	cdc.RegisterConcrete(sdk.DepositInvalidatedFlow{}, "hbtcchain/receipt/DepositInvalidatedFlow", nil)
+	cdc.RegisterConcrete(sdk.DepositRoutedFlow{}, "hbtcchain/receipt/DepositRoutedFlow", nil)
+	cdc.RegisterConcrete(sdk.SuspenseReassignedFlow{}, "hbtcchain/receipt/SuspenseReassignedFlow", nil)
+	cdc.RegisterConcrete(sdk.HTLCCreatedFlow{}, "hbtcchain/receipt/HTLCCreatedFlow", nil)
+	cdc.RegisterConcrete(sdk.HTLCClaimedFlow{}, "hbtcchain/receipt/HTLCClaimedFlow", nil)
+	cdc.RegisterConcrete(sdk.HTLCRefundedFlow{}, "hbtcchain/receipt/HTLCRefundedFlow", nil)
+	cdc.RegisterConcrete(sdk.ScheduledTransferCreatedFlow{}, "hbtcchain/receipt/ScheduledTransferCreatedFlow", nil)
+	cdc.RegisterConcrete(sdk.ScheduledTransferExecutedFlow{}, "hbtcchain/receipt/ScheduledTransferExecutedFlow", nil)
+	cdc.RegisterConcrete(sdk.ScheduledTransferCancelledFlow{}, "hbtcchain/receipt/ScheduledTransferCancelledFlow", nil)
 	cdc.RegisterConcrete(sdk.CollectWaitSignFlow{}, "hbtcchain/receipt/CollectWaitSignFlow", nil)
 	cdc.RegisterConcrete(sdk.CollectSignFinishFlow{}, "hbtcchain/receipt/CollectSignFinishFlow", nil)
 	cdc.RegisterConcrete(sdk.CollectFinishFlow{}, "hbtcchain/receipt/CollectFinishFlow", nil)
@@ -28,6 +37,8 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(sdk.WithdrawalWaitSignFlow{}, "hbtcchain/receipt/WithdrawalWaitSignFlow", nil)
 	cdc.RegisterConcrete(sdk.WithdrawalSignFinishFlow{}, "hbtcchain/receipt/WithdrawalSignFinishFlow", nil)
 	cdc.RegisterConcrete(sdk.WithdrawalFinishFlow{}, "hbtcchain/receipt/WithdrawalFinishFlow", nil)
+	cdc.RegisterConcrete(sdk.BatchWithdrawalFlow{}, "hbtcchain/receipt/BatchWithdrawalFlow", nil)
+	cdc.RegisterConcrete(sdk.WithdrawalFeeBumpFlow{}, "hbtcchain/receipt/WithdrawalFeeBumpFlow", nil)
 	cdc.RegisterConcrete(sdk.SysTransferFlow{}, "hbtcchain/receipt/SysTransferFlow", nil)
 	cdc.RegisterConcrete(sdk.SysTransferWaitSignFlow{}, "hbtcchain/receipt/SysTransferWaitSignFlow", nil)
 	cdc.RegisterConcrete(sdk.SysTransferSignFinishFlow{}, "hbtcchain/receipt/SysTransferSignFinishFlow", nil)
@@ -36,6 +47,9 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(sdk.OpcuAssetTransferWaitSignFlow{}, "hbtcchain/receipt/OpcuAssetTransferWaitSignFlow", nil)
 	cdc.RegisterConcrete(sdk.OpcuAssetTransferSignFinishFlow{}, "hbtcchain/receipt/OpcuAssetTransferSignFinishFlow", nil)
 	cdc.RegisterConcrete(sdk.OpcuAssetTransferFinishFlow{}, "hbtcchain/receipt/OpcuAssetTransferFinishFlow", nil)
+	cdc.RegisterConcrete(sdk.UtxoConsolidationWaitSignFlow{}, "hbtcchain/receipt/UtxoConsolidationWaitSignFlow", nil)
+	cdc.RegisterConcrete(sdk.UtxoConsolidationSignFinishFlow{}, "hbtcchain/receipt/UtxoConsolidationSignFinishFlow", nil)
+	cdc.RegisterConcrete(sdk.UtxoConsolidationFinishFlow{}, "hbtcchain/receipt/UtxoConsolidationFinishFlow", nil)
 
 	cdc.RegisterConcrete(sdk.KeyGenFlow{}, "hbtcchain/receipt/KeyGenFlow", nil)
 	cdc.RegisterConcrete(sdk.KeyGenWaitSignFlow{}, "hbtcchain/receipt/KeyGenWaitSignFlow", nil)
@@ -24,6 +24,11 @@ type ReceiptKeeperI interface {
 	SaveReceiptToResult(receipt *sdk.Receipt, result *Result) *Result
 
 	GetReceiptFromResult(result *Result) (*sdk.Receipt, error)
+
+	// SaveReceiptToEvents emits the receipt as an event.
+	SaveReceiptToEvents(receipt *sdk.Receipt, em *sdk.EventManager)
+
+	GetReceiptsFromEvents(events sdk.Events) ([]*sdk.Receipt, error)
 }
 
 var _ ReceiptKeeperI = (*Keeper)(nil)
@@ -91,6 +96,99 @@ func (r *Keeper) NewDepositConfirmedFlow(validOrderIds, invalidOrderIds []string
 	}
 }
 
+func (r *Keeper) NewDepositInvalidatedFlow(orderID, cuAddress, symbol, txHash string, index uint64, amount, heldAmount, deficit sdk.Int) sdk.DepositInvalidatedFlow {
+	return sdk.DepositInvalidatedFlow{
+		OrderID:    orderID,
+		CuAddress:  cuAddress,
+		Symbol:     symbol,
+		Txhash:     txHash,
+		Index:      index,
+		Amount:     amount,
+		HeldAmount: heldAmount,
+		Deficit:    deficit,
+	}
+}
+
+func (r *Keeper) NewDepositRoutedFlow(orderID, fromCU, toCU, memo, symbol string, amount sdk.Int, suspense bool) sdk.DepositRoutedFlow {
+	return sdk.DepositRoutedFlow{
+		OrderID:  orderID,
+		FromCU:   fromCU,
+		ToCU:     toCU,
+		Memo:     memo,
+		Symbol:   symbol,
+		Amount:   amount,
+		Suspense: suspense,
+	}
+}
+
+func (r *Keeper) NewSuspenseReassignedFlow(fromCU, toCU, symbol string, amount sdk.Int) sdk.SuspenseReassignedFlow {
+	return sdk.SuspenseReassignedFlow{
+		FromCU: fromCU,
+		ToCU:   toCU,
+		Symbol: symbol,
+		Amount: amount,
+	}
+}
+
+func (r *Keeper) NewHTLCCreatedFlow(hashlock, sender, receiver string, amount sdk.Coins, expireHeight uint64) sdk.HTLCCreatedFlow {
+	return sdk.HTLCCreatedFlow{
+		Hashlock:     hashlock,
+		Sender:       sender,
+		Receiver:     receiver,
+		Amount:       amount,
+		ExpireHeight: expireHeight,
+	}
+}
+
+func (r *Keeper) NewHTLCClaimedFlow(hashlock, preimage, sender, receiver string, amount sdk.Coins) sdk.HTLCClaimedFlow {
+	return sdk.HTLCClaimedFlow{
+		Hashlock: hashlock,
+		Preimage: preimage,
+		Sender:   sender,
+		Receiver: receiver,
+		Amount:   amount,
+	}
+}
+
+func (r *Keeper) NewHTLCRefundedFlow(hashlock, sender string, amount sdk.Coins) sdk.HTLCRefundedFlow {
+	return sdk.HTLCRefundedFlow{
+		Hashlock: hashlock,
+		Sender:   sender,
+		Amount:   amount,
+	}
+}
+
+func (r *Keeper) NewScheduledTransferCreatedFlow(id uint64, fromCU, toCU string, amount sdk.Coins, byTime bool, start, interval, times uint64) sdk.ScheduledTransferCreatedFlow {
+	return sdk.ScheduledTransferCreatedFlow{
+		ID:       id,
+		FromCU:   fromCU,
+		ToCU:     toCU,
+		Amount:   amount,
+		ByTime:   byTime,
+		Start:    start,
+		Interval: interval,
+		Times:    times,
+	}
+}
+
+func (r *Keeper) NewScheduledTransferExecutedFlow(id uint64, fromCU, toCU string, amount sdk.Coins, remaining uint64) sdk.ScheduledTransferExecutedFlow {
+	return sdk.ScheduledTransferExecutedFlow{
+		ID:        id,
+		FromCU:    fromCU,
+		ToCU:      toCU,
+		Amount:    amount,
+		Remaining: remaining,
+	}
+}
+
+func (r *Keeper) NewScheduledTransferCancelledFlow(id uint64, fromCU string, refund sdk.Coins) sdk.ScheduledTransferCancelledFlow {
+	return sdk.ScheduledTransferCancelledFlow{
+		ID:     id,
+		FromCU: fromCU,
+		Refund: refund,
+	}
+}
+
 func (r *Keeper) NewOrderRetryFlow(orderIDs []string, excludedKeyNode sdk.CUAddress) sdk.OrderRetryFlow {
 	return sdk.OrderRetryFlow{
 		OrderIDs:        orderIDs,
@@ -107,6 +205,32 @@ func (r *Keeper) SaveReceiptToResult(receipt *sdk.Receipt, result *Result) *Resu
 	return result
 }
 
+// SaveReceiptToEvents emits the receipt as an event, for the state transitions not caused by a tx, e.g. in EndBlocker.
+func (r *Keeper) SaveReceiptToEvents(receipt *sdk.Receipt, em *sdk.EventManager) {
+	em.EmitEvent(sdk.NewEvent(TagKeyReceipt, sdk.NewAttribute(TagKeyReceipt, string(r.cdc.MustMarshalJSON(*receipt)))))
+}
+
+// GetReceiptsFromEvents returns the receipts emitted by SaveReceiptToEvents.
+func (r *Keeper) GetReceiptsFromEvents(events sdk.Events) ([]*sdk.Receipt, error) {
+	var receipts []*sdk.Receipt
+	for _, event := range events {
+		if event.Type != TagKeyReceipt {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if string(attr.Key) != TagKeyReceipt {
+				continue
+			}
+			var rc sdk.Receipt
+			if err := ModuleCdc.UnmarshalJSON(attr.Value, &rc); err != nil {
+				return nil, err
+			}
+			receipts = append(receipts, &rc)
+		}
+	}
+	return receipts, nil
+}
+
 func (r *Keeper) GetReceiptFromResult(result *Result) (*sdk.Receipt, error) {
 	var rc sdk.Receipt
 
@@ -172,11 +296,29 @@ func (r *Keeper) NewWithdrawalSignFinishFlow(orderIDs []string, signedTx []byte)
 	}
 }
 
-func (r *Keeper) NewWithdrawalFinishFlow(orderIDs []string, costFee sdk.Int, valid bool) sdk.WithdrawalFinishFlow {
+func (r *Keeper) NewWithdrawalFinishFlow(orderIDs []string, costFee sdk.Int, valid bool, bumpedFee sdk.Int) sdk.WithdrawalFinishFlow {
 	return sdk.WithdrawalFinishFlow{
+		OrderIDs:  orderIDs,
+		CostFee:   costFee,
+		Valid:     valid,
+		BumpedFee: bumpedFee,
+	}
+}
+
+func (r *Keeper) NewBatchWithdrawalFlow(batchID string, orderIDs []string) sdk.BatchWithdrawalFlow {
+	return sdk.BatchWithdrawalFlow{
+		BatchID:  batchID,
 		OrderIDs: orderIDs,
-		CostFee:  costFee,
-		Valid:    valid,
+	}
+}
+
+func (r *Keeper) NewWithdrawalFeeBumpFlow(orderIDs []string, replacedTxHash string, rawData []byte, costFee, bumpedFee sdk.Int) sdk.WithdrawalFeeBumpFlow {
+	return sdk.WithdrawalFeeBumpFlow{
+		OrderIDs:       orderIDs,
+		ReplacedTxHash: replacedTxHash,
+		RawData:        rawData,
+		CostFee:        costFee,
+		BumpedFee:      bumpedFee,
 	}
 }
 
@@ -248,3 +390,24 @@ func (r *Keeper) NewOpcuAssetTransferFinishFlow(orderID string, costFee Int) sdk
 		CostFee: costFee,
 	}
 }
+
+func (r *Keeper) NewUtxoConsolidationWaitSignFlow(orderID string, rawData []byte) sdk.UtxoConsolidationWaitSignFlow {
+	return sdk.UtxoConsolidationWaitSignFlow{
+		OrderID: orderID,
+		RawData: rawData,
+	}
+}
+
+func (r *Keeper) NewUtxoConsolidationSignFinishFlow(orderID string, signedTx []byte) sdk.UtxoConsolidationSignFinishFlow {
+	return sdk.UtxoConsolidationSignFinishFlow{
+		OrderID:  orderID,
+		SignedTx: signedTx,
+	}
+}
+
+func (r *Keeper) NewUtxoConsolidationFinishFlow(orderID string, costFee Int) sdk.UtxoConsolidationFinishFlow {
+	return sdk.UtxoConsolidationFinishFlow{
+		OrderID: orderID,
+		CostFee: costFee,
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hbtc-chain/bhchain/codec"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/version"
+	"github.com/hbtc-chain/bhchain/x/staking/client/utils"
 	"github.com/hbtc-chain/bhchain/x/staking/types"
 )
 
@@ -40,6 +41,7 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		GetCmdQueryParams(queryRoute, cdc),
 		GetCmdQueryPool(queryRoute, cdc),
 		GetCmdQueryEpoch(queryRoute, cdc),
+		GetCmdQueryKeyNodeStats(cdc),
 	)...)
 
 	return stakingQueryCmd
@@ -641,3 +643,41 @@ $ %s query staking epoch [--index 1] [--block 100]
 	cmd.Flags().String(FlagEpochHeight, "", "The height of epoch")
 	return cmd
 }
+
+// GetCmdQueryKeyNodeStats implements the key node stats query command.
+func GetCmdQueryKeyNodeStats(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keynode-stats",
+		Short: "Query the liveness and signing participation of the key nodes of an epoch, default to current",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the last heartbeat and the behaviours of the key nodes of an epoch, default to current.
+The epoch behaviours are counted within the epoch: "keygen" is the keygen participation, "dsign" the
+sign participation missed by OrderRetry evidence and "Vote" the participation in the votes of the
+transactions. The missed order retries are the OrderRetry evidences against the key node within the
+epoch. The last heartbeat and the behaviours within their sliding windows are the current ones.
+
+Example:
+$ %s query staking keynode-stats [--index 1] [--block 100]
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			var params types.QueryEpochParams
+			params.Index, _ = strconv.ParseUint(viper.GetString(FlagEpochIndex), 10, 64)
+			params.Height, _ = strconv.ParseUint(viper.GetString(FlagEpochHeight), 10, 64)
+
+			stats, _, err := utils.QueryKeyNodeStats(cliCtx, params)
+			if err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(stats)
+		},
+	}
+
+	cmd.Flags().String(FlagEpochIndex, "", "The index of epoch")
+	cmd.Flags().String(FlagEpochHeight, "", "The height of epoch")
+	return cmd
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hbtc-chain/bhchain/client/context"
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/types/rest"
+	"github.com/hbtc-chain/bhchain/x/staking/client/utils"
 	"github.com/hbtc-chain/bhchain/x/staking/types"
 )
 
@@ -104,6 +105,12 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		epochHandlerFn(cliCtx),
 	).Methods("GET")
 
+	// Get the current liveness and signing participation of the key nodes of the current epoch
+	r.HandleFunc(
+		"/staking/keynode_stats",
+		keyNodeStatsHandlerFn(cliCtx),
+	).Methods("GET")
+
 }
 
 // HTTP request handler to query a delegator delegations
@@ -383,3 +390,23 @@ func epochHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+func keyNodeStatsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+		index, _ := strconv.ParseUint(r.FormValue("index"), 10, 64)
+		blockHeight, _ := strconv.ParseUint(r.FormValue("block"), 10, 64)
+
+		stats, height, err := utils.QueryKeyNodeStats(cliCtx, types.NewQueryEpochParams(index, blockHeight))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, stats)
+	}
+}
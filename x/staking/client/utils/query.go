@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/hbtc-chain/bhchain/client/context"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	evidencetypes "github.com/hbtc-chain/bhchain/x/evidence/types"
+	"github.com/hbtc-chain/bhchain/x/staking/types"
+)
+
+// QueryKeyNodeStats queries the liveness of the key nodes of the epoch selected by params from the staking module,
+// and completes it with their behaviours from the evidence module. It returns the stats and the height of the query.
+func QueryKeyNodeStats(cliCtx context.CLIContext, params types.QueryEpochParams) (types.KeyNodeStats, int64, error) {
+	var stats types.KeyNodeStats
+	bz, err := cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return stats, 0, err
+	}
+	res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryKeyNodeStats), bz)
+	if err != nil {
+		return stats, 0, err
+	}
+	if err = cliCtx.Codec.UnmarshalJSON(res, &stats); err != nil {
+		return stats, 0, err
+	}
+	if len(stats.KeyNodes) == 0 {
+		return stats, height, nil
+	}
+
+	// query the behaviours at the same height
+	cliCtx = cliCtx.WithHeight(height)
+	validators := make([]sdk.ValAddress, 0, len(stats.KeyNodes))
+	for _, keyNode := range stats.KeyNodes {
+		validators = append(validators, sdk.ValAddress(keyNode.Address))
+	}
+	bz, err = cliCtx.Codec.MarshalJSON(evidencetypes.NewQueryValidatorBehavioursParams(validators, stats.Epoch))
+	if err != nil {
+		return stats, 0, err
+	}
+	res, _, err = cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", evidencetypes.QuerierRoute, evidencetypes.QueryValidatorBehaviours), bz)
+	if err != nil {
+		return stats, 0, err
+	}
+	var behaviours []evidencetypes.ValidatorBehaviourStats
+	if err = cliCtx.Codec.UnmarshalJSON(res, &behaviours); err != nil {
+		return stats, 0, err
+	}
+	for i := range stats.KeyNodes {
+		if i >= len(behaviours) {
+			break
+		}
+		stats.KeyNodes[i].Behaviours = behaviours[i].Behaviours
+		stats.KeyNodes[i].EpochBehaviours = behaviours[i].EpochBehaviours
+		for _, behaviour := range behaviours[i].EpochBehaviours {
+			if behaviour.Behaviour == evidencetypes.DsignBehaviourKey {
+				stats.KeyNodes[i].MissedOrderRetries = behaviour.MisbehaviourCounter
+			}
+		}
+	}
+	return stats, height, nil
+}
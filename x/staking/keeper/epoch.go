@@ -76,3 +76,26 @@ func (k Keeper) SetMigrationFinished(ctx sdk.Context) {
 	epoch.MigrationFinished = true
 	k.SetEpoch(ctx, epoch)
 }
+
+// GetKeyNodeStats returns the current liveness of the key nodes of epoch
+func (k Keeper) GetKeyNodeStats(ctx sdk.Context, epoch sdk.Epoch) types.KeyNodeStats {
+	stats := types.KeyNodeStats{
+		Epoch:         epoch.Index,
+		StartBlockNum: epoch.StartBlockNum,
+		EndBlockNum:   epoch.EndBlockNum,
+		KeyNodes:      make([]types.KeyNodeStat, 0, len(epoch.KeyNodeSet)),
+	}
+	height := uint64(ctx.BlockHeight())
+	maxInterval := k.MaxCandidateKeyNodeHeartbeatInterval(ctx)
+	for _, keyNode := range epoch.KeyNodeSet {
+		stat := types.KeyNodeStat{Address: keyNode, HeartbeatOverdue: true}
+		if val, found := k.GetValidator(ctx, sdk.ValAddress(keyNode)); found {
+			stat.Moniker = val.Description.Moniker
+			stat.Jailed = val.Jailed
+			stat.LastHeartbeatHeight = val.LastKeyNodeHeartbeatHeight
+			stat.HeartbeatOverdue = height-val.LastKeyNodeHeartbeatHeight > maxInterval
+		}
+		stats.KeyNodes = append(stats.KeyNodes, stat)
+	}
+	return stats
+}
@@ -44,6 +44,8 @@ func NewQuerier(k Keeper) sdk.Querier {
 			return queryParameters(ctx, k)
 		case types.QueryEpoch:
 			return queryEpoch(ctx, req, k)
+		case types.QueryKeyNodeStats:
+			return queryKeyNodeStats(ctx, req, k)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown staking query endpoint")
 		}
@@ -347,17 +349,9 @@ func queryEpoch(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.E
 		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
 	}
 
-	var epoch sdk.Epoch
-	if params.Index == 0 && params.Height == 0 {
-		epoch = k.GetCurrentEpoch(ctx)
-	} else if params.Index > 0 {
-		var found bool
-		epoch, found = k.GetEpoch(ctx, params.Index)
-		if !found {
-			return nil, sdk.ErrInternal(fmt.Sprintf("epoch %d not found", params.Index))
-		}
-	} else {
-		epoch = k.GetEpochByHeight(ctx, params.Height)
+	epoch, sdkErr := getQueriedEpoch(ctx, k, params)
+	if sdkErr != nil {
+		return nil, sdkErr
 	}
 	res, err := codec.MarshalJSONIndent(types.ModuleCdc, epoch)
 	if err != nil {
@@ -367,9 +361,45 @@ func queryEpoch(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.E
 	return res, nil
 }
 
+func queryKeyNodeStats(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryEpochParams
+
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	epoch, sdkErr := getQueriedEpoch(ctx, k, params)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+	stats := k.GetKeyNodeStats(ctx, epoch)
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, stats)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
 //______________________________________________________
 // util
 
+// getQueriedEpoch returns the epoch of the given index, or the one of the given height, or the current one
+func getQueriedEpoch(ctx sdk.Context, k Keeper, params types.QueryEpochParams) (sdk.Epoch, sdk.Error) {
+	if params.Index == 0 && params.Height == 0 {
+		return k.GetCurrentEpoch(ctx), nil
+	}
+	if params.Index > 0 {
+		epoch, found := k.GetEpoch(ctx, params.Index)
+		if !found {
+			return epoch, sdk.ErrInternal(fmt.Sprintf("epoch %d not found", params.Index))
+		}
+		return epoch, nil
+	}
+	return k.GetEpochByHeight(ctx, params.Height), nil
+}
+
 func delegationToDelegationResponse(ctx sdk.Context, k Keeper, del types.Delegation) (types.DelegationResponse, sdk.Error) {
 	val, found := k.GetValidator(ctx, del.ValidatorAddress)
 	if !found {
@@ -613,3 +613,50 @@ func TestQueryEpoch(t *testing.T) {
 	require.NoError(t, cdc.UnmarshalJSON(res, &epoch))
 	fmt.Printf("%v\n", epoch)
 }
+
+func TestQueryKeyNodeStats(t *testing.T) {
+	cdc := codec.New()
+	ctx, _, keeper, _ := CreateTestInput(t, false, 10000)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 254})
+	vals := addrVals[2 : 2+3]
+	for i, addr := range vals {
+		val := types.NewValidator(sdk.ValAddress(addr), PKs[i], types.Description{Moniker: fmt.Sprintf("node%d", i)})
+		val.LastKeyNodeHeartbeatHeight = uint64(100 + 100*i)
+		keeper.SetValidator(ctx, val)
+	}
+	keeper.StartNewEpoch(ctx, vals)
+
+	query := abci.RequestQuery{
+		Path: "/custom/staking/" + types.QueryKeyNodeStats,
+		Data: cdc.MustMarshalJSON(types.NewQueryEpochParams(0, 0)),
+	}
+	res, err := queryKeyNodeStats(ctx.WithBlockHeight(300), query, keeper)
+	require.Nil(t, err)
+
+	var stats types.KeyNodeStats
+	require.NoError(t, cdc.UnmarshalJSON(res, &stats))
+	require.Equal(t, uint64(2), stats.Epoch)
+	require.Equal(t, uint64(255), stats.StartBlockNum)
+	require.Len(t, stats.KeyNodes, 3)
+	for i, keyNode := range stats.KeyNodes {
+		require.Equal(t, vals[i], keyNode.Address)
+		require.Equal(t, fmt.Sprintf("node%d", i), keyNode.Moniker)
+		require.Equal(t, uint64(100+100*i), keyNode.LastHeartbeatHeight)
+	}
+	// the heartbeat interval is 100 blocks by default
+	require.True(t, stats.KeyNodes[0].HeartbeatOverdue)
+	require.False(t, stats.KeyNodes[1].HeartbeatOverdue)
+	require.False(t, stats.KeyNodes[2].HeartbeatOverdue)
+
+	// the first epoch, selected by its height
+	query.Data = cdc.MustMarshalJSON(types.NewQueryEpochParams(0, 100))
+	res, err = queryKeyNodeStats(ctx.WithBlockHeight(300), query, keeper)
+	require.Nil(t, err)
+	require.NoError(t, cdc.UnmarshalJSON(res, &stats))
+	require.Equal(t, uint64(1), stats.Epoch)
+
+	query.Data = cdc.MustMarshalJSON(types.NewQueryEpochParams(3, 0))
+	_, err = queryKeyNodeStats(ctx.WithBlockHeight(300), query, keeper)
+	require.NotNil(t, err)
+}
@@ -1,7 +1,11 @@
 package types
 
 import (
+	"fmt"
+	"strings"
+
 	sdk "github.com/hbtc-chain/bhchain/types"
+	evidencetypes "github.com/hbtc-chain/bhchain/x/evidence/types"
 )
 
 // query endpoints supported by the staking Querier
@@ -21,6 +25,7 @@ const (
 	QueryPool                          = "pool"
 	QueryParameters                    = "parameters"
 	QueryEpoch                         = "epoch"
+	QueryKeyNodeStats                  = "keynode_stats"
 )
 
 // defines the params for the following queries:
@@ -74,6 +79,59 @@ func NewQueryEpochParams(index, height uint64) QueryEpochParams {
 	}
 }
 
+// KeyNodeStats is the liveness and signing participation of the key nodes of an epoch
+type KeyNodeStats struct {
+	Epoch         uint64        `json:"epoch" yaml:"epoch"`
+	StartBlockNum uint64        `json:"start_block_num" yaml:"start_block_num"`
+	EndBlockNum   uint64        `json:"end_block_num" yaml:"end_block_num"`
+	KeyNodes      []KeyNodeStat `json:"key_nodes" yaml:"key_nodes"`
+}
+
+// KeyNodeStat is the liveness and signing participation of a key node. The heartbeat and the window
+// behaviours are the current ones of the validator, the epoch behaviours are counted within the epoch
+// of the stats. The behaviours are queried from the evidence module.
+type KeyNodeStat struct {
+	Address             sdk.CUAddress `json:"address" yaml:"address"`
+	Moniker             string        `json:"moniker" yaml:"moniker"`
+	Jailed              bool          `json:"jailed" yaml:"jailed"`
+	LastHeartbeatHeight uint64        `json:"last_heartbeat_height" yaml:"last_heartbeat_height"`
+	// HeartbeatOverdue is set if no heartbeat was sent within MaxCandidateKeyNodeHeartbeatInterval blocks
+	HeartbeatOverdue bool `json:"heartbeat_overdue" yaml:"heartbeat_overdue"`
+	// MissedOrderRetries is the number of OrderRetry evidences against the key node within the epoch,
+	// which is the epoch misbehaviour counter of the dsign behaviour
+	MissedOrderRetries int64                               `json:"missed_order_retries" yaml:"missed_order_retries"`
+	Behaviours         []evidencetypes.BehaviourStats      `json:"behaviours" yaml:"behaviours"`
+	EpochBehaviours    []evidencetypes.EpochBehaviourStats `json:"epoch_behaviours" yaml:"epoch_behaviours"`
+}
+
+func (s KeyNodeStats) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`Key Node Stats
+  Epoch:           %d
+  Start Block Num: %d
+  End Block Num:   %d`, s.Epoch, s.StartBlockNum, s.EndBlockNum))
+	for _, keyNode := range s.KeyNodes {
+		b.WriteString(fmt.Sprintf(`
+  Key Node %s (%s)
+    Jailed:                %t
+    Last Heartbeat Height: %d
+    Heartbeat Overdue:     %t
+    Missed Order Retries:  %d`, keyNode.Address, keyNode.Moniker, keyNode.Jailed, keyNode.LastHeartbeatHeight, keyNode.HeartbeatOverdue,
+			keyNode.MissedOrderRetries))
+		for _, behaviour := range keyNode.Behaviours {
+			b.WriteString(fmt.Sprintf(`
+    %s: participation %s, misbehaviours %d/%d of %d recorded in window %d`, behaviour.Behaviour,
+				behaviour.ParticipationRate, behaviour.MisbehaviourCounter, behaviour.MaxMisbehaviourCount, behaviour.Recorded, behaviour.Window))
+		}
+		for _, behaviour := range keyNode.EpochBehaviours {
+			b.WriteString(fmt.Sprintf(`
+    %s: participation %s, misbehaviours %d of %d recorded in epoch %d`, behaviour.Behaviour,
+				behaviour.ParticipationRate, behaviour.MisbehaviourCounter, behaviour.Recorded, s.Epoch))
+		}
+	}
+	return b.String()
+}
+
 func NewQueryBondsParams(delegatorAddr sdk.CUAddress, validatorAddr sdk.ValAddress) QueryBondsParams {
 	return QueryBondsParams{
 		DelegatorAddr: delegatorAddr,
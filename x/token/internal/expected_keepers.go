@@ -2,9 +2,14 @@ package internal
 
 import (
 	sdk "github.com/hbtc-chain/bhchain/types"
+	cuexported "github.com/hbtc-chain/bhchain/x/custodianunit/exported"
 	"github.com/hbtc-chain/bhchain/x/evidence/exported"
 )
 
+type CUKeeper interface {
+	GetCU(ctx sdk.Context, addr sdk.CUAddress) cuexported.CustodianUnit
+}
+
 type StakingKeeper interface {
 	IsActiveKeyNode(ctx sdk.Context, addr sdk.CUAddress) (bool, int)
 }
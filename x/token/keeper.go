@@ -16,6 +16,7 @@ type Keeper struct {
 	cdc            *codec.Codec // The wire codec for binary encoding/decoding
 	sk             internal.StakingKeeper
 	evidenceKeeper internal.EvidenceKeeper
+	ck             internal.CUKeeper
 }
 
 func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec) Keeper {
@@ -38,6 +39,10 @@ func (k *Keeper) SetEvidenceKeeper(evidenceKeeper internal.EvidenceKeeper) {
 	k.evidenceKeeper = evidenceKeeper
 }
 
+func (k *Keeper) SetCUKeeper(ck internal.CUKeeper) {
+	k.ck = ck
+}
+
 //Set entire TokenInfo
 func (k *Keeper) SetToken(ctx sdk.Context, tokenInfo sdk.Token) {
 	store := ctx.KVStore(k.storeKey)
@@ -6,6 +6,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 
 	"github.com/hbtc-chain/bhchain/store"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
 	"github.com/hbtc-chain/bhchain/x/evidence"
 	"github.com/hbtc-chain/bhchain/x/params"
 	stakingtypes "github.com/hbtc-chain/bhchain/x/staking/types"
@@ -33,6 +34,7 @@ type testEnv struct {
 	validators        []stakingtypes.Validator
 	evidenceKeeper    evidence.Keeper
 	mockStakingKeeper *mockStakingKeeper
+	ck                custodianunit.CUKeeper
 }
 
 var (
@@ -144,6 +146,8 @@ func setupUnitTestEnv(initDefaultTokens ...bool) testEnv {
 	cdc := codec.New()
 	types.RegisterCodec(cdc)
 	evidence.RegisterCodec(cdc)
+	custodianunit.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
 	ctx := sdk.NewContext(ms, abci.Header{
 		Height: 100,
 	}, false, log.NewNopLogger())
@@ -157,6 +161,8 @@ func setupUnitTestEnv(initDefaultTokens ...bool) testEnv {
 	evidenceKeeper := evidence.NewKeeper(cdc, keyEvid, pk.Subspace(evidence.DefaultParamspace), stakingKeeper)
 	evidence.InitGenesis(ctx, evidenceKeeper, evidence.DefaultGenesisState())
 	tk.SetEvidenceKeeper(evidenceKeeper)
+	ck := custodianunit.NewCUKeeper(cdc, cuKey, pk.Subspace(custodianunit.DefaultParamspace), custodianunit.ProtoBaseCU)
+	tk.SetCUKeeper(ck)
 	if len(initDefaultTokens) > 0 && initDefaultTokens[0] {
 		InitGenesis(ctx, tk, DefaultGenesisState())
 	} else {
@@ -173,6 +179,7 @@ func setupUnitTestEnv(initDefaultTokens ...bool) testEnv {
 		validators:        validators,
 		evidenceKeeper:    evidenceKeeper,
 		mockStakingKeeper: stakingKeeper,
+		ck:                ck,
 	}
 }
 
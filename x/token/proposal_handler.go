@@ -205,6 +205,38 @@ func processIBCTokenChangeParam(key, value string, ti *sdk.IBCToken, cdc *codec.
 			return err
 		}
 		ti.NeedCollectFee = val
+	case sdk.KeyKeyRotationInterval:
+		var val uint64
+		err := cdc.UnmarshalJSON([]byte(value), &val)
+		if err != nil {
+			return err
+		}
+		if ti.Symbol != ti.Chain || (val != 0 && val < sdk.MinKeyRotationInterval) {
+			return types.ErrInvalidParameter(DefaultCodespace, key, value)
+		}
+		ti.KeyRotationInterval = val
+
+	case sdk.KeyKeyNodeThreshold:
+		var val uint64
+		err := cdc.UnmarshalJSON([]byte(value), &val)
+		if err != nil {
+			return err
+		}
+		if ti.Symbol != ti.Chain || val > sdk.MaxKeyNodeThreshold {
+			return types.ErrInvalidParameter(DefaultCodespace, key, value)
+		}
+		ti.KeyNodeThreshold = val
+
+	case sdk.KeyOpCUTiers:
+		var val *sdk.OpCUTierParams
+		err := cdc.UnmarshalJSON([]byte(value), &val)
+		if err != nil {
+			return err
+		}
+		if val != nil && (!val.IsValid() || uint64(len(val.ColdOpCUs)) >= ti.MaxOpCUNumber) {
+			return types.ErrInvalidParameter(DefaultCodespace, key, value)
+		}
+		ti.OpCUTiers = val
 
 	default:
 		return errors.New(fmt.Sprintf("Unkonwn parameter:%v for token %s", key, ti.Symbol))
@@ -213,6 +245,35 @@ func processIBCTokenChangeParam(key, value string, ti *sdk.IBCToken, cdc *codec.
 	return nil
 }
 
+// checkKeyRotationInterval checks the key rotation interval of ti is the same as the other chains rotating their keys.
+// The OPCU keys of all chains are rotated together by a new epoch, so a single interval is shared by the chains.
+func checkKeyRotationInterval(ctx sdk.Context, keeper Keeper, ti *sdk.IBCToken) error {
+	if ti.KeyRotationInterval == 0 {
+		return nil
+	}
+	for _, other := range keeper.GetIBCTokenList(ctx) {
+		if other.Symbol == other.Chain && other.Symbol != ti.Symbol &&
+			other.KeyRotationInterval != 0 && other.KeyRotationInterval != ti.KeyRotationInterval {
+			return fmt.Errorf("key rotation interval %d differs from %d of chain %s", ti.KeyRotationInterval, other.KeyRotationInterval, other.Chain)
+		}
+	}
+	return nil
+}
+
+// checkColdOpCUs checks the cold OPCUs of ti are OPCUs of the token
+func checkColdOpCUs(ctx sdk.Context, keeper Keeper, ti *sdk.IBCToken) error {
+	if ti.OpCUTiers == nil {
+		return nil
+	}
+	for _, addr := range ti.OpCUTiers.ColdOpCUs {
+		cu := keeper.ck.GetCU(ctx, addr)
+		if cu == nil || cu.GetCUType() != sdk.CUTypeOp || cu.GetSymbol() != ti.Symbol.String() {
+			return fmt.Errorf("cold OPCU %v is not an OPCU of %s", addr, ti.Symbol)
+		}
+	}
+	return nil
+}
+
 func handleTokenParamsChangeProposal(ctx sdk.Context, keeper Keeper, proposal types.TokenParamsChangeProposal) sdk.Result {
 	ctx.Logger().Info("handleTokenParamsChangeProposal", "proposal", proposal)
 
@@ -226,6 +287,12 @@ func handleTokenParamsChangeProposal(ctx sdk.Context, keeper Keeper, proposal ty
 		var err error
 		if ti.IsIBCToken() {
 			err = processIBCTokenChangeParam(pc.Key, pc.Value, ti.(*sdk.IBCToken), keeper.cdc)
+			if err == nil && pc.Key == sdk.KeyKeyRotationInterval {
+				err = checkKeyRotationInterval(ctx, keeper, ti.(*sdk.IBCToken))
+			}
+			if err == nil && pc.Key == sdk.KeyOpCUTiers {
+				err = checkColdOpCUs(ctx, keeper, ti.(*sdk.IBCToken))
+			}
 		} else {
 			err = processBaseTokenChangeParam(pc.Key, pc.Value, ti.(*sdk.BaseToken), keeper.cdc)
 		}
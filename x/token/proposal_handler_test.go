@@ -1,6 +1,7 @@
 package token
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -184,6 +185,121 @@ func TestTokenParamsChangeProposalFailed(t *testing.T) {
 	require.Equal(t, ap.TokenInfo, tokenInfo)
 }
 
+func TestKeyRotationIntervalChangeProposal(t *testing.T) {
+	input := setupUnitTestEnv()
+	ctx := input.ctx
+	keeper := input.tk
+	hdlr := NewTokenProposalHandler(keeper)
+
+	// only the chain's main token has a key rotation interval
+	cp := changeProposal(testUsdtSymbol.String(), []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"20000"`)})
+	res := hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"10"`)})
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	require.Equal(t, uint64(0), keeper.GetIBCToken(ctx, "eth").KeyRotationInterval)
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"20000"`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	require.Equal(t, uint64(20000), keeper.GetIBCToken(ctx, "eth").KeyRotationInterval)
+
+	// the chains rotate their keys together, with the same interval
+	keeper.SetIBCTokenSymbols(ctx, []sdk.Symbol{testBtcSymbol, "eth"})
+	cp = changeProposal("btc", []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"30000"`)})
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	require.Equal(t, uint64(0), keeper.GetIBCToken(ctx, "btc").KeyRotationInterval)
+	cp = changeProposal("btc", []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"20000"`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyRotationInterval, `"0"`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	require.Equal(t, uint64(0), keeper.GetIBCToken(ctx, "eth").KeyRotationInterval)
+}
+
+func TestKeyNodeThresholdChangeProposal(t *testing.T) {
+	input := setupUnitTestEnv()
+	ctx := input.ctx
+	keeper := input.tk
+	hdlr := NewTokenProposalHandler(keeper)
+
+	// only the chain's main token has a key node threshold
+	cp := changeProposal(testUsdtSymbol.String(), []types.ParamChange{types.NewParamChange(sdk.KeyKeyNodeThreshold, `"90"`)})
+	res := hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyNodeThreshold, `"101"`)})
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	require.Equal(t, 7, keeper.GetIBCToken(ctx, "eth").GetKeyNodeThreshold(10))
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyNodeThreshold, `"90"`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	require.Equal(t, uint64(90), keeper.GetIBCToken(ctx, "eth").KeyNodeThreshold)
+	require.Equal(t, 9, keeper.GetIBCToken(ctx, "eth").GetKeyNodeThreshold(10))
+
+	// never below the 2/3 majority
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyKeyNodeThreshold, `"50"`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	require.Equal(t, 7, keeper.GetIBCToken(ctx, "eth").GetKeyNodeThreshold(10))
+}
+
+func TestOpCUTiersChangeProposal(t *testing.T) {
+	input := setupUnitTestEnv()
+	ctx := input.ctx
+	keeper := input.tk
+	hdlr := NewTokenProposalHandler(keeper)
+
+	cold := sdk.NewCUAddress()
+	tiers := fmt.Sprintf(`{"cold_op_cus":["%s"],"cold_withdrawal_threshold":"100","hot_high_watermark":"50","hot_low_watermark":"10"}`, cold)
+	// the cold OPCUs must be OPCUs of the token
+	cp := changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyOpCUTiers, tiers)})
+	res := hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	input.ck.SetCU(ctx, input.ck.NewOpCUWithAddress(ctx, testBtcSymbol.String(), cold))
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	require.Nil(t, keeper.GetIBCToken(ctx, "eth").OpCUTiers)
+
+	cold = sdk.NewCUAddress()
+	input.ck.SetCU(ctx, input.ck.NewOpCUWithAddress(ctx, "eth", cold))
+	tiers = fmt.Sprintf(`{"cold_op_cus":["%s"],"cold_withdrawal_threshold":"100","hot_high_watermark":"50","hot_low_watermark":"10"}`, cold)
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyOpCUTiers, tiers)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	ti := keeper.GetIBCToken(ctx, "eth")
+	require.True(t, ti.IsColdOpCU(cold))
+	require.False(t, ti.IsColdOpCU(sdk.NewCUAddress()))
+	require.True(t, ti.OpCUTiers.CanWithdraw(sdk.NewInt(100)))
+	require.False(t, ti.OpCUTiers.CanWithdraw(sdk.NewInt(99)))
+	require.Equal(t, sdk.NewInt(30), ti.OpCUTiers.RebalanceTarget())
+
+	// the low watermark is above the high one
+	invalid := fmt.Sprintf(`{"cold_op_cus":["%s"],"cold_withdrawal_threshold":"100","hot_high_watermark":"50","hot_low_watermark":"60"}`, cold)
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyOpCUTiers, invalid)})
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+
+	// duplicated cold OPCUs
+	invalid = fmt.Sprintf(`{"cold_op_cus":["%s","%s"],"cold_withdrawal_threshold":"100","hot_high_watermark":"50","hot_low_watermark":"10"}`, cold, cold)
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyOpCUTiers, invalid)})
+	res = hdlr(ctx, cp)
+	require.NotEqual(t, sdk.CodeOK, res.Code)
+	require.Equal(t, []sdk.CUAddress{cold}, keeper.GetIBCToken(ctx, "eth").OpCUTiers.ColdOpCUs)
+
+	cp = changeProposal("eth", []types.ParamChange{types.NewParamChange(sdk.KeyOpCUTiers, `null`)})
+	res = hdlr(ctx, cp)
+	require.Equal(t, sdk.CodeOK, res.Code)
+	require.Nil(t, keeper.GetIBCToken(ctx, "eth").OpCUTiers)
+}
+
 func parseSymbolFromProposalResp(res sdk.Result) sdk.Symbol {
 	for _, event := range res.Events {
 		if event.Type == types.EventTypeExecuteAddTokenProposal {
@@ -39,6 +39,10 @@ var (
 	NewInput               = types.NewInput
 	NewOutput              = types.NewOutput
 	ParamKeyTable          = types.ParamKeyTable
+	ErrAddressFrozen       = types.ErrAddressFrozen
+
+	NewFreezeAddressesProposal     = types.NewFreezeAddressesProposal
+	DefaultUtxoConsolidationParams = types.DefaultUtxoConsolidationParams
 
 	// variable aliases
 	ModuleCdc                = types.ModuleCdc
@@ -54,6 +58,8 @@ type (
 	MsgMultiSend                   = types.MsgMultiSend
 	MsgDeposit                     = types.MsgDeposit
 	MsgConfirmedDeposit            = types.MsgConfirmedDeposit
+	MsgInvalidateDeposit           = types.MsgInvalidateDeposit
+	MsgSettleDepositDeficit        = types.MsgSettleDepositDeficit
 	MsgCollectWaitSign             = types.MsgCollectWaitSign
 	MsgCollectSignFinish           = types.MsgCollectSignFinish
 	MsgCollectFinish               = types.MsgCollectFinish
@@ -62,6 +68,8 @@ type (
 	MsgWithdrawalWaitSign          = types.MsgWithdrawalWaitSign
 	MsgWithdrawalSignFinish        = types.MsgWithdrawalSignFinish
 	MsgWithdrawalFinish            = types.MsgWithdrawalFinish
+	MsgWithdrawalFeeBump           = types.MsgWithdrawalFeeBump
+	MsgBatchWithdrawal             = types.MsgBatchWithdrawal
 	MsgSysTransfer                 = types.MsgSysTransfer
 	MsgSysTransferWaitSign         = types.MsgSysTransferWaitSign
 	MsgSysTransferSignFinish       = types.MsgSysTransferSignFinish
@@ -70,6 +78,20 @@ type (
 	MsgOpcuAssetTransferWaitSign   = types.MsgOpcuAssetTransferWaitSign
 	MsgOpcuAssetTransferSignFinish = types.MsgOpcuAssetTransferSignFinish
 	MsgOpcuAssetTransferFinish     = types.MsgOpcuAssetTransferFinish
+	MsgUtxoConsolidationWaitSign   = types.MsgUtxoConsolidationWaitSign
+	MsgUtxoConsolidationSignFinish = types.MsgUtxoConsolidationSignFinish
+	MsgUtxoConsolidationFinish     = types.MsgUtxoConsolidationFinish
 	MsgOrderRetry                  = types.MsgOrderRetry
 	MsgCancelWithdrawal            = types.MsgCancelWithdrawal
+	MsgSetDepositRoute             = types.MsgSetDepositRoute
+	MsgReassignSuspense            = types.MsgReassignSuspense
+	MsgCreateHTLC                  = types.MsgCreateHTLC
+	MsgClaimHTLC                   = types.MsgClaimHTLC
+	MsgRefundHTLC                  = types.MsgRefundHTLC
+	MsgScheduleTransfer            = types.MsgScheduleTransfer
+	MsgCancelScheduledTransfer     = types.MsgCancelScheduledTransfer
+	MsgCreateVestingCU             = types.MsgCreateVestingCU
+	MsgCreatePeriodicVestingCU     = types.MsgCreatePeriodicVestingCU
+	FreezeAddressesProposal        = types.FreezeAddressesProposal
+	UtxoConsolidationParams        = types.UtxoConsolidationParams
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/hbtc-chain/bhchain/client"
 	"github.com/hbtc-chain/bhchain/client/context"
@@ -12,6 +13,11 @@ import (
 	"github.com/hbtc-chain/bhchain/x/transfer/types"
 )
 
+const (
+	flagAddress    = "address"
+	flagCrossCheck = "cross-check"
+)
+
 // GetQueryCmd returns the cli query commands for the minting module.
 func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 	transferQueryCmd := &cobra.Command{
@@ -26,6 +32,13 @@ func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 		client.GetCommands(
 			GetCmdQueryBalance(cdc),
 			GetCmdQueryAllBalance(cdc),
+			GetCmdQueryReserve(cdc),
+			GetCmdQueryDepositRoute(cdc),
+			GetCmdQueryHTLC(cdc),
+			GetCmdQueryScheduledTransfers(cdc),
+			GetCmdQueryFrozenAddresses(cdc),
+			GetCmdQueryWithdrawalFee(cdc),
+			GetCmdQueryDepositDeficit(cdc),
 		)...,
 	)
 
@@ -87,3 +100,241 @@ func GetCmdQueryAllBalance(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdQueryReserve(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reserve [symbol]",
+		Short: "Query reserve report of some IBC token",
+		Long: `Query reserve report of some IBC token, which reconciles the sum of balances against the custodied reserves.
+With --address, the liability proof of the CU is returned and verified against the liability root.
+With --cross-check, the recorded reserves are compared with the external balances queried by the node.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			var addr sdk.CUAddress
+			if addrStr := viper.GetString(flagAddress); addrStr != "" {
+				var err error
+				addr, err = sdk.CUAddressFromBase58(addrStr)
+				if err != nil {
+					return err
+				}
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryReserveParams(args[0], addr, viper.GetBool(flagCrossCheck)))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryReserve)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			if addr != nil {
+				var report types.ReserveReport
+				if err := cdc.UnmarshalJSON(res, &report); err != nil {
+					return err
+				}
+				if report.LiabilityProof == nil {
+					return fmt.Errorf("%v holds no %v", addr, args[0])
+				}
+				if err := report.LiabilityProof.Verify(report.LiabilityRoot, report.Liabilities); err != nil {
+					return fmt.Errorf("fail to verify liability proof: %v", err)
+				}
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+	cmd.Flags().String(flagAddress, "", "CU address whose liability proof is returned and verified")
+	cmd.Flags().Bool(flagCrossCheck, false, "Cross check the reserves with external balances queried from chainnode")
+	return cmd
+}
+
+func GetCmdQueryDepositRoute(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "deposit-route [address]",
+		Short: "Query deposit routing table and suspense balance of some address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.CUAddressFromBase58(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryDepositRouteParams(addr))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDepositRoute)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func GetCmdQueryHTLC(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "htlc [sender] [hashlock]",
+		Short: "Query an unrefunded htlc of the sender by its hex encoded hashlock, a claimed one shows its preimage",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			sender, err := sdk.CUAddressFromBase58(args[0])
+			if err != nil {
+				return err
+			}
+			hashlock, err := types.DecodeHTLCHash(args[1])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryHTLCParams(sender, hashlock))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryHTLC)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func GetCmdQueryScheduledTransfers(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "scheduled-transfers [address]",
+		Short: "Query scheduled transfers with remaining executions of some address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.CUAddressFromBase58(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryScheduledTransfersParams(addr))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryScheduledTransfers)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func GetCmdQueryFrozenAddresses(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "frozen-addresses [address]",
+		Short: "Query the addresses frozen by governance, or whether some address is frozen",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			if len(args) == 0 {
+				route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryFrozenAddresses)
+				res, _, err := cliCtx.QueryWithData(route, nil)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(string(res))
+				return nil
+			}
+
+			addr, err := sdk.CUAddressFromBase58(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryAddressFrozenParams(addr))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAddressFrozen)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func GetCmdQueryWithdrawalFee(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "withdrawal-fee [symbol] [amount] [to_address]",
+		Short: "Query the recommended gas fee of a withdrawal at the current gas price",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			amt, ok := sdk.NewIntFromString(args[1])
+			if !ok {
+				return fmt.Errorf("invalid amount:%v", args[1])
+			}
+			var toAddr string
+			if len(args) > 2 {
+				toAddr = args[2]
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryWithdrawalFeeParams(args[0], amt, toAddr))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryWithdrawalFee)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func GetCmdQueryDepositDeficit(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "deposit-deficit [address]",
+		Short: "Query the unsettled coins of a CU credited by invalidated deposits",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.CUAddressFromBase58(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(types.NewQueryDepositDeficitParams(addr))
+			if err != nil {
+				return err
+			}
+			route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDepositDeficit)
+			res, _, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
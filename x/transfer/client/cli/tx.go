@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strconv"
+	"strings"
 
 	"github.com/hbtc-chain/bhchain/client"
 	"github.com/hbtc-chain/bhchain/client/context"
@@ -10,6 +12,9 @@ import (
 	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/x/custodianunit"
 	"github.com/hbtc-chain/bhchain/x/custodianunit/client/utils"
+	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
+	govcli "github.com/hbtc-chain/bhchain/x/gov/client/cli"
+	gov "github.com/hbtc-chain/bhchain/x/gov/types"
 	"github.com/hbtc-chain/bhchain/x/transfer/types"
 
 	uuid "github.com/satori/go.uuid"
@@ -20,7 +25,12 @@ import (
 var flagOrderID = "order-id"
 
 const (
-	flagOutfile = "output-document"
+	flagOutfile  = "output-document"
+	flagByTime   = "by-time"
+	flagInterval = "interval"
+	flagTimes    = "times"
+
+	flagVestingStart = "vesting-start-time"
 )
 
 // GetTxCmd returns the transaction commands for this module
@@ -38,6 +48,17 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 		CancelWithDrawalCmd(cdc),
 		DepositCmd(cdc),
 		WithDrawalCmd(cdc),
+		BatchWithDrawalCmd(cdc),
+		SetDepositRouteCmd(cdc),
+		SettleDepositDeficitCmd(cdc),
+		ReassignSuspenseCmd(cdc),
+		CreateHTLCCmd(cdc),
+		ClaimHTLCCmd(cdc),
+		RefundHTLCCmd(cdc),
+		ScheduleTransferCmd(cdc),
+		CancelScheduledTransferCmd(cdc),
+		CreateVestingCUCmd(cdc),
+		CreatePeriodicVestingCUCmd(cdc),
 	)
 	return txCmd
 }
@@ -261,3 +282,412 @@ func WithDrawalCmd(cdc *codec.Codec) *cobra.Command {
 
 	return cmd
 }
+
+func BatchWithDrawalCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch-withdrawal [from_key_or_address] [symbol] [to_address:amount,...] [gas]",
+		Short: "withdrawal to sign tx for withdrawal asset from sepecified CU to multiple withdrawal addresses",
+		Long: `  withdrawal to sign tx for withdrawal asset from sepecified CU to multiple withdrawal addresses, gas is the total fee of all outputs
+  Example: hbtccli tx transfer batch-withdrawal alice btc mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg:100000,mxwvjAzLxg6VYtnXUxrC4Rz1AZtFaCgpVz:200000 20000  --chain-id bhchain`,
+
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			var outputs []types.WithdrawalOutput
+			for _, output := range strings.Split(args[2], ",") {
+				parts := strings.Split(output, ":")
+				if len(parts) != 2 {
+					return fmt.Errorf("Invalid output:%v", output)
+				}
+				amount, ok := sdk.NewIntFromString(parts[1])
+				if !ok {
+					return fmt.Errorf("Invalid amount:%v", parts[1])
+				}
+				outputs = append(outputs, types.NewWithdrawalOutput(uuid.NewV4().String(), parts[0], amount))
+			}
+			gas, ok := sdk.NewIntFromString(args[3])
+			if !ok {
+				return fmt.Errorf("Invalid gas:%v", args[3])
+			}
+
+			batchID := uuid.NewV4()
+			msg := types.NewMsgBatchWithdrawal(cliCtx.GetFromAddress().String(), batchID.String(), args[1], outputs, gas)
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("batchID:", batchID)
+			for _, output := range outputs {
+				fmt.Println("orderID:", output.OrderID, "to:", output.ToMultisignAddress)
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func SetDepositRouteCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-deposit-route [from_key_or_address] [memo] [to_address]",
+		Short: "route deposits to the addresses of sepecified CU carrying memo to another CU",
+		Long: `  route deposits to the addresses of sepecified CU carrying memo to another CU, omit to_address to remove the route.
+  Once a route is set, deposits with unknown memos go to the suspense balance of the CU.
+  Example: hbtccli tx transfer set-deposit-route alice 10086 HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy --chain-id bhchain`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			toCU := ""
+			if len(args) == 3 {
+				toCU = args[2]
+			}
+			msg := types.NewMsgSetDepositRoute(cliCtx.GetFromAddress().String(), args[1], toCU)
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func SettleDepositDeficitCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settle-deposit-deficit [from_key_or_address] [symbol]",
+		Short: "settle the coins of sepecified CU credited by invalidated deposits",
+		Long: `  burn the coins of sepecified CU credited by invalidated deposits, the held part from its locked balance and
+  the spent part from its available balance. Withdrawals of the CU are unfrozen once all the deficits are settled.
+  Example: hbtccli tx transfer settle-deposit-deficit alice btc --chain-id bhchain`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			msg := types.NewMsgSettleDepositDeficit(cliCtx.GetFromAddress(), args[1])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func ReassignSuspenseCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reassign-suspense [from_key_or_address] [to_address] [coin]",
+		Short: "move suspense balance of sepecified CU to another CU",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			coin, err := sdk.ParseCoin(args[2])
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgReassignSuspense(cliCtx.GetFromAddress().String(), args[1], coin.Denom, coin.Amount)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func CreateHTLCCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-htlc [from_key_or_address] [to_address] [coins] [hashlock] [time_lock]",
+		Short: "lock coins for another CU under a hashlock for some blocks",
+		Long: `  lock coins for another CU under a hex encoded sha256 hashlock for time_lock blocks.
+  The receiver claims the coins with the preimage of the hashlock before expiry, otherwise they can be refunded to the sender.
+  Example: hbtccli tx transfer create-htlc alice HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy 100hbc 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 1000 --chain-id bhchain`,
+		Args: cobra.ExactArgs(5),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			coins, err := sdk.ParseCoins(args[2])
+			if err != nil {
+				return err
+			}
+			timeLock, err := strconv.ParseUint(args[4], 10, 64)
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgCreateHTLC(cliCtx.GetFromAddress().String(), args[1], coins, args[3], timeLock)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func ClaimHTLCCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-htlc [from_key_or_address] [sender] [hashlock] [preimage]",
+		Short: "pay an htlc of the sender to its receiver with the hex encoded preimage of its hashlock",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			msg := types.NewMsgClaimHTLC(cliCtx.GetFromAddress().String(), args[1], args[2], args[3])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func RefundHTLCCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refund-htlc [from_key_or_address] [sender] [hashlock]",
+		Short: "return an expired htlc to its sender",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			msg := types.NewMsgRefundHTLC(cliCtx.GetFromAddress().String(), args[1], args[2])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func ScheduleTransferCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule-transfer [from_key_or_address] [to_address] [coins] [start]",
+		Short: "transfer coins to another CU at a future height or time, optionally repeating on an interval",
+		Long: `  transfer coins to another CU at the start height, or at the start unix time with --by-time.
+  With --times greater than 1, the transfer repeats every --interval blocks, or seconds with --by-time.
+  The coins for all the executions are locked until they are executed or cancelled.
+  Example: hbtccli tx transfer schedule-transfer alice HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy 100hbc 1700000000 --by-time --interval 2592000 --times 12 --chain-id bhchain`,
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			coins, err := sdk.ParseCoins(args[2])
+			if err != nil {
+				return err
+			}
+			start, err := strconv.ParseUint(args[3], 10, 64)
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgScheduleTransfer(cliCtx.GetFromAddress().String(), args[1], coins,
+				viper.GetBool(flagByTime), start, viper.GetUint64(flagInterval), viper.GetUint64(flagTimes))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().Bool(flagByTime, false, "start and interval are in unix seconds of the block time rather than block heights")
+	cmd.Flags().Uint64(flagInterval, 0, "interval between two executions of a recurring transfer")
+	cmd.Flags().Uint64(flagTimes, 1, "number of executions")
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func CancelScheduledTransferCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-scheduled-transfer [from_key_or_address] [id]",
+		Short: "cancel the remaining executions of a scheduled transfer and unlock their coins",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			id, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgCancelScheduledTransfer(cliCtx.GetFromAddress().String(), id)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func CreateVestingCUCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-vesting-cu [from_key_or_address] [to_address] [coins] [end_time]",
+		Short: "create a new vesting CU funded with coins which are locked until they vest",
+		Long: `  create a new vesting CU funded with coins, the coins vest all at the end unix time, or continuously
+  from --vesting-start-time to the end time if it's given. The vesting coins can be delegated but not sent.
+  Example: hbtccli tx transfer create-vesting-cu alice HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy 1000hbc 1700000000 --vesting-start-time 1600000000 --chain-id bhchain`,
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			coins, err := sdk.ParseCoins(args[2])
+			if err != nil {
+				return err
+			}
+			endTime, err := strconv.ParseInt(args[3], 10, 64)
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgCreateVestingCU(cliCtx.GetFromAddress().String(), args[1], coins, viper.GetInt64(flagVestingStart), endTime)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().Int64(flagVestingStart, 0, "unix time from which the coins vest continuously, the coins vest all at the end time if not given")
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func CreatePeriodicVestingCUCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-periodic-vesting-cu [from_key_or_address] [to_address] [start_time] [periods_file]",
+		Short: "create a new vesting CU funded with the coins of vesting periods, each vests at the end of its period",
+		Long: `  create a new vesting CU funded with the total coins of the periods in a JSON file, the periods follow
+  each other from the start unix time, and the coins of each period vest at its end.
+  Example: hbtccli tx transfer create-periodic-vesting-cu alice HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy 1600000000 periods.json --chain-id bhchain
+  where periods.json is like: [{"length":"2592000","amount":[{"denom":"hbc","amount":"100"}]}]`,
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContextWithFrom(args[0]).WithCodec(cdc)
+
+			startTime, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				return err
+			}
+			bz, err := ioutil.ReadFile(args[3])
+			if err != nil {
+				return err
+			}
+			var periods cutypes.VestingPeriods
+			if err = cdc.UnmarshalJSON(bz, &periods); err != nil {
+				return fmt.Errorf("invalid periods file: %v", err)
+			}
+			msg := types.NewMsgCreatePeriodicVestingCU(cliCtx.GetFromAddress().String(), args[1], startTime, periods)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func NewCmdSubmitFreezeAddressesProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "freeze-addresses [addresses] [freeze]",
+		Short: "Create a proposal freezing or unfreezing comma separated addresses",
+		Long: `  Create a proposal freezing the comma separated addresses if freeze is true, otherwise unfreezing them.
+  A frozen address can not send, withdraw, swap or place openswap orders, but can still receive deposits.
+  Example: hbtccli tx gov submit-proposal freeze-addresses HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy true --title freeze --description "compliance" --deposit 1000hbc --from alice`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			txBldr := custodianunit.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			from := cliCtx.GetFromAddress()
+
+			title, err := cmd.Flags().GetString(govcli.FlagTitle)
+			if err != nil {
+				return err
+			}
+			description, err := cmd.Flags().GetString(govcli.FlagDescription)
+			if err != nil {
+				return err
+			}
+			var addrs []sdk.CUAddress
+			for _, s := range strings.Split(args[0], ",") {
+				addr, err := sdk.CUAddressFromBase58(strings.TrimSpace(s))
+				if err != nil {
+					return err
+				}
+				addrs = append(addrs, addr)
+			}
+			freeze, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return err
+			}
+			content := types.NewFreezeAddressesProposal(title, description, addrs, freeze)
+			err = content.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			depositStr, err := cmd.Flags().GetString(govcli.FlagDeposit)
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoins(depositStr)
+			if err != nil {
+				return err
+			}
+			voteTime, err := cmd.Flags().GetUint32(govcli.FlagVoteTime)
+			if err != nil {
+				return err
+			}
+			msg := gov.NewMsgSubmitProposal(content, deposit, from, voteTime)
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	cmd.Flags().Uint32(govcli.FlagVoteTime, 0, "votetime of proposal")
+
+	return cmd
+}
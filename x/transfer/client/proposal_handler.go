@@ -0,0 +1,8 @@
+package client
+
+import (
+	govclient "github.com/hbtc-chain/bhchain/x/gov/client"
+	"github.com/hbtc-chain/bhchain/x/transfer/client/cli"
+)
+
+var FreezeAddressesProposalHandler = govclient.NewProposalHandler(cli.NewCmdSubmitFreezeAddressesProposal, nil)
@@ -16,6 +16,8 @@ import (
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/transfer/balance/{address}/{symbol}", queryBalancesRequestHandlerFn(cliCtx)).Methods("GET")
 	r.HandleFunc("/transfer/balances/{address}", queryAllBalancesRequestHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/transfer/withdrawal_fee/{symbol}/{amount}", queryWithdrawalFeeRequestHandlerFn(cliCtx)).Methods("GET")
+	registerTxRoutes(cliCtx, r)
 }
 
 func queryBalancesRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
@@ -79,3 +81,35 @@ func queryAllBalancesRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFun
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+// queryWithdrawalFeeRequestHandlerFn estimates the fee of a withdrawal, the destination is passed by the optional query parameter to_address
+func queryWithdrawalFeeRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryWithdrawalFee)
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		amt, ok := sdk.NewIntFromString(mux.Vars(r)["amount"])
+		if !ok {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid amount:%v", mux.Vars(r)["amount"]))
+			return
+		}
+		bz, err := cliCtx.Codec.MarshalJSON(types.NewQueryWithdrawalFeeParams(mux.Vars(r)["symbol"], amt, r.URL.Query().Get("to_address")))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
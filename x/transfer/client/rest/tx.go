@@ -0,0 +1,188 @@
+package rest
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/hbtc-chain/bhchain/client/context"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/types/rest"
+	"github.com/hbtc-chain/bhchain/x/custodianunit/client/utils"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func registerTxRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc("/transfer/accounts/{address}/transfers", sendRequestHandlerFn(cliCtx)).Methods("POST")
+	r.HandleFunc("/transfer/multisend", multiSendRequestHandlerFn(cliCtx)).Methods("POST")
+	r.HandleFunc("/transfer/withdrawals", withdrawalRequestHandlerFn(cliCtx)).Methods("POST")
+	r.HandleFunc("/transfer/withdrawals/{orderID}/cancel", cancelWithdrawalRequestHandlerFn(cliCtx)).Methods("POST")
+}
+
+type (
+	// SendReq defines the properties of a send request's body.
+	SendReq struct {
+		BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+		Amount  sdk.Coins    `json:"amount" yaml:"amount"`
+	}
+
+	// MultiSendReq defines the properties of a multi-send request's body.
+	MultiSendReq struct {
+		BaseReq   rest.BaseReq   `json:"base_req" yaml:"base_req"`
+		Inputs    []types.Input  `json:"inputs" yaml:"inputs"`
+		Outputs   []types.Output `json:"outputs" yaml:"outputs"`
+		MaxHeight uint64         `json:"max_height" yaml:"max_height"`
+	}
+
+	// WithdrawalReq defines the properties of a withdrawal request's body,
+	// a random order id is used if it's empty.
+	WithdrawalReq struct {
+		BaseReq   rest.BaseReq `json:"base_req" yaml:"base_req"`
+		OrderID   string       `json:"order_id" yaml:"order_id"`
+		ToAddress string       `json:"to_address" yaml:"to_address"`
+		Amount    sdk.Coin     `json:"amount" yaml:"amount"`
+		GasFee    sdk.Int      `json:"gas_fee" yaml:"gas_fee"`
+	}
+
+	// CancelWithdrawalReq defines the properties of a cancel-withdrawal request's body.
+	CancelWithdrawalReq struct {
+		BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+	}
+)
+
+func sendRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		toAddr, err := sdk.CUAddressFromBase58(mux.Vars(r)["address"])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var req SendReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		fromAddr, err := sdk.CUAddressFromBase58(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		msg := types.NewMsgSend(fromAddr, toAddr, req.Amount)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func multiSendRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req MultiSendReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		fromAddr, err := sdk.CUAddressFromBase58(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		isInput := false
+		for _, in := range req.Inputs {
+			if bytes.Equal(fromAddr, in.Address) {
+				isInput = true
+				break
+			}
+		}
+		if !isInput {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized, "must use one of the input addresses")
+			return
+		}
+
+		msg := types.NewMsgMultiSend(req.Inputs, req.Outputs, req.MaxHeight)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func withdrawalRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WithdrawalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		fromAddr, err := sdk.CUAddressFromBase58(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		orderID := req.OrderID
+		if orderID == "" {
+			orderID = uuid.NewV4().String()
+		}
+
+		msg := types.NewMsgWithdrawal(fromAddr.String(), req.ToAddress, req.Amount.Denom, orderID, req.Amount.Amount, req.GasFee)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func cancelWithdrawalRequestHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CancelWithdrawalReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		fromAddr, err := sdk.CUAddressFromBase58(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		msg := types.NewMsgCancelWithdrawal(fromAddr.String(), mux.Vars(r)["orderID"])
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
@@ -6,27 +6,39 @@ import (
 
 // GenesisState is the bank state that must be provided at genesis.
 type GenesisState struct {
-	SendEnabled bool `json:"send_enabled" yaml:"send_enabled"`
+	SendEnabled       bool                    `json:"send_enabled" yaml:"send_enabled"`
+	UtxoConsolidation UtxoConsolidationParams `json:"utxo_consolidation" yaml:"utxo_consolidation"`
 }
 
 // NewGenesisState creates a new genesis state.
-func NewGenesisState(sendEnabled bool) GenesisState {
-	return GenesisState{SendEnabled: sendEnabled}
+func NewGenesisState(sendEnabled bool, utxoConsolidation UtxoConsolidationParams) GenesisState {
+	return GenesisState{SendEnabled: sendEnabled, UtxoConsolidation: utxoConsolidation}
 }
 
 // DefaultGenesisState returns a default genesis state
-func DefaultGenesisState() GenesisState { return NewGenesisState(true) }
+func DefaultGenesisState() GenesisState {
+	return NewGenesisState(true, DefaultUtxoConsolidationParams())
+}
 
 // InitGenesis sets distribution information for genesis.
 func InitGenesis(ctx sdk.Context, keeper BaseKeeper, data GenesisState) {
 	keeper.SetSendEnabled(ctx, data.SendEnabled)
+	// genesis files predating the utxo consolidation params keep the defaults
+	if !data.UtxoConsolidation.DustRatio.IsNil() {
+		keeper.SetUtxoConsolidationParams(ctx, data.UtxoConsolidation)
+	}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper BaseKeeper) GenesisState {
-	return NewGenesisState(keeper.IsSendEnabled(ctx))
+	return NewGenesisState(keeper.IsSendEnabled(ctx), keeper.GetUtxoConsolidationParams(ctx))
 }
 
 // ValidateGenesis performs basic validation of bank genesis data returning an
 // error for any failed validation criteria.
-func ValidateGenesis(data GenesisState) error { return nil }
+func ValidateGenesis(data GenesisState) error {
+	if data.UtxoConsolidation.DustRatio.IsNil() {
+		return nil
+	}
+	return data.UtxoConsolidation.Validate()
+}
@@ -29,6 +29,12 @@ func NewHandler(k keeper.BaseKeeper) sdk.Handler {
 		case MsgConfirmedDeposit:
 			return handleMsgConfirmedDeposit(ctx, k, msg)
 
+		case MsgInvalidateDeposit:
+			return handleMsgInvalidateDeposit(ctx, k, msg)
+
+		case MsgSettleDepositDeficit:
+			return handleMsgSettleDepositDeficit(ctx, k, msg)
+
 		case MsgCollectWaitSign:
 			return handleMsgCollectWaitSign(ctx, k, msg)
 
@@ -41,6 +47,9 @@ func NewHandler(k keeper.BaseKeeper) sdk.Handler {
 		case MsgWithdrawal:
 			return handleMsgWithdrawal(ctx, k, msg)
 
+		case MsgBatchWithdrawal:
+			return handleMsgBatchWithdrawal(ctx, k, msg)
+
 		case MsgWithdrawalConfirm:
 			return handleMsgWithdrawalConfirm(ctx, k, msg)
 
@@ -53,6 +62,9 @@ func NewHandler(k keeper.BaseKeeper) sdk.Handler {
 		case MsgWithdrawalFinish:
 			return handleMsgWithdrawalFinish(ctx, k, msg)
 
+		case MsgWithdrawalFeeBump:
+			return handleMsgWithdrawalFeeBump(ctx, k, msg)
+
 		case MsgSysTransfer:
 			return handleMsgSysTransfer(ctx, k, msg)
 
@@ -77,12 +89,48 @@ func NewHandler(k keeper.BaseKeeper) sdk.Handler {
 		case MsgOpcuAssetTransferFinish:
 			return handleMsgOpcuAssetTransferFinish(ctx, k, msg)
 
+		case MsgUtxoConsolidationWaitSign:
+			return handleMsgUtxoConsolidationWaitSign(ctx, k, msg)
+
+		case MsgUtxoConsolidationSignFinish:
+			return handleMsgUtxoConsolidationSignFinish(ctx, k, msg)
+
+		case MsgUtxoConsolidationFinish:
+			return handleMsgUtxoConsolidationFinish(ctx, k, msg)
+
 		case MsgOrderRetry:
 			return handleMsgOrderRetry(ctx, k, msg)
 
 		case MsgCancelWithdrawal:
 			return handleMsgCancelWithdrawal(ctx, k, msg)
 
+		case MsgSetDepositRoute:
+			return handleMsgSetDepositRoute(ctx, k, msg)
+
+		case MsgReassignSuspense:
+			return handleMsgReassignSuspense(ctx, k, msg)
+
+		case MsgCreateHTLC:
+			return handleMsgCreateHTLC(ctx, k, msg)
+
+		case MsgClaimHTLC:
+			return handleMsgClaimHTLC(ctx, k, msg)
+
+		case MsgRefundHTLC:
+			return handleMsgRefundHTLC(ctx, k, msg)
+
+		case MsgScheduleTransfer:
+			return handleMsgScheduleTransfer(ctx, k, msg)
+
+		case MsgCancelScheduledTransfer:
+			return handleMsgCancelScheduledTransfer(ctx, k, msg)
+
+		case MsgCreateVestingCU:
+			return handleMsgCreateVestingCU(ctx, k, msg)
+
+		case MsgCreatePeriodicVestingCU:
+			return handleMsgCreatePeriodicVestingCU(ctx, k, msg)
+
 		default:
 			errMsg := fmt.Sprintf("unrecognized bank message type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -181,6 +229,52 @@ func handleMsgConfirmedDeposit(ctx sdk.Context, k keeper.BaseKeeper, msg MsgConf
 	return result
 }
 
+func handleMsgInvalidateDeposit(ctx sdk.Context, k keeper.BaseKeeper, msg MsgInvalidateDeposit) sdk.Result {
+	ctx.Logger().Info("handleMsgInvalidateDeposit ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	result := k.InvalidateDeposit(ctx, msg.From, msg.OrderIDs)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeInvalidateDeposit,
+			sdk.NewAttribute(types.AttributeKeySender, msg.From.String()),
+			sdk.NewAttribute(types.AttributeKeyOrderIDs, strings.Join(msg.OrderIDs, ",")),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgSettleDepositDeficit(ctx sdk.Context, k keeper.BaseKeeper, msg MsgSettleDepositDeficit) sdk.Result {
+	ctx.Logger().Info("handleMsgSettleDepositDeficit ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	result := k.SettleDepositDeficit(ctx, msg.From, msg.Symbol)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeSettleDepositDeficit,
+			sdk.NewAttribute(types.AttributeKeySender, msg.From.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, msg.Symbol),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
 func handleMsgCollectWaitSign(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCollectWaitSign) sdk.Result {
 	ctx.Logger().Info("handleMsgCollectWaitSign ", "msg", msg)
 	if !k.IsSendEnabled(ctx) {
@@ -296,6 +390,40 @@ func handleMsgWithdrawal(ctx sdk.Context, k keeper.BaseKeeper, msg MsgWithdrawal
 	return result
 }
 
+func handleMsgBatchWithdrawal(ctx sdk.Context, k keeper.BaseKeeper, msg MsgBatchWithdrawal) sdk.Result {
+	ctx.Logger().Info("handleMsgBatchWithdrawal ", "msg", msg)
+
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.FromCU)).Result()
+	}
+
+	result := k.BatchWithdrawal(ctx, fromCUAddr, msg.BatchID, msg.Symbol, msg.Outputs, msg.GasFee)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	orderIDs := make([]string, len(msg.Outputs))
+	for i, output := range msg.Outputs {
+		orderIDs[i] = output.OrderID
+	}
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeBatchWithdrawal,
+			sdk.NewAttribute(types.AttributeKeySender, msg.FromCU),
+			sdk.NewAttribute(types.AttributeKeySymbol, msg.Symbol),
+			sdk.NewAttribute(types.AttributeKeyOrderIDs, strings.Join(orderIDs, ",")),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
 func handleMsgWithdrawalConfirm(ctx sdk.Context, k keeper.BaseKeeper, msg MsgWithdrawalConfirm) sdk.Result {
 	ctx.Logger().Info("handleMsgWithdrawalConfirm", "msg", msg)
 
@@ -383,7 +511,12 @@ func handleMsgWithdrawalFinish(ctx sdk.Context, k keeper.BaseKeeper, msg MsgWith
 		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.Validator)).Result()
 	}
 
-	result := k.WithdrawalFinish(ctx, fromCU, msg.OrderIDs, msg.CostFee, msg.Valid)
+	var result sdk.Result
+	if msg.TxHash == "" {
+		result = k.WithdrawalFinish(ctx, fromCU, msg.OrderIDs, msg.CostFee, msg.Valid)
+	} else {
+		result = k.WithdrawalReplacedTxFinish(ctx, fromCU, msg.OrderIDs, msg.TxHash, msg.CostFee, msg.Valid)
+	}
 	if result.Code != sdk.CodeOK {
 		return result
 	}
@@ -400,6 +533,34 @@ func handleMsgWithdrawalFinish(ctx sdk.Context, k keeper.BaseKeeper, msg MsgWith
 	return result
 }
 
+func handleMsgWithdrawalFeeBump(ctx sdk.Context, k keeper.BaseKeeper, msg MsgWithdrawalFeeBump) sdk.Result {
+	ctx.Logger().Info("handleMsgWithdrawalFeeBump ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCU, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.Validator)).Result()
+	}
+
+	result := k.WithdrawalFeeBump(ctx, fromCU, msg.OrderIDs, msg.SignHashes, msg.RawData)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeWithdrawalFeeBump,
+			sdk.NewAttribute(types.AttributeKeySender, msg.Validator),
+			sdk.NewAttribute(types.AttributeKeyOrderIDs, strings.Join(msg.OrderIDs, ",")),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
 func handleMsgSysTransfer(ctx sdk.Context, k keeper.BaseKeeper, msg MsgSysTransfer) sdk.Result {
 	ctx.Logger().Info("handleMsgSysTransfer ", "msg", msg)
 
@@ -594,6 +755,80 @@ func handleMsgOpcuAssetTransferFinish(ctx sdk.Context, k keeper.BaseKeeper, msg
 	return result
 }
 
+func handleMsgUtxoConsolidationWaitSign(ctx sdk.Context, k keeper.BaseKeeper, msg MsgUtxoConsolidationWaitSign) sdk.Result {
+	ctx.Logger().Info("handleMsgUtxoConsolidationWaitSign ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	result := k.UtxoConsolidationWaitSign(ctx, msg.OrderID, msg.SignHashes, msg.RawData)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeUtxoConsolidationWaitSign,
+			sdk.NewAttribute(types.AttributeKeySender, msg.Validator),
+			sdk.NewAttribute(types.AttributeKeyOrderID, msg.OrderID),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgUtxoConsolidationSignFinish(ctx sdk.Context, k keeper.BaseKeeper, msg MsgUtxoConsolidationSignFinish) sdk.Result {
+	ctx.Logger().Info("handleMsgUtxoConsolidationSignFinish ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	result := k.UtxoConsolidationSignFinish(ctx, msg.OrderID, msg.SignedTx)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeUtxoConsolidationSignFinish,
+			sdk.NewAttribute(types.AttributeKeySender, msg.Validator),
+			sdk.NewAttribute(types.AttributeKeyOrderID, msg.OrderID),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgUtxoConsolidationFinish(ctx sdk.Context, k keeper.BaseKeeper, msg MsgUtxoConsolidationFinish) sdk.Result {
+	ctx.Logger().Info("handleMsgUtxoConsolidationFinish ", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCU, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.Validator)).Result()
+	}
+
+	result := k.UtxoConsolidationFinish(ctx, fromCU, msg.OrderID, msg.CostFee)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeUtxoConsolidationFinish,
+			sdk.NewAttribute(types.AttributeKeySender, msg.Validator),
+			sdk.NewAttribute(types.AttributeKeyOrderID, msg.OrderID),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
 func handleMsgOrderRetry(ctx sdk.Context, k keeper.BaseKeeper, msg MsgOrderRetry) sdk.Result {
 	ctx.Logger().Info("handleMsgOrderRetry", "msg", msg)
 	if !k.IsSendEnabled(ctx) {
@@ -651,3 +886,283 @@ func handleMsgCancelWithdrawal(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCanc
 	result.Events = append(result.Events, ctx.EventManager().Events()...)
 	return result
 }
+
+func handleMsgSetDepositRoute(ctx sdk.Context, k keeper.BaseKeeper, msg MsgSetDepositRoute) sdk.Result {
+	ctx.Logger().Info("handleMsgSetDepositRoute", "msg", msg)
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+
+	var toCUAddr sdk.CUAddress
+	if msg.ToCU != "" {
+		toCUAddr, err = sdk.CUAddressFromBase58(msg.ToCU)
+		if err != nil {
+			return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+		}
+	}
+
+	result := k.SetDepositRoute(ctx, fromCUAddr, msg.Memo, toCUAddr)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeSetDepositRoute,
+			sdk.NewAttribute(types.AttributeKeySender, msg.FromCU),
+			sdk.NewAttribute(types.AttributeKeyMemo, msg.Memo),
+			sdk.NewAttribute(types.AttributeKeyRecipient, msg.ToCU),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgReassignSuspense(ctx sdk.Context, k keeper.BaseKeeper, msg MsgReassignSuspense) sdk.Result {
+	ctx.Logger().Info("handleMsgReassignSuspense", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+	toCUAddr, err := sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+	}
+
+	result := k.ReassignSuspense(ctx, fromCUAddr, toCUAddr, msg.Symbol, msg.Amount)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeReassignSuspense,
+			sdk.NewAttribute(types.AttributeKeySender, msg.FromCU),
+			sdk.NewAttribute(types.AttributeKeyRecipient, msg.ToCU),
+			sdk.NewAttribute(types.AttributeKeySymbol, msg.Symbol),
+			sdk.NewAttribute(types.AttributeKeyAmount, msg.Amount.String()),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgCreateHTLC(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCreateHTLC) sdk.Result {
+	ctx.Logger().Info("handleMsgCreateHTLC", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+	toCUAddr, err := sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+	}
+	hashlock, err := types.DecodeHTLCHash(msg.Hashlock)
+	if err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err)).Result()
+	}
+
+	result := k.CreateHTLC(ctx, fromCUAddr, toCUAddr, msg.Amount, hashlock, msg.TimeLock)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeCreateHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, msg.FromCU),
+			sdk.NewAttribute(types.AttributeKeyRecipient, msg.ToCU),
+			sdk.NewAttribute(types.AttributeKeyAmount, msg.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeyHashlock, msg.Hashlock),
+			sdk.NewAttribute(types.AttributeKeyExpireHeight, fmt.Sprintf("%d", uint64(ctx.BlockHeight())+msg.TimeLock)),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgClaimHTLC(ctx sdk.Context, k keeper.BaseKeeper, msg MsgClaimHTLC) sdk.Result {
+	ctx.Logger().Info("handleMsgClaimHTLC", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	senderAddr, err := sdk.CUAddressFromBase58(msg.Sender)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid sender:%v", msg.Sender)).Result()
+	}
+	hashlock, err := types.DecodeHTLCHash(msg.Hashlock)
+	if err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err)).Result()
+	}
+	preimage, err := types.DecodeHTLCHash(msg.Preimage)
+	if err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid preimage: %v", err)).Result()
+	}
+
+	htlc := k.GetHTLC(ctx, senderAddr, hashlock)
+	if htlc == nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %v does not exist", msg.Hashlock)).Result()
+	}
+
+	result := k.ClaimHTLC(ctx, senderAddr, hashlock, preimage)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	// the preimage is revealed so that the counterparty can claim on the other chain
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeClaimHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, htlc.Sender.String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, htlc.Receiver.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, htlc.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeyHashlock, msg.Hashlock),
+			sdk.NewAttribute(types.AttributeKeyPreimage, msg.Preimage),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgRefundHTLC(ctx sdk.Context, k keeper.BaseKeeper, msg MsgRefundHTLC) sdk.Result {
+	ctx.Logger().Info("handleMsgRefundHTLC", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	senderAddr, err := sdk.CUAddressFromBase58(msg.Sender)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid sender:%v", msg.Sender)).Result()
+	}
+	hashlock, err := types.DecodeHTLCHash(msg.Hashlock)
+	if err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err)).Result()
+	}
+
+	htlc := k.GetHTLC(ctx, senderAddr, hashlock)
+	if htlc == nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %v does not exist", msg.Hashlock)).Result()
+	}
+
+	result := k.RefundHTLC(ctx, senderAddr, hashlock)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeRefundHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, htlc.Sender.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, htlc.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeyHashlock, msg.Hashlock),
+		),
+	})
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgScheduleTransfer(ctx sdk.Context, k keeper.BaseKeeper, msg MsgScheduleTransfer) sdk.Result {
+	ctx.Logger().Info("handleMsgScheduleTransfer", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+	toCUAddr, err := sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+	}
+
+	// the event carrying the schedule id is emitted by the keeper
+	result := k.ScheduleTransfer(ctx, fromCUAddr, toCUAddr, msg.Amount, msg.ByTime, msg.Start, msg.Interval, msg.Times)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgCancelScheduledTransfer(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCancelScheduledTransfer) sdk.Result {
+	ctx.Logger().Info("handleMsgCancelScheduledTransfer", "msg", msg)
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+
+	result := k.CancelScheduledTransfer(ctx, fromCUAddr, msg.ID)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgCreateVestingCU(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCreateVestingCU) sdk.Result {
+	ctx.Logger().Info("handleMsgCreateVestingCU", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+	toCUAddr, err := sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+	}
+
+	result := k.CreateVestingCU(ctx, fromCUAddr, toCUAddr, msg.Amount, msg.StartTime, msg.EndTime)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
+
+func handleMsgCreatePeriodicVestingCU(ctx sdk.Context, k keeper.BaseKeeper, msg MsgCreatePeriodicVestingCU) sdk.Result {
+	ctx.Logger().Info("handleMsgCreatePeriodicVestingCU", "msg", msg)
+	if !k.IsSendEnabled(ctx) {
+		return types.ErrSendDisabled(k.Codespace()).Result()
+	}
+
+	fromCUAddr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid from CU:%v", msg.FromCU)).Result()
+	}
+	toCUAddr, err := sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("invalid to CU:%v", msg.ToCU)).Result()
+	}
+
+	result := k.CreatePeriodicVestingCU(ctx, fromCUAddr, toCUAddr, msg.StartTime, msg.Periods)
+	if result.Code != sdk.CodeOK {
+		return result
+	}
+
+	result.Events = append(result.Events, ctx.EventManager().Events()...)
+	return result
+}
@@ -34,8 +34,9 @@ func (keeper BaseKeeper) Deposit(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAdd
 		return sdk.ErrInvalidTx(fmt.Sprintf("deposit invalid index:%v", index)).Result()
 	}
 
-	if amt.LT(tokenInfo.DepositThreshold) {
-		return sdk.ErrInsufficientCoins(fmt.Sprintf("desposit %v LT deposit threshold %v", amt, tokenInfo.DepositThreshold)).Result()
+	//deposits below DepositThreshold are accepted as dust, see confirmDustDepositOrder
+	if !amt.IsPositive() {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("desposit %v is not positive", amt)).Result()
 	}
 
 	valid, canonicalToAddr := keeper.cn.ValidAddress(chain, symbol.String(), toAddr)
@@ -53,13 +54,27 @@ func (keeper BaseKeeper) Deposit(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAdd
 	if asset == sdk.NilAsset {
 		asset = toCUAst.GetAssetByAddr(chain, canonicalToAddr)
 		if symbol.String() != chain && asset != sdk.NilAsset {
-			_ = toCUAst.SetAssetAddress(symbol.String(), canonicalToAddr, asset.Epoch)
+			if asset.DepositOnly {
+				_ = toCUAst.AddDepositAddress(symbol.String(), canonicalToAddr, asset.Epoch)
+			} else {
+				_ = toCUAst.SetAssetAddress(symbol.String(), canonicalToAddr, asset.Epoch)
+			}
 			keeper.ik.SetCUIBCAsset(ctx, toCUAst)
 		} else {
 			return sdk.ErrInvalidTx(fmt.Sprintf("Deposit addr %s does not belong to CU %s", canonicalToAddr, toCUAst.GetAddress().String())).Result()
 		}
 	}
 
+	if amt.LT(tokenInfo.DepositThreshold) {
+		dustThreshold := tokenInfo.DepositThreshold.AddRaw(types.MaxDustDepositsPerAddress - 1).QuoRaw(types.MaxDustDepositsPerAddress)
+		if amt.LT(dustThreshold) {
+			return sdk.ErrInsufficientCoins(fmt.Sprintf("desposit %v LT dust deposit threshold %v", amt, dustThreshold)).Result()
+		}
+		if keeper.dustDepositCount(ctx, toCUAddr, symbol.String(), canonicalToAddr) >= types.MaxDustDepositsPerAddress {
+			return sdk.ErrInvalidTx(fmt.Sprintf("too many dust deposits to %v", canonicalToAddr)).Result()
+		}
+	}
+
 	if keeper.ok.IsExist(ctx, orderID) {
 		return sdk.ErrInvalidOrder(fmt.Sprintf("order %v already exists", orderID)).Result()
 	}
@@ -171,6 +186,13 @@ func (keeper BaseKeeper) confirmDepositOrder(ctx sdk.Context, order *sdk.OrderCo
 		}
 	}
 
+	if toCUAst.GetCUType() == sdk.CUTypeUser {
+		tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(order.Symbol))
+		if order.Amount.LT(tokenInfo.DepositThreshold) {
+			return keeper.confirmDustDepositOrder(ctx, toCUAst, order, tokenInfo)
+		}
+	}
+
 	if toCUAst.GetCUType() == sdk.CUTypeOp {
 		//update order status
 		order.SetOrderStatus(sdk.OrderStatusFinish)
@@ -198,7 +220,7 @@ func (keeper BaseKeeper) confirmDepositOrder(ctx sdk.Context, order *sdk.OrderCo
 					collectFee := tokenInfo.CollectFee()
 					if keeper.GetBalance(ctx, toCUAst.GetAddress(), tokenInfo.Chain.String()).GTE(collectFee.Amount) {
 						depositItemStatus = sdk.DepositItemStatusWaitCollect
-						_, flow, err := keeper.SubCoin(ctx, toCUAst.GetAddress(), collectFee)
+						_, flow, err := keeper.subCoin(ctx, toCUAst.GetAddress(), collectFee)
 						if err != nil {
 							return nil, err
 						}
@@ -225,6 +247,21 @@ func (keeper BaseKeeper) confirmDepositOrder(ctx sdk.Context, order *sdk.OrderCo
 			return nil, err
 		}
 		flows = append(flows, flow)
+
+		routeFlows, err := keeper.routeDeposit(ctx, order, collectAmt)
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, routeFlows...)
+
+		//a collect is going to happen on the address, take the dust along
+		if toCUAst.GetCUType() == sdk.CUTypeUser {
+			dustFlows, err := keeper.aggregateDustDeposits(ctx, order.CollectFromCU, order.Symbol, order.CollectFromAddress, true)
+			if err != nil {
+				return nil, err
+			}
+			flows = append(flows, dustFlows...)
+		}
 	}
 
 	toCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(order.Symbol, order.Amount)))
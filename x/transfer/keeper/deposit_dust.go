@@ -0,0 +1,147 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	ibcexported "github.com/hbtc-chain/bhchain/x/ibcasset/exported"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// confirmDustDepositOrder records a confirmed deposit below the deposit threshold as dust of the user CU. Dust is not
+// credited until it's aggregated into a collect, see aggregateDustDeposits.
+func (keeper BaseKeeper) confirmDustDepositOrder(ctx sdk.Context, toCUAst ibcexported.CUIBCAsset, order *sdk.OrderCollect, tokenInfo *sdk.IBCToken) ([]sdk.Flow, error) {
+	keeper.ok.SetOrder(ctx, order)
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.DustDepositKey(order.CollectFromCU, order.Symbol, order.CollectFromAddress, order.ID), []byte(order.ID))
+
+	depositItem, _ := sdk.NewDepositItem(order.Txhash, order.Index, order.Amount, order.CollectFromAddress, order.Memo, sdk.DepositItemStatusDust)
+	err := keeper.ik.SaveDeposit(ctx, order.Symbol, order.CollectFromCU, depositItem)
+	if err != nil {
+		return nil, err
+	}
+	toCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(order.Symbol, order.Amount)))
+	keeper.ik.SetCUIBCAsset(ctx, toCUAst)
+
+	collecting := false
+	for _, item := range keeper.ik.GetDepositList(ctx, order.Symbol, order.CollectFromCU) {
+		if item.GetStatus() == sdk.DepositItemStatusWaitCollect && item.ExtAddress == order.CollectFromAddress {
+			collecting = true
+			break
+		}
+	}
+
+	return keeper.aggregateDustDeposits(ctx, order.CollectFromCU, order.Symbol, order.CollectFromAddress, collecting)
+}
+
+// aggregateDustDeposits moves the dust deposited to extAddress of the user CU to wait collect, if a collect is going
+// to happen on extAddress or the dust sums up to the deposit threshold. Each dust deposit is credited net of the collect
+// fee in proportion to its amount, for tokens whose collect fee is paid in the chain token the fee is charged from
+// the CU's balance of the chain token, and the dust is left uncollected if the balance is insufficient.
+func (keeper BaseKeeper) aggregateDustDeposits(ctx sdk.Context, cuAddr sdk.CUAddress, symbol, extAddress string, collecting bool) ([]sdk.Flow, sdk.Error) {
+	orders := keeper.getDustCollectOrders(ctx, cuAddr, symbol, extAddress)
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	if tokenInfo == nil {
+		return nil, sdk.ErrUnSupportToken(symbol)
+	}
+
+	total, fees := sdk.ZeroInt(), sdk.ZeroInt()
+	for _, order := range orders {
+		total = total.Add(order.Amount)
+		fees = fees.Add(tokenInfo.DustCollectFee(order.Amount).Amount)
+	}
+	if !collecting && total.LT(tokenInfo.DepositThreshold) {
+		return nil, nil
+	}
+	chain := tokenInfo.Chain.String()
+	if chain != symbol && keeper.GetBalance(ctx, cuAddr, chain).LT(fees) {
+		return nil, nil
+	}
+
+	var flows []sdk.Flow
+	var orderIDs []string
+	store := ctx.KVStore(keeper.storeKey)
+	for _, order := range orders {
+		store.Delete(types.DustDepositKey(cuAddr, symbol, extAddress, order.ID))
+		fee := tokenInfo.DustCollectFee(order.Amount)
+		credited := order.Amount
+		if chain == symbol {
+			fee.Amount = sdk.MinInt(fee.Amount, credited)
+			credited = credited.Sub(fee.Amount)
+		} else if fee.IsPositive() {
+			_, flow, err := keeper.subCoin(ctx, cuAddr, fee)
+			if err != nil {
+				return nil, err
+			}
+			flows = append(flows, flow)
+		}
+		order.CostFee = fee.Amount
+		keeper.ok.SetOrder(ctx, order)
+		if err := keeper.ik.SetDepositStatus(ctx, symbol, cuAddr, order.Txhash, order.Index, sdk.DepositItemStatusWaitCollect); err != nil {
+			return nil, sdk.ErrInvalidOrder(fmt.Sprintf("fail to set status of dust deposit %v %v: %v", order.Txhash, order.Index, err))
+		}
+
+		if credited.IsPositive() {
+			_, flow, err := keeper.AddCoin(ctx, cuAddr, sdk.NewCoin(symbol, credited))
+			if err != nil {
+				return nil, err
+			}
+			flows = append(flows, flow)
+
+			routeFlows, err := keeper.routeDeposit(ctx, order, credited)
+			if err != nil {
+				return nil, err
+			}
+			flows = append(flows, routeFlows...)
+		}
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAggregateDustDeposits,
+			sdk.NewAttribute(types.AttributeKeyAddress, cuAddr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, symbol),
+			sdk.NewAttribute(types.AttributeKeyAmount, total.String()),
+			sdk.NewAttribute(types.AttributeKeyOrderIDs, strings.Join(orderIDs, ",")),
+		),
+	)
+	return flows, nil
+}
+
+// getDustCollectOrders returns the collect orders of the confirmed dust deposited to extAddress of the user CU,
+// whose deposit items are still dust
+func (keeper BaseKeeper) getDustCollectOrders(ctx sdk.Context, cuAddr sdk.CUAddress, symbol, extAddress string) []*sdk.OrderCollect {
+	var orders []*sdk.OrderCollect
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.DustDepositKeyPrefix(cuAddr, symbol, extAddress))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		collectOrder, ok := keeper.ok.GetOrder(ctx, string(iter.Value())).(*sdk.OrderCollect)
+		if !ok || collectOrder.DepositStatus != sdk.DepositConfirmed {
+			continue
+		}
+		item := keeper.ik.GetDeposit(ctx, symbol, cuAddr, collectOrder.Txhash, collectOrder.Index)
+		if item == sdk.DepositNil || item.GetStatus() != sdk.DepositItemStatusDust {
+			continue
+		}
+		orders = append(orders, collectOrder)
+	}
+	return orders
+}
+
+// dustDepositCount returns the number of dust deposits waiting for aggregation on extAddress of the user CU
+func (keeper BaseKeeper) dustDepositCount(ctx sdk.Context, cuAddr sdk.CUAddress, symbol, extAddress string) int {
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.DustDepositKeyPrefix(cuAddr, symbol, extAddress))
+	defer iter.Close()
+	count := 0
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	return count
+}
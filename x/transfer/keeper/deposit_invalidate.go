@@ -0,0 +1,254 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+const invalidateDepositVotePrefix = "invalidate-"
+
+// InvalidateDeposit votes that confirmed deposits vanished from the external chain, e.g. after a chain reorg.
+// Once enough key nodes agree, the deposit item is removed, the coins credited to the CU (or to the CU or suspense
+// balance the deposit is routed to) are moved back into hold or taken out of the suspense balance, the part which
+// has already been spent is recorded as deficit, and withdrawals of the credited CU are frozen until the
+// CU settles them by SettleDepositDeficit. Coins credited to the CU later pay back the deficit first. The tx fails before voting if a deposit can not be invalidated yet,
+// so that the vote can be sent again later.
+func (keeper BaseKeeper) InvalidateDeposit(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string) sdk.Result {
+	bValidator, _ := keeper.sk.IsActiveKeyNode(ctx, fromCUAddr)
+	if !bValidator {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalidate deposit from not a validator :%v", fromCUAddr)).Result()
+	}
+
+	var flows []sdk.Flow
+	for _, id := range orderIDs {
+		order := keeper.ok.GetOrder(ctx, id)
+		if order == nil || order.GetOrderType() != sdk.OrderTypeCollect {
+			continue
+		}
+		collectOrder, ok := order.(*sdk.OrderCollect)
+		if !ok || collectOrder.DepositStatus != sdk.DepositConfirmed {
+			continue
+		}
+		if _, err := keeper.checkInvalidateDepositOrder(ctx, collectOrder); err != nil {
+			return err.Result()
+		}
+		confirmedFirstTime, _, _ := keeper.evidenceKeeper.Vote(ctx, invalidateDepositVotePrefix+id, fromCUAddr, types.NewTxVote(0, false), uint64(ctx.BlockHeight()))
+		if confirmedFirstTime {
+			invalidateFlows, err := keeper.invalidateDepositOrder(ctx, collectOrder)
+			if err != nil {
+				return err.Result()
+			}
+			flows = append(flows, invalidateFlows...)
+		}
+	}
+
+	result := sdk.Result{}
+	if len(flows) > 0 {
+		flows = append([]sdk.Flow{keeper.rk.NewOrderFlow("", nil, "", sdk.OrderTypeDeposit, sdk.OrderStatusFinish)}, flows...)
+		receipt := keeper.rk.NewReceipt(sdk.CategoryTypeDeposit, flows)
+		keeper.rk.SaveReceiptToResult(receipt, &result)
+	}
+	return result
+}
+
+// checkInvalidateDepositOrder checks the deposit of order can be invalidated, and returns its deposit item
+func (keeper BaseKeeper) checkInvalidateDepositOrder(ctx sdk.Context, order *sdk.OrderCollect) (sdk.DepositItem, sdk.Error) {
+	item := keeper.ik.GetDeposit(ctx, order.Symbol, order.CollectFromCU, order.Txhash, order.Index)
+	if item == sdk.DepositNil {
+		return item, sdk.ErrInvalidTx(fmt.Sprintf("deposit %v %v does not exist or has been collected", order.Txhash, order.Index))
+	}
+	if item.GetStatus() == sdk.DepositItemStatusInProcess {
+		return item, sdk.ErrInvalidTx(fmt.Sprintf("deposit %v %v is in process", order.Txhash, order.Index))
+	}
+
+	cuAst := keeper.ik.GetCUIBCAsset(ctx, order.CollectFromCU)
+	if cuAst == nil {
+		return item, sdk.ErrInvalidAccount(fmt.Sprintf("CU %v does not exist", order.CollectFromCU))
+	}
+	if cuAst.GetAssetCoins().AmountOf(order.Symbol).LT(order.Amount) {
+		return item, sdk.ErrInsufficientCoins(fmt.Sprintf("CU %v asset coins %v less than deposit amount %v", order.CollectFromCU, cuAst.GetAssetCoins(), order.Amount))
+	}
+
+	if keeper.tk.GetIBCToken(ctx, sdk.Symbol(order.Symbol)) == nil {
+		return item, sdk.ErrUnSupportToken(order.Symbol)
+	}
+	return item, nil
+}
+
+func (keeper BaseKeeper) invalidateDepositOrder(ctx sdk.Context, order *sdk.OrderCollect) ([]sdk.Flow, sdk.Error) {
+	item, err := keeper.checkInvalidateDepositOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	cuAst := keeper.ik.GetCUIBCAsset(ctx, order.CollectFromCU)
+	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(order.Symbol))
+
+	var flows []sdk.Flow
+	heldAmt, deficit := sdk.ZeroInt(), sdk.ZeroInt()
+	if item.GetStatus() == sdk.DepositItemStatusWaitCollect {
+		//coins are credited only when the deposit item is waiting for collect, the collect fee charged is not refunded
+		credited := order.Amount
+		if tokenInfo.Symbol == tokenInfo.Chain {
+			credited = credited.Sub(order.CostFee)
+		}
+
+		target := order.CollectFromCU
+		if !order.CreditedTo.Empty() {
+			target = order.CreditedTo
+		}
+
+		depositDeficit := keeper.GetDepositDeficit(ctx, target, order.Symbol)
+		if order.CreditedToSuspense {
+			//the part still in the suspense balance is taken back directly, the reassigned part is spent
+			suspense := keeper.GetSuspenseBalance(ctx, target, order.Symbol)
+			heldAmt = sdk.MinInt(credited, suspense)
+			keeper.setSuspenseBalance(ctx, target, order.Symbol, suspense.Sub(heldAmt))
+		} else {
+			heldAmt = sdk.MinInt(credited, keeper.GetBalance(ctx, target, order.Symbol))
+			if heldAmt.IsPositive() {
+				lockFlows, err := keeper.lockCoin(ctx, target, sdk.NewCoin(order.Symbol, heldAmt))
+				if err != nil {
+					return nil, err
+				}
+				flows = append(flows, lockFlows...)
+				depositDeficit.Held = depositDeficit.Held.Add(heldAmt)
+			}
+		}
+
+		deficit = credited.Sub(heldAmt)
+		depositDeficit.Deficit = depositDeficit.Deficit.Add(deficit)
+		if !depositDeficit.IsZero() {
+			keeper.setDepositDeficit(ctx, target, depositDeficit)
+			keeper.setWithdrawalFrozen(ctx, target, true)
+		}
+	}
+
+	keeper.ik.DelDeposit(ctx, order.Symbol, order.CollectFromCU, order.Txhash, order.Index)
+	if item.GetStatus() == sdk.DepositItemStatusDust {
+		store := ctx.KVStore(keeper.storeKey)
+		store.Delete(types.DustDepositKey(order.CollectFromCU, order.Symbol, order.CollectFromAddress, order.ID))
+	}
+	cuAst.SubAssetCoins(sdk.NewCoins(sdk.NewCoin(order.Symbol, order.Amount)))
+	keeper.ik.SetCUIBCAsset(ctx, cuAst)
+
+	order.DepositStatus = sdk.DepositInvalidated
+	order.SetOrderStatus(sdk.OrderStatusFinish)
+	keeper.ok.SetOrder(ctx, order)
+
+	flows = append(flows, keeper.rk.NewDepositInvalidatedFlow(order.ID, order.CollectFromCU.String(), order.Symbol, order.Txhash, order.Index, order.Amount, heldAmt, deficit))
+	return flows, nil
+}
+
+// SettleDepositDeficit burns the coins of the CU credited by invalidated deposits of symbol, the held part from its
+// hold balance and the spent part from its balance. Withdrawals of the CU are unfrozen once all its deficits are settled.
+func (keeper BaseKeeper) SettleDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, symbol string) sdk.Result {
+	depositDeficit := keeper.GetDepositDeficit(ctx, addr, symbol)
+	if depositDeficit.IsZero() {
+		return sdk.ErrInvalidTx(fmt.Sprintf("CU %v has no deposit deficit of %v", addr, symbol)).Result()
+	}
+	if balance := keeper.GetBalance(ctx, addr, symbol); balance.LT(depositDeficit.Deficit) {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("need:%v%v, actual have:%v%v", depositDeficit.Deficit, symbol, balance, symbol)).Result()
+	}
+
+	var flows []sdk.Flow
+	if depositDeficit.Deficit.IsPositive() {
+		_, flow, err := keeper.subCoin(ctx, addr, sdk.NewCoin(symbol, depositDeficit.Deficit))
+		if err != nil {
+			return err.Result()
+		}
+		flows = append(flows, flow)
+	}
+	if depositDeficit.Held.IsPositive() {
+		_, flow, err := keeper.SubCoinHold(ctx, addr, sdk.NewCoin(symbol, depositDeficit.Held))
+		if err != nil {
+			return err.Result()
+		}
+		flows = append(flows, flow)
+	}
+
+	keeper.setDepositDeficit(ctx, addr, types.NewDepositDeficit(symbol))
+	if len(keeper.GetDepositDeficits(ctx, addr)) == 0 {
+		keeper.setWithdrawalFrozen(ctx, addr, false)
+	}
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeDeposit, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// netDepositDeficit pays back the spent part of the deposit deficit of the CU by coin credited to it,
+// and returns the amount left to credit
+func (keeper BaseKeeper) netDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) sdk.Int {
+	depositDeficit := keeper.GetDepositDeficit(ctx, addr, coin.Denom)
+	if !depositDeficit.Deficit.IsPositive() || !coin.Amount.IsPositive() {
+		return coin.Amount
+	}
+	netted := sdk.MinInt(coin.Amount, depositDeficit.Deficit)
+	depositDeficit.Deficit = depositDeficit.Deficit.Sub(netted)
+	keeper.setDepositDeficit(ctx, addr, depositDeficit)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeNetDepositDeficit,
+			sdk.NewAttribute(types.AttributeKeyAddress, addr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, coin.Denom),
+			sdk.NewAttribute(types.AttributeKeyAmount, netted.String()),
+		),
+	)
+	return coin.Amount.Sub(netted)
+}
+
+// GetDepositDeficit returns the coins credited by invalidated deposits of symbol which are not settled by the CU
+func (keeper BaseKeeper) GetDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, symbol string) types.DepositDeficit {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.DepositDeficitKey(addr, symbol))
+	if len(bz) == 0 {
+		return types.NewDepositDeficit(symbol)
+	}
+	var depositDeficit types.DepositDeficit
+	keeper.cdc.MustUnmarshalBinaryBare(bz, &depositDeficit)
+	return depositDeficit
+}
+
+// GetDepositDeficits returns all the deposit deficits of the CU which are not settled
+func (keeper BaseKeeper) GetDepositDeficits(ctx sdk.Context, addr sdk.CUAddress) []types.DepositDeficit {
+	var deficits []types.DepositDeficit
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.DepositDeficitKeyPrefix(addr))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var depositDeficit types.DepositDeficit
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &depositDeficit)
+		deficits = append(deficits, depositDeficit)
+	}
+	return deficits
+}
+
+func (keeper BaseKeeper) setDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, depositDeficit types.DepositDeficit) {
+	store := ctx.KVStore(keeper.storeKey)
+	key := types.DepositDeficitKey(addr, depositDeficit.Symbol)
+	if depositDeficit.IsZero() {
+		store.Delete(key)
+	} else {
+		bz := keeper.cdc.MustMarshalBinaryBare(depositDeficit)
+		store.Set(key, bz)
+	}
+}
+
+// IsWithdrawalFrozen returns whether withdrawals of the CU are frozen by invalidated deposits
+func (keeper BaseKeeper) IsWithdrawalFrozen(ctx sdk.Context, addr sdk.CUAddress) bool {
+	store := ctx.KVStore(keeper.storeKey)
+	return store.Has(types.WithdrawalFrozenKey(addr))
+}
+
+func (keeper BaseKeeper) setWithdrawalFrozen(ctx sdk.Context, addr sdk.CUAddress, frozen bool) {
+	store := ctx.KVStore(keeper.storeKey)
+	if frozen {
+		store.Set(types.WithdrawalFrozenKey(addr), []byte{})
+	} else {
+		store.Delete(types.WithdrawalFrozenKey(addr))
+	}
+}
@@ -0,0 +1,178 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// SetDepositRoute routes the deposits to the addresses of owner carrying memo to toCUAddr,
+// a nil toCUAddr removes the route. Once owner has a route, deposits with unknown memos go to its suspense balance.
+func (keeper BaseKeeper) SetDepositRoute(ctx sdk.Context, owner sdk.CUAddress, memo string, toCUAddr sdk.CUAddress) sdk.Result {
+	if err := keeper.checkNotFrozen(ctx, owner); err != nil {
+		return err.Result()
+	}
+	if memo == "" || len(memo) > types.MaxDepositMemoLength {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid memo length %v", len(memo))).Result()
+	}
+
+	ownerCU := keeper.ck.GetCU(ctx, owner)
+	if ownerCU == nil {
+		return sdk.ErrInvalidAccount(owner.String()).Result()
+	}
+	if ownerCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("deposit route of a non user CU :%v", owner)).Result()
+	}
+
+	store := ctx.KVStore(keeper.storeKey)
+	if toCUAddr.Empty() {
+		store.Delete(types.DepositRouteKey(owner, memo))
+		return sdk.Result{}
+	}
+
+	if toCUAddr.Equals(owner) {
+		return sdk.ErrInvalidTx("deposit route to the owner itself").Result()
+	}
+	if toCU := keeper.ck.GetCU(ctx, toCUAddr); toCU != nil && toCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("deposit route to a non user CU :%v", toCUAddr)).Result()
+	}
+
+	store.Set(types.DepositRouteKey(owner, memo), toCUAddr)
+	return sdk.Result{}
+}
+
+// GetDepositRoute returns the CU the deposits of owner carrying memo are routed to, nil if the memo is unknown
+func (keeper BaseKeeper) GetDepositRoute(ctx sdk.Context, owner sdk.CUAddress, memo string) sdk.CUAddress {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.DepositRouteKey(owner, memo))
+	if len(bz) == 0 {
+		return nil
+	}
+	return sdk.CUAddress(bz)
+}
+
+// GetDepositRoutes returns the routing table of owner, memo -> CU
+func (keeper BaseKeeper) GetDepositRoutes(ctx sdk.Context, owner sdk.CUAddress) map[string]sdk.CUAddress {
+	routes := make(map[string]sdk.CUAddress)
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.DepositRouteKeyPrefix(owner))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		routes[types.GetMemoFromDepositRouteKey(iter.Key())] = sdk.CUAddress(iter.Value())
+	}
+	return routes
+}
+
+func (keeper BaseKeeper) hasDepositRoute(ctx sdk.Context, owner sdk.CUAddress) bool {
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.DepositRouteKeyPrefix(owner))
+	defer iter.Close()
+	return iter.Valid()
+}
+
+// GetSuspenseBalance returns the deposits to the addresses of owner with unknown memos, which are not reassigned yet
+func (keeper BaseKeeper) GetSuspenseBalance(ctx sdk.Context, owner sdk.CUAddress, symbol string) sdk.Int {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.SuspenseBalanceKey(owner, symbol))
+	if len(bz) == 0 {
+		return sdk.ZeroInt()
+	}
+	var amt sdk.Int
+	keeper.cdc.MustUnmarshalBinaryBare(bz, &amt)
+	return amt
+}
+
+// GetAllSuspenseBalance returns the suspense balance of owner of all symbols
+func (keeper BaseKeeper) GetAllSuspenseBalance(ctx sdk.Context, owner sdk.CUAddress) sdk.Coins {
+	coins := sdk.NewCoins()
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.SuspenseBalanceKeyPrefix(owner))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var amt sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &amt)
+		coins = coins.Add(sdk.NewCoins(sdk.NewCoin(types.GetSymbolFromSuspenseBalanceKey(iter.Key()), amt)))
+	}
+	return coins
+}
+
+func (keeper BaseKeeper) setSuspenseBalance(ctx sdk.Context, owner sdk.CUAddress, symbol string, amt sdk.Int) {
+	store := ctx.KVStore(keeper.storeKey)
+	key := types.SuspenseBalanceKey(owner, symbol)
+	if amt.IsZero() {
+		store.Delete(key)
+	} else {
+		store.Set(key, keeper.cdc.MustMarshalBinaryBare(amt))
+	}
+}
+
+// ReassignSuspense moves amt of the suspense balance of owner to toCUAddr
+func (keeper BaseKeeper) ReassignSuspense(ctx sdk.Context, owner, toCUAddr sdk.CUAddress, symbol string, amt sdk.Int) sdk.Result {
+	if err := keeper.checkNotFrozen(ctx, owner); err != nil {
+		return err.Result()
+	}
+	if !amt.IsPositive() {
+		return sdk.ErrInvalidAmount("amount is not positive").Result()
+	}
+	if toCU := keeper.ck.GetCU(ctx, toCUAddr); toCU != nil && toCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("reassign to a non user CU :%v", toCUAddr)).Result()
+	}
+
+	suspense := keeper.GetSuspenseBalance(ctx, owner, symbol)
+	if suspense.LT(amt) {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("suspense balance %v%v is less than %v", suspense, symbol, amt)).Result()
+	}
+	keeper.setSuspenseBalance(ctx, owner, symbol, suspense.Sub(amt))
+
+	_, balanceFlow, err := keeper.AddCoin(ctx, toCUAddr, sdk.NewCoin(symbol, amt))
+	if err != nil {
+		return err.Result()
+	}
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewSuspenseReassignedFlow(owner.String(), toCUAddr.String(), symbol, amt))
+	flows = append(flows, balanceFlow)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeTransfer, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// routeDeposit re-credits the amount of a confirmed deposit credited to the owner of the deposit address
+// according to its memo, and records the credited target on the order. It does nothing if the owner has no deposit route
+// or is frozen.
+func (keeper BaseKeeper) routeDeposit(ctx sdk.Context, order *sdk.OrderCollect, credited sdk.Int) ([]sdk.Flow, sdk.Error) {
+	owner := order.CollectFromCU
+	if !credited.IsPositive() || !keeper.hasDepositRoute(ctx, owner) || keeper.IsAddressFrozen(ctx, owner) {
+		return nil, nil
+	}
+
+	coin := sdk.NewCoin(order.Symbol, credited)
+	_, subFlow, err := keeper.SubCoin(ctx, owner, coin)
+	if err != nil {
+		return nil, err
+	}
+	flows := []sdk.Flow{subFlow}
+
+	toCUAddr := keeper.GetDepositRoute(ctx, owner, order.Memo)
+	if toCUAddr == nil {
+		keeper.setSuspenseBalance(ctx, owner, order.Symbol, keeper.GetSuspenseBalance(ctx, owner, order.Symbol).Add(credited))
+		order.CreditedTo = owner
+		order.CreditedToSuspense = true
+		keeper.ok.SetOrder(ctx, order)
+		flows = append(flows, keeper.rk.NewDepositRoutedFlow(order.ID, owner.String(), owner.String(), order.Memo, order.Symbol, credited, true))
+		return flows, nil
+	}
+
+	_, addFlow, err := keeper.AddCoin(ctx, toCUAddr, coin)
+	if err != nil {
+		return nil, err
+	}
+	order.CreditedTo = toCUAddr
+	keeper.ok.SetOrder(ctx, order)
+	flows = append(flows, addFlow)
+	flows = append(flows, keeper.rk.NewDepositRoutedFlow(order.ID, owner.String(), toCUAddr.String(), order.Memo, order.Symbol, credited, false))
+	return flows, nil
+}
@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// IsAddressFrozen returns true if addr is frozen by governance
+func (keeper BaseKeeper) IsAddressFrozen(ctx sdk.Context, addr sdk.CUAddress) bool {
+	store := ctx.KVStore(keeper.storeKey)
+	return store.Has(types.FrozenAddressKey(addr))
+}
+
+// SetAddressFrozen freezes addr if frozen is true, otherwise unfreezes it and resumes its parked scheduled transfers
+func (keeper BaseKeeper) SetAddressFrozen(ctx sdk.Context, addr sdk.CUAddress, frozen bool) {
+	store := ctx.KVStore(keeper.storeKey)
+	if frozen {
+		store.Set(types.FrozenAddressKey(addr), []byte{})
+	} else {
+		store.Delete(types.FrozenAddressKey(addr))
+		keeper.resumeScheduledTransfers(ctx, addr)
+	}
+}
+
+// GetFrozenAddresses returns all the frozen addresses
+func (keeper BaseKeeper) GetFrozenAddresses(ctx sdk.Context) []sdk.CUAddress {
+	var addrs []sdk.CUAddress
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.FrozenAddressKeyPrefix())
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		addrs = append(addrs, types.GetAddressFromFrozenAddressKey(iter.Key()))
+	}
+	return addrs
+}
+
+// IsFrozenRestrictedMsg returns true if msg can not be signed by a frozen address
+func (keeper BaseKeeper) IsFrozenRestrictedMsg(msg sdk.Msg) bool {
+	return types.IsFrozenRestrictedMsg(msg)
+}
+
+// checkNotFrozen returns an error if addr is frozen
+func (keeper BaseKeeper) checkNotFrozen(ctx sdk.Context, addr sdk.CUAddress) sdk.Error {
+	if keeper.IsAddressFrozen(ctx, addr) {
+		return types.ErrAddressFrozen(keeper.Codespace(), addr)
+	}
+	return nil
+}
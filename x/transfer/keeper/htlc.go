@@ -0,0 +1,146 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// CreateHTLC locks amount of sender under hashlock, it can be claimed by receiver with the preimage
+// of hashlock before timeLock blocks passed, or refunded to sender after that.
+func (keeper BaseKeeper) CreateHTLC(ctx sdk.Context, sender, receiver sdk.CUAddress, amount sdk.Coins, hashlock []byte, timeLock uint64) sdk.Result {
+	if len(hashlock) != types.HTLCHashLength {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock length %v", len(hashlock))).Result()
+	}
+	if timeLock < types.MinHTLCTimeLock || timeLock > types.MaxHTLCTimeLock {
+		return sdk.ErrInvalidTx(fmt.Sprintf("time lock %v out of range [%v, %v]", timeLock, types.MinHTLCTimeLock, types.MaxHTLCTimeLock)).Result()
+	}
+	if err := keeper.checkNotFrozen(ctx, sender); err != nil {
+		return err.Result()
+	}
+	if sender.Equals(receiver) {
+		return sdk.ErrInvalidTx("htlc to the sender itself").Result()
+	}
+	if keeper.GetHTLC(ctx, sender, hashlock) != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x already exists", hashlock)).Result()
+	}
+	if toCU := keeper.ck.GetCU(ctx, receiver); toCU != nil && toCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc to a non user CU :%v", receiver)).Result()
+	}
+
+	lockFlows, err := keeper.LockCoins(ctx, sender, amount)
+	if err != nil {
+		return err.Result()
+	}
+
+	htlc := types.HTLC{
+		Hashlock:     hashlock,
+		Sender:       sender,
+		Receiver:     receiver,
+		Amount:       amount,
+		ExpireHeight: uint64(ctx.BlockHeight()) + timeLock,
+	}
+	keeper.setHTLC(ctx, htlc)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewHTLCCreatedFlow(hex.EncodeToString(hashlock), sender.String(), receiver.String(), amount, htlc.ExpireHeight))
+	flows = append(flows, lockFlows...)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeHTLC, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// ClaimHTLC pays the locked amount of an unexpired HTLC of sender to its receiver if preimage matches its hashlock,
+// the claimed HTLC is kept with the preimage
+func (keeper BaseKeeper) ClaimHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock, preimage []byte) sdk.Result {
+	htlc := keeper.GetHTLC(ctx, sender, hashlock)
+	if htlc == nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x does not exist", hashlock)).Result()
+	}
+	if htlc.IsClaimed() {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x has been claimed", hashlock)).Result()
+	}
+	if htlc.IsExpired(uint64(ctx.BlockHeight())) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x expired at %v", hashlock, htlc.ExpireHeight)).Result()
+	}
+	if !bytes.Equal(types.HTLCHashlock(preimage), htlc.Hashlock) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("preimage %x does not match hashlock %x", preimage, hashlock)).Result()
+	}
+
+	_, subFlows, err := keeper.SubCoinsHold(ctx, htlc.Sender, htlc.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	_, addFlows, err := keeper.AddCoins(ctx, htlc.Receiver, htlc.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	htlc.Preimage = preimage
+	keeper.setHTLC(ctx, *htlc)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewHTLCClaimedFlow(hex.EncodeToString(hashlock), hex.EncodeToString(preimage), htlc.Sender.String(), htlc.Receiver.String(), htlc.Amount))
+	flows = append(flows, subFlows...)
+	flows = append(flows, addFlows...)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeHTLC, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// RefundHTLC unlocks the amount of an expired and unclaimed HTLC to its sender
+func (keeper BaseKeeper) RefundHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock []byte) sdk.Result {
+	htlc := keeper.GetHTLC(ctx, sender, hashlock)
+	if htlc == nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x does not exist", hashlock)).Result()
+	}
+	if htlc.IsClaimed() {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x has been claimed", hashlock)).Result()
+	}
+	if !htlc.IsExpired(uint64(ctx.BlockHeight())) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("htlc %x does not expire until %v", hashlock, htlc.ExpireHeight)).Result()
+	}
+
+	unlockFlows, err := keeper.UnlockCoins(ctx, htlc.Sender, htlc.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	keeper.deleteHTLC(ctx, sender, hashlock)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewHTLCRefundedFlow(hex.EncodeToString(hashlock), htlc.Sender.String(), htlc.Amount))
+	flows = append(flows, unlockFlows...)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeHTLC, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// GetHTLC returns the unrefunded HTLC of sender locked under hashlock, nil if it does not exist
+func (keeper BaseKeeper) GetHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock []byte) *types.HTLC {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.HTLCKey(sender, hashlock))
+	if len(bz) == 0 {
+		return nil
+	}
+	var htlc types.HTLC
+	keeper.cdc.MustUnmarshalBinaryBare(bz, &htlc)
+	return &htlc
+}
+
+func (keeper BaseKeeper) setHTLC(ctx sdk.Context, htlc types.HTLC) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.HTLCKey(htlc.Sender, htlc.Hashlock), keeper.cdc.MustMarshalBinaryBare(htlc))
+}
+
+func (keeper BaseKeeper) deleteHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock []byte) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(types.HTLCKey(sender, hashlock))
+}
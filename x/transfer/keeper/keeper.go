@@ -9,6 +9,8 @@ import (
 	"github.com/hbtc-chain/bhchain/codec"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit/exported"
+	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
 	"github.com/hbtc-chain/bhchain/x/params"
 	"github.com/hbtc-chain/bhchain/x/transfer/types"
 )
@@ -25,17 +27,50 @@ type Keeper interface {
 	UndelegateCoins(ctx sdk.Context, moduleAccAddr, delegatorAddr sdk.CUAddress, amt sdk.Coins) (sdk.Result, sdk.Error)
 	Deposit(ctx sdk.Context, fromCU, toCUAddr sdk.CUAddress, symbol sdk.Symbol, toAddr, hash string, index uint64, amt sdk.Int, orderID, memo string) sdk.Result
 	ConfirmedDeposit(ctx sdk.Context, fromCUAddr sdk.CUAddress, validOrderIDs, invalidOrderIds []string) sdk.Result
+	InvalidateDeposit(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string) sdk.Result
+	GetDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, symbol string) types.DepositDeficit
+	GetDepositDeficits(ctx sdk.Context, addr sdk.CUAddress) []types.DepositDeficit
+	SettleDepositDeficit(ctx sdk.Context, addr sdk.CUAddress, symbol string) sdk.Result
+	IsWithdrawalFrozen(ctx sdk.Context, addr sdk.CUAddress) bool
+	GetReserveReport(ctx sdk.Context, symbol string, prove sdk.CUAddress, crossCheck bool) (types.ReserveReport, sdk.Error)
 	CollectWaitSign(ctx sdk.Context, toCUAddr sdk.CUAddress, orderIDs []string, rawData []byte) sdk.Result
 	CollectSignFinish(ctx sdk.Context, orderIDs []string, signedTx []byte) sdk.Result
 	CollectFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, costFee sdk.Int) sdk.Result
 
 	Withdrawal(ctx sdk.Context, fromCU sdk.CUAddress, toAddr, orderID, symbol string, amt, gasFee sdk.Int) sdk.Result
+	EstimateWithdrawalFee(ctx sdk.Context, symbol string, amt sdk.Int, toAddr string) (types.ResWithdrawalFee, sdk.Error)
+	SetDepositRoute(ctx sdk.Context, owner sdk.CUAddress, memo string, toCUAddr sdk.CUAddress) sdk.Result
+	GetDepositRoute(ctx sdk.Context, owner sdk.CUAddress, memo string) sdk.CUAddress
+	GetDepositRoutes(ctx sdk.Context, owner sdk.CUAddress) map[string]sdk.CUAddress
+	GetSuspenseBalance(ctx sdk.Context, owner sdk.CUAddress, symbol string) sdk.Int
+	GetAllSuspenseBalance(ctx sdk.Context, owner sdk.CUAddress) sdk.Coins
+	ReassignSuspense(ctx sdk.Context, owner, toCUAddr sdk.CUAddress, symbol string, amt sdk.Int) sdk.Result
+	CreateHTLC(ctx sdk.Context, sender, receiver sdk.CUAddress, amount sdk.Coins, hashlock []byte, timeLock uint64) sdk.Result
+	ClaimHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock, preimage []byte) sdk.Result
+	RefundHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock []byte) sdk.Result
+	GetHTLC(ctx sdk.Context, sender sdk.CUAddress, hashlock []byte) *types.HTLC
+	ScheduleTransfer(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, amount sdk.Coins, byTime bool, start, interval, times uint64) sdk.Result
+	CancelScheduledTransfer(ctx sdk.Context, fromCUAddr sdk.CUAddress, id uint64) sdk.Result
+
+	IsAddressFrozen(ctx sdk.Context, addr sdk.CUAddress) bool
+	SetAddressFrozen(ctx sdk.Context, addr sdk.CUAddress, frozen bool)
+	GetFrozenAddresses(ctx sdk.Context) []sdk.CUAddress
+	IsFrozenRestrictedMsg(msg sdk.Msg) bool
+
+	CreateVestingCU(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, amount sdk.Coins, startTime, endTime int64) sdk.Result
+	CreatePeriodicVestingCU(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, startTime int64, periods cutypes.VestingPeriods) sdk.Result
+	ExecuteScheduledTransfers(ctx sdk.Context)
+	GetScheduledTransfer(ctx sdk.Context, id uint64) *types.ScheduledTransfer
+	GetScheduledTransfers(ctx sdk.Context, owner sdk.CUAddress) []types.ScheduledTransfer
+	BatchWithdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddress, batchID, symbol string, outputs []types.WithdrawalOutput, gasFee sdk.Int) sdk.Result
 	WithdrawalConfirm(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderID string, valid bool) sdk.Result
 
 	WithdrawalWaitSign(ctx sdk.Context, opCUAddr sdk.CUAddress, orderIDs []string, signHashes [][]byte, rawData []byte) sdk.Result
 	WithdrawalSignFinish(ctx sdk.Context, orderIDs []string, signedTx []byte) sdk.Result
 	WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, costFee sdk.Int, valid bool) sdk.Result
+	WithdrawalReplacedTxFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, txHash string, costFee sdk.Int, valid bool) sdk.Result
 	CancelWithdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderID string) sdk.Result
+	WithdrawalFeeBump(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, signHashes [][]byte, rawData []byte) sdk.Result
 
 	SysTransfer(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, toAddr, orderID, symbol string) sdk.Result
 	SysTransferWaitSign(ctx sdk.Context, orderID string, signHash []byte, rawData []byte) sdk.Result
@@ -46,6 +81,12 @@ type Keeper interface {
 	OpcuAssetTransferWaitSign(ctx sdk.Context, orderID string, signHashes [][]byte, rawData []byte) sdk.Result
 	OpcuAssetTransferSignFinish(ctx sdk.Context, orderID string, signedTx []byte) sdk.Result
 	OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderID string, costFee sdk.Int) sdk.Result
+	ScheduleUtxoConsolidation(ctx sdk.Context)
+	ScheduleOpCURebalance(ctx sdk.Context)
+	UtxoConsolidationWaitSign(ctx sdk.Context, orderID string, signHashes [][]byte, rawData []byte) sdk.Result
+	UtxoConsolidationSignFinish(ctx sdk.Context, orderID string, signedTx []byte) sdk.Result
+	UtxoConsolidationFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderID string, costFee sdk.Int) sdk.Result
+	GetGasPriceAverage(ctx sdk.Context, chain string) sdk.Int
 
 	OrderRetry(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, retryTimes uint32, evidences []types.EvidenceValidator) sdk.Result
 }
@@ -116,6 +157,18 @@ func (keeper *BaseKeeper) DelegateCoins(ctx sdk.Context, delegatorAddr, moduleAc
 		return sdkErr.Result(), sdkErr
 	}
 
+	if vcu, ok := delegatorAcc.(exported.VestingCU); ok {
+		for _, coin := range amt {
+			if balance := keeper.GetBalance(ctx, delegatorAddr, coin.Denom); balance.LT(coin.Amount) {
+				sdkErr = sdk.ErrInsufficientCoins(fmt.Sprintf("token %s balance not enough, has %s, need %s", coin.Denom, balance, coin.Amount))
+				return sdkErr.Result(), sdkErr
+			}
+		}
+		// the delegated vesting coins are no longer locked, so that they can be sent to the module CU
+		vcu.TrackDelegation(ctx.BlockHeader().Time, amt)
+		keeper.ck.SetCU(ctx, vcu)
+	}
+
 	_, flows, err := keeper.SendCoins(ctx, delegatorAddr, moduleAccAddr, amt)
 	if err != nil {
 		return err.Result(), err
@@ -152,6 +205,11 @@ func (keeper *BaseKeeper) UndelegateCoins(ctx sdk.Context, moduleAccAddr, delega
 		return err.Result(), err
 	}
 
+	if vcu, ok := delegatorAcc.(exported.VestingCU); ok {
+		vcu.TrackUndelegation(amt)
+		keeper.ck.SetCU(ctx, vcu)
+	}
+
 	var result sdk.Result
 	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeTransfer, flows)
 	keeper.rk.SaveReceiptToResult(receipt, &result)
@@ -209,8 +267,9 @@ func (keeper *BaseKeeper) OrderRetry(ctx sdk.Context, fromCUAddr sdk.CUAddress,
 	if firstConfirmed {
 		var excludedKeyNode sdk.CUAddress
 		if orderType == sdk.OrderTypeKeyGen {
+			keygenOrder := order.(*sdk.OrderKeyGen)
 			keyNodes := keeper.sk.GetCurrentEpoch(ctx).KeyNodeSet
-			excludedKeyNode = keeper.getExcludedKeyNode(ctx, keyNodes)
+			excludedKeyNode = keeper.getExcludedKeyNode(ctx, keyNodes, int(keygenOrder.SignThreshold))
 			var i int
 			for _, keyNode := range keyNodes {
 				if !keyNode.Equals(excludedKeyNode) {
@@ -218,8 +277,8 @@ func (keeper *BaseKeeper) OrderRetry(ctx sdk.Context, fromCUAddr sdk.CUAddress,
 					i++
 				}
 			}
-			keygenOrder := order.(*sdk.OrderKeyGen)
 			keygenOrder.KeyNodes = keyNodes[:i]
+			keygenOrder.StageHeight = uint64(ctx.BlockHeight())
 			order.SetOrderStatus(sdk.OrderStatusBegin)
 			keeper.ok.SetOrder(ctx, order)
 		}
@@ -0,0 +1,186 @@
+package keeper
+
+import (
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/ibcasset/exported"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// ScheduleOpCURebalance checks the balances of hot OPCUs every OpCURebalanceInterval blocks, and schedules an opcu
+// asset transfer to the cold tier if a hot OPCU is above the high watermark, or from the cold tier if it is below the
+// low watermark. The hot OPCU is rebalanced to the middle of the watermarks.
+func (keeper BaseKeeper) ScheduleOpCURebalance(ctx sdk.Context) {
+	if ctx.BlockHeight()%types.OpCURebalanceInterval != 0 || !keeper.IsSendEnabled(ctx) {
+		return
+	}
+
+	curEpoch := keeper.sk.GetCurrentEpoch(ctx)
+	if !curEpoch.MigrationFinished {
+		return
+	}
+
+	for _, opCU := range keeper.ck.GetOpCUs(ctx, "") {
+		tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(opCU.GetSymbol()))
+		if tokenInfo == nil || tokenInfo.OpCUTiers == nil || !tokenInfo.SendEnabled || tokenInfo.IsColdOpCU(opCU.GetAddress()) {
+			continue
+		}
+		keeper.scheduleOpCURebalance(ctx, opCU.GetAddress(), tokenInfo, curEpoch.Index)
+	}
+}
+
+func (keeper BaseKeeper) scheduleOpCURebalance(ctx sdk.Context, hotAddr sdk.CUAddress, tokenInfo *sdk.IBCToken, epochIndex uint64) {
+	hotAst := keeper.ik.GetCUIBCAsset(ctx, hotAddr)
+	if hotAst == nil {
+		return
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	tiers := tokenInfo.OpCUTiers
+	balance := hotAst.GetAssetCoins().AmountOf(symbol)
+	target := tiers.RebalanceTarget()
+
+	var from, to exported.CUIBCAsset
+	var amount sdk.Int
+	switch {
+	case tiers.HotHighWatermark.IsPositive() && balance.GT(tiers.HotHighWatermark):
+		from, to = hotAst, keeper.getColdOpCU(ctx, tokenInfo, epochIndex, false)
+		amount = balance.Sub(target)
+	case tiers.HotLowWatermark.IsPositive() && balance.LT(tiers.HotLowWatermark):
+		from, to = keeper.getColdOpCU(ctx, tokenInfo, epochIndex, true), hotAst
+		if from == nil {
+			return
+		}
+		amount = sdk.MinInt(target.Sub(balance), from.GetAssetCoins().AmountOf(symbol))
+	default:
+		return
+	}
+	if from == nil || to == nil || !amount.IsPositive() {
+		return
+	}
+
+	keeper.newOpCURebalanceOrder(ctx, from, to, tokenInfo, amount, epochIndex)
+}
+
+// getColdOpCU returns the cold OPCU with the highest balance of the token if highest is set, the lowest otherwise
+func (keeper BaseKeeper) getColdOpCU(ctx sdk.Context, tokenInfo *sdk.IBCToken, epochIndex uint64, highest bool) exported.CUIBCAsset {
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+
+	var res exported.CUIBCAsset
+	for _, addr := range tokenInfo.OpCUTiers.ColdOpCUs {
+		cu := keeper.ck.GetCU(ctx, addr)
+		if cu == nil || cu.GetCUType() != sdk.CUTypeOp || cu.GetSymbol() != symbol {
+			continue
+		}
+		cuAst := keeper.ik.GetCUIBCAsset(ctx, addr)
+		if cuAst == nil || cuAst.GetAssetAddress(chain, epochIndex) == "" {
+			continue
+		}
+		if res == nil {
+			res = cuAst
+			continue
+		}
+		balance, resBalance := cuAst.GetAssetCoins().AmountOf(symbol), res.GetAssetCoins().AmountOf(symbol)
+		if (highest && balance.GT(resBalance)) || (!highest && balance.LT(resBalance)) {
+			res = cuAst
+		}
+	}
+	return res
+}
+
+func (keeper BaseKeeper) newOpCURebalanceOrder(ctx sdk.Context, from, to exported.CUIBCAsset, tokenInfo *sdk.IBCToken, amount sdk.Int, epochIndex uint64) {
+	if keeper.hasUnfinishedOrder(ctx, from.GetAddress()) {
+		return
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	fromAddr := from.GetAssetAddress(chain, epochIndex)
+	toAddr := to.GetAssetAddress(chain, epochIndex)
+	if fromAddr == "" || toAddr == "" || !from.IsEnabledSendTx(chain, fromAddr) {
+		return
+	}
+
+	var items []sdk.TransferItem
+	switch tokenInfo.TokenType {
+	case sdk.UtxoBased:
+		depositList := keeper.ik.GetDepositList(ctx, symbol, from.GetAddress())
+		depositList = depositList.Filter(func(d sdk.DepositItem) bool {
+			return d.ExtAddress == fromAddr && d.Status == sdk.DepositItemStatusConfirmed
+		})
+		// move the largest utxos that fit in the amount, as there is no change the amount is never exceeded
+		depositList.SortByAmount()
+		sum := sdk.ZeroInt()
+		for i := len(depositList) - 1; i >= 0 && sum.LT(amount) && len(items) < sdk.MaxVinNum; i-- {
+			d := depositList[i]
+			if sum.Add(d.Amount).GT(amount) {
+				continue
+			}
+			items = append(items, sdk.TransferItem{Hash: d.Hash, Index: d.Index, Amount: d.Amount})
+			sum = sum.Add(d.Amount)
+		}
+		if len(items) == 0 || sum.LTE(keeper.utxoOpcuAstTransferThreshold(len(items), tokenInfo)) {
+			return
+		}
+		amount = sum
+
+	case sdk.AccountBased:
+		chainToken := tokenInfo
+		if symbol != chain {
+			chainToken = keeper.tk.GetIBCToken(ctx, sdk.Symbol(chain))
+			if chainToken == nil {
+				return
+			}
+		}
+		gasFee := chainToken.GasPrice.Mul(tokenInfo.GasLimit)
+		if symbol == chain {
+			// the gas fee is paid out of the transferred amount
+			if amount.LTE(gasFee) {
+				return
+			}
+		} else if from.GetAssetCoins().AmountOf(chain).LT(gasFee) {
+			return
+		}
+		items = []sdk.TransferItem{{Amount: amount}}
+
+	default:
+		return
+	}
+
+	orderID := uuid.NewV5(uuid.NamespaceOID, fmt.Sprintf("opcu-rebalance-%s-%s-%d", from.GetAddress(), symbol, ctx.BlockHeight())).String()
+	if keeper.ok.IsExist(ctx, orderID) {
+		return
+	}
+	order := keeper.ok.NewOrderOpcuAssetTransfer(ctx, from.GetAddress(), orderID, symbol, items, toAddr)
+	if order == nil || order.ID == "" {
+		ctx.Logger().Error("fail to create opcu rebalance order", "opcu", from.GetAddress(), "symbol", symbol)
+		return
+	}
+	order.ToCUAddress = to.GetAddress()
+	order.FromEpoch = epochIndex
+	keeper.ok.SetOrder(ctx, order)
+
+	if tokenInfo.TokenType == sdk.UtxoBased {
+		for _, item := range items {
+			_ = keeper.ik.SetDepositStatus(ctx, symbol, from.GetAddress(), item.Hash, item.Index, sdk.DepositItemStatusInProcess)
+		}
+	} else if tokenInfo.IsNonceBased {
+		from.SetEnableSendTx(false, chain, fromAddr)
+		keeper.ik.SetCUIBCAsset(ctx, from)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeOpCURebalance,
+			sdk.NewAttribute(types.AttributeKeySender, from.GetAddress().String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, to.GetAddress().String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, symbol),
+			sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+			sdk.NewAttribute(types.AttributeKeyOrderID, orderID),
+		),
+	)
+}
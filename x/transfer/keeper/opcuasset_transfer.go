@@ -336,7 +336,7 @@ func (keeper BaseKeeper) OpcuAssetTransferWaitSign(ctx sdk.Context, orderID stri
 			return sdk.ErrInvalidTx(fmt.Sprintf("gas price is too low, actual:%v, lowlimit:%v", tx.GasPrice, priceLowLimit)).Result()
 		}
 
-		lastAsset := opCUAst.GetAsset(tokenInfo.Chain.String(), curEpoch.Index-1)
+		lastAsset := opCUAst.GetAsset(tokenInfo.Chain.String(), opcuTransferFromEpoch(order, curEpoch.Index))
 		if lastAsset == sdk.NilAsset {
 			return sdk.ErrInvalidTx("asset not found").Result()
 		}
@@ -394,7 +394,7 @@ func (keeper BaseKeeper) OpcuAssetTransferSignFinish(ctx sdk.Context, orderID st
 
 	opCUAst := keeper.ik.GetCUIBCAsset(ctx, order.GetCUAddress())
 
-	lastAsset := opCUAst.GetAsset(symbol, curEpoch.Index-1)
+	lastAsset := opCUAst.GetAsset(symbol, opcuTransferFromEpoch(order, curEpoch.Index))
 	if lastAsset == sdk.NilAsset {
 		return sdk.ErrInvalidTx("asset not found").Result()
 	}
@@ -458,10 +458,18 @@ func (keeper BaseKeeper) OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk
 
 	opCUAst := keeper.ik.GetCUIBCAsset(ctx, order.GetCUAddress())
 	curEpoch := keeper.sk.GetCurrentEpoch(ctx)
-	lastAsset := opCUAst.GetAsset(chain, curEpoch.Index-1)
+	lastAsset := opCUAst.GetAsset(chain, opcuTransferFromEpoch(order, curEpoch.Index))
 	if lastAsset == sdk.NilAsset {
 		return sdk.ErrInvalidTx("asset not found").Result()
 	}
+	// a migration returns the assets to the OPCU itself
+	toCUAst := opCUAst
+	if order.IsRebalance() {
+		toCUAst = keeper.ik.GetCUIBCAsset(ctx, order.ToCUAddress)
+		if toCUAst == nil {
+			return sdk.ErrInvalidAccount(order.ToCUAddress.String()).Result()
+		}
+	}
 
 	switch tokenInfo.TokenType {
 	case sdk.UtxoBased:
@@ -501,7 +509,7 @@ func (keeper BaseKeeper) OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk
 				if err != nil {
 					return sdk.ErrInvalidOrder(fmt.Sprintf("fail to create deposit item, %v %v %v", vin.Hash, vin.Index, vin.Amount)).Result()
 				}
-				_ = keeper.ik.SaveDeposit(ctx, symbol, opCUAst.GetAddress(), depositItem)
+				_ = keeper.ik.SaveDeposit(ctx, symbol, toCUAst.GetAddress(), depositItem)
 				outSum = outSum.Add(vout.Amount)
 			}
 		}
@@ -516,10 +524,10 @@ func (keeper BaseKeeper) OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk
 		}
 
 		fee := inSum.Sub(outSum)
-		opCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(chain, outSum)))
+		toCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(chain, outSum)))
 		opCUAst.AddGasUsed(sdk.NewCoins(sdk.NewCoin(chain, fee)))
 
-		if keeper.checkUtxoOpcuAstTransferFinish(ctx, lastAsset.Address, symbol, opCUAst.GetAddress()) {
+		if !order.IsRebalance() && keeper.checkUtxoOpcuAstTransferFinish(ctx, lastAsset.Address, symbol, opCUAst.GetAddress()) {
 			opCUAst.SetMigrationStatus(sdk.MigrationFinish)
 		}
 
@@ -535,13 +543,15 @@ func (keeper BaseKeeper) OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk
 			opCUAst.SubAssetCoins(feeCoins)
 		}
 		//update opcu's assetcoinshold, and refund unused gas fee if necessary
-		opCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, tx.Amount)))
+		toCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, tx.Amount)))
 		opCUAst.AddGasUsed(feeCoins)
 
-		if symbol != chain {
-			opCUAst.SetMigrationStatus(sdk.MigrationMainTokenFinish)
-		} else {
-			opCUAst.SetMigrationStatus(sdk.MigrationFinish)
+		if !order.IsRebalance() {
+			if symbol != chain {
+				opCUAst.SetMigrationStatus(sdk.MigrationMainTokenFinish)
+			} else {
+				opCUAst.SetMigrationStatus(sdk.MigrationFinish)
+			}
 		}
 
 		if tokenInfo.IsNonceBased {
@@ -558,8 +568,11 @@ func (keeper BaseKeeper) OpcuAssetTransferFinish(ctx sdk.Context, fromCUAddr sdk
 	order.CostFee = costFee
 	keeper.ok.SetOrder(ctx, order)
 	keeper.ik.SetCUIBCAsset(ctx, opCUAst)
-
-	keeper.checkOpcusMigrationStatus(ctx)
+	if order.IsRebalance() {
+		keeper.ik.SetCUIBCAsset(ctx, toCUAst)
+	} else {
+		keeper.checkOpcusMigrationStatus(ctx)
+	}
 
 	var flows []sdk.Flow
 	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), order.GetCUAddress(), orderID, sdk.OrderTypeOpcuAssetTransfer, sdk.OrderStatusFinish))
@@ -609,6 +622,16 @@ func (keeper BaseKeeper) checkOpcuTransferOrder(ctx sdk.Context, orderID string,
 	return
 }
 
+// opcuTransferFromEpoch returns the epoch of the address an opcu asset transfer is sent from,
+// a migration sweeps the address of the last epoch while a rebalancing moves the assets of the
+// epoch it's created in, which stays the same if a new epoch starts before it finishes
+func opcuTransferFromEpoch(order *sdk.OrderOpcuAssetTransfer, curEpochIndex uint64) uint64 {
+	if order.IsRebalance() {
+		return order.FromEpoch
+	}
+	return curEpochIndex - 1
+}
+
 func (keeper BaseKeeper) checkUtxoOpcuAstTransferFinish(ctx sdk.Context, lastAddr, symbol string, opcuAddr sdk.CUAddress) bool {
 	depositList := keeper.ik.GetDepositList(ctx, symbol, opcuAddr)
 	depositList = depositList.Filter(func(d sdk.DepositItem) bool {
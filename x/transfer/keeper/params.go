@@ -13,6 +13,18 @@ func (keeper BaseKeeper) IsSendEnabled(ctx sdk.Context) bool {
 	return enabled
 }
 
+// GetUtxoConsolidationParams returns the current utxo consolidation params, the default ones if they are not set
+func (keeper BaseKeeper) GetUtxoConsolidationParams(ctx sdk.Context) types.UtxoConsolidationParams {
+	params := types.DefaultUtxoConsolidationParams()
+	keeper.paramSpace.GetIfExists(ctx, types.ParamStoreKeyUtxoConsolidation, &params)
+	return params
+}
+
+// SetUtxoConsolidationParams sets the utxo consolidation params
+func (keeper BaseKeeper) SetUtxoConsolidationParams(ctx sdk.Context, params types.UtxoConsolidationParams) {
+	keeper.paramSpace.Set(ctx, types.ParamStoreKeyUtxoConsolidation, &params)
+}
+
 // SetSendEnabled sets the send enabled
 func (keeper BaseKeeper) SetSendEnabled(ctx sdk.Context, enabled bool) {
 	keeper.paramSpace.Set(ctx, types.ParamStoreKeySendEnabled, &enabled)
@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"fmt"
+	"sort"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 
@@ -18,6 +19,22 @@ func NewQuerier(k BaseKeeper) sdk.Querier {
 			return queryBalance(ctx, req, k)
 		case types.QueryAllBalance:
 			return queryAllBalance(ctx, req, k)
+		case types.QueryReserve:
+			return queryReserve(ctx, req, k)
+		case types.QueryDepositRoute:
+			return queryDepositRoute(ctx, req, k)
+		case types.QueryHTLC:
+			return queryHTLC(ctx, req, k)
+		case types.QueryScheduledTransfers:
+			return queryScheduledTransfers(ctx, req, k)
+		case types.QueryFrozenAddresses:
+			return queryFrozenAddresses(ctx, k)
+		case types.QueryAddressFrozen:
+			return queryAddressFrozen(ctx, req, k)
+		case types.QueryDepositDeficit:
+			return queryDepositDeficit(ctx, req, k)
+		case types.QueryWithdrawalFee:
+			return queryWithdrawalFee(ctx, req, k)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown bank query endpoint")
 		}
@@ -65,3 +82,159 @@ func queryAllBalance(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]by
 
 	return res, nil
 }
+
+// queryReserve produces the reserve report of an IBC token, with the liability proof of a CU if specified.
+func queryReserve(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryReserveParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	report, sdkErr := k.GetReserveReport(ctx, r.Symbol, r.Addr, r.CrossCheck)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, report)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+// queryDepositRoute returns the deposit routing table of a CU, sorted by memo, and its suspense balance.
+func queryDepositRoute(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryDepositRouteParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	routes := k.GetDepositRoutes(ctx, r.Addr)
+	memos := make([]string, 0, len(routes))
+	for memo := range routes {
+		memos = append(memos, memo)
+	}
+	sort.Strings(memos)
+
+	route := types.ResDepositRoute{Routes: []types.DepositRoute{}, Suspense: k.GetAllSuspenseBalance(ctx, r.Addr)}
+	for _, memo := range memos {
+		route.Routes = append(route.Routes, types.DepositRoute{Memo: memo, ToCU: routes[memo]})
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, route)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryHTLC(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryHTLCParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	htlc := k.GetHTLC(ctx, r.Sender, r.Hashlock)
+	if htlc == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("htlc %x of %v does not exist", r.Hashlock, r.Sender))
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, htlc)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryScheduledTransfers(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryScheduledTransfersParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	sts := k.GetScheduledTransfers(ctx, r.Addr)
+	if sts == nil {
+		sts = []types.ScheduledTransfer{}
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, sts)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryFrozenAddresses(ctx sdk.Context, k BaseKeeper) ([]byte, sdk.Error) {
+	addrs := k.GetFrozenAddresses(ctx)
+	if addrs == nil {
+		addrs = []sdk.CUAddress{}
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, addrs)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryDepositDeficit(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryDepositDeficitParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.ResDepositDeficit{
+		Address:          r.Addr,
+		WithdrawalFrozen: k.IsWithdrawalFrozen(ctx, r.Addr),
+		Deficits:         k.GetDepositDeficits(ctx, r.Addr),
+	})
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryAddressFrozen(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryAddressFrozenParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.ResAddressFrozen{Address: r.Addr, Frozen: k.IsAddressFrozen(ctx, r.Addr)})
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryWithdrawalFee(ctx sdk.Context, req abci.RequestQuery, k BaseKeeper) ([]byte, sdk.Error) {
+
+	var r types.QueryWithdrawalFeeParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &r); err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	fee, sdkErr := k.EstimateWithdrawalFee(ctx, r.Symbol, r.Amount, r.ToAddr)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, fee)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return res, nil
+}
@@ -0,0 +1,145 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	ibcexported "github.com/hbtc-chain/bhchain/x/ibcasset/exported"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// GetReserveReport reconciles the liabilities, i.e. balances of all CUs, of an IBC token against the reserves
+// custodied by OPCUs and user CUs. The liabilities are committed by a merkle sum tree, so that a CU can verify
+// its balance is counted with the proof. If crossCheck is set, the recorded reserves are compared with the
+// balances queried from chainnode of the answering node, which is not deterministic and only for queries.
+func (keeper BaseKeeper) GetReserveReport(ctx sdk.Context, symbol string, prove sdk.CUAddress, crossCheck bool) (types.ReserveReport, sdk.Error) {
+	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	if tokenInfo == nil {
+		return types.ReserveReport{}, sdk.ErrUnSupportToken(symbol)
+	}
+
+	liabilities := keeper.getLiabilities(ctx, symbol)
+	root, sum, proof := types.LiabilityRoot(liabilities, prove)
+	report := types.ReserveReport{
+		Symbol:         symbol,
+		Liabilities:    sum,
+		LiabilityRoot:  root,
+		LiabilityCount: len(liabilities),
+		LiabilityProof: proof,
+		OpCUReserves:   sdk.ZeroInt(),
+		UserCUReserves: sdk.ZeroInt(),
+	}
+
+	var cuAsts []ibcexported.CUIBCAsset
+	keeper.ik.IterateCUAssets(ctx, func(cuAst ibcexported.CUIBCAsset) bool {
+		recorded := cuAst.GetAssetCoins().AmountOf(symbol).Add(cuAst.GetAssetCoinsHold().AmountOf(symbol))
+		if recorded.IsZero() {
+			return false
+		}
+		if cuAst.GetCUType() == sdk.CUTypeOp {
+			report.OpCUReserves = report.OpCUReserves.Add(recorded)
+		} else {
+			report.UserCUReserves = report.UserCUReserves.Add(recorded)
+		}
+		cuAsts = append(cuAsts, cuAst)
+		return false
+	})
+	report.Reserves = report.OpCUReserves.Add(report.UserCUReserves)
+	report.Surplus = report.Reserves.Sub(report.Liabilities)
+
+	if crossCheck {
+		for _, cuAst := range cuAsts {
+			report.ExternalBalances = append(report.ExternalBalances, keeper.queryExternalBalance(tokenInfo, cuAst))
+		}
+	}
+
+	return report, nil
+}
+
+// getLiabilities returns the balances, including the locked and suspense part, of all CUs holding the symbol, sorted by address
+func (keeper BaseKeeper) getLiabilities(ctx sdk.Context, symbol string) []types.Liability {
+	amounts := make(map[string]sdk.Int)
+	store := ctx.KVStore(keeper.storeKey)
+
+	iter := sdk.KVStorePrefixIterator(store, types.BalanceKeyPrefix(nil))
+	for ; iter.Valid(); iter.Next() {
+		if types.GetSymbolFromBalanceKey(iter.Key()) != symbol {
+			continue
+		}
+		var balance sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &balance)
+		addr := string(types.GetAddressFromBalanceKey(iter.Key()))
+		amounts[addr] = balance
+	}
+	iter.Close()
+
+	iter = sdk.KVStorePrefixIterator(store, types.HoldBalanceKeyPrefix(nil))
+	for ; iter.Valid(); iter.Next() {
+		if types.GetSymbolFromHoldBalanceKey(iter.Key()) != symbol {
+			continue
+		}
+		var balance sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &balance)
+		addr := string(types.GetAddressFromHoldBalanceKey(iter.Key()))
+		if amt, ok := amounts[addr]; ok {
+			balance = balance.Add(amt)
+		}
+		amounts[addr] = balance
+	}
+	iter.Close()
+
+	// suspense balance is owed to the owner of the deposit address until reassigned
+	iter = sdk.KVStorePrefixIterator(store, types.SuspenseBalanceKeyPrefix(nil))
+	for ; iter.Valid(); iter.Next() {
+		if types.GetSymbolFromSuspenseBalanceKey(iter.Key()) != symbol {
+			continue
+		}
+		var balance sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &balance)
+		addr := string(types.GetAddressFromSuspenseBalanceKey(iter.Key()))
+		if amt, ok := amounts[addr]; ok {
+			balance = balance.Add(amt)
+		}
+		amounts[addr] = balance
+	}
+	iter.Close()
+
+	liabilities := make([]types.Liability, 0, len(amounts))
+	for addr, amt := range amounts {
+		liabilities = append(liabilities, types.Liability{Address: sdk.CUAddress(addr), Amount: amt})
+	}
+	sort.Slice(liabilities, func(i, j int) bool {
+		return bytes.Compare(liabilities[i].Address, liabilities[j].Address) < 0
+	})
+	return liabilities
+}
+
+func (keeper BaseKeeper) queryExternalBalance(tokenInfo *sdk.IBCToken, cuAst ibcexported.CUIBCAsset) types.ExternalBalance {
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	contractAddress := ""
+	if symbol != chain {
+		contractAddress = tokenInfo.Issuer
+	}
+
+	eb := types.ExternalBalance{
+		CUAddress: cuAst.GetAddress(),
+		Recorded:  cuAst.GetAssetCoins().AmountOf(symbol).Add(cuAst.GetAssetCoinsHold().AmountOf(symbol)),
+		OnChain:   sdk.ZeroInt(),
+	}
+	for _, asset := range cuAst.GetAssets() {
+		if asset.Denom != symbol || asset.Address == "" {
+			continue
+		}
+		eb.Addresses = append(eb.Addresses, asset.Address)
+		balance, err := keeper.cn.QueryBalance(chain, symbol, asset.Address, contractAddress, 0)
+		if err != nil {
+			eb.Error = fmt.Sprintf("fail to query balance of %v: %v", asset.Address, err)
+			continue
+		}
+		eb.OnChain = eb.OnChain.Add(balance)
+	}
+	return eb
+}
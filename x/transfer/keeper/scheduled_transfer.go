@@ -0,0 +1,308 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// ScheduleTransfer holds amount of fromCUAddr for each of the times executions, which pay amount to toCUAddr
+// at start and then every interval. start and interval are in unix seconds of the block time if byTime, otherwise in block heights.
+func (keeper BaseKeeper) ScheduleTransfer(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, amount sdk.Coins, byTime bool, start, interval, times uint64) sdk.Result {
+	if times == 0 || times > types.MaxScheduledTransferTimes {
+		return sdk.ErrInvalidTx(fmt.Sprintf("times %v out of range [1, %v]", times, types.MaxScheduledTransferTimes)).Result()
+	}
+	if times > 1 && (interval == 0 || interval > (math.MaxUint64-start)/(times-1)) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid interval %v", interval)).Result()
+	}
+	if now := scheduleNow(ctx, byTime); start <= now {
+		return sdk.ErrInvalidTx(fmt.Sprintf("start %v is not after %v", start, now)).Result()
+	}
+	if err := keeper.checkNotFrozen(ctx, fromCUAddr); err != nil {
+		return err.Result()
+	}
+	if fromCUAddr.Equals(toCUAddr) {
+		return sdk.ErrInvalidTx("scheduled transfer to the sender itself").Result()
+	}
+	if toCU := keeper.ck.GetCU(ctx, toCUAddr); toCU != nil && toCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("scheduled transfer to a non user CU :%v", toCUAddr)).Result()
+	}
+
+	lockFlows, err := keeper.LockCoins(ctx, fromCUAddr, types.MulCoins(amount, times))
+	if err != nil {
+		return err.Result()
+	}
+
+	st := types.ScheduledTransfer{
+		ID:        keeper.nextScheduledTransferID(ctx),
+		From:      fromCUAddr,
+		To:        toCUAddr,
+		Amount:    amount,
+		ByTime:    byTime,
+		Next:      start,
+		Interval:  interval,
+		Remaining: times,
+	}
+	keeper.setScheduledTransfer(ctx, st)
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID), []byte{})
+	store.Set(types.ScheduledTransferOwnerKey(st.From, st.ID), []byte{})
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeScheduleTransfer,
+			sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprintf("%d", st.ID)),
+			sdk.NewAttribute(types.AttributeKeySender, fromCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, toCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+		),
+	)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewScheduledTransferCreatedFlow(st.ID, fromCUAddr.String(), toCUAddr.String(), amount, byTime, start, interval, times))
+	flows = append(flows, lockFlows...)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeScheduledTransfer, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// CancelScheduledTransfer cancels the remaining executions of a scheduled transfer of fromCUAddr, and unlocks their amount
+func (keeper BaseKeeper) CancelScheduledTransfer(ctx sdk.Context, fromCUAddr sdk.CUAddress, id uint64) sdk.Result {
+	st := keeper.GetScheduledTransfer(ctx, id)
+	if st == nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("scheduled transfer %v does not exist", id)).Result()
+	}
+	if !st.From.Equals(fromCUAddr) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("scheduled transfer %v does not belong to %v", id, fromCUAddr)).Result()
+	}
+
+	flows, err := keeper.cancelScheduledTransfer(ctx, *st)
+	if err != nil {
+		return err.Result()
+	}
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeScheduledTransfer, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+func (keeper BaseKeeper) cancelScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer) ([]sdk.Flow, sdk.Error) {
+	refund := st.Reserved()
+	unlockFlows, err := keeper.UnlockCoins(ctx, st.From, refund)
+	if err != nil {
+		return nil, err
+	}
+	keeper.deleteScheduledTransfer(ctx, st)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCancelScheduledTransfer,
+			sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprintf("%d", st.ID)),
+			sdk.NewAttribute(types.AttributeKeySender, st.From.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, refund.String()),
+		),
+	)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewScheduledTransferCancelledFlow(st.ID, st.From.String(), refund))
+	flows = append(flows, unlockFlows...)
+	return flows, nil
+}
+
+// ExecuteScheduledTransfers executes the scheduled transfers due at the current block, called in EndBlocker.
+// The due ones of frozen senders are parked out of the queue until the senders are unfrozen, they don't count
+// towards MaxScheduledTransfersPerBlock.
+func (keeper BaseKeeper) ExecuteScheduledTransfers(ctx sdk.Context) {
+	if !keeper.IsSendEnabled(ctx) {
+		return
+	}
+
+	var due []uint64
+	var parked []types.ScheduledTransfer
+	store := ctx.KVStore(keeper.storeKey)
+	for _, byTime := range []bool{false, true} {
+		iter := store.Iterator(types.ScheduledTransferQueueKindPrefix(byTime), types.ScheduledTransferQueuePrefix(byTime, scheduleNow(ctx, byTime)+1))
+		for ; iter.Valid() && len(due) < types.MaxScheduledTransfersPerBlock; iter.Next() {
+			id := types.GetIDFromScheduledTransferQueueKey(iter.Key())
+			if st := keeper.GetScheduledTransfer(ctx, id); st != nil && keeper.IsAddressFrozen(ctx, st.From) {
+				parked = append(parked, *st)
+				continue
+			}
+			due = append(due, id)
+		}
+		iter.Close()
+	}
+
+	for _, st := range parked {
+		keeper.parkScheduledTransfer(ctx, st)
+	}
+
+	for _, id := range due {
+		st := keeper.GetScheduledTransfer(ctx, id)
+		if st == nil {
+			continue
+		}
+		cacheCtx, write := ctx.CacheContext()
+		flows, err := keeper.executeScheduledTransfer(cacheCtx, *st)
+		if err != nil {
+			ctx.Logger().Error("fail to execute scheduled transfer, cancel it", "id", id, "err", err)
+			cacheCtx, write = ctx.CacheContext()
+			flows, err = keeper.cancelScheduledTransfer(cacheCtx, *st)
+			if err != nil {
+				// keep the schedule and its reserved amount, retry it in the next block
+				ctx.Logger().Error("fail to cancel scheduled transfer", "id", id, "err", err)
+				keeper.requeueScheduledTransfer(ctx, *st, scheduleNow(ctx, st.ByTime)+1)
+				continue
+			}
+		}
+		write()
+		ctx.EventManager().EmitEvents(cacheCtx.EventManager().Events())
+		receipt := keeper.rk.NewReceipt(sdk.CategoryTypeScheduledTransfer, flows)
+		keeper.rk.SaveReceiptToEvents(receipt, ctx.EventManager())
+	}
+}
+
+func (keeper BaseKeeper) executeScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer) ([]sdk.Flow, sdk.Error) {
+	_, subFlows, err := keeper.SubCoinsHold(ctx, st.From, st.Amount)
+	if err != nil {
+		return nil, err
+	}
+	_, addFlows, err := keeper.AddCoins(ctx, st.To, st.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Remaining--
+	if st.Remaining > 0 {
+		keeper.requeueScheduledTransfer(ctx, st, st.Next+st.Interval)
+	} else {
+		keeper.deleteScheduledTransfer(ctx, st)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeExecuteScheduledTransfer,
+			sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprintf("%d", st.ID)),
+			sdk.NewAttribute(types.AttributeKeySender, st.From.String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, st.To.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, st.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeyRemaining, fmt.Sprintf("%d", st.Remaining)),
+		),
+	)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewScheduledTransferExecutedFlow(st.ID, st.From.String(), st.To.String(), st.Amount, st.Remaining))
+	flows = append(flows, subFlows...)
+	flows = append(flows, addFlows...)
+	return flows, nil
+}
+
+// GetScheduledTransfer returns the scheduled transfer with remaining executions, nil if it does not exist
+func (keeper BaseKeeper) GetScheduledTransfer(ctx sdk.Context, id uint64) *types.ScheduledTransfer {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.ScheduledTransferKey(id))
+	if len(bz) == 0 {
+		return nil
+	}
+	var st types.ScheduledTransfer
+	keeper.cdc.MustUnmarshalBinaryBare(bz, &st)
+	return &st
+}
+
+// GetScheduledTransfers returns the scheduled transfers of owner, ordered by id
+func (keeper BaseKeeper) GetScheduledTransfers(ctx sdk.Context, owner sdk.CUAddress) []types.ScheduledTransfer {
+	var sts []types.ScheduledTransfer
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ScheduledTransferOwnerKeyPrefix(owner))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if st := keeper.GetScheduledTransfer(ctx, types.GetIDFromScheduledTransferOwnerKey(iter.Key())); st != nil {
+			sts = append(sts, *st)
+		}
+	}
+	return sts
+}
+
+func (keeper BaseKeeper) setScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.ScheduledTransferKey(st.ID), keeper.cdc.MustMarshalBinaryBare(st))
+}
+
+func (keeper BaseKeeper) deleteScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(types.ScheduledTransferKey(st.ID))
+	store.Delete(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID))
+	store.Delete(types.ScheduledTransferOwnerKey(st.From, st.ID))
+	store.Delete(types.ScheduledTransferParkedKey(st.From, st.ID))
+}
+
+// parkScheduledTransfer moves st of a frozen sender out of the queue, its Next is kept for resuming on its cadence
+func (keeper BaseKeeper) parkScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID))
+	store.Set(types.ScheduledTransferParkedKey(st.From, st.ID), []byte{})
+}
+
+// resumeScheduledTransfers puts the parked scheduled transfers of owner back to the queue, at the first execution
+// on their cadence after now. The remaining executions are kept.
+func (keeper BaseKeeper) resumeScheduledTransfers(ctx sdk.Context, owner sdk.CUAddress) {
+	var ids []uint64
+	store := ctx.KVStore(keeper.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ScheduledTransferParkedKeyPrefix(owner))
+	for ; iter.Valid(); iter.Next() {
+		ids = append(ids, types.GetIDFromScheduledTransferParkedKey(iter.Key()))
+	}
+	iter.Close()
+
+	for _, id := range ids {
+		store.Delete(types.ScheduledTransferParkedKey(owner, id))
+		st := keeper.GetScheduledTransfer(ctx, id)
+		if st == nil {
+			continue
+		}
+		next, now := st.Next, scheduleNow(ctx, st.ByTime)
+		if next <= now {
+			if st.Interval == 0 {
+				next = now + 1
+			} else {
+				next += ((now-next)/st.Interval + 1) * st.Interval
+			}
+		}
+		st.Next = next
+		keeper.setScheduledTransfer(ctx, *st)
+		store.Set(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID), []byte{})
+	}
+}
+
+// requeueScheduledTransfer saves st and moves it in the queue to next
+func (keeper BaseKeeper) requeueScheduledTransfer(ctx sdk.Context, st types.ScheduledTransfer, next uint64) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID))
+	st.Next = next
+	keeper.setScheduledTransfer(ctx, st)
+	store.Set(types.ScheduledTransferQueueKey(st.ByTime, st.Next, st.ID), []byte{})
+}
+
+func (keeper BaseKeeper) nextScheduledTransferID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(keeper.storeKey)
+	var id uint64 = 1
+	if bz := store.Get(types.ScheduledTransferIDKey); len(bz) != 0 {
+		id = binary.BigEndian.Uint64(bz)
+	}
+	store.Set(types.ScheduledTransferIDKey, sdk.Uint64ToBigEndian(id+1))
+	return id
+}
+
+// scheduleNow returns the current block time in unix seconds if byTime, otherwise the current block height
+func scheduleNow(ctx sdk.Context, byTime bool) uint64 {
+	if byTime {
+		return uint64(ctx.BlockHeader().Time.Unix())
+	}
+	return uint64(ctx.BlockHeight())
+}
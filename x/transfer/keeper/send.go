@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit/exported"
 	"github.com/hbtc-chain/bhchain/x/transfer/types"
 )
 
@@ -28,6 +29,7 @@ type SendKeeper interface {
 	GetAllBalance(ctx sdk.Context, addr sdk.CUAddress) sdk.Coins
 
 	GetHoldBalance(ctx sdk.Context, addr sdk.CUAddress, symbol string) sdk.Int
+	GetVestingLockedCoins(ctx sdk.Context, addr sdk.CUAddress) sdk.Coins
 	GetAllHoldBalance(ctx sdk.Context, addr sdk.CUAddress) sdk.Coins
 
 	Codespace() sdk.CodespaceType
@@ -47,6 +49,7 @@ func (keeper BaseKeeper) InputOutputCoins(ctx sdk.Context, inputs []types.Input,
 	if err := types.ValidateInputsOutputs(inputs, outputs); err != nil {
 		return err.Result(), err
 	}
+
 	var flows []sdk.Flow
 	for _, in := range inputs {
 		_, inFlows, err := keeper.SubCoins(ctx, in.Address, in.Coins)
@@ -175,14 +178,16 @@ func (keeper BaseKeeper) AddCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.C
 		return coin, sdk.BalanceFlow{}, sdk.ErrInvalidCoins(fmt.Sprintf("invalid coin %s", coin.String()))
 	}
 
+	// the spent part of the deficit left by invalidated deposits is paid back first
+	amount := keeper.netDepositDeficit(ctx, addr, coin)
 	before := keeper.GetBalance(ctx, addr, coin.Denom)
-	after := before.Add(coin.Amount)
+	after := before.Add(amount)
 	keeper.setBalance(ctx, addr, coin.Denom, after)
 	return sdk.NewCoin(coin.Denom, after), sdk.BalanceFlow{
 		CUAddress:             addr,
 		Symbol:                sdk.Symbol(coin.Denom),
 		PreviousBalance:       before,
-		BalanceChange:         coin.Amount,
+		BalanceChange:         amount,
 		PreviousBalanceOnHold: sdk.ZeroInt(),
 		BalanceOnHoldChange:   sdk.ZeroInt(),
 	}, nil
@@ -208,7 +213,17 @@ func (keeper BaseKeeper) AddCoins(ctx sdk.Context, addr sdk.CUAddress, coins sdk
 	return newCoins, flows, nil
 }
 
+// SubCoin debits coin from the balance of addr, it fails if addr is frozen
 func (keeper BaseKeeper) SubCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) (sdk.Coin, sdk.Flow, sdk.Error) {
+	if err := keeper.checkNotFrozen(ctx, addr); err != nil {
+		return coin, sdk.BalanceFlow{}, err
+	}
+	return keeper.subCoin(ctx, addr, coin)
+}
+
+// subCoin debits coin from the balance of addr even if it's frozen, it's for the debits made by the chain itself,
+// e.g. the fees of deposits and the reverse of invalidated deposits
+func (keeper BaseKeeper) subCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) (sdk.Coin, sdk.Flow, sdk.Error) {
 	if !coin.IsValid() {
 		return coin, sdk.BalanceFlow{}, sdk.ErrInvalidCoins(fmt.Sprintf("invalid coin %s", coin.String()))
 	}
@@ -218,6 +233,9 @@ func (keeper BaseKeeper) SubCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.C
 		return coin, sdk.BalanceFlow{}, sdk.ErrInsufficientCoins(fmt.Sprintf("token %s balance not enough, has %s, need %s", coin.Denom, before.String(), coin.Amount.String()))
 	}
 	after := before.Sub(coin.Amount)
+	if locked := keeper.GetVestingLockedCoins(ctx, addr).AmountOf(coin.Denom); after.LT(locked) {
+		return coin, sdk.BalanceFlow{}, sdk.ErrInsufficientCoins(fmt.Sprintf("token %s balance not enough, has %s with %s locked by vesting, need %s", coin.Denom, before.String(), locked.String(), coin.Amount.String()))
+	}
 	keeper.setBalance(ctx, addr, coin.Denom, after)
 	return sdk.NewCoin(coin.Denom, after), sdk.BalanceFlow{
 		CUAddress:             addr,
@@ -229,6 +247,15 @@ func (keeper BaseKeeper) SubCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.C
 	}, nil
 }
 
+// GetVestingLockedCoins returns the coins of a vesting CU which are still vesting and not delegated, they can not be spent
+func (keeper BaseKeeper) GetVestingLockedCoins(ctx sdk.Context, addr sdk.CUAddress) sdk.Coins {
+	vcu, ok := keeper.ck.GetCU(ctx, addr).(exported.VestingCU)
+	if !ok {
+		return nil
+	}
+	return vcu.LockedCoins(ctx.BlockHeader().Time)
+}
+
 func (keeper BaseKeeper) SubCoins(ctx sdk.Context, addr sdk.CUAddress, coins sdk.Coins) (sdk.Coins, []sdk.Flow, sdk.Error) {
 	if !coins.IsValid() {
 		return coins, nil, sdk.ErrInvalidCoins(fmt.Sprintf("invalid coins %s", coins.String()))
@@ -329,9 +356,17 @@ func (keeper BaseKeeper) SubCoinsHold(ctx sdk.Context, addr sdk.CUAddress, coins
 }
 
 func (keeper BaseKeeper) LockCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) ([]sdk.Flow, sdk.Error) {
+	if err := keeper.checkNotFrozen(ctx, addr); err != nil {
+		return nil, err
+	}
+	return keeper.lockCoin(ctx, addr, coin)
+}
+
+// lockCoin moves coin of addr into hold even if addr is frozen
+func (keeper BaseKeeper) lockCoin(ctx sdk.Context, addr sdk.CUAddress, coin sdk.Coin) ([]sdk.Flow, sdk.Error) {
 	var flows []sdk.Flow
 
-	_, flow, err := keeper.SubCoin(ctx, addr, coin)
+	_, flow, err := keeper.subCoin(ctx, addr, coin)
 	if err != nil {
 		return nil, err
 	}
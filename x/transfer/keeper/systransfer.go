@@ -100,7 +100,7 @@ func (keeper BaseKeeper) SysTransfer(ctx sdk.Context, fromCUAddr, toCUAddr sdk.C
 				return err.Result()
 			}
 			balanceFlows = append(balanceFlows, flow)
-			_, flow, err = keeper.SubCoin(ctx, toCUAddr, tokenInfo.CollectFee())
+			_, flow, err = keeper.subCoin(ctx, toCUAddr, tokenInfo.CollectFee())
 			if err != nil {
 				return err.Result()
 			}
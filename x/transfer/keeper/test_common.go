@@ -25,3 +25,15 @@ func (keeper BaseKeeper) CheckWithdrawalOpCU(ctx sdk.Context, opCU exported.CUIB
 func (keeper BaseKeeper) CheckSysTransferOrders(ctx sdk.Context, order *sdk.OrderSysTransfer, orderStatus sdk.OrderStatus) (*sdk.IBCToken, sdk.Error) {
 	return keeper.checkSysTransferOrder(ctx, order, orderStatus)
 }
+
+func (keeper BaseKeeper) CheckOpCUSignThreshold(ctx sdk.Context, opCU exported.CUIBCAsset, tokenInfo *sdk.IBCToken) sdk.Error {
+	return keeper.checkOpCUSignThreshold(ctx, opCU, tokenInfo)
+}
+
+func (keeper BaseKeeper) CheckWithdrawalOpCUTier(opCUAddr sdk.CUAddress, tokenInfo *sdk.IBCToken, orders []*sdk.OrderWithdrawal) sdk.Error {
+	return keeper.checkWithdrawalOpCUTier(opCUAddr, tokenInfo, orders)
+}
+
+func (keeper BaseKeeper) CheckBatchWithdrawalOrders(ctx sdk.Context, orders []*sdk.OrderWithdrawal) sdk.Error {
+	return keeper.checkBatchWithdrawalOrders(ctx, orders)
+}
@@ -192,7 +192,7 @@ func (keeper BaseKeeper) checkNeedSysTransfer(ctx sdk.Context, chain, toAddr str
 }
 
 func (keeper BaseKeeper) hasUnfinishedOrder(ctx sdk.Context, opcu sdk.CUAddress) bool {
-	for _, orderID := range keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeCollect, sdk.OrderTypeWithdrawal, sdk.OrderTypeSysTransfer) {
+	for _, orderID := range keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeCollect, sdk.OrderTypeWithdrawal, sdk.OrderTypeSysTransfer, sdk.OrderTypeUtxoConsolidation, sdk.OrderTypeOpcuAssetTransfer) {
 		order := keeper.ok.GetOrder(ctx, orderID)
 		if order == nil {
 			continue
@@ -210,6 +210,14 @@ func (keeper BaseKeeper) hasUnfinishedOrder(ctx sdk.Context, opcu sdk.CUAddress)
 			if orderDetail.OpCUaddress == opcu.String() || orderDetail.ToCU == opcu.String() {
 				return true
 			}
+		case *sdk.OrderUtxoConsolidation:
+			if orderDetail.CUAddress.Equals(opcu) {
+				return true
+			}
+		case *sdk.OrderOpcuAssetTransfer:
+			if orderDetail.IsRebalance() && (orderDetail.CUAddress.Equals(opcu) || orderDetail.ToCUAddress.Equals(opcu)) {
+				return true
+			}
 		}
 	}
 	return false
@@ -282,7 +290,11 @@ func (keeper BaseKeeper) hasEvidenceHandled(ctx sdk.Context, txID string, retryT
 	return store.Has(types.GetOrderRetryEvidenceHandledKey(txID, retryTimes))
 }
 
-func (k BaseKeeper) getExcludedKeyNode(ctx sdk.Context, keyNodes []sdk.CUAddress) sdk.CUAddress {
+// getExcludedKeyNode returns the key node without heartbeat for the longest time, unless the others would not reach threshold
+func (k BaseKeeper) getExcludedKeyNode(ctx sdk.Context, keyNodes []sdk.CUAddress, threshold int) sdk.CUAddress {
+	if len(keyNodes) <= threshold {
+		return nil
+	}
 	var excluded sdk.CUAddress
 	var longest int64
 	blkHeight := ctx.BlockHeight()
@@ -309,6 +321,8 @@ func getOrderRawData(order sdk.Order) []byte {
 		return underlyingOrder.RawData
 	case *sdk.OrderOpcuAssetTransfer:
 		return underlyingOrder.RawData
+	case *sdk.OrderUtxoConsolidation:
+		return underlyingOrder.RawData
 	}
 	return nil
 }
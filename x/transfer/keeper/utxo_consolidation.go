@@ -0,0 +1,447 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// ScheduleUtxoConsolidation checks utxos of OPCUs every UtxoConsolidationInterval blocks, and schedules a self transfer
+// to merge the smallest utxos of an OPCU if it holds too many utxos or too many dust utxos, and the gas price is
+// low compared with its moving average.
+func (keeper BaseKeeper) ScheduleUtxoConsolidation(ctx sdk.Context) {
+	if ctx.BlockHeight()%types.UtxoConsolidationInterval != 0 || !keeper.IsSendEnabled(ctx) {
+		return
+	}
+
+	params := keeper.GetUtxoConsolidationParams(ctx)
+	curEpoch := keeper.sk.GetCurrentEpoch(ctx)
+	lowGasPrice := make(map[string]bool)
+	for _, opCU := range keeper.ck.GetOpCUs(ctx, "") {
+		tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(opCU.GetSymbol()))
+		if tokenInfo == nil || tokenInfo.TokenType != sdk.UtxoBased {
+			continue
+		}
+
+		chain := tokenInfo.Chain.String()
+		low, updated := lowGasPrice[chain]
+		if !updated {
+			low = keeper.updateGasPriceAverage(ctx, chain, tokenInfo.GasPrice, params.GasPriceRatio)
+			lowGasPrice[chain] = low
+		}
+
+		if !low || !curEpoch.MigrationFinished {
+			continue
+		}
+		keeper.scheduleUtxoConsolidation(ctx, opCU.GetAddress(), tokenInfo, curEpoch.Index, params)
+	}
+}
+
+func (keeper BaseKeeper) scheduleUtxoConsolidation(ctx sdk.Context, opCUAddr sdk.CUAddress, tokenInfo *sdk.IBCToken, epochIndex uint64, params types.UtxoConsolidationParams) {
+	if keeper.hasUnfinishedOrder(ctx, opCUAddr) {
+		return
+	}
+
+	opCUAst := keeper.ik.GetCUIBCAsset(ctx, opCUAddr)
+	if opCUAst == nil {
+		return
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	addr := opCUAst.GetAssetAddress(chain, epochIndex)
+	if addr == "" || !opCUAst.IsEnabledSendTx(chain, addr) {
+		return
+	}
+
+	depositList := keeper.ik.GetDepositList(ctx, symbol, opCUAddr)
+	depositList = depositList.Filter(func(d sdk.DepositItem) bool {
+		return d.ExtAddress == addr && d.Status == sdk.DepositItemStatusConfirmed
+	})
+	if len(depositList) < sdk.MaxVinNum {
+		return
+	}
+
+	dustThreshold := keeper.utxoOpcuAstTransferThreshold(1, tokenInfo).MulRaw(types.UtxoDustFeeMultiple)
+	dustNum := len(depositList.Filter(func(d sdk.DepositItem) bool {
+		return d.Amount.LTE(dustThreshold)
+	}))
+	dustRatio := sdk.NewDec(int64(dustNum)).QuoInt64(int64(len(depositList)))
+	if uint64(len(depositList)) < params.NumThreshold && dustRatio.LT(params.DustRatio) {
+		return
+	}
+
+	depositList.SortByAmount()
+	items := make([]sdk.TransferItem, 0, sdk.MaxVinNum)
+	sum := sdk.ZeroInt()
+	for _, d := range depositList[:sdk.MaxVinNum] {
+		items = append(items, sdk.TransferItem{Hash: d.Hash, Index: d.Index, Amount: d.Amount})
+		sum = sum.Add(d.Amount)
+	}
+	// the utxos are not worth merging if they can not afford the fee
+	if sum.LTE(keeper.utxoOpcuAstTransferThreshold(len(items), tokenInfo)) {
+		return
+	}
+
+	orderID := uuid.NewV5(uuid.NamespaceOID, fmt.Sprintf("utxo-consolidation-%s-%s-%d", opCUAddr, symbol, ctx.BlockHeight())).String()
+	if keeper.ok.IsExist(ctx, orderID) {
+		return
+	}
+	order := keeper.ok.NewOrderUtxoConsolidation(ctx, opCUAddr, orderID, symbol, items, addr)
+	if order == nil || order.ID == "" {
+		ctx.Logger().Error("fail to create utxo consolidation order", "opcu", opCUAddr, "symbol", symbol)
+		return
+	}
+	keeper.ok.SetOrder(ctx, order)
+
+	for _, item := range items {
+		_ = keeper.ik.SetDepositStatus(ctx, symbol, opCUAddr, item.Hash, item.Index, sdk.DepositItemStatusInProcess)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeUtxoConsolidation,
+			sdk.NewAttribute(types.AttributeKeySender, opCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, symbol),
+			sdk.NewAttribute(types.AttributeKeyOrderID, orderID),
+		),
+	)
+}
+
+func (keeper BaseKeeper) UtxoConsolidationWaitSign(ctx sdk.Context, orderID string, signHashes [][]byte, rawData []byte) sdk.Result {
+	tokenInfo, order, err := keeper.checkUtxoConsolidationOrder(ctx, orderID, sdk.OrderStatusBegin)
+	if err != nil {
+		return err.Result()
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	opCUAst := keeper.ik.GetCUIBCAsset(ctx, order.GetCUAddress())
+
+	vins, e := keeper.cn.QueryUtxoInsFromData(chain, symbol, rawData)
+	if e != nil {
+		return sdk.ErrInvalidTx(e.Error()).Result()
+	}
+	if len(vins) != len(order.ConsolidateItems) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("utxo consolidation vins(%d) not match", len(vins))).Result()
+	}
+
+	amt := sdk.ZeroInt()
+	for _, vin := range vins {
+		item := keeper.ik.GetDeposit(ctx, symbol, opCUAst.GetAddress(), vin.Hash, vin.Index)
+		if item == sdk.DepositNil {
+			return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v does not exist", vin.Hash, vin.Index)).Result()
+		}
+		if item.GetStatus() != sdk.DepositItemStatusInProcess {
+			return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v status is %v", vin.Hash, vin.Index, item.GetStatus())).Result()
+		}
+
+		found := false
+		for _, orderItem := range order.ConsolidateItems {
+			if vin.Hash == orderItem.Hash && vin.Index == orderItem.Index && item.Amount.Equal(orderItem.Amount) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return sdk.ErrInvalidTx(fmt.Sprintf("utxo consolidation vin(%v) not found in order", vin.Hash)).Result()
+		}
+
+		vin.Address = item.ExtAddress
+		vin.Amount = item.Amount
+		amt = amt.Add(vin.Amount)
+	}
+
+	tx, hashes, e := keeper.cn.QueryUtxoTransactionFromData(chain, symbol, rawData, vins)
+	if e != nil {
+		return sdk.ErrInvalidTx(e.Error()).Result()
+	}
+
+	//Estimate SignedTx Size and calculate price
+	size := sdk.EstimateSignedUtxoTxSize(len(tx.Vins), len(tx.Vouts)).ToDec()
+	price := sdk.NewDecFromInt(tx.CostFee).MulInt64(sdk.KiloBytes).Quo(size)
+	priceUpLimit := sdk.NewDecFromInt(tokenInfo.GasPrice).Mul(PriceUpLimitRatio)
+	priceLowLimit := sdk.NewDecFromInt(tokenInfo.GasPrice).Mul(PriceLowLimitRatio)
+	if price.GT(priceUpLimit) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("gas price is too high, actual:%v, uplimit:%v", price, priceUpLimit)).Result()
+	}
+	if price.LT(priceLowLimit) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("gas price is too low, actual:%v, lowlimit:%v", price, priceLowLimit)).Result()
+	}
+
+	// check hashes
+	if len(hashes) != len(signHashes) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("signhashes's number mismatch, expected:%v, have:%v", len(hashes), len(signHashes))).Result()
+	}
+	for i := 0; i < len(hashes); i++ {
+		if !bytes.Equal(hashes[i], signHashes[i]) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("mismatch hashes, expected:%v, have:%v", hashes[i], signHashes[i])).Result()
+		}
+	}
+
+	if len(tx.Vouts) != 1 {
+		return sdk.ErrInvalidTx("vout number should be 1").Result()
+	}
+	if tx.Vouts[0].Address != order.Address {
+		return sdk.ErrInvalidTx(fmt.Sprintf("mismatch vout Addr, expected:%v, got:%v", order.Address, tx.Vouts[0].Address)).Result()
+	}
+
+	order.Status = sdk.OrderStatusWaitSign
+	order.StageHeight = uint64(ctx.BlockHeight())
+	order.RawData = make([]byte, len(rawData))
+	copy(order.RawData, rawData)
+	keeper.ok.SetOrder(ctx, order)
+
+	opCUAst.SubAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	keeper.ik.SetCUIBCAsset(ctx, opCUAst)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), order.GetCUAddress(), orderID, sdk.OrderTypeUtxoConsolidation, sdk.OrderStatusWaitSign))
+	flows = append(flows, keeper.rk.NewUtxoConsolidationWaitSignFlow(orderID, rawData))
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeUtxoConsolidation, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+func (keeper BaseKeeper) UtxoConsolidationSignFinish(ctx sdk.Context, orderID string, signedTx []byte) sdk.Result {
+	tokenInfo, order, err := keeper.checkUtxoConsolidationOrder(ctx, orderID, sdk.OrderStatusWaitSign)
+	if err != nil {
+		return err.Result()
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+
+	result, txHash := keeper.verifyUtxoBasedSignedTx(ctx, nil, order.GetCUAddress(), chain, symbol, order.RawData, signedTx)
+	if result.Code != sdk.CodeOK {
+		return sdk.ErrInvalidTx(fmt.Sprintf("Fail to verify signed transaction:%v, err:%v", signedTx, result.Log)).Result()
+	}
+
+	order.Status = sdk.OrderStatusSignFinish
+	order.StageHeight = uint64(ctx.BlockHeight())
+	order.SignedTx = make([]byte, len(signedTx))
+	copy(order.SignedTx, signedTx)
+	order.Txhash = txHash
+	keeper.ok.SetOrder(ctx, order)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), order.GetCUAddress(), orderID, sdk.OrderTypeUtxoConsolidation, sdk.OrderStatusSignFinish))
+	flows = append(flows, keeper.rk.NewUtxoConsolidationSignFinishFlow(orderID, signedTx))
+
+	result = sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeUtxoConsolidation, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+func (keeper BaseKeeper) UtxoConsolidationFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderID string, costFee sdk.Int) sdk.Result {
+	bValidator, _ := keeper.sk.IsActiveKeyNode(ctx, fromCUAddr)
+	if !bValidator {
+		return sdk.ErrInvalidTx(fmt.Sprintf("utxo consolidation from not a validator :%v", fromCUAddr)).Result()
+	}
+
+	tokenInfo, order, err := keeper.checkUtxoConsolidationOrder(ctx, orderID, sdk.OrderStatusSignFinish)
+	if err != nil {
+		return err.Result()
+	}
+
+	confirmedFirstTime, _, _ := keeper.evidenceKeeper.Vote(ctx, order.Txhash, fromCUAddr, types.NewTxVote(costFee.Int64(), true), uint64(ctx.BlockHeight()))
+
+	result := sdk.Result{}
+	if !confirmedFirstTime {
+		return result
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	opCUAst := keeper.ik.GetCUIBCAsset(ctx, order.GetCUAddress())
+
+	vins, e := keeper.cn.QueryUtxoInsFromData(chain, symbol, order.RawData)
+	if e != nil {
+		return sdk.ErrInvalidTx(e.Error()).Result()
+	}
+
+	inSum := sdk.ZeroInt()
+	for _, vin := range vins {
+		item := keeper.ik.GetDeposit(ctx, symbol, opCUAst.GetAddress(), vin.Hash, vin.Index)
+		if item == sdk.DepositNil {
+			return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v does not exist", vin.Hash, vin.Index)).Result()
+		}
+		if item.GetStatus() != sdk.DepositItemStatusInProcess {
+			return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v status is %v", vin.Hash, vin.Index, item.GetStatus())).Result()
+		}
+
+		vin.Address = item.ExtAddress
+		vin.Amount = item.Amount
+		inSum = inSum.Add(vin.Amount)
+	}
+
+	tx, e := keeper.cn.QueryUtxoTransactionFromSignedData(chain, symbol, order.SignedTx, vins)
+	if e != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("Fail to get transaction from signed transaction:%v", order.SignedTx)).Result()
+	}
+
+	//save the merged utxo as a new deposit item of the opCU
+	outSum := sdk.ZeroInt()
+	for i, vout := range tx.Vouts {
+		if order.Address == vout.Address {
+			depositItem, err := sdk.NewDepositItem(tx.Hash, uint64(i), vout.Amount, vout.Address, "", sdk.DepositItemStatusConfirmed)
+			if err != nil {
+				return sdk.ErrInvalidOrder(fmt.Sprintf("fail to create deposit item, %v %v %v", tx.Hash, i, vout.Amount)).Result()
+			}
+			_ = keeper.ik.SaveDeposit(ctx, symbol, opCUAst.GetAddress(), depositItem)
+			outSum = outSum.Add(vout.Amount)
+		}
+	}
+
+	//delete used Vins from opCU
+	for _, vin := range tx.Vins {
+		keeper.ik.DelDeposit(ctx, symbol, opCUAst.GetAddress(), vin.Hash, vin.Index)
+	}
+
+	opCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(chain, outSum)))
+	opCUAst.AddGasUsed(sdk.NewCoins(sdk.NewCoin(chain, inSum.Sub(outSum))))
+	keeper.ik.SetCUIBCAsset(ctx, opCUAst)
+
+	//update order's status and costFee
+	order.Status = sdk.OrderStatusFinish
+	order.CostFee = costFee
+	keeper.ok.SetOrder(ctx, order)
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), order.GetCUAddress(), orderID, sdk.OrderTypeUtxoConsolidation, sdk.OrderStatusFinish))
+	flows = append(flows, keeper.rk.NewUtxoConsolidationFinishFlow(orderID, costFee))
+
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeUtxoConsolidation, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+// CancelTimeoutUtxoConsolidations cancels the utxo consolidation orders which are not signed in Timeout blocks after
+// entering Begin or WaitSign, releases their utxos and restores the assets held back by WaitSign. Orders in
+// SignFinish are kept, since their signed tx spends the utxos and can still be broadcast.
+func (keeper BaseKeeper) CancelTimeoutUtxoConsolidations(ctx sdk.Context) {
+	timeout := keeper.GetUtxoConsolidationParams(ctx).Timeout
+	curHeight := uint64(ctx.BlockHeight())
+	for _, orderID := range keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeUtxoConsolidation) {
+		order, ok := keeper.ok.GetOrder(ctx, orderID).(*sdk.OrderUtxoConsolidation)
+		if !ok || order.Status != sdk.OrderStatusBegin && order.Status != sdk.OrderStatusWaitSign {
+			continue
+		}
+		stageHeight := order.StageHeight
+		if stageHeight == 0 {
+			stageHeight = order.Height
+		}
+		if curHeight < stageHeight+timeout {
+			continue
+		}
+		keeper.cancelUtxoConsolidation(ctx, order)
+	}
+}
+
+func (keeper BaseKeeper) cancelUtxoConsolidation(ctx sdk.Context, order *sdk.OrderUtxoConsolidation) {
+	symbol := order.Symbol
+	opCUAddr := order.GetCUAddress()
+	amt := sdk.ZeroInt()
+	for _, item := range order.ConsolidateItems {
+		if err := keeper.ik.SetDepositStatus(ctx, symbol, opCUAddr, item.Hash, item.Index, sdk.DepositItemStatusConfirmed); err != nil {
+			ctx.Logger().Error("fail to release utxo of consolidation order", "order", order.ID, "hash", item.Hash, "index", item.Index, "err", err)
+			continue
+		}
+		amt = amt.Add(item.Amount)
+	}
+
+	if order.Status == sdk.OrderStatusWaitSign {
+		opCUAst := keeper.ik.GetCUIBCAsset(ctx, opCUAddr)
+		opCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+		keeper.ik.SetCUIBCAsset(ctx, opCUAst)
+	}
+
+	order.Status = sdk.OrderStatusCancel
+	order.StageHeight = uint64(ctx.BlockHeight())
+	keeper.ok.SetOrder(ctx, order)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeUtxoConsolidationTimeout,
+			sdk.NewAttribute(types.AttributeKeySender, opCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeySymbol, symbol),
+			sdk.NewAttribute(types.AttributeKeyOrderID, order.ID),
+		),
+	)
+}
+
+func (keeper BaseKeeper) checkUtxoConsolidationOrder(ctx sdk.Context, orderID string, orderStatus sdk.OrderStatus) (tokenInfo *sdk.IBCToken, consolidationOrder *sdk.OrderUtxoConsolidation, err sdk.Error) {
+	order := keeper.ok.GetOrder(ctx, orderID)
+	if order == nil {
+		err = sdk.ErrNotFoundOrder(fmt.Sprintf("orderid:%v does not exist", orderID))
+		return
+	}
+
+	consolidationOrder, valid := order.(*sdk.OrderUtxoConsolidation)
+	if !valid {
+		err = sdk.ErrInvalidOrder(fmt.Sprintf("order %v is not utxo consolidation order", orderID))
+		return
+	}
+
+	tokenInfo = keeper.tk.GetIBCToken(ctx, sdk.Symbol(order.GetSymbol()))
+	if tokenInfo == nil || tokenInfo.TokenType != sdk.UtxoBased {
+		err = sdk.ErrUnSupportToken(order.GetSymbol())
+		return
+	}
+
+	if !orderStatus.Match(consolidationOrder.Status) {
+		err = sdk.ErrInvalidOrder(fmt.Sprintf("order %v status doesn't match expctedStatus:%v", orderID, orderStatus))
+		return
+	}
+
+	if orderStatus == sdk.OrderStatusWaitSign || orderStatus == sdk.OrderStatusSignFinish {
+		if len(consolidationOrder.RawData) == 0 {
+			err = sdk.ErrInvalidOrder(fmt.Sprintf("order %v RawData is empty", orderID))
+			return
+		}
+	}
+
+	if orderStatus == sdk.OrderStatusSignFinish {
+		if len(consolidationOrder.SignedTx) == 0 || consolidationOrder.Txhash == "" {
+			err = sdk.ErrInvalidOrder(fmt.Sprintf("order %v SignTx or Txhash is empty", orderID))
+			return
+		}
+	}
+
+	return
+}
+
+// GetGasPriceAverage returns the moving average of gas price of the chain sampled by ScheduleUtxoConsolidation
+func (keeper BaseKeeper) GetGasPriceAverage(ctx sdk.Context, chain string) sdk.Int {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(types.GasPriceAverageKey(chain))
+	if len(bz) == 0 {
+		return sdk.ZeroInt()
+	}
+	var average sdk.Int
+	keeper.cdc.MustUnmarshalBinaryBare(bz, &average)
+	return average
+}
+
+// updateGasPriceAverage samples the gas price into its moving average, and returns whether the gas price is
+// at most ratio of the average before sampling
+func (keeper BaseKeeper) updateGasPriceAverage(ctx sdk.Context, chain string, gasPrice sdk.Int, ratio sdk.Dec) bool {
+	average := keeper.GetGasPriceAverage(ctx, chain)
+	low := average.IsPositive() && sdk.NewDecFromInt(gasPrice).LTE(sdk.NewDecFromInt(average).Mul(ratio))
+
+	if average.IsZero() {
+		average = gasPrice
+	} else {
+		average = average.MulRaw(types.GasPriceAverageWindow - 1).Add(gasPrice).QuoRaw(types.GasPriceAverageWindow)
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.GasPriceAverageKey(chain), keeper.cdc.MustMarshalBinaryBare(average))
+	return low
+}
@@ -0,0 +1,64 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit/exported"
+	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// CreateVestingCU creates toCUAddr as a vesting CU and sends amount to it from fromCUAddr. amount vests continuously
+// from startTime to endTime, or all at endTime if startTime is zero.
+func (keeper BaseKeeper) CreateVestingCU(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, amount sdk.Coins, startTime, endTime int64) sdk.Result {
+	bcu := cutypes.NewBaseCU(sdk.CUTypeUser, toCUAddr, nil, 0)
+	if startTime == 0 {
+		return keeper.createVestingCU(ctx, fromCUAddr, cutypes.NewDelayedVestingCU(bcu, amount, endTime))
+	}
+	return keeper.createVestingCU(ctx, fromCUAddr, cutypes.NewContinuousVestingCU(bcu, amount, startTime, endTime))
+}
+
+// CreatePeriodicVestingCU creates toCUAddr as a periodic vesting CU and sends the total amount of periods to it from fromCUAddr.
+func (keeper BaseKeeper) CreatePeriodicVestingCU(ctx sdk.Context, fromCUAddr, toCUAddr sdk.CUAddress, startTime int64, periods cutypes.VestingPeriods) sdk.Result {
+	bcu := cutypes.NewBaseCU(sdk.CUTypeUser, toCUAddr, nil, 0)
+	return keeper.createVestingCU(ctx, fromCUAddr, cutypes.NewPeriodicVestingCU(bcu, startTime, periods))
+}
+
+func (keeper BaseKeeper) createVestingCU(ctx sdk.Context, fromCUAddr sdk.CUAddress, vcu exported.VestingCU) sdk.Result {
+	toCUAddr := vcu.GetAddress()
+	if err := keeper.checkNotFrozen(ctx, fromCUAddr); err != nil {
+		return err.Result()
+	}
+	if fromCUAddr.Equals(toCUAddr) {
+		return sdk.ErrInvalidTx("vesting CU funded by itself").Result()
+	}
+	if keeper.ck.GetCU(ctx, toCUAddr) != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("CU %v already exists", toCUAddr)).Result()
+	}
+	if err := vcu.Validate(); err != nil {
+		return sdk.ErrInvalidTx(err.Error()).Result()
+	}
+	if vcu.GetEndTime() <= ctx.BlockHeader().Time.Unix() {
+		return sdk.ErrInvalidTx(fmt.Sprintf("vesting end-time %v is not after the block time", vcu.GetEndTime())).Result()
+	}
+
+	keeper.ck.SetCU(ctx, vcu)
+	result, _, err := keeper.SendCoins(ctx, fromCUAddr, toCUAddr, vcu.GetOriginalVesting())
+	if err != nil {
+		return err.Result()
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCreateVestingCU,
+			sdk.NewAttribute(types.AttributeKeySender, fromCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, toCUAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, vcu.GetOriginalVesting().String()),
+			sdk.NewAttribute(types.AttributeKeyStartTime, fmt.Sprintf("%d", vcu.GetStartTime())),
+			sdk.NewAttribute(types.AttributeKeyEndTime, fmt.Sprintf("%d", vcu.GetEndTime())),
+		),
+	)
+
+	return result
+}
@@ -12,6 +12,9 @@ import (
 )
 
 func (keeper BaseKeeper) Withdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddress, toAddr, orderID, symbol string, amt, gasFee sdk.Int) sdk.Result {
+	if err := keeper.checkNotFrozen(ctx, fromCUAddr); err != nil {
+		return err.Result()
+	}
 	if sdk.IsIllegalOrderID(orderID) {
 		return sdk.ErrInvalidTx(fmt.Sprintf("invalid OrderID:%v", orderID)).Result()
 	}
@@ -24,6 +27,10 @@ func (keeper BaseKeeper) Withdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddress, t
 		return sdk.ErrInvalidTx(fmt.Sprintf("withdrawal from a non user CU :%v", fromCUAddr)).Result()
 	}
 
+	if keeper.IsWithdrawalFrozen(ctx, fromCUAddr) {
+		return sdk.ErrTransactionIsNotEnabled(fmt.Sprintf("withdrawal of CU %v is frozen", fromCUAddr)).Result()
+	}
+
 	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
 	if tokenInfo == nil {
 		return sdk.ErrUnSupportToken(symbol).Result()
@@ -167,6 +174,12 @@ func (keeper BaseKeeper) WithdrawalWaitSign(ctx sdk.Context, opCUAddr sdk.CUAddr
 	if err != nil {
 		return err.Result()
 	}
+	if err = keeper.checkWithdrawalOpCUTier(opCUAddr, tokenInfo, withdrawalOrders); err != nil {
+		return err.Result()
+	}
+	if err = keeper.checkOpCUSignThreshold(ctx, opCUAst, tokenInfo); err != nil {
+		return err.Result()
+	}
 
 	//Retrieve gas Price
 	gasPrice := tokenInfo.GasPrice
@@ -189,6 +202,10 @@ func (keeper BaseKeeper) WithdrawalWaitSign(ctx sdk.Context, opCUAddr sdk.CUAddr
 			return sdk.ErrInvalidTx(fmt.Sprintf("contains too many vouts %v", len(orderIDs))).Result()
 		}
 
+		if err := keeper.checkBatchWithdrawalOrders(ctx, withdrawalOrders); err != nil {
+			return err.Result()
+		}
+
 		//formulate the vins
 		vins, err := keeper.cn.QueryUtxoInsFromData(chain, symbol, rawData)
 		if err != nil {
@@ -382,6 +399,9 @@ func (keeper BaseKeeper) WithdrawalSignFinish(ctx sdk.Context, orderIDs []string
 	if err != nil {
 		return err.Result()
 	}
+	if err = keeper.checkOpCUSignThreshold(ctx, opCUAst, tokenInfo); err != nil {
+		return err.Result()
+	}
 	var txHash string
 
 	switch tokenInfo.TokenType {
@@ -428,6 +448,17 @@ func (keeper BaseKeeper) WithdrawalSignFinish(ctx sdk.Context, orderIDs []string
 }
 
 func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, costFee sdk.Int, valid bool) sdk.Result {
+	return keeper.withdrawalFinish(ctx, fromCUAddr, orderIDs, "", costFee, valid)
+}
+
+// WithdrawalReplacedTxFinish finishes withdrawal orders by a tx replaced by fee bumps, which is packed on the
+// external chain instead of the bumping one. The orders may be waiting for the signature of the bumping tx.
+func (keeper BaseKeeper) WithdrawalReplacedTxFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, txHash string, costFee sdk.Int, valid bool) sdk.Result {
+	return keeper.withdrawalFinish(ctx, fromCUAddr, orderIDs, txHash, costFee, valid)
+}
+
+// withdrawalFinish finishes withdrawal orders by the packed tx, replacedTxHash is empty if it is the latest signed tx
+func (keeper BaseKeeper) withdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, replacedTxHash string, costFee sdk.Int, valid bool) sdk.Result {
 	bValidator, _ := keeper.sk.IsActiveKeyNode(ctx, fromCUAddr)
 	if !bValidator {
 		return sdk.ErrInvalidTx(fmt.Sprintf("withdrawal from not a validator :%v", fromCUAddr)).Result()
@@ -445,13 +476,25 @@ func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddr
 		return err.Result()
 	}
 
-	confirmedFirstTime, _, _ := keeper.evidenceKeeper.Vote(ctx, withdrawalOrder.Txhash, fromCUAddr, types.NewTxVote(costFee.Int64(), true), uint64(ctx.BlockHeight()))
+	hash, signedTx, orderStatus := withdrawalOrder.Txhash, withdrawalOrder.SignedTx, sdk.OrderStatusSignFinish
+	if replacedTxHash != "" {
+		signedTx = withdrawalOrder.GetReplacedSignedTx(replacedTxHash)
+		if signedTx == nil {
+			return sdk.ErrInvalidTx(fmt.Sprintf("tx %v is not replaced by order %v", replacedTxHash, orderIDs[0])).Result()
+		}
+		if withdrawalOrder.Status != sdk.OrderStatusWaitSign && withdrawalOrder.Status != sdk.OrderStatusSignFinish {
+			return sdk.ErrInvalidOrder(fmt.Sprintf("order %v status is %v, can not finish", orderIDs[0], withdrawalOrder.Status)).Result()
+		}
+		hash, orderStatus = replacedTxHash, withdrawalOrder.Status
+	}
+
+	confirmedFirstTime, _, _ := keeper.evidenceKeeper.Vote(ctx, hash, fromCUAddr, types.NewTxVote(costFee.Int64(), true), uint64(ctx.BlockHeight()))
 	result := sdk.Result{}
 	if !confirmedFirstTime {
 		return result
 	}
 
-	tokenInfo, withdrawalOrders, err := keeper.checkWithdrawalOrders(ctx, orderIDs, sdk.OrderStatusSignFinish)
+	tokenInfo, withdrawalOrders, err := keeper.checkWithdrawalOrders(ctx, orderIDs, orderStatus)
 	if err != nil {
 		return err.Result()
 	}
@@ -472,8 +515,6 @@ func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddr
 		return err.Result()
 	}
 
-	hash := order.Txhash
-
 	switch tokenInfo.TokenType {
 	case sdk.UtxoBased:
 		vins, err := keeper.cn.QueryUtxoInsFromData(chain, symbol, order.RawData)
@@ -495,9 +536,9 @@ func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddr
 			vin.Amount = item.Amount
 		}
 
-		tx, err := keeper.cn.QueryUtxoTransactionFromSignedData(chain, symbol, order.SignedTx, vins)
+		tx, err := keeper.cn.QueryUtxoTransactionFromSignedData(chain, symbol, signedTx, vins)
 		if err != nil {
-			return sdk.ErrInvalidTx(fmt.Sprintf("Fail to get transaction from signed transaction:%v", order.SignedTx)).Result()
+			return sdk.ErrInvalidTx(fmt.Sprintf("Fail to get transaction from signed transaction:%v", signedTx)).Result()
 		}
 
 		//check the change and update deposit item's status
@@ -542,9 +583,9 @@ func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddr
 		opCUAst.AddGasUsed(sdk.NewCoins(sdk.NewCoin(chain, costFee)))
 
 	case sdk.AccountBased:
-		tx, err := keeper.cn.QueryAccountTransactionFromSignedData(chain, symbol, order.SignedTx)
+		tx, err := keeper.cn.QueryAccountTransactionFromSignedData(chain, symbol, signedTx)
 		if err != nil {
-			return sdk.ErrInvalidTx(fmt.Sprintf("Fail to get transaction from signed transaction:%v", order.SignedTx)).Result()
+			return sdk.ErrInvalidTx(fmt.Sprintf("Fail to get transaction from signed transaction:%v", signedTx)).Result()
 		}
 		if tx.Hash != hash {
 			return sdk.ErrInvalidTx(fmt.Sprintf("hash mismatch, expected: %v, actual:%v", hash, tx.Hash)).Result()
@@ -624,7 +665,7 @@ func (keeper BaseKeeper) WithdrawalFinish(ctx sdk.Context, fromCUAddr sdk.CUAddr
 	var flows []sdk.Flow
 	withdrawalOrder = withdrawalOrders[0]
 	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), opCUAddr, withdrawalOrder.GetID(), sdk.OrderTypeWithdrawal, sdk.OrderStatusFinish))
-	flows = append(flows, keeper.rk.NewWithdrawalFinishFlow(orderIDs, costFee, valid))
+	flows = append(flows, keeper.rk.NewWithdrawalFinishFlow(orderIDs, costFee, valid, withdrawalOrder.GetBumpedFee()))
 	flows = append(flows, balanceFlows...)
 
 	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeWithdrawal, flows)
@@ -916,3 +957,39 @@ func (keeper BaseKeeper) CancelWithdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddr
 	keeper.rk.SaveReceiptToResult(receipt, &result)
 	return result
 }
+
+// checkOpCUSignThreshold checks the key of a cold OPCU requires at least the key node threshold of the chain to sign.
+// The keys of hot OPCUs keep signing with their own threshold until they are rotated, the large withdrawals from
+// cold OPCUs wait for the rotation, which generates the keys with the raised threshold.
+func (keeper BaseKeeper) checkOpCUSignThreshold(ctx sdk.Context, opCUAst exported.CUIBCAsset, tokenInfo *sdk.IBCToken) sdk.Error {
+	if !tokenInfo.IsColdOpCU(opCUAst.GetAddress()) {
+		return nil
+	}
+	chainToken := keeper.tk.GetIBCToken(ctx, tokenInfo.Chain)
+	if chainToken == nil {
+		return sdk.ErrInvalidSymbol(fmt.Sprintf("%s does not exist", tokenInfo.Chain))
+	}
+	keyNodeNum := len(keeper.sk.GetCurrentEpoch(ctx).KeyNodeSet)
+	threshold := opCUAst.GetAssetPubkeyThreshold()
+	// keys generated before their threshold was recorded require the 2/3 majority
+	if threshold == 0 {
+		threshold = uint64(sdk.Majority23(keyNodeNum))
+	}
+	if required := chainToken.GetKeyNodeThreshold(keyNodeNum); threshold < uint64(required) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("cold OPCU %v key threshold %d is below %d required by %s", opCUAst.GetAddress(), threshold, required, tokenInfo.Chain))
+	}
+	return nil
+}
+
+// checkWithdrawalOpCUTier checks a cold OPCU only takes part in withdrawals of at least the cold withdrawal threshold
+func (keeper BaseKeeper) checkWithdrawalOpCUTier(opCUAddr sdk.CUAddress, tokenInfo *sdk.IBCToken, withdrawalOrders []*sdk.OrderWithdrawal) sdk.Error {
+	if !tokenInfo.IsColdOpCU(opCUAddr) {
+		return nil
+	}
+	for _, order := range withdrawalOrders {
+		if !tokenInfo.OpCUTiers.CanWithdraw(order.Amount) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("cold OPCU %v can not withdraw %v of order %v", opCUAddr, order.Amount, order.ID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// BatchWithdrawal withdraws one symbol to multiple recipients. Every output becomes a withdrawal order
+// tagged with batchID, UTXO based outputs are packed into one tx, account based outputs are sent one by one.
+// A batch is identified by its sender and batchID, the sender can not reuse a batchID.
+func (keeper BaseKeeper) BatchWithdrawal(ctx sdk.Context, fromCUAddr sdk.CUAddress, batchID, symbol string, outputs []types.WithdrawalOutput, gasFee sdk.Int) sdk.Result {
+	if err := keeper.checkNotFrozen(ctx, fromCUAddr); err != nil {
+		return err.Result()
+	}
+	if sdk.IsIllegalOrderID(batchID) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid BatchID:%v", batchID)).Result()
+	}
+	if len(outputs) == 0 {
+		return sdk.ErrInvalidTx("no withdrawal outputs").Result()
+	}
+	if keeper.hasBatchWithdrawal(ctx, fromCUAddr, batchID) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("BatchID %v of %v already exists", batchID, fromCUAddr)).Result()
+	}
+
+	orderIDs := make([]string, len(outputs))
+	for i, output := range outputs {
+		orderIDs[i] = output.OrderID
+	}
+	if sdk.IsIllegalOrderIDList(orderIDs) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid OrderIDs:%v", strings.Join(orderIDs, ","))).Result()
+	}
+
+	fromCU := keeper.ck.GetCU(ctx, fromCUAddr)
+	if fromCU == nil {
+		return sdk.ErrInvalidAccount(fromCUAddr.String()).Result()
+	}
+	if fromCU.GetCUType() != sdk.CUTypeUser {
+		return sdk.ErrInvalidTx(fmt.Sprintf("withdrawal from a non user CU :%v", fromCUAddr)).Result()
+	}
+
+	if keeper.IsWithdrawalFrozen(ctx, fromCUAddr) {
+		return sdk.ErrTransactionIsNotEnabled(fmt.Sprintf("withdrawal of CU %v is frozen", fromCUAddr)).Result()
+	}
+
+	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	if tokenInfo == nil {
+		return sdk.ErrUnSupportToken(symbol).Result()
+	}
+	chain := tokenInfo.Chain.String()
+
+	if !tokenInfo.WithdrawalEnabled || !tokenInfo.SendEnabled || !keeper.IsSendEnabled(ctx) {
+		return sdk.ErrTransactionIsNotEnabled(fmt.Sprintf("%v's withdraw is not enabled temporary", symbol)).Result()
+	}
+
+	// a UTXO based batch is packed into one tx, which keeps one vout for the change
+	maxOutputNum := types.MaxBatchWithdrawalNum
+	if tokenInfo.TokenType == sdk.UtxoBased {
+		maxOutputNum = sdk.MaxVoutNum - 1
+	}
+	if len(outputs) > maxOutputNum {
+		return sdk.ErrInvalidTx(fmt.Sprintf("too many withdrawal outputs %v, max:%v", len(outputs), maxOutputNum)).Result()
+	}
+
+	toAddrs := make([]string, len(outputs))
+	amt := sdk.ZeroInt()
+	for i, output := range outputs {
+		if !output.Amount.IsPositive() {
+			return sdk.ErrInvalidAmount(fmt.Sprintf("amount of order %v is not positive", output.OrderID)).Result()
+		}
+
+		valid, canonicalToAddr := keeper.cn.ValidAddress(chain, symbol, output.ToMultisignAddress)
+		if !valid {
+			return sdk.ErrInvalidAddr(fmt.Sprintf("%v is not a valid address", output.ToMultisignAddress)).Result()
+		}
+
+		toCUAddr, _ := keeper.ck.GetCUFromExtAddress(ctx, chain, canonicalToAddr)
+		if toCUAddr != nil {
+			return sdk.ErrInvalidTx(fmt.Sprintf("withdrawal to a chain CU :%v not support, use send cmd directly instead", toCUAddr)).Result()
+		}
+
+		if keeper.ok.IsExist(ctx, output.OrderID) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("order %v already exists", output.OrderID)).Result()
+		}
+
+		toAddrs[i] = canonicalToAddr
+		amt = amt.Add(output.Amount)
+	}
+
+	needFee := tokenInfo.BatchWithdrawalFee(len(outputs))
+	if gasFee.LT(needFee.Amount) {
+		return sdk.ErrInsufficientFee(fmt.Sprintf("need:%v, actual have:%v", needFee, gasFee)).Result()
+	}
+
+	feeCoins := sdk.NewCoins(sdk.NewCoin(chain, gasFee))
+	coins := sdk.NewCoins(sdk.NewCoin(symbol, amt))
+	need := coins.Add(feeCoins)
+
+	balanceFlows, err := keeper.LockCoins(ctx, fromCUAddr, need)
+	if err != nil {
+		return err.Result()
+	}
+
+	// the gas fee is shared evenly by the outputs, the first one takes the remainder,
+	// so that every output can be refunded on its own
+	outputNum := sdk.NewInt(int64(len(outputs)))
+	orderFee := gasFee.Quo(outputNum)
+	remainder := gasFee.Sub(orderFee.Mul(outputNum))
+
+	var flows []sdk.Flow
+	for i, output := range outputs {
+		fee := orderFee
+		if i == 0 {
+			fee = fee.Add(remainder)
+		}
+
+		withdrawalOrder := keeper.ok.NewOrderWithdrawal(ctx, fromCUAddr, output.OrderID, symbol, output.Amount, fee, sdk.ZeroInt(), toAddrs[i], "", "")
+		if withdrawalOrder == nil {
+			return sdk.ErrInvalidOrder(fmt.Sprintf("Fail to create order:%v", output.OrderID)).Result()
+		}
+		withdrawalOrder.BatchID = batchID
+		if tokenInfo.TokenType == sdk.UtxoBased {
+			withdrawalOrder.WithdrawStatus = sdk.WithdrawStatusValid
+		} else {
+			withdrawalOrder.WithdrawStatus = sdk.WithdrawStatusUnconfirmed
+		}
+		keeper.ok.SetOrder(ctx, withdrawalOrder)
+
+		flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), withdrawalOrder.GetCUAddress(), withdrawalOrder.GetID(), sdk.OrderTypeWithdrawal, sdk.OrderStatusBegin))
+		flows = append(flows, keeper.rk.NewWithdrawalFlow(output.OrderID, fromCUAddr.String(), toAddrs[i], symbol, output.Amount, fee, withdrawalOrder.WithdrawStatus))
+	}
+	keeper.setBatchWithdrawal(ctx, fromCUAddr, batchID)
+	flows = append(flows, keeper.rk.NewBatchWithdrawalFlow(batchID, orderIDs))
+	flows = append(flows, balanceFlows...)
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeWithdrawal, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+func (keeper BaseKeeper) hasBatchWithdrawal(ctx sdk.Context, owner sdk.CUAddress, batchID string) bool {
+	store := ctx.KVStore(keeper.storeKey)
+	return store.Has(types.BatchWithdrawalKey(owner, batchID))
+}
+
+func (keeper BaseKeeper) setBatchWithdrawal(ctx sdk.Context, owner sdk.CUAddress, batchID string) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.BatchWithdrawalKey(owner, batchID), []byte{})
+}
+
+// batchKey identifies a batch withdrawal, batchIDs of different senders may collide
+type batchKey struct {
+	owner   string
+	batchID string
+}
+
+// checkBatchWithdrawalOrders checks a UTXO based tx does not split a batch withdrawal,
+// all the pending orders of a batch must be packed into the same tx.
+func (keeper BaseKeeper) checkBatchWithdrawalOrders(ctx sdk.Context, withdrawalOrders []*sdk.OrderWithdrawal) sdk.Error {
+	included := make(map[string]bool, len(withdrawalOrders))
+	batches := make(map[batchKey]bool)
+	for _, order := range withdrawalOrders {
+		included[order.ID] = true
+		if order.BatchID != "" {
+			batches[batchKey{order.CUAddress.String(), order.BatchID}] = true
+		}
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+
+	for _, orderID := range keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeWithdrawal) {
+		if included[orderID] {
+			continue
+		}
+		order, ok := keeper.ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+		if !ok || order.Status != sdk.OrderStatusBegin || order.BatchID == "" || !batches[batchKey{order.CUAddress.String(), order.BatchID}] {
+			continue
+		}
+		return sdk.ErrInvalidTx(fmt.Sprintf("order %v of batch %v is not included", orderID, order.BatchID))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+// EstimateWithdrawalFee estimates the gasFee of withdrawing amt of symbol to toAddr at the gas price voted by
+// validators. UTXO based withdrawals are estimated with the inputs expected to spend, and withdrawals of non chain
+// tokens with the sys transfer topping up OPCUs if none of them has enough gas.
+func (keeper BaseKeeper) EstimateWithdrawalFee(ctx sdk.Context, symbol string, amt sdk.Int, toAddr string) (types.ResWithdrawalFee, sdk.Error) {
+	tokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	if tokenInfo == nil {
+		return types.ResWithdrawalFee{}, sdk.ErrUnSupportToken(symbol)
+	}
+	if !amt.IsPositive() {
+		return types.ResWithdrawalFee{}, sdk.ErrInvalidAmount(fmt.Sprintf("withdrawal amount %v is not positive", amt))
+	}
+	chain := tokenInfo.Chain.String()
+	if toAddr != "" {
+		if valid, _ := keeper.cn.ValidAddress(chain, symbol, toAddr); !valid {
+			return types.ResWithdrawalFee{}, sdk.ErrInvalidAddr(fmt.Sprintf("%v is not a valid address", toAddr))
+		}
+	}
+
+	res := types.ResWithdrawalFee{
+		Symbol:    symbol,
+		GasPrice:  tokenInfo.GasPrice,
+		MinGasFee: tokenInfo.WithdrawalFee(),
+	}
+
+	var baseGasFee, gasFee sdk.Int
+	switch tokenInfo.TokenType {
+	case sdk.UtxoBased:
+		baseGasFee = sdk.DefaultUtxoWithdrawTxSize().Mul(tokenInfo.GasPrice).QuoRaw(sdk.KiloBytes)
+		inputs := keeper.estimateUtxoInputs(ctx, tokenInfo, amt)
+		if inputs == 0 {
+			return types.ResWithdrawalFee{}, sdk.ErrInsufficientCoins(fmt.Sprintf("no OPCU is able to withdraw %v%v", amt, symbol))
+		}
+		res.UtxoInputs = inputs
+		gasFee = utxoWithdrawalGasFee(tokenInfo, inputs)
+	default:
+		baseGasFee = tokenInfo.GasPrice.Mul(tokenInfo.GasLimit)
+		gasFee = baseGasFee
+		if chain != symbol && keeper.needWithdrawalSysTransfer(ctx, tokenInfo, amt, baseGasFee) {
+			chainTokenInfo := keeper.tk.GetIBCToken(ctx, sdk.Symbol(chain))
+			if chainTokenInfo == nil {
+				return types.ResWithdrawalFee{}, sdk.ErrUnSupportToken(chain)
+			}
+			res.NeedSysTransfer = true
+			gasFee = gasFee.Add(chainTokenInfo.GasPrice.Mul(chainTokenInfo.GasLimit))
+		}
+	}
+
+	platformFee := sdk.ZeroInt()
+	if res.MinGasFee.Amount.GT(baseGasFee) {
+		platformFee = res.MinGasFee.Amount.Sub(baseGasFee)
+	}
+	res.GasFee = sdk.NewCoin(chain, gasFee)
+	res.PlatformFee = sdk.NewCoin(chain, platformFee)
+	res.RecommendedGasFee = sdk.NewCoin(chain, sdk.MaxInt(res.MinGasFee.Amount, gasFee.Add(platformFee)))
+	return res, nil
+}
+
+// estimateUtxoInputs returns the fewest inputs of an OPCU to withdraw amt with, spending the largest UTXOs first,
+// 0 if no OPCU is able to withdraw amt within sdk.MaxVinNum inputs. Cold OPCUs are skipped if amt is below the
+// cold withdrawal threshold, see checkWithdrawalOpCUTier.
+func (keeper BaseKeeper) estimateUtxoInputs(ctx sdk.Context, tokenInfo *sdk.IBCToken, amt sdk.Int) int {
+	symbol := tokenInfo.Symbol.String()
+	fewest := 0
+	for _, opCU := range keeper.ck.GetOpCUs(ctx, symbol) {
+		if tokenInfo.IsColdOpCU(opCU.GetAddress()) && !tokenInfo.OpCUTiers.CanWithdraw(amt) {
+			continue
+		}
+		var amounts []sdk.Int
+		for _, item := range keeper.ik.GetDepositList(ctx, symbol, opCU.GetAddress()) {
+			if item.GetStatus() == sdk.DepositItemStatusConfirmed {
+				amounts = append(amounts, item.Amount)
+			}
+		}
+		sort.Slice(amounts, func(i, j int) bool { return amounts[i].GT(amounts[j]) })
+
+		sum := sdk.ZeroInt()
+		for i := 0; i < len(amounts) && i < sdk.MaxVinNum; i++ {
+			sum = sum.Add(amounts[i])
+			if sum.GTE(amt.Add(utxoWithdrawalGasFee(tokenInfo, i+1))) {
+				if fewest == 0 || i+1 < fewest {
+					fewest = i + 1
+				}
+				break
+			}
+		}
+	}
+	return fewest
+}
+
+// needWithdrawalSysTransfer returns true if no OPCU holding amt has enough gas left for the withdrawal transaction
+func (keeper BaseKeeper) needWithdrawalSysTransfer(ctx sdk.Context, tokenInfo *sdk.IBCToken, amt, gasFee sdk.Int) bool {
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	curEpoch := keeper.sk.GetCurrentEpoch(ctx)
+	for _, opCU := range keeper.ck.GetOpCUs(ctx, symbol) {
+		opCUAst := keeper.ik.GetCUIBCAsset(ctx, opCU.GetAddress())
+		if opCUAst == nil || opCUAst.GetAssetCoins().AmountOf(symbol).LT(amt) {
+			continue
+		}
+		addr := opCUAst.GetAssetAddress(chain, curEpoch.Index)
+		if addr == "" {
+			continue
+		}
+		if opCUAst.GetGasRemained(chain, addr).GTE(gasFee) &&
+			!keeper.checkNeedSysTransfer(ctx, chain, addr, gasFee, sdk.CUTypeOp, opCU.GetAddress()) {
+			return false
+		}
+	}
+	return true
+}
+
+// utxoWithdrawalGasFee returns the gas fee of a withdrawal transaction spending inputs, with a change output
+func utxoWithdrawalGasFee(tokenInfo *sdk.IBCToken, inputs int) sdk.Int {
+	return sdk.EstimateSignedUtxoTxSize(inputs, 2).Mul(tokenInfo.GasPrice).QuoRaw(sdk.KiloBytes)
+}
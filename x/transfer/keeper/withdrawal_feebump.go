@@ -0,0 +1,269 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// WithdrawalFeeBump replaces the raw data of withdrawal orders whose signed tx is stuck on the external chain
+// by a higher fee one. Account based tokens must reuse the nonce of the replaced tx and utxo based tokens must
+// spend the same vins (replace-by-fee), so at most one of the txs can be packed. The extra cost is charged to
+// the orders' GasFee, and the orders go back to WaitSign to collect the new signature. The replaced tx may still
+// be packed, its signed tx is kept so that the orders can be finished by it, see WithdrawalReplacedTxFinish.
+func (keeper BaseKeeper) WithdrawalFeeBump(ctx sdk.Context, fromCUAddr sdk.CUAddress, orderIDs []string, signHashes [][]byte, rawData []byte) sdk.Result {
+	bValidator, _ := keeper.sk.IsActiveKeyNode(ctx, fromCUAddr)
+	if !bValidator {
+		return sdk.ErrInvalidTx(fmt.Sprintf("withdrawal fee bump from not a validator :%v", fromCUAddr)).Result()
+	}
+
+	tokenInfo, withdrawalOrders, err := keeper.checkWithdrawalOrders(ctx, orderIDs, sdk.OrderStatusSignFinish)
+	if err != nil {
+		return err.Result()
+	}
+
+	order := withdrawalOrders[0]
+	if order.Status != sdk.OrderStatusSignFinish {
+		return sdk.ErrInvalidOrder(fmt.Sprintf("order %v status is %v, can not bump fee", order.ID, order.Status)).Result()
+	}
+
+	// all orders packed in the stuck tx must be bumped together
+	var expectNum int
+	for _, orderID := range keeper.ok.GetProcessOrderListByType(ctx, sdk.OrderTypeWithdrawal) {
+		processOrder := keeper.ok.GetOrder(ctx, orderID)
+		if processOrder.GetOrderStatus() == sdk.OrderStatusSignFinish && bytes.Equal(getOrderRawData(processOrder), order.RawData) {
+			expectNum++
+		}
+	}
+	if expectNum != len(orderIDs) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("order number mismatch, expected:%v, have:%v", expectNum, len(orderIDs))).Result()
+	}
+
+	symbol := tokenInfo.Symbol.String()
+	chain := tokenInfo.Chain.String()
+	opCUAddr, _ := sdk.CUAddressFromBase58(order.OpCUaddress)
+
+	opCUAst := keeper.ik.GetCUIBCAsset(ctx, opCUAddr)
+	if opCUAst == nil {
+		return sdk.ErrInvalidAccount(fmt.Sprintf("CU %v does not exist", opCUAddr)).Result()
+	}
+
+	err = keeper.checkWithdrawalOpCU(opCUAst, chain, symbol, !tokenInfo.IsNonceBased, order.FromAddress)
+	if err != nil {
+		return err.Result()
+	}
+
+	//Retrieve gas Price
+	gasPrice := tokenInfo.GasPrice
+	if chain != symbol {
+		ti := keeper.tk.GetIBCToken(ctx, sdk.Symbol(chain))
+		if ti == nil {
+			return sdk.ErrInvalidSymbol(fmt.Sprintf("%s does not exist", chain)).Result()
+		}
+		gasPrice = ti.GasPrice
+	}
+	priceUpLimit := sdk.NewDecFromInt(gasPrice).Mul(PriceUpLimitRatio)
+
+	gasFeeBudget := sdk.ZeroInt()
+	for _, withdrawalOrder := range withdrawalOrders {
+		gasFeeBudget = gasFeeBudget.Add(withdrawalOrder.GasFee)
+	}
+
+	costFee := sdk.ZeroInt()
+	switch tokenInfo.TokenType {
+	case sdk.UtxoBased:
+		oldVins, err := keeper.cn.QueryUtxoInsFromData(chain, symbol, order.RawData)
+		if err != nil {
+			return sdk.ErrInvalidTx(err.Error()).Result()
+		}
+
+		vins, err := keeper.cn.QueryUtxoInsFromData(chain, symbol, rawData)
+		if err != nil {
+			return sdk.ErrInvalidTx(err.Error()).Result()
+		}
+
+		if len(vins) != len(oldVins) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("vin number mismatch, expected:%v, have:%v", len(oldVins), len(vins))).Result()
+		}
+
+		for i, vin := range vins {
+			if vin.Hash != oldVins[i].Hash || vin.Index != oldVins[i].Index {
+				return sdk.ErrInvalidTx(fmt.Sprintf("vin mismatch, expected:%v %v, have:%v %v", oldVins[i].Hash, oldVins[i].Index, vin.Hash, vin.Index)).Result()
+			}
+
+			item := keeper.ik.GetDeposit(ctx, symbol, opCUAddr, vin.Hash, vin.Index)
+			if item == sdk.DepositNil {
+				return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v does not exist", vin.Hash, vin.Index)).Result()
+			}
+
+			if item.GetStatus() != sdk.DepositItemStatusInProcess {
+				return sdk.ErrInvalidTx(fmt.Sprintf("vin %v %v status is %v", vin.Hash, vin.Index, item.GetStatus())).Result()
+			}
+
+			vin.Address = item.ExtAddress
+			vin.Amount = item.Amount
+		}
+
+		tx, hashes, err := keeper.cn.QueryUtxoTransactionFromData(chain, symbol, rawData, vins)
+		if err != nil {
+			return sdk.ErrInvalidTx(err.Error()).Result()
+		}
+
+		sdkErr := checkFeeBumpedUtxoTransaction(withdrawalOrders, tx, order.FromAddress)
+		if sdkErr != nil {
+			return sdkErr.Result()
+		}
+
+		size := sdk.EstimateSignedUtxoTxSize(len(tx.Vins), len(tx.Vouts)).ToDec()
+		price := sdk.NewDecFromInt(tx.CostFee).MulInt64(sdk.KiloBytes).Quo(size)
+		if price.GT(priceUpLimit) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("gas price is too high, actual:%v, uplimit:%v", price, priceUpLimit)).Result()
+		}
+
+		if len(hashes) != len(signHashes) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("signhashes's number mismatch, expected:%v, have:%v", len(hashes), len(signHashes))).Result()
+		}
+		for i := 0; i < len(hashes); i++ {
+			if !bytes.Equal(hashes[i], signHashes[i]) {
+				return sdk.ErrInvalidTx(fmt.Sprintf("mismatch hashes, expected:%v, have:%v", hashes[i], signHashes[i])).Result()
+			}
+		}
+		costFee = tx.CostFee
+
+	case sdk.AccountBased:
+		if len(signHashes) != sdk.LimitAccountBasedOrderNum || len(orderIDs) != sdk.LimitAccountBasedOrderNum {
+			return sdk.ErrInvalidTx(fmt.Sprintf("AccountBased token supports only one withdrawal at one time, ordernum:%v, signhashnum:%v", len(orderIDs), len(signHashes))).Result()
+		}
+
+		oldTx, _, err := keeper.cn.QueryAccountTransactionFromData(chain, symbol, order.RawData)
+		if err != nil {
+			return sdk.ErrInvalidTx(err.Error()).Result()
+		}
+
+		tx, hash, err := keeper.cn.QueryAccountTransactionFromData(chain, symbol, rawData)
+		if err != nil {
+			return sdk.ErrInvalidTx(err.Error()).Result()
+		}
+
+		if tx.Nonce != oldTx.Nonce {
+			return sdk.ErrInvalidTx(fmt.Sprintf("tx nonce mismatch, expected:%v, have:%v", oldTx.Nonce, tx.Nonce)).Result()
+		}
+
+		if tx.To != order.WithdrawToAddress {
+			return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected withdrawal to address:%v, expected:%v", tx.To, order.WithdrawToAddress)).Result()
+		}
+
+		if !tx.Amount.Equal(order.Amount) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected withdrawal Amount:%v, expected:%v", tx.Amount, order.Amount)).Result()
+		}
+
+		if tx.ContractAddress != oldTx.ContractAddress {
+			return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected withdrawal contract address:%v, expected:%v", tx.ContractAddress, oldTx.ContractAddress)).Result()
+		}
+
+		if !tx.GasLimit.Equal(oldTx.GasLimit) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("gas limit mismatch, expected:%v, have:%v", oldTx.GasLimit, tx.GasLimit)).Result()
+		}
+
+		if !tx.GasPrice.GT(oldTx.GasPrice) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("gas price is not bumped, actual:%v, replaced:%v", tx.GasPrice, oldTx.GasPrice)).Result()
+		}
+
+		if sdk.NewDecFromInt(tx.GasPrice).GT(priceUpLimit) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("gas price is too high, actual:%v, uplimit:%v", tx.GasPrice, priceUpLimit)).Result()
+		}
+
+		if !bytes.Equal(hash, signHashes[0]) {
+			return sdk.ErrInvalidTx(fmt.Sprintf("hash mismatch, expected:%v, have:%v", hash, signHashes[0])).Result()
+		}
+		costFee = tx.GasPrice.Mul(tx.GasLimit)
+
+	case sdk.AccountSharedBased:
+		return sdk.ErrInvalidTx("Not support AccountSharedBased temporary").Result()
+	}
+
+	if !costFee.GT(order.CostFee) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("cost fee is not bumped, actual:%v, replaced:%v", costFee, order.CostFee)).Result()
+	}
+
+	if costFee.GT(gasFeeBudget) {
+		return sdk.ErrGasOverflow(fmt.Sprintf("actual gas:%v > gas uplimit:%v", costFee, gasFeeBudget)).Result()
+	}
+
+	extraFee := costFee.Sub(order.CostFee)
+	if tokenInfo.TokenType == sdk.AccountBased {
+		//the replaced tx has locked its cost fee in opCU, lock the extra part
+		extraCoins := sdk.NewCoins(sdk.NewCoin(chain, extraFee))
+		have := opCUAst.GetAssetCoins()
+		if have.AmountOf(chain).LT(extraFee) {
+			return sdk.ErrInsufficientCoins(fmt.Sprintf("need:%v, actual have:%v", extraCoins, have)).Result()
+		}
+		opCUAst.SubAssetCoins(extraCoins)
+		opCUAst.AddAssetCoinsHold(extraCoins)
+		keeper.ik.SetCUIBCAsset(ctx, opCUAst)
+	}
+
+	replacedTxHash := order.Txhash
+	bumpedFee := order.GetBumpedFee().Add(extraFee)
+	for _, orderID := range orderIDs {
+		withdrawalOrder := keeper.ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+		withdrawalOrder.ReplacedTxHashes = append(withdrawalOrder.ReplacedTxHashes, replacedTxHash)
+		withdrawalOrder.ReplacedSignedTxs = append(withdrawalOrder.ReplacedSignedTxs, withdrawalOrder.SignedTx)
+		withdrawalOrder.BumpedFee = bumpedFee
+		withdrawalOrder.CostFee = costFee
+		withdrawalOrder.Status = sdk.OrderStatusWaitSign
+		withdrawalOrder.Txhash = ""
+		withdrawalOrder.SignedTx = nil
+		withdrawalOrder.RawData = make([]byte, len(rawData))
+		copy(withdrawalOrder.RawData, rawData)
+		keeper.ok.SetOrder(ctx, withdrawalOrder)
+	}
+
+	var flows []sdk.Flow
+	flows = append(flows, keeper.rk.NewOrderFlow(sdk.Symbol(symbol), order.GetCUAddress(), order.GetID(), sdk.OrderTypeWithdrawal, sdk.OrderStatusWaitSign))
+	flows = append(flows, keeper.rk.NewWithdrawalFeeBumpFlow(orderIDs, replacedTxHash, rawData, costFee, bumpedFee))
+
+	result := sdk.Result{}
+	receipt := keeper.rk.NewReceipt(sdk.CategoryTypeWithdrawal, flows)
+	keeper.rk.SaveReceiptToResult(receipt, &result)
+	return result
+}
+
+func checkFeeBumpedUtxoTransaction(withdrawalOrders []*sdk.OrderWithdrawal, tx *chainnode.ExtUtxoTransaction, fromAddr string) sdk.Error {
+	if len(tx.Vouts) < len(withdrawalOrders) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("vout number mismatch, expected at least:%v, have:%v", len(withdrawalOrders), len(tx.Vouts)))
+	}
+
+	inAmt := sdk.ZeroInt()
+	for _, vin := range tx.Vins {
+		if fromAddr != vin.Address {
+			return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected Vin address:%v, expected:%v", vin.Address, fromAddr))
+		}
+		inAmt = inAmt.Add(vin.Amount)
+	}
+
+	outAmt := sdk.ZeroInt()
+	for i, vout := range tx.Vouts {
+		if i < len(withdrawalOrders) {
+			withdrawalOrder := withdrawalOrders[i]
+			if vout.Address != withdrawalOrder.WithdrawToAddress {
+				return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected Vout address:%v, expected:%v", vout.Address, withdrawalOrder.WithdrawToAddress))
+			}
+			if !vout.Amount.Equal(withdrawalOrder.Amount) {
+				return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected Vout Amount:%v, expected:%v", vout.Amount, withdrawalOrder.Amount))
+			}
+		} else if vout.Address != fromAddr {
+			return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected Changeback address:%v, expected:%v", vout.Address, fromAddr))
+		}
+		outAmt = outAmt.Add(vout.Amount)
+	}
+
+	calculatedFee := inAmt.Sub(outAmt)
+	if !tx.CostFee.Equal(calculatedFee) {
+		return sdk.ErrInvalidTx(fmt.Sprintf("Unexpected Gas:%v, expected:%v", calculatedFee, tx.CostFee))
+	}
+
+	return nil
+}
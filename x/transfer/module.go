@@ -127,6 +127,10 @@ func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
 func (AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {}
 
 // module end-block
-func (AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	am.keeper.CancelTimeoutUtxoConsolidations(ctx)
+	am.keeper.ScheduleUtxoConsolidation(ctx)
+	am.keeper.ScheduleOpCURebalance(ctx)
+	am.keeper.ExecuteScheduledTransfers(ctx)
 	return []abci.ValidatorUpdate{}
 }
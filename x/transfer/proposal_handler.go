@@ -0,0 +1,42 @@
+package transfer
+
+import (
+	"fmt"
+	"strconv"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	govtypes "github.com/hbtc-chain/bhchain/x/gov/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/keeper"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func handleFreezeAddressesProposal(ctx sdk.Context, k keeper.Keeper, proposal types.FreezeAddressesProposal) sdk.Result {
+	ctx.Logger().Info("handleFreezeAddressesProposal", "proposal", proposal)
+
+	for _, addr := range proposal.Addresses {
+		k.SetAddressFrozen(ctx, addr, proposal.Freeze)
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeExecuteFreezeAddressesProposal,
+				sdk.NewAttribute(types.AttributeKeyAddress, addr.String()),
+				sdk.NewAttribute(types.AttributeKeyFreeze, strconv.FormatBool(proposal.Freeze)),
+			),
+		)
+	}
+
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func NewTransferProposalHandler(k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Result {
+		switch c := content.(type) {
+		case types.FreezeAddressesProposal:
+			return handleFreezeAddressesProposal(ctx, k, c)
+
+		default:
+			errMsg := fmt.Sprintf("unrecognized transfer proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
@@ -348,6 +348,10 @@ func TestOrderRetryPunishment(t *testing.T) {
 			result := keeper.OrderRetry(ctx, sdk.CUAddress(validator.OperatorAddress), []string{orderID}, 1, evidences)
 			require.Equal(t, sdk.CodeOK, result.Code, result)
 		}
+		// the retried order restarts its timeout
+		order := input.ok.GetOrder(ctx, orderID).(*sdk.OrderKeyGen)
+		require.Equal(t, sdk.OrderStatusBegin, order.Status)
+		require.Equal(t, uint64(100), order.StageHeight)
 	}
 	ctx = ctx.WithBlockHeight(200)
 	input.evidenceKeeper.RecordMisbehaviourVoter(ctx)
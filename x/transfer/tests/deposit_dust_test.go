@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestDustDepositAggregationEth(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	ik := input.ik
+	tk := input.tk
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := symbol
+
+	cuAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	toAddr := "0xc96d141c9110a8E61eD62caaD8A7c858dB15B82c"
+	mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+	cu := newTestCU(ck.GetCU(ctx, cuAddr))
+	cu.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), 1)
+	require.Nil(t, cu.AddAsset(symbol, toAddr, 1))
+	ck.SetCU(ctx, cu)
+
+	deposit := func(hash string, amt sdk.Int) string {
+		orderID := uuid.NewV1().String()
+		result := keeper.Deposit(ctx, cuAddr, cuAddr, sdk.Symbol(symbol), toAddr, hash, 0, amt, orderID, "")
+		require.Equal(t, sdk.CodeOK, result.Code, result)
+		for i := 0; i < 3; i++ {
+			result = keeper.ConfirmedDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID}, []string{})
+			require.Equal(t, sdk.CodeOK, result.Code)
+		}
+		return orderID
+	}
+	depositStatus := func(hash string) sdk.DepositItemStatus {
+		return ik.GetDeposit(ctx, symbol, cuAddr, hash, 0).GetStatus()
+	}
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.NeedCollectFee = true
+	tk.SetToken(ctx, tokenInfo)
+	threshold := tokenInfo.DepositThreshold
+	credited := func(amt sdk.Int) sdk.Int {
+		return amt.Sub(tokenInfo.DustCollectFee(amt).Amount)
+	}
+
+	result := keeper.Deposit(ctx, cuAddr, cuAddr, sdk.Symbol(symbol), toAddr, "0x00", 0, sdk.ZeroInt(), uuid.NewV1().String(), "")
+	require.Equal(t, sdk.CodeInsufficientCoins, result.Code)
+	// too small to aggregate
+	dustThreshold := threshold.AddRaw(types.MaxDustDepositsPerAddress - 1).QuoRaw(types.MaxDustDepositsPerAddress)
+	result = keeper.Deposit(ctx, cuAddr, cuAddr, sdk.Symbol(symbol), toAddr, "0x00", 0, dustThreshold.SubRaw(1), uuid.NewV1().String(), "")
+	require.Equal(t, sdk.CodeInsufficientCoins, result.Code)
+
+	// dust is tracked but not credited
+	dust1, dust2 := threshold.QuoRaw(4), threshold.QuoRaw(2)
+	deposit("0x01", dust1)
+	deposit("0x02", dust2)
+	require.Equal(t, sdk.DepositItemStatusDust, depositStatus("0x01"))
+	require.Equal(t, sdk.DepositItemStatusDust, depositStatus("0x02"))
+	require.True(t, keeper.GetBalance(ctx, cuAddr, symbol).IsZero())
+	require.Equal(t, dust1.Add(dust2), newTestCU(ck.GetCU(ctx, cuAddr)).GetAssetCoins().AmountOf(symbol))
+
+	// an invalidated dust deposit is left out of the aggregation
+	orderID := deposit("0x05", threshold.QuoRaw(8))
+	for i := 0; i < 3; i++ {
+		result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID})
+		require.Equal(t, sdk.CodeOK, result.Code, result)
+	}
+	require.Equal(t, sdk.DepositNil, ik.GetDeposit(ctx, symbol, cuAddr, "0x05", 0))
+	require.True(t, keeper.GetDepositDeficit(ctx, cuAddr, symbol).IsZero())
+	require.Equal(t, dust1.Add(dust2), newTestCU(ck.GetCU(ctx, cuAddr)).GetAssetCoins().AmountOf(symbol))
+
+	// aggregated once the dust sums up to the threshold
+	deposit("0x03", dust2)
+	for _, hash := range []string{"0x01", "0x02", "0x03"} {
+		require.Equal(t, sdk.DepositItemStatusWaitCollect, depositStatus(hash))
+	}
+	expected := credited(dust1).Add(credited(dust2)).Add(credited(dust2))
+	require.Equal(t, expected, keeper.GetBalance(ctx, cuAddr, symbol))
+	require.True(t, expected.LT(dust1.Add(dust2).Add(dust2)))
+
+	// dust joins a pending collect of the address
+	deposit("0x04", dust1)
+	require.Equal(t, sdk.DepositItemStatusWaitCollect, depositStatus("0x04"))
+	require.Equal(t, expected.Add(credited(dust1)), keeper.GetBalance(ctx, cuAddr, symbol))
+}
+
+func TestDustDepositLimitErc20(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	ik := input.ik
+	tk := input.tk
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "usdt"
+	chain := "eth"
+
+	cuAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	toAddr := "0xc96d141c9110a8E61eD62caaD8A7c858dB15B82c"
+	mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+	cu := newTestCU(ctx, input.trk, input.ik, ck.GetCU(ctx, cuAddr))
+	cu.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), 1)
+	require.Nil(t, cu.AddAsset(chain, toAddr, 1))
+	ck.SetCU(ctx, cu)
+
+	deposit := func(hash string, amt sdk.Int) sdk.Result {
+		orderID := uuid.NewV1().String()
+		result := keeper.Deposit(ctx, cuAddr, cuAddr, sdk.Symbol(symbol), toAddr, hash, 0, amt, orderID, "")
+		if result.Code != sdk.CodeOK {
+			return result
+		}
+		for i := 0; i < 3; i++ {
+			result = keeper.ConfirmedDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID}, []string{})
+			require.Equal(t, sdk.CodeOK, result.Code)
+		}
+		return result
+	}
+
+	// the collect fee is paid in eth, the dust stays uncollected without eth
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.NeedCollectFee = true
+	tk.SetToken(ctx, tokenInfo)
+	require.True(t, tokenInfo.CollectFee().IsPositive())
+	require.True(t, keeper.GetBalance(ctx, cuAddr, chain).IsZero())
+
+	dust := tokenInfo.DepositThreshold.AddRaw(types.MaxDustDepositsPerAddress - 1).QuoRaw(types.MaxDustDepositsPerAddress)
+	for i := 0; i < types.MaxDustDepositsPerAddress; i++ {
+		hash := fmt.Sprintf("0x%02x", i)
+		result := deposit(hash, dust)
+		require.Equal(t, sdk.CodeOK, result.Code, result)
+		require.Equal(t, sdk.DepositItemStatusDust, ik.GetDeposit(ctx, symbol, cuAddr, hash, 0).GetStatus())
+	}
+	result := deposit("0xff", dust)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// deposits above the threshold are not limited, and take the dust along
+	_, _, err = keeper.AddCoins(ctx, cuAddr, sdk.NewCoins(sdk.NewCoin(chain, tokenInfo.CollectFee().Amount.MulRaw(types.MaxDustDepositsPerAddress))))
+	require.Nil(t, err)
+	result = deposit("0xff", tokenInfo.DepositThreshold)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	for i := 0; i < types.MaxDustDepositsPerAddress; i++ {
+		require.Equal(t, sdk.DepositItemStatusWaitCollect, ik.GetDeposit(ctx, symbol, cuAddr, fmt.Sprintf("0x%02x", i), 0).GetStatus())
+	}
+	result = deposit("0xfe", dust)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+}
@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/transfer/keeper"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestInvalidateDepositBtcToUserCU(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	ok := input.ok
+	rk := input.rk
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+	chain := symbol
+	pubkey := ed25519.GenPrivKey().PubKey()
+
+	toCUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	otherCUAddr, err := sdk.CUAddressFromBase58("HBCiopN1Vw38QyjEnfJ7nKeVgMSi9sFjQfkg")
+	require.Nil(t, err)
+
+	hash := "9ae3c919d84f4b72802de6f4f4aa0d88abcc9fd57315ddf27b8e25f032e4a180"
+	index := uint64(1)
+	toAddr := "mhoGjKn5xegDXL6u5LFSUQdm5ozdM6xao9"
+	amt := sdk.NewInt(85475551)
+	orderID := uuid.NewV1().String()
+	mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+
+	toCU := newTestCU(ck.GetCU(ctx, toCUAddr))
+	toCU.SetAssetPubkey(pubkey.Bytes(), 1)
+	err = toCU.AddAsset(symbol, toAddr, 1)
+	require.Nil(t, err)
+	ck.SetCU(ctx, toCU)
+
+	result := keeper.Deposit(ctx, toCUAddr, toCUAddr, sdk.Symbol(symbol), toAddr, hash, index, amt, orderID, "")
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	// invalidating an unconfirmed deposit is ignored
+	for i := 0; i < 3; i++ {
+		result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID})
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+	require.Equal(t, sdk.DepositUnconfirm, int(ok.GetOrder(ctx, orderID).(*sdk.OrderCollect).DepositStatus))
+
+	for i := 0; i < 3; i++ {
+		result = keeper.ConfirmedDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID}, []string{})
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+	require.Equal(t, sdk.DepositConfirmed, int(ok.GetOrder(ctx, orderID).(*sdk.OrderCollect).DepositStatus))
+	require.Equal(t, 1, len(ik.GetDepositList(ctx, symbol, toCUAddr)))
+
+	credited := keeper.GetBalance(ctx, toCUAddr, symbol)
+	require.True(t, credited.IsPositive())
+
+	// spend part of the credited coins before the reorg is found
+	spent := sdk.NewInt(10000000)
+	_, _, sdkErr := keeper.SendCoin(ctx, toCUAddr, otherCUAddr, sdk.NewCoin(symbol, spent))
+	require.Nil(t, sdkErr)
+
+	// not a key node
+	result = keeper.InvalidateDeposit(ctx, toCUAddr, []string{orderID})
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// the deposit can not be invalidated while it is being collected, the tx fails without voting
+	require.Nil(t, ik.SetDepositStatus(ctx, symbol, toCUAddr, hash, index, sdk.DepositItemStatusInProcess))
+	for i := 0; i < 3; i++ {
+		result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID})
+		require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	}
+	require.Nil(t, ik.SetDepositStatus(ctx, symbol, toCUAddr, hash, index, sdk.DepositItemStatusWaitCollect))
+
+	for i := 0; i < 2; i++ {
+		result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID})
+		require.Equal(t, sdk.CodeOK, result.Code)
+		_, err = rk.GetReceiptFromResult(&result)
+		require.NotNil(t, err)
+	}
+	require.False(t, keeper.IsWithdrawalFrozen(ctx, toCUAddr))
+
+	result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[2].OperatorAddress), []string{orderID})
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	//check order
+	order := ok.GetOrder(ctx, orderID)
+	require.Equal(t, sdk.OrderStatusFinish, order.GetOrderStatus())
+	require.Equal(t, sdk.DepositInvalidated, int(order.(*sdk.OrderCollect).DepositStatus))
+
+	//check receipt
+	receipt, err := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, sdk.CategoryTypeDeposit, receipt.Category)
+	require.Equal(t, 4, len(receipt.Flows))
+
+	of, valid := receipt.Flows[0].(sdk.OrderFlow)
+	require.True(t, valid)
+	require.Equal(t, sdk.OrderTypeDeposit, of.OrderType)
+	require.Equal(t, sdk.OrderStatusFinish, of.OrderStatus)
+
+	bf, valid := receipt.Flows[1].(sdk.BalanceFlow)
+	require.True(t, valid)
+	require.Equal(t, credited.Sub(spent).Neg(), bf.BalanceChange)
+
+	bf, valid = receipt.Flows[2].(sdk.BalanceFlow)
+	require.True(t, valid)
+	require.Equal(t, credited.Sub(spent), bf.BalanceOnHoldChange)
+
+	dif, valid := receipt.Flows[3].(sdk.DepositInvalidatedFlow)
+	require.True(t, valid)
+	require.Equal(t, orderID, dif.OrderID)
+	require.Equal(t, toCUAddr.String(), dif.CuAddress)
+	require.Equal(t, hash, dif.Txhash)
+	require.Equal(t, index, dif.Index)
+	require.Equal(t, amt, dif.Amount)
+	require.Equal(t, credited.Sub(spent), dif.HeldAmount)
+	require.Equal(t, spent, dif.Deficit)
+
+	//check CU's coins, assetcoins and deposit items
+	require.Equal(t, sdk.ZeroInt(), keeper.GetBalance(ctx, toCUAddr, symbol))
+	require.Equal(t, credited.Sub(spent), keeper.GetHoldBalance(ctx, toCUAddr, symbol))
+	depositDeficit := keeper.GetDepositDeficit(ctx, toCUAddr, symbol)
+	require.Equal(t, credited.Sub(spent), depositDeficit.Held)
+	require.Equal(t, spent, depositDeficit.Deficit)
+	require.Equal(t, sdk.ZeroInt(), newTestCU(ck.GetCU(ctx, toCUAddr)).GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, 0, len(ik.GetDepositList(ctx, symbol, toCUAddr)))
+	require.True(t, keeper.IsWithdrawalFrozen(ctx, toCUAddr))
+
+	// 4th vote does nothing
+	result = keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[3].OperatorAddress), []string{orderID})
+	require.Equal(t, sdk.CodeOK, result.Code)
+	_, err = rk.GetReceiptFromResult(&result)
+	require.NotNil(t, err)
+
+	// withdrawal is frozen
+	result = keeper.Withdrawal(ctx, toCUAddr, "mxUjBw6aWvGjcWH6JGvpWC8D9GxuQJEDG7", uuid.NewV1().String(), symbol, sdk.NewInt(100), sdk.NewInt(10000))
+	require.Equal(t, sdk.CodeTransactionIsNotEnabled, result.Code)
+
+	res := queryDepositDeficit(t, input, toCUAddr)
+	require.True(t, res.WithdrawalFrozen)
+	require.Equal(t, []types.DepositDeficit{depositDeficit}, res.Deficits)
+
+	// coins credited later pay back the spent part first, they can not be sent away
+	half := spent.QuoRaw(2)
+	_, _, sdkErr = keeper.SendCoin(ctx, otherCUAddr, toCUAddr, sdk.NewCoin(symbol, half))
+	require.Nil(t, sdkErr)
+	require.Equal(t, sdk.ZeroInt(), keeper.GetBalance(ctx, toCUAddr, symbol))
+	require.Equal(t, spent.Sub(half), keeper.GetDepositDeficit(ctx, toCUAddr, symbol).Deficit)
+	result = keeper.SettleDepositDeficit(ctx, toCUAddr, symbol)
+	require.Equal(t, sdk.CodeInsufficientCoins, result.Code)
+
+	_, _, sdkErr = keeper.SendCoin(ctx, otherCUAddr, toCUAddr, sdk.NewCoin(symbol, spent.Sub(half)))
+	require.Nil(t, sdkErr)
+	require.Equal(t, sdk.ZeroInt(), keeper.GetBalance(ctx, toCUAddr, symbol))
+	require.Equal(t, sdk.ZeroInt(), keeper.GetDepositDeficit(ctx, toCUAddr, symbol).Deficit)
+	require.True(t, keeper.IsWithdrawalFrozen(ctx, toCUAddr))
+
+	// settle the held part
+	result = keeper.SettleDepositDeficit(ctx, toCUAddr, symbol)
+	require.Equal(t, sdk.CodeOK, result.Code, result.Log)
+	receipt, err = rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(receipt.Flows))
+
+	require.Equal(t, sdk.ZeroInt(), keeper.GetBalance(ctx, toCUAddr, symbol))
+	require.Equal(t, sdk.ZeroInt(), keeper.GetHoldBalance(ctx, toCUAddr, symbol))
+	require.False(t, keeper.IsWithdrawalFrozen(ctx, toCUAddr))
+	res = queryDepositDeficit(t, input, toCUAddr)
+	require.False(t, res.WithdrawalFrozen)
+	require.Empty(t, res.Deficits)
+
+	// nothing left to settle
+	result = keeper.SettleDepositDeficit(ctx, toCUAddr, symbol)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+}
+
+func queryDepositDeficit(t *testing.T, input testInput, addr sdk.CUAddress) types.ResDepositDeficit {
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDepositDeficit),
+		Data: input.cdc.MustMarshalJSON(types.NewQueryDepositDeficitParams(addr)),
+	}
+	bz, err := keeper.NewQuerier(input.k)(input.ctx, []string{types.QueryDepositDeficit}, req)
+	require.Nil(t, err)
+
+	var res types.ResDepositDeficit
+	require.NoError(t, input.cdc.UnmarshalJSON(bz, &res))
+	return res
+}
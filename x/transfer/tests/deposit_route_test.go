@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+)
+
+func TestDepositRouteEth(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	rk := input.rk
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := symbol
+
+	ownerCUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	toAddr := "0xc96d141c9110a8E61eD62caaD8A7c858dB15B82c"
+	mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+	ownerCU := newTestCU(ck.GetCU(ctx, ownerCUAddr))
+	ownerCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), 1)
+	require.Nil(t, ownerCU.AddAsset(symbol, toAddr, 1))
+	ck.SetCU(ctx, ownerCU)
+
+	subCUAddr := sdk.NewCUAddress()
+	opCUAddr, err := sdk.CUAddressFromBase58("HBCLXBebMwEWaEZYsqJij7xcpBayzJqdrKJP")
+	require.Nil(t, err)
+
+	result := keeper.SetDepositRoute(ctx, ownerCUAddr, "", subCUAddr)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.SetDepositRoute(ctx, ownerCUAddr, "user-a", ownerCUAddr)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.SetDepositRoute(ctx, ownerCUAddr, "user-a", opCUAddr)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.SetDepositRoute(ctx, opCUAddr, "user-a", subCUAddr)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	var orderID string
+	deposit := func(hash, memo string, amt sdk.Int) sdk.Result {
+		orderID = uuid.NewV1().String()
+		result := keeper.Deposit(ctx, ownerCUAddr, ownerCUAddr, sdk.Symbol(symbol), toAddr, hash, 0, amt, orderID, memo)
+		require.Equal(t, sdk.CodeOK, result.Code)
+		for i := 0; i < 3; i++ {
+			result = keeper.ConfirmedDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID}, []string{})
+			require.Equal(t, sdk.CodeOK, result.Code)
+		}
+		return result
+	}
+	amt := sdk.TokensFromConsensusPower(1)
+
+	// without any route, the owner is credited
+	deposit("0x01", "user-a", amt)
+	credited := keeper.GetBalance(ctx, ownerCUAddr, symbol)
+	require.True(t, credited.IsPositive())
+
+	result = keeper.SetDepositRoute(ctx, ownerCUAddr, "user-a", subCUAddr)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.Equal(t, subCUAddr, keeper.GetDepositRoute(ctx, ownerCUAddr, "user-a"))
+
+	// known memo is routed to the sub CU
+	result = deposit("0x02", "user-a", amt)
+	routedOrderID := orderID
+	require.Equal(t, subCUAddr, input.ok.GetOrder(ctx, routedOrderID).(*sdk.OrderCollect).CreditedTo)
+	require.Equal(t, credited, keeper.GetBalance(ctx, ownerCUAddr, symbol))
+	require.Equal(t, amt, keeper.GetBalance(ctx, subCUAddr, symbol))
+	receipt, err := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	rf, valid := receipt.Flows[len(receipt.Flows)-1].(sdk.DepositRoutedFlow)
+	require.True(t, valid)
+	require.Equal(t, subCUAddr.String(), rf.ToCU)
+	require.Equal(t, "user-a", rf.Memo)
+	require.Equal(t, amt, rf.Amount)
+	require.False(t, rf.Suspense)
+
+	// unknown memo goes to suspense
+	result = deposit("0x03", "user-b", amt)
+	suspenseOrderID := orderID
+	require.True(t, input.ok.GetOrder(ctx, suspenseOrderID).(*sdk.OrderCollect).CreditedToSuspense)
+	require.Equal(t, credited, keeper.GetBalance(ctx, ownerCUAddr, symbol))
+	require.Equal(t, amt, keeper.GetSuspenseBalance(ctx, ownerCUAddr, symbol))
+	receipt, err = rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	rf, valid = receipt.Flows[len(receipt.Flows)-1].(sdk.DepositRoutedFlow)
+	require.True(t, valid)
+	require.True(t, rf.Suspense)
+
+	// the asset stays with the owner
+	require.Equal(t, amt.MulRaw(3), newTestCU(ck.GetCU(ctx, ownerCUAddr)).GetAssetCoins().AmountOf(symbol))
+
+	// suspense is a liability of the owner
+	report, sdkErr := keeper.GetReserveReport(ctx, symbol, nil, false)
+	require.Nil(t, sdkErr)
+	require.Equal(t, credited.Add(amt).Add(amt), report.Liabilities)
+
+	// reassign suspense
+	sub2CUAddr := sdk.NewCUAddress()
+	result = keeper.ReassignSuspense(ctx, ownerCUAddr, sub2CUAddr, symbol, amt.AddRaw(1))
+	require.Equal(t, sdk.CodeInsufficientCoins, result.Code)
+	result = keeper.ReassignSuspense(ctx, ownerCUAddr, sub2CUAddr, symbol, amt)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.True(t, keeper.GetSuspenseBalance(ctx, ownerCUAddr, symbol).IsZero())
+	require.Equal(t, amt, keeper.GetBalance(ctx, sub2CUAddr, symbol))
+	receipt, err = rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	sf, valid := receipt.Flows[0].(sdk.SuspenseReassignedFlow)
+	require.True(t, valid)
+	require.Equal(t, sub2CUAddr.String(), sf.ToCU)
+
+	invalidate := func(orderID string) {
+		for i := 0; i < 3; i++ {
+			result := keeper.InvalidateDeposit(ctx, sdk.CUAddress(validators[i].OperatorAddress), []string{orderID})
+			require.Equal(t, sdk.CodeOK, result.Code)
+		}
+		require.Equal(t, sdk.DepositInvalidated, int(input.ok.GetOrder(ctx, orderID).(*sdk.OrderCollect).DepositStatus))
+	}
+
+	// invalidating a deposit still in the suspense balance takes it back from there
+	deposit("0x04", "user-c", amt)
+	require.Equal(t, amt, keeper.GetSuspenseBalance(ctx, ownerCUAddr, symbol))
+	invalidate(orderID)
+	require.True(t, keeper.GetSuspenseBalance(ctx, ownerCUAddr, symbol).IsZero())
+	require.Equal(t, credited, keeper.GetBalance(ctx, ownerCUAddr, symbol))
+	require.True(t, keeper.GetDepositDeficit(ctx, ownerCUAddr, symbol).IsZero())
+	require.False(t, keeper.IsWithdrawalFrozen(ctx, ownerCUAddr))
+
+	// invalidating a routed deposit holds the coins of the sub CU
+	invalidate(routedOrderID)
+	require.Equal(t, credited, keeper.GetBalance(ctx, ownerCUAddr, symbol))
+	require.True(t, keeper.GetBalance(ctx, subCUAddr, symbol).IsZero())
+	require.Equal(t, amt, keeper.GetHoldBalance(ctx, subCUAddr, symbol))
+	require.Equal(t, amt, keeper.GetDepositDeficit(ctx, subCUAddr, symbol).Held)
+	require.True(t, keeper.IsWithdrawalFrozen(ctx, subCUAddr))
+	require.False(t, keeper.IsWithdrawalFrozen(ctx, ownerCUAddr))
+
+	// the suspense balance of an invalidated deposit has been reassigned, it is a deficit of the owner
+	invalidate(suspenseOrderID)
+	require.Equal(t, credited, keeper.GetBalance(ctx, ownerCUAddr, symbol))
+	require.Equal(t, amt, keeper.GetDepositDeficit(ctx, ownerCUAddr, symbol).Deficit)
+	require.True(t, keeper.IsWithdrawalFrozen(ctx, ownerCUAddr))
+
+	// remove the route
+	result = keeper.SetDepositRoute(ctx, ownerCUAddr, "user-a", nil)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.Nil(t, keeper.GetDepositRoute(ctx, ownerCUAddr, "user-a"))
+	require.Equal(t, 0, len(keeper.GetDepositRoutes(ctx, ownerCUAddr)))
+}
@@ -1185,9 +1185,9 @@ func TestDespoistEthToUserCUError(t *testing.T) {
 	require.Equal(t, sdk.CodeTransactionIsNotEnabled, result.Code)
 	require.Equal(t, 0, len(ok.GetProcessOrderList(ctx)))
 
-	//amt < DepositThreshold
+	//amt is zero
 	keeper.SetSendEnabled(ctx, true)
-	result = keeper.Deposit(ctx, fromCUAddr, toCUAddr, sdk.Symbol(symbol), toAddr, hash, 0, tokenInfo.DepositThreshold.Sub(sdk.NewInt(1)), orderID, memo)
+	result = keeper.Deposit(ctx, fromCUAddr, toCUAddr, sdk.Symbol(symbol), toAddr, hash, 0, sdk.ZeroInt(), orderID, memo)
 	require.Equal(t, sdk.CodeInsufficientCoins, result.Code)
 
 	//toAddr is different
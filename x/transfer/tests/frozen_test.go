@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	mappingtypes "github.com/hbtc-chain/bhchain/x/mapping/types"
+	"github.com/hbtc-chain/bhchain/x/transfer"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestFreezeAddressesProposal(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx.WithBlockHeight(100)
+	handler := transfer.NewTransferProposalHandler(&keeper)
+
+	frozen := sdk.NewCUAddress()
+	other := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, frozen, amount)
+	require.Nil(t, err)
+
+	proposal := types.NewFreezeAddressesProposal("freeze", "compliance", []sdk.CUAddress{frozen}, true)
+	require.Nil(t, proposal.ValidateBasic())
+	result := handler(ctx, proposal)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.True(t, keeper.IsAddressFrozen(ctx, frozen))
+	require.False(t, keeper.IsAddressFrozen(ctx, other))
+	require.Equal(t, []sdk.CUAddress{frozen}, keeper.GetFrozenAddresses(ctx))
+
+	require.True(t, keeper.IsFrozenRestrictedMsg(types.NewMsgSend(frozen, other, amount)))
+	require.False(t, keeper.IsFrozenRestrictedMsg(types.NewMsgCancelScheduledTransfer(frozen.String(), 1)))
+
+	msgHandler := transfer.NewHandler(keeper)
+	result = msgHandler(ctx, types.NewMsgSend(frozen, other, amount))
+	require.Equal(t, types.CodeAddressFrozen, result.Code)
+	_, err = keeper.InputOutputCoins(ctx, []types.Input{types.NewInput(frozen, amount)}, []types.Output{types.NewOutput(other, amount)})
+	require.NotNil(t, err)
+	result = keeper.ScheduleTransfer(ctx, frozen, other, amount, false, 110, 0, 1)
+	require.Equal(t, types.CodeAddressFrozen, result.Code)
+
+	// spending through the keeper by other modules is rejected as well
+	_, _, err = keeper.SendCoins(ctx, frozen, other, amount)
+	require.Equal(t, types.CodeAddressFrozen, err.Code())
+	_, _, err = keeper.SubCoins(ctx, frozen, amount)
+	require.Equal(t, types.CodeAddressFrozen, err.Code())
+	require.True(t, keeper.IsFrozenRestrictedMsg(mappingtypes.MsgMappingSwap{From: frozen}))
+	require.False(t, keeper.IsFrozenRestrictedMsg(types.NewMsgSetDepositRoute(frozen.String(), "memo", other.String())))
+
+	// still able to receive
+	_, _, err = keeper.AddCoins(ctx, frozen, amount)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(200), keeper.GetBalance(ctx, frozen, "eth"))
+
+	result = handler(ctx, types.NewFreezeAddressesProposal("unfreeze", "resolved", []sdk.CUAddress{frozen}, false))
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.False(t, keeper.IsAddressFrozen(ctx, frozen))
+	result = msgHandler(ctx, types.NewMsgSend(frozen, other, amount))
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+}
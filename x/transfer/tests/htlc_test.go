@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestHTLCClaim(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	rk := input.rk
+	ctx := input.ctx.WithBlockHeight(100)
+
+	sender := sdk.NewCUAddress()
+	receiver := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1000)))
+	_, _, err := keeper.AddCoins(ctx, sender, amount)
+	require.Nil(t, err)
+
+	preimage := sha256.Sum256([]byte("secret"))
+	hashlock := types.HTLCHashlock(preimage[:])
+
+	result := keeper.CreateHTLC(ctx, sender, receiver, amount, hashlock, types.MinHTLCTimeLock-1)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.CreateHTLC(ctx, sender, receiver, amount.Add(amount), hashlock, types.MinHTLCTimeLock)
+	require.NotEqual(t, sdk.CodeOK, result.Code)
+
+	result = keeper.CreateHTLC(ctx, sender, receiver, amount, hashlock, types.MinHTLCTimeLock)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.True(t, keeper.GetBalance(ctx, sender, "eth").IsZero())
+	require.Equal(t, sdk.NewInt(1000), keeper.GetHoldBalance(ctx, sender, "eth"))
+	htlc := keeper.GetHTLC(ctx, sender, hashlock)
+	require.NotNil(t, htlc)
+	require.Equal(t, uint64(100+types.MinHTLCTimeLock), htlc.ExpireHeight)
+
+	// the same hashlock can not be reused while it's active
+	result = keeper.CreateHTLC(ctx, sender, receiver, amount, hashlock, types.MinHTLCTimeLock)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// another sender locking under the same hashlock does not collide
+	other := sdk.NewCUAddress()
+	_, _, err = keeper.AddCoins(ctx, other, amount)
+	require.Nil(t, err)
+	result = keeper.CreateHTLC(ctx, other, receiver, amount, hashlock, types.MinHTLCTimeLock*2)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.Equal(t, other, keeper.GetHTLC(ctx, other, hashlock).Sender)
+	require.Equal(t, sender, keeper.GetHTLC(ctx, sender, hashlock).Sender)
+
+	// not refundable before expiry
+	result = keeper.RefundHTLC(ctx, sender, hashlock)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	wrong := sha256.Sum256([]byte("wrong"))
+	result = keeper.ClaimHTLC(ctx, sender, hashlock, wrong[:])
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.ClaimHTLC(ctx, sender, hashlock, preimage[:])
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.True(t, keeper.GetHoldBalance(ctx, sender, "eth").IsZero())
+	require.Equal(t, sdk.NewInt(1000), keeper.GetBalance(ctx, receiver, "eth"))
+	htlc = keeper.GetHTLC(ctx, sender, hashlock)
+	require.NotNil(t, htlc)
+	require.True(t, htlc.IsClaimed())
+	require.Equal(t, preimage[:], htlc.Preimage)
+
+	receipt, err1 := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err1)
+	require.Equal(t, sdk.CategoryTypeHTLC, receipt.Category)
+	cf, valid := receipt.Flows[0].(sdk.HTLCClaimedFlow)
+	require.True(t, valid)
+	require.Equal(t, receiver.String(), cf.Receiver)
+
+	// claimed only once
+	result = keeper.ClaimHTLC(ctx, sender, hashlock, preimage[:])
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// the claimed hashlock can not be reused by the sender, nor refunded
+	_, _, err = keeper.AddCoins(ctx, sender, amount)
+	require.Nil(t, err)
+	result = keeper.CreateHTLC(ctx, sender, receiver, amount, hashlock, types.MinHTLCTimeLock)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.RefundHTLC(ctx.WithBlockHeight(100+types.MinHTLCTimeLock), sender, hashlock)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+}
+
+func TestHTLCRefund(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx.WithBlockHeight(100)
+
+	sender := sdk.NewCUAddress()
+	receiver := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1000)))
+	_, _, err := keeper.AddCoins(ctx, sender, amount)
+	require.Nil(t, err)
+
+	preimage := sha256.Sum256([]byte("secret"))
+	hashlock := types.HTLCHashlock(preimage[:])
+
+	result := keeper.CreateHTLC(ctx, sender, receiver, amount, hashlock, types.MinHTLCTimeLock)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+
+	// not claimable after expiry
+	ctx = ctx.WithBlockHeight(100 + types.MinHTLCTimeLock)
+	result = keeper.ClaimHTLC(ctx, sender, hashlock, preimage[:])
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.RefundHTLC(ctx, sender, hashlock)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.Equal(t, sdk.NewInt(1000), keeper.GetBalance(ctx, sender, "eth"))
+	require.True(t, keeper.GetHoldBalance(ctx, sender, "eth").IsZero())
+	require.True(t, keeper.GetBalance(ctx, receiver, "eth").IsZero())
+	require.Nil(t, keeper.GetHTLC(ctx, sender, hashlock))
+
+	result = keeper.RefundHTLC(ctx, sender, hashlock)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+}
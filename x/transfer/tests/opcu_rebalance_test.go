@@ -0,0 +1,278 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestOpCURebalanceEth(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := "eth"
+	epochIndex := input.stakingkeeper.GetCurrentEpoch(ctx).Index
+
+	// setup a hot and a cold OPCU
+	hotEthAddress := "0xd139E358aE9cB5424B2067da96F94cC938343446"
+	coldEthAddress := "0x4543429c2110d850BA382C815fD2FeC9E821ee96"
+	hotOPCUAddr, err := sdk.CUAddressFromBase58("HBCLXBebMwEWaEZYsqJij7xcpBayzJqdrKJP")
+	require.Nil(t, err)
+	hotCU := newTestCU(ck.GetCU(ctx, hotOPCUAddr))
+	require.Nil(t, hotCU.SetAssetAddress(symbol, hotEthAddress, epochIndex))
+	hotCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), epochIndex)
+	hotCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, sdk.NewInt(90000000))))
+
+	coldOPCUAddr := sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())
+	coldCU := newTestCU(ck.NewOpCUWithAddress(ctx, symbol, coldOPCUAddr))
+	ck.SetCU(ctx, coldCU)
+	ik.SetCUIBCAsset(ctx, ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeOp, coldOPCUAddr))
+	require.Nil(t, coldCU.SetAssetAddress(symbol, coldEthAddress, epochIndex))
+	coldCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), epochIndex)
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.GasLimit = sdk.NewInt(10000)
+	tokenInfo.GasPrice = sdk.NewInt(100)
+	tokenInfo.OpCUTiers = &sdk.OpCUTierParams{
+		ColdOpCUs:               []sdk.CUAddress{coldOPCUAddr},
+		ColdWithdrawalThreshold: sdk.NewInt(50000000),
+		HotHighWatermark:        sdk.NewInt(40000000),
+		HotLowWatermark:         sdk.NewInt(10000000),
+	}
+	tk.SetToken(ctx, tokenInfo)
+
+	// cold OPCUs only take part in large withdrawals
+	small := []*sdk.OrderWithdrawal{{OrderBase: sdk.OrderBase{ID: "small"}, Amount: sdk.NewInt(100)}}
+	large := []*sdk.OrderWithdrawal{{OrderBase: sdk.OrderBase{ID: "large"}, Amount: sdk.NewInt(50000000)}}
+	require.NotNil(t, keeper.CheckWithdrawalOpCUTier(coldOPCUAddr, tokenInfo, small))
+	require.Nil(t, keeper.CheckWithdrawalOpCUTier(coldOPCUAddr, tokenInfo, large))
+	require.Nil(t, keeper.CheckWithdrawalOpCUTier(hotOPCUAddr, tokenInfo, small))
+
+	findOrders := func() []string {
+		return ok.GetProcessOrderListByType(ctx, sdk.OrderTypeOpcuAssetTransfer)
+	}
+
+	// not at the check interval
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval - 1)
+	keeper.ScheduleOpCURebalance(ctx)
+	require.Equal(t, 0, len(findOrders()))
+
+	// the hot OPCU is above the high watermark, it is rebalanced to the middle of the watermarks
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval)
+	keeper.ScheduleOpCURebalance(ctx)
+	orderIDs := findOrders()
+	require.Equal(t, 1, len(orderIDs))
+	orderID := orderIDs[0]
+	order := ok.GetOrder(ctx, orderID).(*sdk.OrderOpcuAssetTransfer)
+	require.True(t, order.IsRebalance())
+	require.Equal(t, hotOPCUAddr, order.GetCUAddress())
+	require.Equal(t, coldOPCUAddr, order.ToCUAddress)
+	require.Equal(t, coldEthAddress, order.ToAddr)
+	require.Equal(t, epochIndex, order.FromEpoch)
+	require.Equal(t, sdk.NewInt(65000000), order.TransfertItems[0].Amount)
+	require.False(t, newTestCU(ck.GetCU(ctx, hotOPCUAddr)).IsEnabledSendTx(chain, hotEthAddress))
+
+	// no more rebalancing before the order finishes
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval * 2)
+	keeper.ScheduleOpCURebalance(ctx)
+	require.Equal(t, 1, len(findOrders()))
+
+	// WaitSign
+	chainnodeTx := &chainnode.ExtAccountTransaction{
+		Hash:     "rebalanceTxHash",
+		From:     hotEthAddress,
+		To:       coldEthAddress,
+		Amount:   sdk.NewInt(64000000),
+		Nonce:    0,
+		GasLimit: sdk.NewInt(10000),
+		GasPrice: sdk.NewInt(100),
+	}
+	rawData := []byte("rawData")
+	signHash := []byte("signHash")
+	mockCN.On("QueryAccountTransactionFromData", chain, symbol, rawData).Return(chainnodeTx, signHash, nil)
+	result := keeper.OpcuAssetTransferWaitSign(ctx, orderID, [][]byte{signHash}, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.Equal(t, sdk.NewInt(25000000), ik.GetCUIBCAsset(ctx, hotOPCUAddr).GetAssetCoins().AmountOf(symbol))
+
+	// SignFinish
+	signedData := []byte("signedData")
+	mockCN.On("VerifyAccountSignedTransaction", chain, symbol, hotEthAddress, signedData).Return(true, nil)
+	mockCN.On("QueryAccountTransactionFromSignedData", chain, symbol, signedData).Return(chainnodeTx, nil)
+	result = keeper.OpcuAssetTransferSignFinish(ctx, orderID, signedData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	// Finish
+	costFee := sdk.NewInt(1000000)
+	for _, val := range validators[:3] {
+		result = keeper.OpcuAssetTransferFinish(ctx, sdk.CUAddress(val.GetOperator()), orderID, costFee)
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+	require.Equal(t, sdk.OrderStatusFinish, ok.GetOrder(ctx, orderID).GetOrderStatus())
+
+	hotAst := ik.GetCUIBCAsset(ctx, hotOPCUAddr)
+	require.Equal(t, sdk.NewInt(25000000), hotAst.GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, costFee, hotAst.GetGasUsed().AmountOf(chain))
+	require.Equal(t, uint64(1), hotAst.GetNonce(chain, hotEthAddress))
+	require.True(t, hotAst.IsEnabledSendTx(chain, hotEthAddress))
+	require.Equal(t, sdk.MigrationFinish, hotAst.GetMigrationStatus())
+	require.Equal(t, sdk.NewInt(64000000), ik.GetCUIBCAsset(ctx, coldOPCUAddr).GetAssetCoins().AmountOf(symbol))
+
+	// the hot OPCU falls below a raised low watermark and is refilled from the cold tier
+	tokenInfo.OpCUTiers.HotLowWatermark = sdk.NewInt(30000000)
+	tk.SetToken(ctx, tokenInfo)
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval * 3)
+	keeper.ScheduleOpCURebalance(ctx)
+	orderIDs = findOrders()
+	require.Equal(t, 1, len(orderIDs))
+	order = ok.GetOrder(ctx, orderIDs[0]).(*sdk.OrderOpcuAssetTransfer)
+	require.Equal(t, coldOPCUAddr, order.GetCUAddress())
+	require.Equal(t, hotOPCUAddr, order.ToCUAddress)
+	require.Equal(t, hotEthAddress, order.ToAddr)
+	require.Equal(t, sdk.NewInt(10000000), order.TransfertItems[0].Amount)
+}
+
+func TestOpCURebalanceBtc(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+	chain := "btc"
+	epochIndex := input.stakingkeeper.GetCurrentEpoch(ctx).Index
+
+	// setup a hot OPCU holding 4 utxos and a cold OPCU
+	hotBtcAddress := "mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg"
+	coldBtcAddress := "miaTKppfCyfW1FdRYfmUuDwbjKMNcRtSMb"
+	hotOPCUAddr, err := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+	require.Nil(t, err)
+	hotCU := newTestCU(ck.GetCU(ctx, hotOPCUAddr))
+	require.Nil(t, hotCU.SetAssetAddress(symbol, hotBtcAddress, epochIndex))
+	hotCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), epochIndex)
+	depositList := sdk.DepositList{}
+	totalAmount := sdk.ZeroInt()
+	for i, amount := range []int64{60000000, 30000000, 10000000, 5000000} {
+		d, err := sdk.NewDepositItem("rebalance_utxo_"+strconv.Itoa(i), 0, sdk.NewInt(amount), hotBtcAddress, "", sdk.DepositItemStatusConfirmed)
+		require.Nil(t, err)
+		depositList = append(depositList, d)
+		totalAmount = totalAmount.Add(d.Amount)
+	}
+	ik.SetDepositList(ctx, symbol, hotOPCUAddr, depositList)
+	hotCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, totalAmount)))
+
+	coldOPCUAddr := sdk.CUAddress(ed25519.GenPrivKey().PubKey().Address())
+	coldCU := newTestCU(ck.NewOpCUWithAddress(ctx, symbol, coldOPCUAddr))
+	ck.SetCU(ctx, coldCU)
+	ik.SetCUIBCAsset(ctx, ik.NewCUIBCAssetWithAddress(ctx, sdk.CUTypeOp, coldOPCUAddr))
+	require.Nil(t, coldCU.SetAssetAddress(symbol, coldBtcAddress, epochIndex))
+	coldCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), epochIndex)
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.GasPrice = sdk.NewInt(10000000 / 380)
+	tokenInfo.OpCUTiers = &sdk.OpCUTierParams{
+		ColdOpCUs:               []sdk.CUAddress{coldOPCUAddr},
+		ColdWithdrawalThreshold: sdk.NewInt(50000000),
+		HotHighWatermark:        sdk.NewInt(40000000),
+		HotLowWatermark:         sdk.NewInt(10000000),
+	}
+	tk.SetToken(ctx, tokenInfo)
+
+	// 80000000 is to be moved to reach the middle of the watermarks, the utxos that fit in it are picked
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval)
+	keeper.ScheduleOpCURebalance(ctx)
+	orderIDs := ok.GetProcessOrderListByType(ctx, sdk.OrderTypeOpcuAssetTransfer)
+	require.Equal(t, 1, len(orderIDs))
+	orderID := orderIDs[0]
+	order := ok.GetOrder(ctx, orderID).(*sdk.OrderOpcuAssetTransfer)
+	require.True(t, order.IsRebalance())
+	require.Equal(t, coldOPCUAddr, order.ToCUAddress)
+	require.Equal(t, coldBtcAddress, order.ToAddr)
+	require.Equal(t, epochIndex, order.FromEpoch)
+	require.Equal(t, 3, len(order.TransfertItems))
+	transferAmount := sdk.ZeroInt()
+	for _, item := range order.TransfertItems {
+		transferAmount = transferAmount.Add(item.Amount)
+	}
+	require.Equal(t, sdk.NewInt(75000000), transferAmount)
+	require.Equal(t, sdk.DepositItemStatusConfirmed, ik.GetDeposit(ctx, symbol, hotOPCUAddr, "rebalance_utxo_1", 0).Status)
+
+	// WaitSign
+	var vins []*sdk.UtxoIn
+	var signHashes [][]byte
+	for i, item := range order.TransfertItems {
+		vin := sdk.NewUtxoIn(item.Hash, item.Index, item.Amount, hotBtcAddress)
+		vins = append(vins, &vin)
+		signHashes = append(signHashes, []byte(strconv.Itoa(i)))
+	}
+	gasFee := sdk.NewInt(15000)
+	chainnodeTx := &chainnode.ExtUtxoTransaction{
+		Hash:    "rebalanceTxHash",
+		Vins:    vins,
+		Vouts:   []*sdk.UtxoOut{{Address: coldBtcAddress, Amount: transferAmount.Sub(gasFee)}},
+		CostFee: gasFee,
+	}
+	rawData := []byte("rawData")
+	mockCN.On("QueryUtxoInsFromData", chain, symbol, rawData).Return(vins, nil)
+	mockCN.On("QueryUtxoTransactionFromData", chain, symbol, rawData, vins).Return(chainnodeTx, signHashes, nil)
+	result := keeper.OpcuAssetTransferWaitSign(ctx, orderID, signHashes, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	// a new epoch starts before the rebalancing finishes
+	var valAddr []sdk.CUAddress
+	for _, val := range validators {
+		valAddr = append(valAddr, sdk.CUAddress(val.OperatorAddress))
+	}
+	input.stakingkeeper.StartNewEpoch(ctx, valAddr)
+	ctx = ctx.WithBlockHeight(types.OpCURebalanceInterval + 1)
+	require.Equal(t, epochIndex+1, input.stakingkeeper.GetCurrentEpoch(ctx).Index)
+
+	// SignFinish
+	signedData := []byte("signedData")
+	mockCN.On("QueryUtxoInsFromData", chain, symbol, signedData).Return(vins, nil)
+	mockCN.On("VerifyUtxoSignedTransaction", chain, symbol, mock.Anything, signedData, vins).Return(true, nil)
+	mockCN.On("QueryUtxoTransactionFromSignedData", chain, symbol, signedData, vins).Return(chainnodeTx, nil)
+	result = keeper.OpcuAssetTransferSignFinish(ctx, orderID, signedData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	// Finish
+	for _, val := range validators[:3] {
+		result = keeper.OpcuAssetTransferFinish(ctx, sdk.CUAddress(val.GetOperator()), orderID, gasFee)
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+	require.Equal(t, sdk.OrderStatusFinish, ok.GetOrder(ctx, orderID).GetOrderStatus())
+
+	hotAst := ik.GetCUIBCAsset(ctx, hotOPCUAddr)
+	require.Equal(t, sdk.NewInt(30000000), hotAst.GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, gasFee, hotAst.GetGasUsed().AmountOf(chain))
+	require.Equal(t, 1, len(ik.GetDepositList(ctx, symbol, hotOPCUAddr)))
+	coldAst := ik.GetCUIBCAsset(ctx, coldOPCUAddr)
+	require.Equal(t, transferAmount.Sub(gasFee), coldAst.GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, 1, len(ik.GetDepositList(ctx, symbol, coldOPCUAddr)))
+}
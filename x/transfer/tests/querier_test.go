@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 
 	abci "github.com/tendermint/tendermint/abci/types"
 
+	"github.com/hbtc-chain/bhchain/chainnode"
 	"github.com/hbtc-chain/bhchain/x/transfer/keeper"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
@@ -60,3 +62,87 @@ func TestQuerierRouteNotFound(t *testing.T) {
 	_, err := querier(input.ctx, []string{"notfound"}, req)
 	require.Error(t, err)
 }
+
+func TestReserve(t *testing.T) {
+	input := setupTestInput(t)
+	ctx := input.ctx
+	ik := input.ik
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+
+	querier := keeper.NewQuerier(input.k)
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("custom/transfer/%s", types.QueryReserve),
+		Data: input.cdc.MustMarshalJSON(types.NewQueryReserveParams("notexist", nil, false)),
+	}
+	_, err := querier(ctx, []string{types.QueryReserve}, req)
+	require.NotNil(t, err)
+
+	user1, _ := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	user2, _ := sdk.CUAddressFromBase58("HBCLG5zCH4FtXi3G6wZps8TNfYYWgzb1Rr2q")
+	opCUAddr, _ := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+
+	_, _, sdkErr := input.k.AddCoin(ctx, user1, sdk.NewInt64Coin(symbol, 300))
+	require.Nil(t, sdkErr)
+	_, sdkErr = input.k.LockCoin(ctx, user1, sdk.NewInt64Coin(symbol, 100))
+	require.Nil(t, sdkErr)
+	_, _, sdkErr = input.k.AddCoin(ctx, user2, sdk.NewInt64Coin(symbol, 500))
+	require.Nil(t, sdkErr)
+	_, _, sdkErr = input.k.AddCoin(ctx, user2, sdk.NewInt64Coin("eth", 700))
+	require.Nil(t, sdkErr)
+
+	user2Ast := ik.GetCUIBCAsset(ctx, user2)
+	require.Nil(t, user2Ast.AddAsset(symbol, "user2btcaddr", 1))
+	user2Ast.AddAssetCoins(sdk.NewCoins(sdk.NewInt64Coin(symbol, 200)))
+	ik.SetCUIBCAsset(ctx, user2Ast)
+
+	opCUAst := ik.GetCUIBCAsset(ctx, opCUAddr)
+	require.Nil(t, opCUAst.SetAssetAddress(symbol, "opcubtcaddr", 1))
+	opCUAst.AddAssetCoins(sdk.NewCoins(sdk.NewInt64Coin(symbol, 500)))
+	opCUAst.AddAssetCoinsHold(sdk.NewCoins(sdk.NewInt64Coin(symbol, 150)))
+	ik.SetCUIBCAsset(ctx, opCUAst)
+
+	req.Data = input.cdc.MustMarshalJSON(types.NewQueryReserveParams(symbol, user1, false))
+	res, err := querier(ctx, []string{types.QueryReserve}, req)
+	require.Nil(t, err)
+
+	var report types.ReserveReport
+	require.NoError(t, input.cdc.UnmarshalJSON(res, &report))
+	require.Equal(t, symbol, report.Symbol)
+	require.Equal(t, 2, report.LiabilityCount)
+	require.Equal(t, sdk.NewInt(800), report.Liabilities)
+	require.Equal(t, sdk.NewInt(650), report.OpCUReserves)
+	require.Equal(t, sdk.NewInt(200), report.UserCUReserves)
+	require.Equal(t, sdk.NewInt(850), report.Reserves)
+	require.Equal(t, sdk.NewInt(50), report.Surplus)
+	require.Nil(t, report.ExternalBalances)
+
+	require.NotNil(t, report.LiabilityProof)
+	require.Equal(t, user1, report.LiabilityProof.Liability.Address)
+	require.Equal(t, sdk.NewInt(300), report.LiabilityProof.Liability.Amount)
+	require.Nil(t, report.LiabilityProof.Verify(report.LiabilityRoot, report.Liabilities))
+
+	// cross check with chainnode
+	mockCN.On("QueryBalance", "btc", symbol, "opcubtcaddr", "", uint64(0)).Return(sdk.NewInt(650), nil)
+	mockCN.On("QueryBalance", "btc", symbol, "user2btcaddr", "", uint64(0)).Return(sdk.ZeroInt(), errors.New("node unavailable"))
+	req.Data = input.cdc.MustMarshalJSON(types.NewQueryReserveParams(symbol, nil, true))
+	res, err = querier(ctx, []string{types.QueryReserve}, req)
+	require.Nil(t, err)
+
+	report = types.ReserveReport{}
+	require.NoError(t, input.cdc.UnmarshalJSON(res, &report))
+	require.Nil(t, report.LiabilityProof)
+	require.Equal(t, 2, len(report.ExternalBalances))
+	for _, eb := range report.ExternalBalances {
+		if eb.CUAddress.Equals(opCUAddr) {
+			require.Equal(t, []string{"opcubtcaddr"}, eb.Addresses)
+			require.Equal(t, sdk.NewInt(650), eb.Recorded)
+			require.Equal(t, sdk.NewInt(650), eb.OnChain)
+			require.Empty(t, eb.Error)
+		} else {
+			require.Equal(t, user2, eb.CUAddress)
+			require.Equal(t, sdk.NewInt(200), eb.Recorded)
+			require.NotEmpty(t, eb.Error)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestScheduledTransferByHeight(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	rk := input.rk
+	ctx := input.ctx.WithBlockHeight(100)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1000))))
+	require.Nil(t, err)
+
+	result := keeper.ScheduleTransfer(ctx, from, to, amount, false, 100, 10, 3)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.ScheduleTransfer(ctx, from, to, amount, false, 110, 0, 3)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	result = keeper.ScheduleTransfer(ctx, from, to, amount, false, 110, 10, 11)
+	require.NotEqual(t, sdk.CodeOK, result.Code)
+
+	result = keeper.ScheduleTransfer(ctx, from, to, amount, false, 110, 10, 3)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.Equal(t, sdk.NewInt(700), keeper.GetBalance(ctx, from, "eth"))
+	require.Equal(t, sdk.NewInt(300), keeper.GetHoldBalance(ctx, from, "eth"))
+	sts := keeper.GetScheduledTransfers(ctx, from)
+	require.Len(t, sts, 1)
+	id := sts[0].ID
+
+	// not due yet
+	ctx = ctx.WithBlockHeight(109).WithEventManager(sdk.NewEventManager())
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.True(t, keeper.GetBalance(ctx, to, "eth").IsZero())
+
+	ctx = ctx.WithBlockHeight(110)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+	require.Equal(t, sdk.NewInt(200), keeper.GetHoldBalance(ctx, from, "eth"))
+	receipts, err1 := rk.GetReceiptsFromEvents(ctx.EventManager().Events())
+	require.Nil(t, err1)
+	require.Len(t, receipts, 1)
+	require.Equal(t, sdk.CategoryTypeScheduledTransfer, receipts[0].Category)
+	ef, valid := receipts[0].Flows[0].(sdk.ScheduledTransferExecutedFlow)
+	require.True(t, valid)
+	require.Equal(t, id, ef.ID)
+	require.Equal(t, uint64(2), ef.Remaining)
+
+	st := keeper.GetScheduledTransfer(ctx, id)
+	require.NotNil(t, st)
+	require.Equal(t, uint64(120), st.Next)
+
+	// executed once per due height
+	ctx = ctx.WithBlockHeight(111)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+
+	ctx = ctx.WithBlockHeight(120)
+	keeper.ExecuteScheduledTransfers(ctx)
+	ctx = ctx.WithBlockHeight(130)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(300), keeper.GetBalance(ctx, to, "eth"))
+	require.True(t, keeper.GetHoldBalance(ctx, from, "eth").IsZero())
+	require.Nil(t, keeper.GetScheduledTransfer(ctx, id))
+	require.Len(t, keeper.GetScheduledTransfers(ctx, from), 0)
+}
+
+func TestScheduledTransferByTimeCancel(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	now := time.Unix(1600000000, 0)
+	ctx := input.ctx.WithBlockHeight(100).WithBlockTime(now)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1000))))
+	require.Nil(t, err)
+
+	start := uint64(now.Unix()) + 60
+	result := keeper.ScheduleTransfer(ctx, from, to, amount, true, start, 3600, 5)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	id := keeper.GetScheduledTransfers(ctx, from)[0].ID
+
+	ctx = ctx.WithBlockHeight(101).WithBlockTime(now.Add(time.Minute))
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+	require.Equal(t, sdk.NewInt(400), keeper.GetHoldBalance(ctx, from, "eth"))
+
+	// only the owner can cancel
+	result = keeper.CancelScheduledTransfer(ctx, to, id)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.CancelScheduledTransfer(ctx, from, id)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.Equal(t, sdk.NewInt(900), keeper.GetBalance(ctx, from, "eth"))
+	require.True(t, keeper.GetHoldBalance(ctx, from, "eth").IsZero())
+	require.Nil(t, keeper.GetScheduledTransfer(ctx, id))
+
+	ctx = ctx.WithBlockHeight(102).WithBlockTime(now.Add(2 * time.Hour))
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+
+	result = keeper.CancelScheduledTransfer(ctx, from, id)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+}
+
+func TestScheduledTransferRetry(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx.WithBlockHeight(100)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1000))))
+	require.Nil(t, err)
+
+	result := keeper.ScheduleTransfer(ctx, from, to, amount, false, 110, 10, 2)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	id := keeper.GetScheduledTransfers(ctx, from)[0].ID
+
+	// parked while the sender is frozen
+	keeper.SetAddressFrozen(ctx, from, true)
+	ctx = ctx.WithBlockHeight(110)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.True(t, keeper.GetBalance(ctx, to, "eth").IsZero())
+	require.Equal(t, sdk.NewInt(200), keeper.GetHoldBalance(ctx, from, "eth"))
+	st := keeper.GetScheduledTransfer(ctx, id)
+	require.NotNil(t, st)
+	require.Equal(t, uint64(110), st.Next)
+	require.Equal(t, uint64(2), st.Remaining)
+
+	// resumed on its cadence once unfrozen
+	ctx = ctx.WithBlockHeight(111)
+	keeper.SetAddressFrozen(ctx, from, false)
+	st = keeper.GetScheduledTransfer(ctx, id)
+	require.Equal(t, uint64(120), st.Next)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.True(t, keeper.GetBalance(ctx, to, "eth").IsZero())
+
+	ctx = ctx.WithBlockHeight(120)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+	require.Equal(t, sdk.NewInt(100), keeper.GetHoldBalance(ctx, from, "eth"))
+	st = keeper.GetScheduledTransfer(ctx, id)
+	require.NotNil(t, st)
+	require.Equal(t, uint64(130), st.Next)
+
+	// neither executed nor cancelled without the hold, kept for the next block
+	_, _, err = keeper.SubCoinsHold(ctx, from, amount)
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeight(130)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+	st = keeper.GetScheduledTransfer(ctx, id)
+	require.NotNil(t, st)
+	require.Equal(t, uint64(131), st.Next)
+	require.Equal(t, uint64(1), st.Remaining)
+
+	_, _, err = keeper.AddCoinsHold(ctx, from, amount)
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeight(131)
+	keeper.ExecuteScheduledTransfers(ctx)
+	require.Equal(t, sdk.NewInt(200), keeper.GetBalance(ctx, to, "eth"))
+	require.True(t, keeper.GetHoldBalance(ctx, from, "eth").IsZero())
+	require.Nil(t, keeper.GetScheduledTransfer(ctx, id))
+	require.Len(t, keeper.GetScheduledTransfers(ctx, from), 0)
+}
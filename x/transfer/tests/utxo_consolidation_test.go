@@ -0,0 +1,291 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestUtxoConsolidationBtc(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	rk := input.rk
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+
+	validators := input.validators
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+	chain := "btc"
+	pubkey := ed25519.GenPrivKey().PubKey()
+
+	//setup OpCU with many dust utxos
+	opCUBtcAddress := "mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg"
+	btcOPCUAddr, err := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, btcOPCUAddr))
+	require.Nil(t, opCU.SetAssetAddress(symbol, opCUBtcAddress, 1))
+	opCU.SetAssetPubkey(pubkey.Bytes(), 1)
+
+	depositList := sdk.DepositList{}
+	totalAmount := sdk.ZeroInt()
+	for i := 10; i > 0; i-- {
+		amount := sdk.NewInt(int64(5000 + 100*i))
+		d, err := sdk.NewDepositItem("opcu_utxo_deposit_"+strconv.Itoa(i), 0, amount, opCUBtcAddress, "", sdk.DepositItemStatusConfirmed)
+		require.Nil(t, err)
+		depositList = append(depositList, d)
+		totalAmount = totalAmount.Add(amount)
+	}
+	ik.SetDepositList(ctx, symbol, btcOPCUAddr, depositList)
+	opCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, totalAmount)))
+	ck.SetCU(ctx, opCU)
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.GasPrice = sdk.NewInt(40000)
+	tk.SetToken(ctx, tokenInfo)
+
+	findOrders := func() []string {
+		return ok.GetProcessOrderListByType(ctx, sdk.OrderTypeUtxoConsolidation)
+	}
+
+	// not at the check interval
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval - 1)
+	keeper.ScheduleUtxoConsolidation(ctx)
+	require.True(t, keeper.GetGasPriceAverage(ctx, chain).IsZero())
+
+	// the first sample of gas price is never low
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval)
+	keeper.ScheduleUtxoConsolidation(ctx)
+	require.Equal(t, sdk.NewInt(40000), keeper.GetGasPriceAverage(ctx, chain))
+	require.Equal(t, 0, len(findOrders()))
+
+	// gas price drops
+	tokenInfo.GasPrice = sdk.NewInt(20000)
+	tk.SetToken(ctx, tokenInfo)
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval * 2)
+	keeper.ScheduleUtxoConsolidation(ctx)
+	require.Equal(t, sdk.NewInt(38000), keeper.GetGasPriceAverage(ctx, chain))
+
+	orderIDs := findOrders()
+	require.Equal(t, 1, len(orderIDs))
+	orderID := orderIDs[0]
+	o := ok.GetOrder(ctx, orderID)
+	require.Equal(t, sdk.OrderTypeUtxoConsolidation, o.GetOrderType())
+	require.Equal(t, sdk.OrderStatusBegin, o.GetOrderStatus())
+	require.Equal(t, btcOPCUAddr, o.GetCUAddress())
+	order := o.(*sdk.OrderUtxoConsolidation)
+	require.Equal(t, opCUBtcAddress, order.Address)
+	require.Equal(t, sdk.MaxVinNum, len(order.ConsolidateItems))
+
+	// the smallest utxos are consolidated
+	consolidateAmount := sdk.ZeroInt()
+	for i, item := range order.ConsolidateItems {
+		require.Equal(t, "opcu_utxo_deposit_"+strconv.Itoa(i+1), item.Hash)
+		require.Equal(t, sdk.DepositItemStatusInProcess, ik.GetDeposit(ctx, symbol, btcOPCUAddr, item.Hash, item.Index).Status)
+		consolidateAmount = consolidateAmount.Add(item.Amount)
+	}
+	require.Equal(t, sdk.DepositItemStatusConfirmed, ik.GetDeposit(ctx, symbol, btcOPCUAddr, "opcu_utxo_deposit_7", 0).Status)
+
+	// no more consolidation before the order finishes
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval * 3)
+	keeper.ScheduleUtxoConsolidation(ctx)
+	require.Equal(t, 1, len(findOrders()))
+
+	// WaitSign
+	var vins []*sdk.UtxoIn
+	var hashes [][]byte
+	for i, item := range order.ConsolidateItems {
+		vin := sdk.NewUtxoIn(item.Hash, item.Index, item.Amount, opCUBtcAddress)
+		vins = append(vins, &vin)
+		hashes = append(hashes, []byte(strconv.Itoa(i)))
+	}
+	gasFee := sdk.NewInt(18800)
+	chainnodeTx := &chainnode.ExtUtxoTransaction{
+		Hash: "consolidationTxHash",
+		Vins: vins,
+		Vouts: []*sdk.UtxoOut{
+			{Address: opCUBtcAddress, Amount: consolidateAmount.Sub(gasFee)},
+		},
+		CostFee: gasFee,
+	}
+	rawData := []byte("rawData")
+	mockCN.On("QueryUtxoInsFromData", chain, symbol, rawData).Return(vins, nil)
+	mockCN.On("QueryUtxoTransactionFromData", chain, symbol, rawData, vins).Return(chainnodeTx, hashes, nil)
+
+	result := keeper.UtxoConsolidationWaitSign(ctx, orderID, hashes[1:], rawData)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.UtxoConsolidationWaitSign(ctx, orderID, hashes, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.Equal(t, sdk.OrderStatusWaitSign, ok.GetOrder(ctx, orderID).GetOrderStatus())
+	require.Equal(t, totalAmount.Sub(consolidateAmount), newTestCU(ck.GetCU(ctx, btcOPCUAddr)).GetAssetCoins().AmountOf(symbol))
+
+	receipt, err := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, sdk.CategoryTypeUtxoConsolidation, receipt.Category)
+	require.Equal(t, 2, len(receipt.Flows))
+	wsf, valid := receipt.Flows[1].(sdk.UtxoConsolidationWaitSignFlow)
+	require.True(t, valid)
+	require.Equal(t, orderID, wsf.OrderID)
+	require.Equal(t, rawData, wsf.RawData)
+
+	// SignFinish
+	signedData := []byte("signedData")
+	mockCN.On("QueryUtxoInsFromData", chain, symbol, signedData).Return(vins, nil)
+	mockCN.On("QueryUtxoTransactionFromSignedData", chain, symbol, signedData, vins).Return(chainnodeTx, nil)
+	mockCN.On("VerifyUtxoSignedTransaction", chain, symbol, mock.Anything, signedData, vins).Return(true, nil)
+
+	result = keeper.UtxoConsolidationSignFinish(ctx, orderID, signedData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	o = ok.GetOrder(ctx, orderID)
+	require.Equal(t, sdk.OrderStatusSignFinish, o.GetOrderStatus())
+	require.Equal(t, "consolidationTxHash", o.(*sdk.OrderUtxoConsolidation).Txhash)
+
+	// Finish
+	result = keeper.UtxoConsolidationFinish(ctx, btcOPCUAddr, orderID, gasFee)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	for i := 0; i < 3; i++ {
+		result = keeper.UtxoConsolidationFinish(ctx, sdk.CUAddress(validators[i].OperatorAddress), orderID, gasFee)
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+	o = ok.GetOrder(ctx, orderID)
+	require.Equal(t, sdk.OrderStatusFinish, o.GetOrderStatus())
+	require.Equal(t, gasFee, o.(*sdk.OrderUtxoConsolidation).CostFee)
+
+	receipt, err = rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, sdk.CategoryTypeUtxoConsolidation, receipt.Category)
+	ff, valid := receipt.Flows[1].(sdk.UtxoConsolidationFinishFlow)
+	require.True(t, valid)
+	require.Equal(t, gasFee, ff.CostFee)
+
+	// the consolidated utxos are replaced by the merged one
+	depositList = ik.GetDepositList(ctx, symbol, btcOPCUAddr)
+	require.Equal(t, 10-sdk.MaxVinNum+1, len(depositList))
+	merged := ik.GetDeposit(ctx, symbol, btcOPCUAddr, "consolidationTxHash", 0)
+	require.Equal(t, consolidateAmount.Sub(gasFee), merged.Amount)
+	require.Equal(t, sdk.DepositItemStatusConfirmed, merged.Status)
+
+	opCU = newTestCU(ck.GetCU(ctx, btcOPCUAddr))
+	require.Equal(t, totalAmount.Sub(gasFee), opCU.GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, gasFee, opCU.GetGasUsed().AmountOf(symbol))
+}
+
+func TestUtxoConsolidationTimeout(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+	chain := "btc"
+	pubkey := ed25519.GenPrivKey().PubKey()
+
+	opCUBtcAddress := "mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg"
+	btcOPCUAddr, err := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, btcOPCUAddr))
+	require.Nil(t, opCU.SetAssetAddress(symbol, opCUBtcAddress, 1))
+	opCU.SetAssetPubkey(pubkey.Bytes(), 1)
+
+	depositList := sdk.DepositList{}
+	totalAmount := sdk.ZeroInt()
+	for i := 10; i > 0; i-- {
+		amount := sdk.NewInt(int64(5000 + 100*i))
+		d, err := sdk.NewDepositItem("opcu_utxo_deposit_"+strconv.Itoa(i), 0, amount, opCUBtcAddress, "", sdk.DepositItemStatusConfirmed)
+		require.Nil(t, err)
+		depositList = append(depositList, d)
+		totalAmount = totalAmount.Add(amount)
+	}
+	ik.SetDepositList(ctx, symbol, btcOPCUAddr, depositList)
+	opCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, totalAmount)))
+	ck.SetCU(ctx, opCU)
+
+	params := types.DefaultUtxoConsolidationParams()
+	params.Timeout = 50
+	keeper.SetUtxoConsolidationParams(ctx, params)
+	require.Equal(t, params, keeper.GetUtxoConsolidationParams(ctx))
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.GasPrice = sdk.NewInt(40000)
+	tk.SetToken(ctx, tokenInfo)
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval)
+	keeper.ScheduleUtxoConsolidation(ctx)
+	tokenInfo.GasPrice = sdk.NewInt(20000)
+	tk.SetToken(ctx, tokenInfo)
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval * 2)
+	keeper.ScheduleUtxoConsolidation(ctx)
+
+	orderIDs := ok.GetProcessOrderListByType(ctx, sdk.OrderTypeUtxoConsolidation)
+	require.Equal(t, 1, len(orderIDs))
+	orderID := orderIDs[0]
+	order := ok.GetOrder(ctx, orderID).(*sdk.OrderUtxoConsolidation)
+
+	// WaitSign restarts the timeout
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval*2 + 40)
+	keeper.CancelTimeoutUtxoConsolidations(ctx)
+	require.Equal(t, sdk.OrderStatusBegin, ok.GetOrder(ctx, orderID).GetOrderStatus())
+
+	var vins []*sdk.UtxoIn
+	var hashes [][]byte
+	consolidateAmount := sdk.ZeroInt()
+	for i, item := range order.ConsolidateItems {
+		vin := sdk.NewUtxoIn(item.Hash, item.Index, item.Amount, opCUBtcAddress)
+		vins = append(vins, &vin)
+		hashes = append(hashes, []byte(strconv.Itoa(i)))
+		consolidateAmount = consolidateAmount.Add(item.Amount)
+	}
+	gasFee := sdk.NewInt(18800)
+	chainnodeTx := &chainnode.ExtUtxoTransaction{
+		Hash:    "consolidationTxHash",
+		Vins:    vins,
+		Vouts:   []*sdk.UtxoOut{{Address: opCUBtcAddress, Amount: consolidateAmount.Sub(gasFee)}},
+		CostFee: gasFee,
+	}
+	rawData := []byte("rawData")
+	mockCN.On("QueryUtxoInsFromData", chain, symbol, rawData).Return(vins, nil)
+	mockCN.On("QueryUtxoTransactionFromData", chain, symbol, rawData, vins).Return(chainnodeTx, hashes, nil)
+
+	result := keeper.UtxoConsolidationWaitSign(ctx, orderID, hashes, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	require.Equal(t, totalAmount.Sub(consolidateAmount), newTestCU(ck.GetCU(ctx, btcOPCUAddr)).GetAssetCoins().AmountOf(symbol))
+
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval*2 + 89)
+	keeper.CancelTimeoutUtxoConsolidations(ctx)
+	require.Equal(t, sdk.OrderStatusWaitSign, ok.GetOrder(ctx, orderID).GetOrderStatus())
+
+	// the order is cancelled, its utxos and assets are given back
+	ctx = ctx.WithBlockHeight(types.UtxoConsolidationInterval*2 + 90)
+	keeper.CancelTimeoutUtxoConsolidations(ctx)
+	require.Equal(t, sdk.OrderStatusCancel, ok.GetOrder(ctx, orderID).GetOrderStatus())
+	require.Equal(t, 0, len(ok.GetProcessOrderListByType(ctx, sdk.OrderTypeUtxoConsolidation)))
+	for _, item := range order.ConsolidateItems {
+		require.Equal(t, sdk.DepositItemStatusConfirmed, ik.GetDeposit(ctx, symbol, btcOPCUAddr, item.Hash, item.Index).Status)
+	}
+	require.Equal(t, totalAmount, newTestCU(ck.GetCU(ctx, btcOPCUAddr)).GetAssetCoins().AmountOf(symbol))
+
+	result = keeper.UtxoConsolidationSignFinish(ctx, orderID, []byte("signedData"))
+	require.Equal(t, sdk.CodeInvalidOrder, result.Code)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/staking"
+	"github.com/hbtc-chain/bhchain/x/supply"
+)
+
+func TestContinuousVestingCUSend(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	now := time.Unix(1600000000, 0)
+	ctx := input.ctx.WithBlockTime(now)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	other := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, from, amount)
+	require.Nil(t, err)
+
+	result := keeper.CreateVestingCU(ctx, from, to, amount, now.Unix()-100, now.Unix())
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	endTime := now.Add(100 * time.Second).Unix()
+	result = keeper.CreateVestingCU(ctx, from, to, amount, now.Unix(), endTime)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, to, "eth"))
+	_, ok := input.ck.GetCU(ctx, to).(*custodianunit.ContinuousVestingCU)
+	require.True(t, ok)
+
+	// the CU exists now
+	result = keeper.CreateVestingCU(ctx, from, to, amount, now.Unix(), endTime)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	_, _, err = keeper.SendCoins(ctx, to, other, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(1))))
+	require.NotNil(t, err)
+
+	ctx = ctx.WithBlockTime(now.Add(40 * time.Second))
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(60))), keeper.GetVestingLockedCoins(ctx, to))
+	_, _, err = keeper.SendCoins(ctx, to, other, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(41))))
+	require.NotNil(t, err)
+	_, _, err = keeper.SendCoins(ctx, to, other, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(40))))
+	require.Nil(t, err)
+
+	ctx = ctx.WithBlockTime(now.Add(100 * time.Second))
+	_, _, err = keeper.SendCoins(ctx, to, other, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(60))))
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewInt(100), keeper.GetBalance(ctx, other, "eth"))
+}
+
+func TestVestingCUDelegate(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	now := time.Unix(1600000000, 0)
+	ctx := input.ctx.WithBlockTime(now)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	amount := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(100)))
+	_, _, err := keeper.AddCoins(ctx, from, amount)
+	require.Nil(t, err)
+
+	result := keeper.CreateVestingCU(ctx, from, to, amount, 0, now.Add(time.Hour).Unix())
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	_, ok := input.ck.GetCU(ctx, to).(*custodianunit.DelayedVestingCU)
+	require.True(t, ok)
+
+	bondPool := supply.NewModuleAddress(staking.BondedPoolName)
+	delegated := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(70)))
+	_, err = keeper.DelegateCoins(ctx, to, bondPool, delegated)
+	require.Nil(t, err)
+	vcu := input.ck.GetCU(ctx, to).(*custodianunit.DelayedVestingCU)
+	require.Equal(t, delegated, vcu.GetDelegatedVesting())
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(30))), keeper.GetVestingLockedCoins(ctx, to))
+
+	// the rest is still locked
+	_, _, err = keeper.SendCoins(ctx, to, from, sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(1))))
+	require.NotNil(t, err)
+
+	_, err = keeper.UndelegateCoins(ctx, bondPool, to, delegated)
+	require.Nil(t, err)
+	vcu = input.ck.GetCU(ctx, to).(*custodianunit.DelayedVestingCU)
+	require.True(t, vcu.GetDelegatedVesting().IsZero())
+	require.Equal(t, amount, keeper.GetVestingLockedCoins(ctx, to))
+}
+
+func TestPeriodicVestingCU(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	now := time.Unix(1600000000, 0)
+	ctx := input.ctx.WithBlockTime(now)
+
+	from := sdk.NewCUAddress()
+	to := sdk.NewCUAddress()
+	_, _, err := keeper.AddCoins(ctx, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(100))))
+	require.Nil(t, err)
+
+	periods := custodianunit.VestingPeriods{
+		{Length: 60, Amount: sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(30)))},
+		{Length: 60, Amount: sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(70)))},
+	}
+	result := keeper.CreatePeriodicVestingCU(ctx, from, to, now.Unix(), periods)
+	require.Equal(t, sdk.CodeOK, result.Code, result)
+	require.True(t, keeper.GetBalance(ctx, from, "eth").IsZero())
+
+	ctx = ctx.WithBlockTime(now.Add(time.Minute))
+	_, _, err = keeper.SendCoins(ctx, to, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(31))))
+	require.NotNil(t, err)
+	_, _, err = keeper.SendCoins(ctx, to, from, sdk.NewCoins(sdk.NewCoin("eth", sdk.NewInt(30))))
+	require.Nil(t, err)
+}
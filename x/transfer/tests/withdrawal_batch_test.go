@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+	"github.com/hbtc-chain/bhchain/x/transfer/types"
+)
+
+func TestBatchWithdrawalBtc(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	rk := input.rk
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	ctx = ctx.WithBlockHeight(10)
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+	chain := "btc"
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.WithdrawalFeeRate = sdk.NewDecWithPrec(1, 2)
+	tokenInfo.GasPrice = sdk.NewInt(10000000 / 380)
+	tk.SetToken(ctx, tokenInfo)
+
+	btcOPCUAddr, err := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, btcOPCUAddr))
+	opCUBtcAddress := "mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg"
+	require.Nil(t, opCU.SetAssetAddress(symbol, opCUBtcAddress, 1))
+	ck.SetCU(ctx, opCU)
+	mockCN.On("ValidAddress", chain, symbol, opCUBtcAddress).Return(true, opCUBtcAddress)
+
+	user1CUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	amt := sdk.NewInt(80000000)
+	user1CU := newTestCU(ck.GetCU(ctx, user1CUAddr))
+	user1CU.AddCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	ck.SetCU(ctx, user1CU)
+
+	toAddrs := []string{"mnRw8TRyxUVEv1CnfzpahuRr5BeWYsCGES", "mxwvjAzLxg6VYtnXUxrC4Rz1AZtFaCgpVz"}
+	for _, toAddr := range toAddrs {
+		mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+	}
+
+	batchID := uuid.NewV1().String()
+	outputs := []types.WithdrawalOutput{
+		types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[0], sdk.NewInt(10000000)),
+		types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[1], sdk.NewInt(20000000)),
+	}
+	gasFee := sdk.NewInt(10001)
+
+	// fee too low
+	needFee := tokenInfo.BatchWithdrawalFee(len(outputs)).Amount
+	require.True(t, needFee.GT(tokenInfo.WithdrawalFee().Amount))
+	result := keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, outputs, needFee.SubRaw(1))
+	require.Equal(t, sdk.CodeInsufficientFee, result.Code)
+
+	// too many outputs for one utxo tx
+	var tooManyOutputs []types.WithdrawalOutput
+	for i := 0; i < sdk.MaxVoutNum; i++ {
+		tooManyOutputs = append(tooManyOutputs, types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[0], sdk.NewInt(100)))
+	}
+	result = keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, tooManyOutputs, gasFee)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// duplicated order id
+	result = keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, []types.WithdrawalOutput{outputs[0], outputs[0]}, gasFee)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, outputs, gasFee)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	total := outputs[0].Amount.Add(outputs[1].Amount).Add(gasFee)
+	user1CU = newTestCU(ck.GetCU(ctx, user1CUAddr))
+	require.Equal(t, amt.Sub(total), user1CU.GetCoins().AmountOf(symbol))
+	require.Equal(t, total, user1CU.GetCoinsHold().AmountOf(symbol))
+
+	// the gas fee is split between the outputs
+	fees := []sdk.Int{sdk.NewInt(5001), sdk.NewInt(5000)}
+	for i, output := range outputs {
+		o := ok.GetOrder(ctx, output.OrderID)
+		require.NotNil(t, o)
+		order := o.(*sdk.OrderWithdrawal)
+		require.Equal(t, batchID, order.BatchID)
+		require.Equal(t, output.Amount, order.Amount)
+		require.Equal(t, fees[i], order.GasFee)
+		require.Equal(t, toAddrs[i], order.WithdrawToAddress)
+		require.EqualValues(t, sdk.WithdrawStatusValid, order.WithdrawStatus)
+	}
+
+	receipt, err := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, sdk.CategoryTypeWithdrawal, receipt.Category)
+	require.Equal(t, 7, len(receipt.Flows))
+	bwf, valid := receipt.Flows[4].(sdk.BatchWithdrawalFlow)
+	require.True(t, valid)
+	require.Equal(t, batchID, bwf.BatchID)
+	require.Equal(t, []string{outputs[0].OrderID, outputs[1].OrderID}, bwf.OrderIDs)
+
+	// a batch can not be split into different txs
+	result = keeper.WithdrawalWaitSign(ctx, btcOPCUAddr, []string{outputs[0].OrderID}, nil, []byte("rawData"))
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	require.Contains(t, result.Log, "is not included")
+
+	// the batchID can not be reused by its sender
+	reusedOutputs := []types.WithdrawalOutput{types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[0], sdk.NewInt(100))}
+	result = keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, reusedOutputs, gasFee)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	require.Contains(t, result.Log, "already exists")
+
+	// the same batchID of another sender is another batch
+	user2CUAddr, err := sdk.CUAddressFromBase58("HBCLG5zCH4FtXi3G6wZps8TNfYYWgzb1Rr2q")
+	require.Nil(t, err)
+	user2CU := newTestCU(ck.GetCU(ctx, user2CUAddr))
+	user2CU.AddCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	ck.SetCU(ctx, user2CU)
+	result = keeper.BatchWithdrawal(ctx, user2CUAddr, batchID, symbol, reusedOutputs, gasFee)
+	require.Equal(t, sdk.CodeOK, result.Code, result.Log)
+
+	var user1Orders []*sdk.OrderWithdrawal
+	for _, output := range outputs {
+		user1Orders = append(user1Orders, ok.GetOrder(ctx, output.OrderID).(*sdk.OrderWithdrawal))
+	}
+	require.Nil(t, keeper.CheckBatchWithdrawalOrders(ctx, user1Orders))
+	require.NotNil(t, keeper.CheckBatchWithdrawalOrders(ctx, user1Orders[:1]))
+	user2Order := ok.GetOrder(ctx, reusedOutputs[0].OrderID).(*sdk.OrderWithdrawal)
+	require.Nil(t, keeper.CheckBatchWithdrawalOrders(ctx, []*sdk.OrderWithdrawal{user2Order}))
+}
+
+func TestBatchWithdrawalEthRefund(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	validators := input.validators
+	ctx = ctx.WithBlockHeight(10)
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := "eth"
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.WithdrawalFeeRate = sdk.NewDecWithPrec(1, 2)
+	tokenInfo.GasLimit = sdk.NewInt(10000)
+	tokenInfo.GasPrice = sdk.NewInt(100)
+	tk.SetToken(ctx, tokenInfo)
+
+	user1CUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	amt := sdk.NewInt(80000000)
+	user1CU := newTestCU(ck.GetCU(ctx, user1CUAddr))
+	user1CU.AddCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	ck.SetCU(ctx, user1CU)
+
+	toAddrs := []string{"0x2e9a512fc6fea120e567ed5faef1440e4f66b5ff", "0x81b7e08f65bdf5648606c89998a9cc8164397647"}
+	for _, toAddr := range toAddrs {
+		mockCN.On("ValidAddress", chain, symbol, toAddr).Return(true, toAddr)
+	}
+
+	batchID := uuid.NewV1().String()
+	outputs := []types.WithdrawalOutput{
+		types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[0], sdk.NewInt(10000000)),
+		types.NewWithdrawalOutput(uuid.NewV1().String(), toAddrs[1], sdk.NewInt(20000000)),
+	}
+	gasFee := sdk.NewInt(20001)
+	result := keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, outputs, gasFee.SubRaw(2))
+	require.Equal(t, sdk.CodeInsufficientFee, result.Code)
+
+	result = keeper.BatchWithdrawal(ctx, user1CUAddr, batchID, symbol, outputs, gasFee)
+	require.Equal(t, sdk.CodeOK, result.Code)
+	for _, output := range outputs {
+		order := ok.GetOrder(ctx, output.OrderID).(*sdk.OrderWithdrawal)
+		require.EqualValues(t, sdk.WithdrawStatusUnconfirmed, order.WithdrawStatus)
+	}
+
+	// the second output is refused and refunded alone
+	for i := 0; i < 3; i++ {
+		result = keeper.WithdrawalConfirm(ctx, sdk.CUAddress(validators[i].OperatorAddress), outputs[1].OrderID, false)
+		require.Equal(t, sdk.CodeOK, result.Code)
+	}
+
+	order := ok.GetOrder(ctx, outputs[1].OrderID).(*sdk.OrderWithdrawal)
+	require.EqualValues(t, sdk.WithdrawStatusInvalid, order.WithdrawStatus)
+	require.Equal(t, sdk.OrderStatusCancel, order.GetOrderStatus())
+	order = ok.GetOrder(ctx, outputs[0].OrderID).(*sdk.OrderWithdrawal)
+	require.EqualValues(t, sdk.WithdrawStatusUnconfirmed, order.WithdrawStatus)
+
+	held := outputs[0].Amount.Add(sdk.NewInt(10001))
+	user1CU = newTestCU(ck.GetCU(ctx, user1CUAddr))
+	require.Equal(t, held, user1CU.GetCoinsHold().AmountOf(symbol))
+	require.Equal(t, amt.Sub(held), user1CU.GetCoins().AmountOf(symbol))
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+)
+
+func TestEstimateWithdrawalFeeBtc(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ik := input.ik
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	mockCN = chainnode.MockChainnode{}
+	symbol := "btc"
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol(symbol))
+	tokenInfo.WithdrawalFeeRate = sdk.NewDecWithPrec(2, 0)
+	tokenInfo.GasPrice = sdk.NewInt(10000000 / 380)
+	tk.SetToken(ctx, tokenInfo)
+
+	opCUBtcAddress := "mh1DurxerNqH3nf9p3ivyn7yjgit1ep2Gg"
+	var deposits sdk.DepositList
+	for i, amt := range []int64{10000000, 20000000, 30000000} {
+		d, err := sdk.NewDepositItem("opcu_utxo_deposit", uint64(i), sdk.NewInt(amt), opCUBtcAddress, "", sdk.DepositItemStatusConfirmed)
+		require.Nil(t, err)
+		deposits = append(deposits, d)
+	}
+	btcOPCUAddr, err := sdk.CUAddressFromBase58("HBCPoshPen4yTWCwCvCVuwbfSmrb3EzNbXTo")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, btcOPCUAddr))
+	require.Nil(t, opCU.SetAssetAddress(symbol, opCUBtcAddress, 1))
+	opCU.SetAssetPubkey(ed25519.GenPrivKey().PubKey().Bytes(), 1)
+	opCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, sdk.NewInt(60000000))))
+	ck.SetCU(ctx, opCU)
+	ik.SetDepositList(ctx, symbol, btcOPCUAddr, deposits.Sort())
+
+	toAddr := "mnRw8TRyxUVEv1CnfzpahuRr5BeWYsCGES"
+	mockCN.On("ValidAddress", "btc", symbol, toAddr).Return(true, toAddr)
+	mockCN.On("ValidAddress", "btc", symbol, "invalid").Return(false, "")
+
+	_, sdkErr := keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(1000), "invalid")
+	require.Equal(t, sdk.CodeInvalidAddress, sdkErr.Code())
+	_, sdkErr = keeper.EstimateWithdrawalFee(ctx, "unknown", sdk.NewInt(1000), "")
+	require.Equal(t, sdk.CodeUnsupportToken, sdkErr.Code())
+
+	baseGasFee := sdk.DefaultUtxoWithdrawTxSize().Mul(tokenInfo.GasPrice).QuoRaw(sdk.KiloBytes)
+	platformFee := tokenInfo.WithdrawalFee().Amount.Sub(baseGasFee)
+
+	// the largest utxo is enough
+	res, sdkErr := keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(20000000), toAddr)
+	require.Nil(t, sdkErr)
+	require.Equal(t, 1, res.UtxoInputs)
+	require.False(t, res.NeedSysTransfer)
+	require.Equal(t, tokenInfo.WithdrawalFee(), res.MinGasFee)
+	require.Equal(t, platformFee, res.PlatformFee.Amount)
+	require.Equal(t, sdk.EstimateSignedUtxoTxSize(1, 2).Mul(tokenInfo.GasPrice).QuoRaw(sdk.KiloBytes), res.GasFee.Amount)
+	require.True(t, res.RecommendedGasFee.Amount.GTE(res.MinGasFee.Amount))
+
+	// more inputs cost more gas
+	res2, sdkErr := keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(45000000), toAddr)
+	require.Nil(t, sdkErr)
+	require.Equal(t, 2, res2.UtxoInputs)
+	require.True(t, res2.GasFee.Amount.GT(res.GasFee.Amount))
+	require.Equal(t, res2.GasFee.Amount.Add(platformFee), res2.RecommendedGasFee.Amount)
+
+	_, sdkErr = keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(60000000), toAddr)
+	require.Equal(t, sdk.CodeInsufficientCoins, sdkErr.Code())
+
+	// a cold OPCU only takes part in withdrawals above the cold withdrawal threshold
+	tokenInfo.OpCUTiers = &sdk.OpCUTierParams{
+		ColdOpCUs:               []sdk.CUAddress{btcOPCUAddr},
+		ColdWithdrawalThreshold: sdk.NewInt(30000000),
+	}
+	tk.SetToken(ctx, tokenInfo)
+	_, sdkErr = keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(20000000), toAddr)
+	require.Equal(t, sdk.CodeInsufficientCoins, sdkErr.Code())
+	res, sdkErr = keeper.EstimateWithdrawalFee(ctx, symbol, sdk.NewInt(45000000), toAddr)
+	require.Nil(t, sdkErr)
+	require.Equal(t, 2, res.UtxoInputs)
+}
+
+func TestEstimateWithdrawalFeeSysTransfer(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	tk := input.tk
+	mockCN = chainnode.MockChainnode{}
+
+	ethInfo := tk.GetIBCToken(ctx, sdk.Symbol("eth"))
+	res, sdkErr := keeper.EstimateWithdrawalFee(ctx, "eth", sdk.NewInt(1000), "")
+	require.Nil(t, sdkErr)
+	require.False(t, res.NeedSysTransfer)
+	require.Equal(t, ethInfo.GasPrice.Mul(ethInfo.GasLimit), res.GasFee.Amount)
+
+	// no OPCU has usdt and gas
+	usdtInfo := tk.GetIBCToken(ctx, sdk.Symbol("usdt"))
+	res, sdkErr = keeper.EstimateWithdrawalFee(ctx, "usdt", sdk.NewInt(1000), "")
+	require.Nil(t, sdkErr)
+	require.True(t, res.NeedSysTransfer)
+	require.Equal(t, "eth", res.GasFee.Denom)
+	require.Equal(t, usdtInfo.GasPrice.Mul(usdtInfo.GasLimit).Add(ethInfo.GasPrice.Mul(ethInfo.GasLimit)), res.GasFee.Amount)
+}
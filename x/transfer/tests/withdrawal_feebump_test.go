@@ -0,0 +1,322 @@
+package tests
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hbtc-chain/bhchain/chainnode"
+	sdk "github.com/hbtc-chain/bhchain/types"
+	"github.com/hbtc-chain/bhchain/x/custodianunit"
+)
+
+func TestWithdrawalEthFeeBump(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+	rk := input.rk
+
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	validators := input.validators
+	ctx = ctx.WithBlockHeight(10)
+
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := "eth"
+
+	//setup token
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol("eth"))
+	tokenInfo.WithdrawalFeeRate = sdk.NewDecWithPrec(1, 2)
+	tokenInfo.GasLimit = sdk.NewInt(10000)
+	tokenInfo.GasPrice = sdk.NewInt(100)
+	tk.SetToken(ctx, tokenInfo)
+
+	ethOPCUAddr, err := sdk.CUAddressFromBase58("HBCLXBebMwEWaEZYsqJij7xcpBayzJqdrKJP")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, sdk.CUAddress(ethOPCUAddr)))
+	opCUEthAddress := "0xd139E358aE9cB5424B2067da96F94cC938343446"
+	err = opCU.SetAssetAddress(symbol, opCUEthAddress, 1)
+	require.Nil(t, err)
+	opCUEthAmt := sdk.NewInt(90000000)
+	opCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, opCUEthAmt)))
+	ck.SetCU(ctx, opCU)
+
+	//set UserCU
+	user1CUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	amt := sdk.NewInt(80000000)
+	user1CU := newTestCU(ck.GetCU(ctx, sdk.CUAddress(user1CUAddr)))
+	user1CU.AddCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	err = user1CU.AddAsset(symbol, "0x81b7e08f65bdf5648606c89998a9cc8164397647", 1)
+	require.Nil(t, err)
+	ck.SetCU(ctx, user1CU)
+
+	withdrawalToAddr := "0xc96d141c9110a8E61eD62caaD8A7c858dB15B82c"
+	mockCN.On("ValidAddress", chain, symbol, withdrawalToAddr).Return(true, withdrawalToAddr)
+	mockCN.On("ValidAddress", chain, symbol, opCUEthAddress).Return(true, opCUEthAddress)
+
+	//Step1, Withdrawal
+	ctx = ctx.WithBlockHeight(11)
+	orderID := uuid.NewV1().String()
+	withdrawalAmt := sdk.NewInt(60000000)
+	gasFee := sdk.NewInt(1300000)
+	result := keeper.Withdrawal(ctx, user1CUAddr, withdrawalToAddr, orderID, symbol, withdrawalAmt, gasFee)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	for i := 0; i < 3; i++ {
+		result := keeper.WithdrawalConfirm(ctx, sdk.CUAddress(validators[i].GetOperator()), orderID, true)
+		require.Equalf(t, sdk.CodeOK, result.Code, "i:%d, log:%s", i, result.Log)
+	}
+
+	//Step2, WithdrawalWaitSign
+	withdrawalTxHash := "withdrawalTxHash"
+	chainnodewithdrawalTx := chainnode.ExtAccountTransaction{
+		Hash:     withdrawalTxHash,
+		From:     opCUEthAddress,
+		To:       withdrawalToAddr,
+		Amount:   withdrawalAmt,
+		Nonce:    0,
+		GasLimit: sdk.NewInt(10000),
+		GasPrice: sdk.NewInt(100),
+	}
+	suggestGasFee := sdk.NewInt(10000).MulRaw(100)
+
+	rawData := []byte("rawData")
+	signHash := []byte("signHash")
+	mockCN.On("QueryAccountTransactionFromData", chain, symbol, rawData).Return(&chainnodewithdrawalTx, signHash, nil)
+
+	ctx = ctx.WithBlockHeight(20)
+	result = keeper.WithdrawalWaitSign(ctx, ethOPCUAddr, []string{orderID}, [][]byte{signHash}, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	//Step3, WithdrawalSignFinish
+	signedData := []byte("signedData")
+	mockCN.On("VerifyAccountSignedTransaction", chain, symbol, opCUEthAddress, signedData).Return(true, nil)
+	mockCN.On("QueryAccountTransactionFromSignedData", chain, symbol, signedData).Return(&chainnodewithdrawalTx, nil).Once()
+
+	result = keeper.WithdrawalSignFinish(ctx, []string{orderID}, signedData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	//Step4, WithdrawalFeeBump, the tx is stuck and re-priced
+	bumpedTxHash := "bumpedTxHash"
+	bumpedTx := chainnodewithdrawalTx
+	bumpedTx.Hash = bumpedTxHash
+	bumpedTx.GasPrice = sdk.NewInt(115)
+	bumpedCostFee := sdk.NewInt(10000).MulRaw(115)
+	bumpedFee := bumpedCostFee.Sub(suggestGasFee)
+
+	bumpedRawData := []byte("bumpedRawData")
+	bumpedSignHash := []byte("bumpedSignHash")
+	mockCN.On("QueryAccountTransactionFromData", chain, symbol, bumpedRawData).Return(&bumpedTx, bumpedSignHash, nil)
+
+	// not a key node
+	ctx = ctx.WithBlockHeight(30)
+	result = keeper.WithdrawalFeeBump(ctx, user1CUAddr, []string{orderID}, [][]byte{bumpedSignHash}, bumpedRawData)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// sign hash mismatch
+	result = keeper.WithdrawalFeeBump(ctx, sdk.CUAddress(validators[0].GetOperator()), []string{orderID}, [][]byte{signHash}, bumpedRawData)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	// gas price not bumped
+	result = keeper.WithdrawalFeeBump(ctx, sdk.CUAddress(validators[0].GetOperator()), []string{orderID}, [][]byte{signHash}, rawData)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+
+	result = keeper.WithdrawalFeeBump(ctx, sdk.CUAddress(validators[0].GetOperator()), []string{orderID}, [][]byte{bumpedSignHash}, bumpedRawData)
+	require.Equal(t, sdk.CodeOK, result.Code, result.Log)
+
+	//check order
+	wo := ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+	require.Equal(t, sdk.OrderStatusWaitSign, wo.GetOrderStatus())
+	require.Equal(t, bumpedRawData, wo.RawData)
+	require.Equal(t, bumpedCostFee, wo.CostFee)
+	require.Equal(t, bumpedFee, wo.GetBumpedFee())
+	require.Equal(t, []string{withdrawalTxHash}, wo.ReplacedTxHashes)
+	require.Equal(t, [][]byte{signedData}, wo.ReplacedSignedTxs)
+	require.Equal(t, "", wo.Txhash)
+	require.Nil(t, wo.SignedTx)
+	require.Equal(t, uint64(20), wo.GetHeight())
+
+	//check receipt
+	receipt, err := rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	require.Equal(t, sdk.CategoryTypeWithdrawal, receipt.Category)
+	require.Equal(t, 2, len(receipt.Flows))
+
+	of, v := receipt.Flows[0].(sdk.OrderFlow)
+	require.True(t, v)
+	require.Equal(t, orderID, of.OrderID)
+	require.Equal(t, sdk.OrderStatusWaitSign, of.OrderStatus)
+
+	fbf, v := receipt.Flows[1].(sdk.WithdrawalFeeBumpFlow)
+	require.True(t, v)
+	require.Equal(t, []string{orderID}, fbf.OrderIDs)
+	require.Equal(t, withdrawalTxHash, fbf.ReplacedTxHash)
+	require.Equal(t, bumpedRawData, fbf.RawData)
+	require.Equal(t, bumpedCostFee, fbf.CostFee)
+	require.Equal(t, bumpedFee, fbf.BumpedFee)
+
+	//check OPCU's coins and coinsHold, the extra fee is locked
+	opCU = newTestCU(ck.GetCU(ctx, ethOPCUAddr))
+	require.Equal(t, withdrawalAmt.Add(bumpedCostFee), opCU.GetAssetCoinsHold().AmountOf(symbol))
+	require.Equal(t, opCUEthAmt.Sub(withdrawalAmt).Sub(bumpedCostFee), opCU.GetAssetCoins().AmountOf(symbol))
+
+	//Step5, WithdrawalSignFinish for the bumped tx
+	bumpedSignedData := []byte("bumpedSignedData")
+	mockCN.On("VerifyAccountSignedTransaction", chain, symbol, opCUEthAddress, bumpedSignedData).Return(true, nil)
+	mockCN.On("QueryAccountTransactionFromSignedData", chain, symbol, bumpedSignedData).Return(&bumpedTx, nil).Once()
+
+	result = keeper.WithdrawalSignFinish(ctx, []string{orderID}, bumpedSignedData)
+	require.Equal(t, sdk.CodeOK, result.Code, result.Log)
+	wo = ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+	require.Equal(t, bumpedTxHash, wo.Txhash)
+
+	//Step6, WithdrawalFinish
+	costFee := sdk.NewInt(1100000)
+	bumpedTx.CostFee = costFee
+	bumpedTx.Status = chainnode.StatusSuccess
+	mockCN.On("QueryAccountTransactionFromSignedData", chain, symbol, bumpedSignedData).Return(&bumpedTx, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		result = keeper.WithdrawalFinish(ctx, sdk.CUAddress(validators[i].GetOperator()), []string{orderID}, costFee, true)
+		require.Equalf(t, sdk.CodeOK, result.Code, "i:%d, log:%s", i, result.Log)
+	}
+
+	wo = ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+	require.Equal(t, sdk.OrderStatusFinish, wo.GetOrderStatus())
+	require.Equal(t, costFee, wo.CostFee)
+
+	receipt, err = rk.GetReceiptFromResult(&result)
+	require.Nil(t, err)
+	wff, v := receipt.Flows[1].(sdk.WithdrawalFinishFlow)
+	require.True(t, v)
+	require.Equal(t, costFee, wff.CostFee)
+	require.Equal(t, bumpedFee, wff.BumpedFee)
+
+	user1CU1 := newTestCU(ck.GetCU(ctx, user1CUAddr))
+	require.Equal(t, amt.Sub(withdrawalAmt).Sub(costFee), user1CU1.GetCoins().AmountOf(symbol))
+	require.Equal(t, sdk.ZeroInt(), user1CU1.GetCoinsHold().AmountOf(symbol))
+
+	opCU = newTestCU(ck.GetCU(ctx, ethOPCUAddr))
+	require.Equal(t, sdk.ZeroInt(), opCU.GetAssetCoinsHold().AmountOf(symbol))
+	require.Equal(t, opCUEthAmt.Sub(withdrawalAmt).Sub(costFee), opCU.GetAssetCoins().AmountOf(symbol))
+	require.True(t, opCU.IsEnabledSendTx(chain, opCUEthAddress))
+}
+
+func TestWithdrawalEthFeeBumpReplacedTxPacked(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	ck := input.ck
+	tk := input.tk
+	ok := input.ok
+
+	newTestCU := func(cu custodianunit.CU) *testCU {
+		return newTestCU(ctx, input.trk, input.ik, cu)
+	}
+	validators := input.validators
+
+	mockCN = chainnode.MockChainnode{}
+	symbol := "eth"
+	chain := "eth"
+
+	tokenInfo := tk.GetIBCToken(ctx, sdk.Symbol("eth"))
+	tokenInfo.WithdrawalFeeRate = sdk.NewDecWithPrec(1, 2)
+	tokenInfo.GasLimit = sdk.NewInt(10000)
+	tokenInfo.GasPrice = sdk.NewInt(100)
+	tk.SetToken(ctx, tokenInfo)
+
+	ethOPCUAddr, err := sdk.CUAddressFromBase58("HBCLXBebMwEWaEZYsqJij7xcpBayzJqdrKJP")
+	require.Nil(t, err)
+	opCU := newTestCU(ck.GetCU(ctx, sdk.CUAddress(ethOPCUAddr)))
+	opCUEthAddress := "0xd139E358aE9cB5424B2067da96F94cC938343446"
+	require.Nil(t, opCU.SetAssetAddress(symbol, opCUEthAddress, 1))
+	opCUEthAmt := sdk.NewInt(90000000)
+	opCU.AddAssetCoins(sdk.NewCoins(sdk.NewCoin(symbol, opCUEthAmt)))
+	ck.SetCU(ctx, opCU)
+
+	user1CUAddr, err := sdk.CUAddressFromBase58("HBCLmQcskpdQivEkRrh1gNPm7c9aVB8hh1fy")
+	require.Nil(t, err)
+	amt := sdk.NewInt(80000000)
+	user1CU := newTestCU(ck.GetCU(ctx, sdk.CUAddress(user1CUAddr)))
+	user1CU.AddCoins(sdk.NewCoins(sdk.NewCoin(symbol, amt)))
+	require.Nil(t, user1CU.AddAsset(symbol, "0x81b7e08f65bdf5648606c89998a9cc8164397647", 1))
+	ck.SetCU(ctx, user1CU)
+
+	withdrawalToAddr := "0xc96d141c9110a8E61eD62caaD8A7c858dB15B82c"
+	mockCN.On("ValidAddress", chain, symbol, withdrawalToAddr).Return(true, withdrawalToAddr)
+	mockCN.On("ValidAddress", chain, symbol, opCUEthAddress).Return(true, opCUEthAddress)
+
+	ctx = ctx.WithBlockHeight(11)
+	orderID := uuid.NewV1().String()
+	withdrawalAmt := sdk.NewInt(60000000)
+	result := keeper.Withdrawal(ctx, user1CUAddr, withdrawalToAddr, orderID, symbol, withdrawalAmt, sdk.NewInt(1300000))
+	require.Equal(t, sdk.CodeOK, result.Code)
+	for i := 0; i < 3; i++ {
+		result := keeper.WithdrawalConfirm(ctx, sdk.CUAddress(validators[i].GetOperator()), orderID, true)
+		require.Equalf(t, sdk.CodeOK, result.Code, "i:%d, log:%s", i, result.Log)
+	}
+
+	withdrawalTxHash := "withdrawalTxHash"
+	withdrawalTx := chainnode.ExtAccountTransaction{
+		Hash:     withdrawalTxHash,
+		From:     opCUEthAddress,
+		To:       withdrawalToAddr,
+		Amount:   withdrawalAmt,
+		Nonce:    0,
+		GasLimit: sdk.NewInt(10000),
+		GasPrice: sdk.NewInt(100),
+	}
+	rawData := []byte("rawData")
+	signHash := []byte("signHash")
+	mockCN.On("QueryAccountTransactionFromData", chain, symbol, rawData).Return(&withdrawalTx, signHash, nil)
+	ctx = ctx.WithBlockHeight(20)
+	result = keeper.WithdrawalWaitSign(ctx, ethOPCUAddr, []string{orderID}, [][]byte{signHash}, rawData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	signedData := []byte("signedData")
+	mockCN.On("VerifyAccountSignedTransaction", chain, symbol, opCUEthAddress, signedData).Return(true, nil)
+	mockCN.On("QueryAccountTransactionFromSignedData", chain, symbol, signedData).Return(&withdrawalTx, nil)
+	result = keeper.WithdrawalSignFinish(ctx, []string{orderID}, signedData)
+	require.Equal(t, sdk.CodeOK, result.Code)
+
+	bumpedTx := withdrawalTx
+	bumpedTx.Hash = "bumpedTxHash"
+	bumpedTx.GasPrice = sdk.NewInt(115)
+	bumpedRawData := []byte("bumpedRawData")
+	bumpedSignHash := []byte("bumpedSignHash")
+	mockCN.On("QueryAccountTransactionFromData", chain, symbol, bumpedRawData).Return(&bumpedTx, bumpedSignHash, nil)
+	ctx = ctx.WithBlockHeight(30)
+	result = keeper.WithdrawalFeeBump(ctx, sdk.CUAddress(validators[0].GetOperator()), []string{orderID}, [][]byte{bumpedSignHash}, bumpedRawData)
+	require.Equal(t, sdk.CodeOK, result.Code, result.Log)
+
+	// the replaced tx is packed before the bumped one is signed
+	costFee := sdk.NewInt(1000000)
+	result = keeper.WithdrawalReplacedTxFinish(ctx, sdk.CUAddress(validators[0].GetOperator()), []string{orderID}, "unknownTxHash", costFee, true)
+	require.Equal(t, sdk.CodeInvalidTx, result.Code)
+	for i := 0; i < 3; i++ {
+		result = keeper.WithdrawalReplacedTxFinish(ctx, sdk.CUAddress(validators[i].GetOperator()), []string{orderID}, withdrawalTxHash, costFee, true)
+		require.Equalf(t, sdk.CodeOK, result.Code, "i:%d, log:%s", i, result.Log)
+	}
+
+	wo := ok.GetOrder(ctx, orderID).(*sdk.OrderWithdrawal)
+	require.Equal(t, sdk.OrderStatusFinish, wo.GetOrderStatus())
+	require.Equal(t, costFee, wo.CostFee)
+
+	user1CU1 := newTestCU(ck.GetCU(ctx, user1CUAddr))
+	require.Equal(t, amt.Sub(withdrawalAmt).Sub(costFee), user1CU1.GetCoins().AmountOf(symbol))
+	require.Equal(t, sdk.ZeroInt(), user1CU1.GetCoinsHold().AmountOf(symbol))
+
+	// the extra fee locked by the bump is released
+	opCU = newTestCU(ck.GetCU(ctx, ethOPCUAddr))
+	require.Equal(t, sdk.ZeroInt(), opCU.GetAssetCoinsHold().AmountOf(symbol))
+	require.Equal(t, opCUEthAmt.Sub(withdrawalAmt).Sub(costFee), opCU.GetAssetCoins().AmountOf(symbol))
+	require.Equal(t, uint64(1), input.ik.GetCUIBCAsset(ctx, ethOPCUAddr).GetNonce(chain, opCUEthAddress))
+	require.True(t, opCU.IsEnabledSendTx(chain, opCUEthAddress))
+}
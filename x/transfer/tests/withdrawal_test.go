@@ -2298,6 +2298,34 @@ func TestCheckWithdrawalOpCU(t *testing.T) {
 
 }
 
+func TestCheckOpCUSignThreshold(t *testing.T) {
+	input := setupTestInput(t)
+	keeper := input.k
+	ctx := input.ctx
+	opCUAst := input.ik.GetCUIBCAsset(ctx, input.opcu.GetAddress())
+
+	btcInfo := input.tk.GetIBCToken(ctx, "btc")
+	btcInfo.KeyNodeThreshold = 100
+	input.tk.SetToken(ctx, btcInfo)
+	// hot OPCUs keep signing until their keys are rotated
+	require.Nil(t, keeper.CheckOpCUSignThreshold(ctx, opCUAst, btcInfo))
+
+	btcInfo.OpCUTiers = &sdk.OpCUTierParams{
+		ColdOpCUs:               []sdk.CUAddress{input.opcu.GetAddress()},
+		ColdWithdrawalThreshold: sdk.NewInt(100),
+		HotHighWatermark:        sdk.NewInt(50),
+		HotLowWatermark:         sdk.NewInt(10),
+	}
+	input.tk.SetToken(ctx, btcInfo)
+	sdkErr := keeper.CheckOpCUSignThreshold(ctx, opCUAst, btcInfo)
+	require.Equal(t, sdk.CodeInvalidTx, sdkErr.Code())
+	require.Contains(t, sdkErr.Error(), "required by btc")
+
+	// the rotated key is generated with the raised threshold
+	opCUAst.SetAssetPubkeyThreshold(uint64(len(input.validators)))
+	require.Nil(t, keeper.CheckOpCUSignThreshold(ctx, opCUAst, btcInfo))
+}
+
 func TestCheckDecodedUtxoTransaction(t *testing.T) {
 	input := setupTestInput(t)
 	keeper := input.k
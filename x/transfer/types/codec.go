@@ -10,6 +10,8 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgMultiSend{}, "hbtcchain/transfer/MsgMultiSend", nil)
 	cdc.RegisterConcrete(MsgDeposit{}, "hbtcchain/transfer/MsgDeposit", nil)
 	cdc.RegisterConcrete(MsgConfirmedDeposit{}, "hbtcchain/transfer/MsgConfirmedDeposit", nil)
+	cdc.RegisterConcrete(MsgInvalidateDeposit{}, "hbtcchain/transfer/MsgInvalidateDeposit", nil)
+	cdc.RegisterConcrete(MsgSettleDepositDeficit{}, "hbtcchain/transfer/MsgSettleDepositDeficit", nil)
 	cdc.RegisterConcrete(MsgCollectWaitSign{}, "hbtcchain/transfer/MsgCollectWaitSign", nil)
 	cdc.RegisterConcrete(MsgCollectSignFinish{}, "hbtcchain/transfer/MsgCollectSignFinish", nil)
 	cdc.RegisterConcrete(MsgCollectFinish{}, "hbtcchain/transfer/MsgCollectFinish", nil)
@@ -18,6 +20,8 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgWithdrawalWaitSign{}, "hbtcchain/transfer/MsgWithdrawalWaitSign", nil)
 	cdc.RegisterConcrete(MsgWithdrawalSignFinish{}, "hbtcchain/transfer/MsgWithdrawalSignFinish", nil)
 	cdc.RegisterConcrete(MsgWithdrawalFinish{}, "hbtcchain/transfer/MsgWithdrawalFinish", nil)
+	cdc.RegisterConcrete(MsgBatchWithdrawal{}, "hbtcchain/transfer/MsgBatchWithdrawal", nil)
+	cdc.RegisterConcrete(MsgWithdrawalFeeBump{}, "hbtcchain/transfer/MsgWithdrawalFeeBump", nil)
 	cdc.RegisterConcrete(MsgSysTransfer{}, "hbtcchain/transfer/MsgSysTransfer", nil)
 	cdc.RegisterConcrete(MsgSysTransferWaitSign{}, "hbtcchain/transfer/MsgSysTransferWaitSign", nil)
 	cdc.RegisterConcrete(MsgSysTransferSignFinish{}, "hbtcchain/transfer/MsgSysTransferSignFinish", nil)
@@ -26,8 +30,21 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgOpcuAssetTransferWaitSign{}, "hbtcchain/transfer/MsgOpcuAssetTransferWaitSign", nil)
 	cdc.RegisterConcrete(MsgOpcuAssetTransferSignFinish{}, "hbtcchain/transfer/MsgOpcuAssetTransferSignFinish", nil)
 	cdc.RegisterConcrete(MsgOpcuAssetTransferFinish{}, "hbtcchain/transfer/MsgOpcuAssetTransferFinish", nil)
+	cdc.RegisterConcrete(MsgUtxoConsolidationWaitSign{}, "hbtcchain/transfer/MsgUtxoConsolidationWaitSign", nil)
+	cdc.RegisterConcrete(MsgUtxoConsolidationSignFinish{}, "hbtcchain/transfer/MsgUtxoConsolidationSignFinish", nil)
+	cdc.RegisterConcrete(MsgUtxoConsolidationFinish{}, "hbtcchain/transfer/MsgUtxoConsolidationFinish", nil)
 	cdc.RegisterConcrete(MsgOrderRetry{}, "hbtcchain/transfer/MsgOrderRetry", nil)
 	cdc.RegisterConcrete(MsgCancelWithdrawal{}, "hbtcchain/transfer/MsgCancelWithdrawal", nil)
+	cdc.RegisterConcrete(MsgSetDepositRoute{}, "hbtcchain/transfer/MsgSetDepositRoute", nil)
+	cdc.RegisterConcrete(MsgReassignSuspense{}, "hbtcchain/transfer/MsgReassignSuspense", nil)
+	cdc.RegisterConcrete(MsgCreateHTLC{}, "hbtcchain/transfer/MsgCreateHTLC", nil)
+	cdc.RegisterConcrete(MsgClaimHTLC{}, "hbtcchain/transfer/MsgClaimHTLC", nil)
+	cdc.RegisterConcrete(MsgRefundHTLC{}, "hbtcchain/transfer/MsgRefundHTLC", nil)
+	cdc.RegisterConcrete(MsgScheduleTransfer{}, "hbtcchain/transfer/MsgScheduleTransfer", nil)
+	cdc.RegisterConcrete(MsgCancelScheduledTransfer{}, "hbtcchain/transfer/MsgCancelScheduledTransfer", nil)
+	cdc.RegisterConcrete(MsgCreateVestingCU{}, "hbtcchain/transfer/MsgCreateVestingCU", nil)
+	cdc.RegisterConcrete(MsgCreatePeriodicVestingCU{}, "hbtcchain/transfer/MsgCreatePeriodicVestingCU", nil)
+	cdc.RegisterConcrete(FreezeAddressesProposal{}, "hbtcchain/transfer/FreezeAddressesProposal", nil)
 	cdc.RegisterConcrete(&TxVote{}, "hbtcchain/transfer/FinishTxVote", nil)
 	cdc.RegisterConcrete(&OrderRetryVoteBox{}, "hbtcchain/transfer/OrderRetryVoteBox", nil)
 	cdc.RegisterConcrete(&OrderRetryVoteItem{}, "hbtcchain/transfer/OrderRetryVoteItem", nil)
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// DepositDeficit records the coins of a CU credited by invalidated deposits, withdrawals of the CU stay frozen
+// until all its deficits are settled.
+type DepositDeficit struct {
+	Symbol string `json:"symbol"`
+	// Held is the unspent part moved into hold
+	Held sdk.Int `json:"held"`
+	// Deficit is the part which had been spent before the invalidation
+	Deficit sdk.Int `json:"deficit"`
+}
+
+func NewDepositDeficit(symbol string) DepositDeficit {
+	return DepositDeficit{
+		Symbol:  symbol,
+		Held:    sdk.ZeroInt(),
+		Deficit: sdk.ZeroInt(),
+	}
+}
+
+// IsZero returns true if nothing is left to settle
+func (d DepositDeficit) IsZero() bool {
+	return d.Held.IsZero() && d.Deficit.IsZero()
+}
+
+func (d DepositDeficit) String() string {
+	return fmt.Sprintf("Symbol:%v Held:%v Deficit:%v", d.Symbol, d.Held, d.Deficit)
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+
 	sdk "github.com/hbtc-chain/bhchain/types"
 )
 
@@ -11,6 +13,7 @@ const (
 
 	CodeInvalidInput         CodeType     = 101
 	CodeSendDisabled         sdk.CodeType = 102
+	CodeAddressFrozen        sdk.CodeType = 103
 	CodeInvalidInputsOutputs sdk.CodeType = 13
 
 	CodeInvalidAddress CodeType = sdk.CodeInvalidAddress
@@ -36,6 +39,11 @@ func ErrSendDisabled(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeSendDisabled, "send transactions are currently disabled")
 }
 
+// ErrAddressFrozen is an error
+func ErrAddressFrozen(codespace sdk.CodespaceType, addr sdk.CUAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeAddressFrozen, fmt.Sprintf("address %s is frozen", addr))
+}
+
 func ErrBadAddress(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidAddress, "address is invalid")
 }
@@ -6,6 +6,12 @@ const (
 	EventTypeMultiTransfer          = "multi_transfer"
 	EventTypeDeposit                = "deposit"
 	EventTypeDepositConfirm         = "deposit_confirm"
+	EventTypeAggregateDustDeposits  = "aggregate_dust_deposits"
+	EventTypeInvalidateDeposit      = "invalidate_deposit"
+	EventTypeSettleDepositDeficit   = "settle_deposit_deficit"
+	EventTypeNetDepositDeficit      = "net_deposit_deficit"
+	EventTypeSetDepositRoute        = "set_deposit_route"
+	EventTypeReassignSuspense       = "reassign_suspense"
 	EventTypeCollectWaitSign        = "collect_wait_sign"
 	EventTypeCollectSignFinish      = "collect_sign_finish"
 	EventTypeCollectFinish          = "collect_finish"
@@ -14,6 +20,8 @@ const (
 	EventTypeWithdrawalWaitSign     = "withdrawal_wait_sign"
 	EventTypeWithdrawalSignFinish   = "withdrawal_sign_finish"
 	EventTypeWithdrawalFinish       = "withdrawal_finish"
+	EventTypeWithdrawalFeeBump      = "withdrawal_fee_bump"
+	EventTypeBatchWithdrawal        = "batch_withdrawal"
 	EventTypeCancelWithdrawal       = "cancel_withdrawal"
 	EventTypeSysTransfer            = "sys_transfer"
 	EventTypeSysTransferWaitSign    = "sys_transfer_wait_sign"
@@ -24,6 +32,25 @@ const (
 	EventTypeOpcuTransferSignFinish = "opcu_transfer_sign_finish"
 	EventTypeOpcuTransferFinish     = "opcu_transfer_finish"
 	EventTypeOrderRetry             = "order_retry"
+	EventTypeCreateHTLC             = "create_htlc"
+	EventTypeClaimHTLC              = "claim_htlc"
+	EventTypeRefundHTLC             = "refund_htlc"
+
+	EventTypeScheduleTransfer         = "schedule_transfer"
+	EventTypeExecuteScheduledTransfer = "execute_scheduled_transfer"
+	EventTypeCancelScheduledTransfer  = "cancel_scheduled_transfer"
+
+	EventTypeCreateVestingCU = "create_vesting_cu"
+
+	EventTypeExecuteFreezeAddressesProposal = "execute_freeze_addresses_proposal"
+
+	EventTypeUtxoConsolidation           = "utxo_consolidation"
+	EventTypeUtxoConsolidationWaitSign   = "utxo_consolidation_wait_sign"
+	EventTypeUtxoConsolidationSignFinish = "utxo_consolidation_sign_finish"
+	EventTypeUtxoConsolidationFinish     = "utxo_consolidation_finish"
+	EventTypeUtxoConsolidationTimeout    = "utxo_consolidation_timeout"
+
+	EventTypeOpCURebalance = "opcu_rebalance"
 
 	AttributeKeyRecipient       = "recipient"
 	AttributeKeySender          = "sender"
@@ -36,6 +63,15 @@ const (
 	AttributeKeyOrderID         = "order_id"
 	AttributeKeyValidOrderIDs   = "valid_order_ids"
 	AttributeKeyInvalidOrderIDs = "invalid_order_ids"
+	AttributeKeyHashlock        = "hashlock"
+	AttributeKeyPreimage        = "preimage"
+	AttributeKeyExpireHeight    = "expire_height"
+	AttributeKeyScheduleID      = "schedule_id"
+	AttributeKeyRemaining       = "remaining"
+	AttributeKeyStartTime       = "start_time"
+	AttributeKeyEndTime         = "end_time"
+	AttributeKeyAddress         = "address"
+	AttributeKeyFreeze          = "freeze"
 
 	AttributeValueCategory = ModuleName
 )
@@ -28,6 +28,7 @@ type IBCAssetKeeper interface {
 	GetCUIBCAsset(context sdk.Context, addresses sdk.CUAddress) ibcexported.CUIBCAsset
 	NewCUIBCAssetWithAddress(ctx sdk.Context, cuType sdk.CUType, cuaddr sdk.CUAddress) ibcexported.CUIBCAsset
 	SetCUIBCAsset(ctx sdk.Context, cuAst ibcexported.CUIBCAsset)
+	IterateCUAssets(ctx sdk.Context, process func(asset ibcexported.CUIBCAsset) (stop bool))
 
 	//Deposit operation
 	GetDepositList(ctx sdk.Context, symbol string, address sdk.CUAddress) sdk.DepositList
@@ -55,6 +56,15 @@ type ReceiptKeeper interface {
 	NewDepositFlow(CuAddress, multisignedadress, symbol, txhash, orderID, memo string,
 		index uint64, amount sdk.Int, depositType sdk.DepositType, epoch uint64) sdk.DepositFlow
 	NewDepositConfirmedFlow(validOrderIds, invalidOrderIds []string) sdk.DepositConfirmedFlow
+	NewDepositInvalidatedFlow(orderID, cuAddress, symbol, txHash string, index uint64, amount, heldAmount, deficit sdk.Int) sdk.DepositInvalidatedFlow
+	NewDepositRoutedFlow(orderID, fromCU, toCU, memo, symbol string, amount sdk.Int, suspense bool) sdk.DepositRoutedFlow
+	NewSuspenseReassignedFlow(fromCU, toCU, symbol string, amount sdk.Int) sdk.SuspenseReassignedFlow
+	NewHTLCCreatedFlow(hashlock, sender, receiver string, amount sdk.Coins, expireHeight uint64) sdk.HTLCCreatedFlow
+	NewHTLCClaimedFlow(hashlock, preimage, sender, receiver string, amount sdk.Coins) sdk.HTLCClaimedFlow
+	NewHTLCRefundedFlow(hashlock, sender string, amount sdk.Coins) sdk.HTLCRefundedFlow
+	NewScheduledTransferCreatedFlow(id uint64, fromCU, toCU string, amount sdk.Coins, byTime bool, start, interval, times uint64) sdk.ScheduledTransferCreatedFlow
+	NewScheduledTransferExecutedFlow(id uint64, fromCU, toCU string, amount sdk.Coins, remaining uint64) sdk.ScheduledTransferExecutedFlow
+	NewScheduledTransferCancelledFlow(id uint64, fromCU string, refund sdk.Coins) sdk.ScheduledTransferCancelledFlow
 	NewOrderRetryFlow(orderIDs []string, excludedKeyNode sdk.CUAddress) sdk.OrderRetryFlow
 
 	NewCollectWaitSignFlow(orderIDs []string, rawData []byte) sdk.CollectWaitSignFlow
@@ -64,7 +74,9 @@ type ReceiptKeeper interface {
 	NewWithdrawalConfirmFlow(orderID string, status sdk.WithdrawStatus) sdk.WithdrawalConfirmFlow
 	NewWithdrawalWaitSignFlow(orderIDs []string, opcu, fromAddr string, rawData []byte) sdk.WithdrawalWaitSignFlow
 	NewWithdrawalSignFinishFlow(orderIDs []string, signedTx []byte) sdk.WithdrawalSignFinishFlow
-	NewWithdrawalFinishFlow(orderIDs []string, costFee sdk.Int, valid bool) sdk.WithdrawalFinishFlow
+	NewWithdrawalFinishFlow(orderIDs []string, costFee sdk.Int, valid bool, bumpedFee sdk.Int) sdk.WithdrawalFinishFlow
+	NewBatchWithdrawalFlow(batchID string, orderIDs []string) sdk.BatchWithdrawalFlow
+	NewWithdrawalFeeBumpFlow(orderIDs []string, replacedTxHash string, rawData []byte, costFee, bumpedFee sdk.Int) sdk.WithdrawalFeeBumpFlow
 	NewSysTransferFlow(orderID, fromcu, tocu, fromAddr, toaddr, symbol string, amount sdk.Int) sdk.SysTransferFlow
 	NewSysTransferWaitSignFlow(orderID string, rawData []byte) sdk.SysTransferWaitSignFlow
 	NewSysTransferSignFinishFlow(orderID string, signedTx []byte) sdk.SysTransferSignFinishFlow
@@ -73,9 +85,14 @@ type ReceiptKeeper interface {
 	NewOpcuAssetTransferWaitSignFlow(orderID string, rawData []byte) sdk.OpcuAssetTransferWaitSignFlow
 	NewOpcuAssetTransferSignFinishFlow(orderID string, signedTx []byte) sdk.OpcuAssetTransferSignFinishFlow
 	NewOpcuAssetTransferFinishFlow(orderID string, costFee sdk.Int) sdk.OpcuAssetTransferFinishFlow
+	NewUtxoConsolidationWaitSignFlow(orderID string, rawData []byte) sdk.UtxoConsolidationWaitSignFlow
+	NewUtxoConsolidationSignFinishFlow(orderID string, signedTx []byte) sdk.UtxoConsolidationSignFinishFlow
+	NewUtxoConsolidationFinishFlow(orderID string, costFee sdk.Int) sdk.UtxoConsolidationFinishFlow
 
 	SaveReceiptToResult(receipt *sdk.Receipt, result *sdk.Result) *sdk.Result
 	GetReceiptFromResult(result *sdk.Result) (*sdk.Receipt, error)
+	SaveReceiptToEvents(receipt *sdk.Receipt, em *sdk.EventManager)
+	GetReceiptsFromEvents(events sdk.Events) ([]*sdk.Receipt, error)
 }
 
 type OrderKeeper interface {
@@ -92,6 +109,8 @@ type OrderKeeper interface {
 		amount, costFee sdk.Int, toCU, toAddr, opCUAddr, fromAddr string) *sdk.OrderSysTransfer
 	NewOrderOpcuAssetTransfer(ctx sdk.Context, from sdk.CUAddress, orderID string, symbol string,
 		items []sdk.TransferItem, toAddr string) *sdk.OrderOpcuAssetTransfer
+	NewOrderUtxoConsolidation(ctx sdk.Context, opcu sdk.CUAddress, orderID string, symbol string,
+		items []sdk.TransferItem, addr string) *sdk.OrderUtxoConsolidation
 	RemoveProcessOrder(ctx sdk.Context, orderType sdk.OrderType, orderID string)
 	GetProcessOrderListByType(ctx sdk.Context, orderTypes ...sdk.OrderType) []string
 }
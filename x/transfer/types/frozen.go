@@ -0,0 +1,41 @@
+package types
+
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+	mappingtypes "github.com/hbtc-chain/bhchain/x/mapping/types"
+	openswaptypes "github.com/hbtc-chain/bhchain/x/openswap/types"
+)
+
+// frozenRestrictedMsgs are the types of msgs, by route, which spend the coins of their signers.
+// A frozen address can not sign them, the msgs returning coins to their signers or receiving deposits are not listed.
+// The keeper rejects spending the coins of a frozen address as well, this list only rejects the txs early in the ante handler.
+var frozenRestrictedMsgs = map[string]map[string]bool{
+	RouterKey: {
+		MsgSend{}.Type():                    true,
+		MsgMultiSend{}.Type():               true,
+		MsgWithdrawal{}.Type():              true,
+		MsgBatchWithdrawal{}.Type():         true,
+		MsgReassignSuspense{}.Type():        true,
+		MsgCreateHTLC{}.Type():              true,
+		MsgScheduleTransfer{}.Type():        true,
+		MsgCreateVestingCU{}.Type():         true,
+		MsgCreatePeriodicVestingCU{}.Type(): true,
+	},
+	mappingtypes.RouterKey: {
+		mappingtypes.TypeMsgMappingSwap:      true,
+		mappingtypes.TypeMsgCreateFreeSwap:   true,
+		mappingtypes.TypeMsgCreateDirectSwap: true,
+		mappingtypes.TypeMsgSwapSymbol:       true,
+	},
+	openswaptypes.RouterKey: {
+		openswaptypes.TypeMsgAddLiquidity: true,
+		openswaptypes.TypeMsgSwapExactIn:  true,
+		openswaptypes.TypeMsgSwapExactOut: true,
+		openswaptypes.TypeMsgLimitSwap:    true,
+	},
+}
+
+// IsFrozenRestrictedMsg returns true if msg can not be signed by a frozen address
+func IsFrozenRestrictedMsg(msg sdk.Msg) bool {
+	return frozenRestrictedMsgs[msg.Route()][msg.Type()]
+}
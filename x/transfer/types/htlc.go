@@ -0,0 +1,50 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// HTLCHashLength is the byte length of both the hashlock and the preimage of an HTLC
+const HTLCHashLength = sha256.Size
+
+// HTLC is a hash time-locked transfer, Amount is held on Sender until it's claimed
+// by Receiver with the preimage of Hashlock, or refunded to Sender at ExpireHeight.
+// A claimed HTLC is kept with its Preimage, so that its hashlock can not be reused by Sender.
+type HTLC struct {
+	Hashlock     []byte        `json:"hashlock"`
+	Sender       sdk.CUAddress `json:"sender"`
+	Receiver     sdk.CUAddress `json:"receiver"`
+	Amount       sdk.Coins     `json:"amount"`
+	ExpireHeight uint64        `json:"expire_height"`
+	Preimage     []byte        `json:"preimage"`
+}
+
+func (h HTLC) IsExpired(height uint64) bool {
+	return height >= h.ExpireHeight
+}
+
+func (h HTLC) IsClaimed() bool {
+	return len(h.Preimage) > 0
+}
+
+// DecodeHTLCHash decodes a hex encoded hashlock or preimage
+func DecodeHTLCHash(s string) ([]byte, error) {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != HTLCHashLength {
+		return nil, fmt.Errorf("length %v, expected %v", len(bz), HTLCHashLength)
+	}
+	return bz, nil
+}
+
+// HTLCHashlock returns the hashlock of the preimage
+func HTLCHashlock(preimage []byte) []byte {
+	h := sha256.Sum256(preimage)
+	return h[:]
+}
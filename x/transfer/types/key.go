@@ -31,6 +31,29 @@ var (
 
 	balanceKeyPrefix     = []byte{0x03}
 	holdBalanceKeyPrefix = []byte{0x04}
+
+	depositDeficitKeyPrefix   = []byte{0x05}
+	withdrawalFrozenKeyPrefix = []byte{0x06}
+
+	gasPriceAverageKeyPrefix = []byte{0x07}
+
+	depositRouteKeyPrefix    = []byte{0x08}
+	suspenseBalanceKeyPrefix = []byte{0x09}
+
+	htlcKeyPrefix = []byte{0x0A}
+
+	scheduledTransferKeyPrefix      = []byte{0x0B}
+	scheduledTransferQueueKeyPrefix = []byte{0x0C}
+	scheduledTransferOwnerKeyPrefix = []byte{0x0D}
+	ScheduledTransferIDKey          = []byte{0x0E}
+
+	frozenAddressKeyPrefix = []byte{0x0F}
+
+	batchWithdrawalKeyPrefix = []byte{0x10}
+
+	dustDepositKeyPrefix = []byte{0x11}
+
+	scheduledTransferParkedKeyPrefix = []byte{0x12}
 )
 
 func GetOrderRetryEvidenceHandledKey(txID string, retryTimes uint32) []byte {
@@ -55,6 +78,26 @@ func HoldBalanceKeyPrefix(addr sdk.CUAddress) []byte {
 	return append(holdBalanceKeyPrefix, addr...)
 }
 
+func DepositDeficitKey(addr sdk.CUAddress, symbol string) []byte {
+	return append(DepositDeficitKeyPrefix(addr), []byte(symbol)...)
+}
+
+func DepositDeficitKeyPrefix(addr sdk.CUAddress) []byte {
+	return append(depositDeficitKeyPrefix, addr...)
+}
+
+func WithdrawalFrozenKey(addr sdk.CUAddress) []byte {
+	return append(withdrawalFrozenKeyPrefix, addr...)
+}
+
+func GetAddressFromBalanceKey(key []byte) sdk.CUAddress {
+	return sdk.CUAddress(key[len(balanceKeyPrefix) : len(balanceKeyPrefix)+sdk.AddrLen])
+}
+
+func GetAddressFromHoldBalanceKey(key []byte) sdk.CUAddress {
+	return sdk.CUAddress(key[len(holdBalanceKeyPrefix) : len(holdBalanceKeyPrefix)+sdk.AddrLen])
+}
+
 func GetSymbolFromBalanceKey(key []byte) string {
 	return string(key[len(balanceKeyPrefix)+sdk.AddrLen:])
 }
@@ -62,3 +105,121 @@ func GetSymbolFromBalanceKey(key []byte) string {
 func GetSymbolFromHoldBalanceKey(key []byte) string {
 	return string(key[len(holdBalanceKeyPrefix)+sdk.AddrLen:])
 }
+
+func GasPriceAverageKey(chain string) []byte {
+	return append(gasPriceAverageKeyPrefix, []byte(chain)...)
+}
+
+// DepositRouteKey: prefix + owner + memo
+func DepositRouteKey(owner sdk.CUAddress, memo string) []byte {
+	return append(DepositRouteKeyPrefix(owner), []byte(memo)...)
+}
+
+func DepositRouteKeyPrefix(owner sdk.CUAddress) []byte {
+	return append(depositRouteKeyPrefix, owner...)
+}
+
+func GetMemoFromDepositRouteKey(key []byte) string {
+	return string(key[len(depositRouteKeyPrefix)+sdk.AddrLen:])
+}
+
+func SuspenseBalanceKey(owner sdk.CUAddress, symbol string) []byte {
+	return append(SuspenseBalanceKeyPrefix(owner), []byte(symbol)...)
+}
+
+func SuspenseBalanceKeyPrefix(owner sdk.CUAddress) []byte {
+	return append(suspenseBalanceKeyPrefix, owner...)
+}
+
+func GetAddressFromSuspenseBalanceKey(key []byte) sdk.CUAddress {
+	return sdk.CUAddress(key[len(suspenseBalanceKeyPrefix) : len(suspenseBalanceKeyPrefix)+sdk.AddrLen])
+}
+
+func GetSymbolFromSuspenseBalanceKey(key []byte) string {
+	return string(key[len(suspenseBalanceKeyPrefix)+sdk.AddrLen:])
+}
+
+func HTLCKey(sender sdk.CUAddress, hashlock []byte) []byte {
+	return append(append(htlcKeyPrefix, sender...), hashlock...)
+}
+
+func FrozenAddressKey(addr sdk.CUAddress) []byte {
+	return append(FrozenAddressKeyPrefix(), addr...)
+}
+
+func FrozenAddressKeyPrefix() []byte {
+	return frozenAddressKeyPrefix
+}
+
+func GetAddressFromFrozenAddressKey(key []byte) sdk.CUAddress {
+	return sdk.CUAddress(key[len(frozenAddressKeyPrefix):])
+}
+
+func ScheduledTransferKey(id uint64) []byte {
+	return append(scheduledTransferKeyPrefix, sdk.Uint64ToBigEndian(id)...)
+}
+
+// ScheduledTransferQueueKey: prefix + byTime + due height or unix time + id
+func ScheduledTransferQueueKey(byTime bool, due, id uint64) []byte {
+	return append(ScheduledTransferQueuePrefix(byTime, due), sdk.Uint64ToBigEndian(id)...)
+}
+
+// ScheduledTransferQueuePrefix returns the prefix of the scheduled transfers due at due,
+// it's also the exclusive end of the iteration of the ones due before due.
+func ScheduledTransferQueuePrefix(byTime bool, due uint64) []byte {
+	return append(ScheduledTransferQueueKindPrefix(byTime), sdk.Uint64ToBigEndian(due)...)
+}
+
+func ScheduledTransferQueueKindPrefix(byTime bool) []byte {
+	kind := byte(0)
+	if byTime {
+		kind = 1
+	}
+	return append(scheduledTransferQueueKeyPrefix, kind)
+}
+
+func GetIDFromScheduledTransferQueueKey(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key[len(key)-8:])
+}
+
+// ScheduledTransferOwnerKey: prefix + owner + id
+func ScheduledTransferOwnerKey(owner sdk.CUAddress, id uint64) []byte {
+	return append(ScheduledTransferOwnerKeyPrefix(owner), sdk.Uint64ToBigEndian(id)...)
+}
+
+func ScheduledTransferOwnerKeyPrefix(owner sdk.CUAddress) []byte {
+	return append(scheduledTransferOwnerKeyPrefix, owner...)
+}
+
+func GetIDFromScheduledTransferOwnerKey(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key[len(key)-8:])
+}
+
+// ScheduledTransferParkedKey: prefix + owner + id
+func ScheduledTransferParkedKey(owner sdk.CUAddress, id uint64) []byte {
+	return append(ScheduledTransferParkedKeyPrefix(owner), sdk.Uint64ToBigEndian(id)...)
+}
+
+func ScheduledTransferParkedKeyPrefix(owner sdk.CUAddress) []byte {
+	return append(scheduledTransferParkedKeyPrefix, owner...)
+}
+
+func GetIDFromScheduledTransferParkedKey(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key[len(key)-8:])
+}
+
+// BatchWithdrawalKey: prefix + owner + batchID
+func BatchWithdrawalKey(owner sdk.CUAddress, batchID string) []byte {
+	return append(append(batchWithdrawalKeyPrefix, owner...), []byte(batchID)...)
+}
+
+// DustDepositKey: prefix + owner + symbol + 0x00 + extAddress + 0x00 + orderID
+func DustDepositKey(owner sdk.CUAddress, symbol, extAddress, orderID string) []byte {
+	return append(DustDepositKeyPrefix(owner, symbol, extAddress), []byte(orderID)...)
+}
+
+func DustDepositKeyPrefix(owner sdk.CUAddress, symbol, extAddress string) []byte {
+	key := append(append(dustDepositKeyPrefix, owner...), []byte(symbol)...)
+	key = append(append(key, 0x00), []byte(extAddress)...)
+	return append(key, 0x00)
+}
@@ -4,19 +4,24 @@ import (
 	"fmt"
 
 	sdk "github.com/hbtc-chain/bhchain/types"
+	cutypes "github.com/hbtc-chain/bhchain/x/custodianunit/types"
 )
 
 // ensure Msg interface compliance at compile time
 var (
 	_ sdk.Msg = &MsgDeposit{}
 	_ sdk.Msg = &MsgConfirmedDeposit{}
+	_ sdk.Msg = &MsgInvalidateDeposit{}
+	_ sdk.Msg = &MsgSettleDepositDeficit{}
 	_ sdk.Msg = &MsgCollectWaitSign{}
 	_ sdk.Msg = &MsgCollectSignFinish{}
 	_ sdk.Msg = &MsgCollectFinish{}
 	_ sdk.Msg = &MsgWithdrawal{}
+	_ sdk.Msg = &MsgBatchWithdrawal{}
 	_ sdk.Msg = &MsgWithdrawalWaitSign{}
 	_ sdk.Msg = &MsgWithdrawalSignFinish{}
 	_ sdk.Msg = &MsgWithdrawalFinish{}
+	_ sdk.Msg = &MsgWithdrawalFeeBump{}
 	_ sdk.Msg = &MsgSysTransferWaitSign{}
 	_ sdk.Msg = &MsgSysTransferSignFinish{}
 	_ sdk.Msg = &MsgSysTransferFinish{}
@@ -25,10 +30,22 @@ var (
 	_ sdk.Msg = &MsgOpcuAssetTransferWaitSign{}
 	_ sdk.Msg = &MsgOpcuAssetTransferSignFinish{}
 	_ sdk.Msg = &MsgOpcuAssetTransferFinish{}
+	_ sdk.Msg = &MsgUtxoConsolidationWaitSign{}
+	_ sdk.Msg = &MsgUtxoConsolidationSignFinish{}
+	_ sdk.Msg = &MsgUtxoConsolidationFinish{}
 	_ sdk.Msg = &MsgSend{}
 	_ sdk.Msg = &MsgMultiSend{}
 	_ sdk.Msg = &MsgOrderRetry{}
 	_ sdk.Msg = &MsgCancelWithdrawal{}
+	_ sdk.Msg = &MsgSetDepositRoute{}
+	_ sdk.Msg = &MsgReassignSuspense{}
+	_ sdk.Msg = &MsgCreateHTLC{}
+	_ sdk.Msg = &MsgClaimHTLC{}
+	_ sdk.Msg = &MsgRefundHTLC{}
+	_ sdk.Msg = &MsgScheduleTransfer{}
+	_ sdk.Msg = &MsgCancelScheduledTransfer{}
+	_ sdk.Msg = &MsgCreateVestingCU{}
+	_ sdk.Msg = &MsgCreatePeriodicVestingCU{}
 )
 
 // MsgSend - high level transaction of the coin module
@@ -339,6 +356,91 @@ func (msg MsgConfirmedDeposit) IsSettleOnlyMsg() bool {
 	return true
 }
 
+//________________________________
+// MsgInvalidateDeposit votes that confirmed deposits vanished from the external chain after a reorg
+type MsgInvalidateDeposit struct {
+	From     sdk.CUAddress `json:"from_cu"`
+	OrderIDs []string      `json:"order_ids"`
+}
+
+func NewMsgInvalidateDeposit(from sdk.CUAddress, orderIDs []string) MsgInvalidateDeposit {
+	return MsgInvalidateDeposit{
+		From:     from,
+		OrderIDs: orderIDs,
+	}
+}
+
+//nolint
+func (msg MsgInvalidateDeposit) Route() string { return RouterKey }
+func (msg MsgInvalidateDeposit) Type() string  { return "invalidate_deposit" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgInvalidateDeposit) GetSigners() []sdk.CUAddress {
+	return []sdk.CUAddress{msg.From}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgInvalidateDeposit) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgInvalidateDeposit) ValidateBasic() sdk.Error {
+	if len(msg.OrderIDs) == 0 || sdk.IsIllegalOrderIDList(msg.OrderIDs) {
+		return sdk.ErrInvalidTx("Invalid order id list")
+	}
+	if msg.From == nil || !msg.From.IsValidAddr() {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("From CU address: %s is invalid", msg.From.String()))
+	}
+	return nil
+}
+
+func (msg MsgInvalidateDeposit) IsSettleOnlyMsg() bool {
+	return true
+}
+
+//________________________________
+// MsgSettleDepositDeficit burns the coins of From credited by invalidated deposits of Symbol, the held part from
+// its hold balance and the spent part from its balance. Withdrawals of From are unfrozen once all are settled.
+type MsgSettleDepositDeficit struct {
+	From   sdk.CUAddress `json:"from_cu"`
+	Symbol string        `json:"symbol"`
+}
+
+func NewMsgSettleDepositDeficit(from sdk.CUAddress, symbol string) MsgSettleDepositDeficit {
+	return MsgSettleDepositDeficit{
+		From:   from,
+		Symbol: symbol,
+	}
+}
+
+//nolint
+func (msg MsgSettleDepositDeficit) Route() string { return RouterKey }
+func (msg MsgSettleDepositDeficit) Type() string  { return "settle_deposit_deficit" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgSettleDepositDeficit) GetSigners() []sdk.CUAddress {
+	return []sdk.CUAddress{msg.From}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgSettleDepositDeficit) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgSettleDepositDeficit) ValidateBasic() sdk.Error {
+	if msg.From == nil || !msg.From.IsValidAddr() {
+		return sdk.ErrInvalidAddr(fmt.Sprintf("From CU address: %s is invalid", msg.From.String()))
+	}
+	if !sdk.Symbol(msg.Symbol).IsValid() {
+		return sdk.ErrInvalidSymbol(msg.Symbol)
+	}
+	return nil
+}
+
 //________________________________
 type MsgCollectWaitSign struct {
 	OrderIDs    []string `json:"order_ids"`
@@ -606,6 +708,99 @@ func (msg MsgWithdrawal) ValidateBasic() sdk.Error {
 	return nil
 }
 
+//________________________________
+// WithdrawalOutput is one recipient of a batch withdrawal
+type WithdrawalOutput struct {
+	OrderID            string  `json:"order_id"`
+	ToMultisignAddress string  `json:"to_multi_sign_address"`
+	Amount             sdk.Int `json:"amount"`
+}
+
+func NewWithdrawalOutput(orderID, toAddr string, amount sdk.Int) WithdrawalOutput {
+	return WithdrawalOutput{
+		OrderID:            orderID,
+		ToMultisignAddress: toAddr,
+		Amount:             amount,
+	}
+}
+
+// MsgBatchWithdrawal withdraws one symbol to multiple recipients, GasFee is the total fee of all outputs
+type MsgBatchWithdrawal struct {
+	FromCU  string             `json:"from_cu"`
+	BatchID string             `json:"batch_id"`
+	Symbol  string             `json:"symbol"`
+	Outputs []WithdrawalOutput `json:"outputs"`
+	GasFee  sdk.Int            `json:"gas_fee"`
+}
+
+func NewMsgBatchWithdrawal(fromCU, batchID, symbol string, outputs []WithdrawalOutput, gasFee sdk.Int) MsgBatchWithdrawal {
+	return MsgBatchWithdrawal{
+		FromCU:  fromCU,
+		BatchID: batchID,
+		Symbol:  symbol,
+		Outputs: outputs,
+		GasFee:  gasFee,
+	}
+}
+
+//nolint
+func (msg MsgBatchWithdrawal) Route() string { return RouterKey }
+func (msg MsgBatchWithdrawal) Type() string  { return "batch_withdrawal" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgBatchWithdrawal) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgBatchWithdrawal) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgBatchWithdrawal) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+
+	if msg.BatchID == "" {
+		return ErrNilOrderID(DefaultCodespace)
+	}
+
+	if len(msg.Outputs) == 0 {
+		return sdk.ErrInvalidTx("no withdrawal outputs")
+	}
+
+	if len(msg.Outputs) > MaxBatchWithdrawalNum {
+		return sdk.ErrInvalidTx(fmt.Sprintf("too many withdrawal outputs %v", len(msg.Outputs)))
+	}
+
+	for _, output := range msg.Outputs {
+		if output.ToMultisignAddress == "" {
+			return ErrBadAddress(DefaultCodespace)
+		}
+
+		if output.OrderID == "" {
+			return ErrNilOrderID(DefaultCodespace)
+		}
+
+		if !output.Amount.IsPositive() {
+			return sdk.ErrInvalidAmount("amount is not positive")
+		}
+	}
+
+	if !msg.GasFee.IsPositive() {
+		return sdk.ErrInvalidAmount("gasFee is not positive")
+	}
+	return nil
+}
+
 //________________________________
 type MsgWithdrawalConfirm struct {
 	FromCU  string `json:"from_cu"`
@@ -806,6 +1001,8 @@ type MsgWithdrawalFinish struct {
 	CostFee   sdk.Int  `json:"cost_fee"`
 	Validator string   `json:"validator"`
 	Valid     bool     `json:"valid"`
+	// TxHash is set if the packed tx is one replaced by fee bumps, empty for the latest signed tx
+	TxHash string `json:"tx_hash"`
 }
 
 func NewMsgWithdrawalFinish(valAddr string, ids []string, fee sdk.Int, valid bool) MsgWithdrawalFinish {
@@ -819,6 +1016,12 @@ func NewMsgWithdrawalFinish(valAddr string, ids []string, fee sdk.Int, valid boo
 	return msg
 }
 
+func NewMsgWithdrawalReplacedTxFinish(valAddr string, ids []string, txHash string, fee sdk.Int, valid bool) MsgWithdrawalFinish {
+	msg := NewMsgWithdrawalFinish(valAddr, ids, fee, valid)
+	msg.TxHash = txHash
+	return msg
+}
+
 //nolint
 func (msg MsgWithdrawalFinish) Route() string { return RouterKey }
 func (msg MsgWithdrawalFinish) Type() string  { return "withdrawal_finish" }
@@ -866,6 +1069,78 @@ func (msg MsgWithdrawalFinish) IsSettleOnlyMsg() bool {
 	return true
 }
 
+//________________________________
+// MsgWithdrawalFeeBump replaces the raw data of stuck withdrawal orders with a higher fee one,
+// same nonce for account based tokens and same vins for utxo based tokens
+type MsgWithdrawalFeeBump struct {
+	OrderIDs   []string `json:"order_ids"`
+	SignHashes [][]byte `json:"sign_hashes"`
+	RawData    []byte   `json:"raw_data"`
+	Validator  string   `json:"validator"`
+}
+
+func NewMsgWithdrawalFeeBump(valAddr string, ids []string, signHashes [][]byte, rawdata []byte) MsgWithdrawalFeeBump {
+	msg := MsgWithdrawalFeeBump{
+		OrderIDs:   make([]string, len(ids)),
+		SignHashes: signHashes,
+		RawData:    make([]byte, len(rawdata)),
+		Validator:  valAddr,
+	}
+
+	copy(msg.OrderIDs, ids)
+	copy(msg.RawData, rawdata)
+	return msg
+}
+
+//nolint
+func (msg MsgWithdrawalFeeBump) Route() string { return RouterKey }
+func (msg MsgWithdrawalFeeBump) Type() string  { return "withdrawal_fee_bump" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgWithdrawalFeeBump) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgWithdrawalFeeBump) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgWithdrawalFeeBump) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+
+	if len(msg.OrderIDs) == 0 {
+		return ErrNilOrderID(DefaultCodespace)
+	}
+
+	if sdk.IsIllegalOrderIDList(msg.OrderIDs) {
+		return ErrNilOrderID(DefaultCodespace)
+	}
+
+	if len(msg.RawData) == 0 {
+		return ErrNilRawData(DefaultCodespace)
+	}
+
+	if len(msg.SignHashes) == 0 {
+		return ErrNilSignHash(DefaultCodespace)
+	}
+
+	return nil
+}
+
+func (msg MsgWithdrawalFeeBump) IsSettleOnlyMsg() bool {
+	return true
+}
+
 //________________________________
 type MsgSysTransfer struct {
 	FromCU    sdk.CUAddress `json:"from_cu"`
@@ -1359,39 +1634,33 @@ func (msg MsgOpcuAssetTransferFinish) IsSettleOnlyMsg() bool {
 	return true
 }
 
-type EvidenceValidator struct {
-	EvidenceType int    `json:"evidence_type"`
-	Validator    string `json:"validator"`
-}
-
 //________________________________
-type MsgOrderRetry struct {
-	OrderIDs   []string            `json:"order_ids"`
-	RetryTimes uint32              `json:"retry_times"`
-	Evidences  []EvidenceValidator `json:"evidences,omitempty"`
-	From       string              `json:"from"`
+type MsgUtxoConsolidationWaitSign struct {
+	OrderID    string   `json:"order_id"`
+	SignHashes [][]byte `json:"sign_hashes"`
+	RawData    []byte   `json:"raw_data"`
+	Validator  string   `json:"validator"`
 }
 
-func NewMsgOrderRetry(from string, ids []string, retrytimes uint32, evidences []EvidenceValidator) MsgOrderRetry {
-	msg := MsgOrderRetry{
-		OrderIDs:   make([]string, len(ids)),
-		RetryTimes: retrytimes,
-		Evidences:  make([]EvidenceValidator, len(evidences)),
-		From:       from,
+func NewMsgUtxoConsolidationWaitSign(valAddr, id string, signHashes [][]byte, rawdata []byte) MsgUtxoConsolidationWaitSign {
+	msg := MsgUtxoConsolidationWaitSign{
+		OrderID:    id,
+		SignHashes: signHashes,
+		RawData:    make([]byte, len(rawdata)),
+		Validator:  valAddr,
 	}
-	copy(msg.OrderIDs, ids)
-	copy(msg.Evidences, evidences)
+
+	copy(msg.RawData, rawdata)
 	return msg
 }
 
 //nolint
-func (msg MsgOrderRetry) Route() string { return RouterKey }
-func (msg MsgOrderRetry) Type() string  { return "order_retry" }
+func (msg MsgUtxoConsolidationWaitSign) Route() string { return RouterKey }
+func (msg MsgUtxoConsolidationWaitSign) Type() string  { return "utxo_consolidation_wait_sign" }
 
 // Return address(es) that must sign over msg.GetSignBytes()
-func (msg MsgOrderRetry) GetSigners() []sdk.CUAddress {
-	// delegator is first signer so delegator pays fees
-	addr, err := sdk.CUAddressFromBase58(msg.From)
+func (msg MsgUtxoConsolidationWaitSign) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.Validator)
 	if err != nil {
 		return []sdk.CUAddress{}
 	}
@@ -1399,60 +1668,54 @@ func (msg MsgOrderRetry) GetSigners() []sdk.CUAddress {
 }
 
 // GetSignBytes returns the message bytes to sign over.
-func (msg MsgOrderRetry) GetSignBytes() []byte {
+func (msg MsgUtxoConsolidationWaitSign) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
 
 // quick validity check
-func (msg MsgOrderRetry) ValidateBasic() sdk.Error {
-	_, err := sdk.CUAddressFromBase58(msg.From)
+func (msg MsgUtxoConsolidationWaitSign) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.Validator)
 	if err != nil {
 		return ErrBadAddress(DefaultCodespace)
 	}
 
-	if len(msg.OrderIDs) == 0 {
-		return ErrNilOrderID(DefaultCodespace)
-	}
-	if sdk.IsIllegalOrderIDList(msg.OrderIDs) {
-		return ErrNilOrderID(DefaultCodespace)
-	}
-	// evidence 中 validator 不可重复
-	validators := make(map[string]bool)
-	for _, evidence := range msg.Evidences {
-		if validators[evidence.Validator] {
-			return sdk.NewError(DefaultCodespace, sdk.CodeInvalidTx, "duplicated validators")
-		}
-		validators[evidence.Validator] = true
+	if len(msg.RawData) == 0 {
+		return ErrNilRawData(DefaultCodespace)
 	}
+
 	return nil
 }
 
-func (msg MsgOrderRetry) IsSettleOnlyMsg() bool {
+func (msg MsgUtxoConsolidationWaitSign) IsSettleOnlyMsg() bool {
 	return true
 }
 
-type MsgCancelWithdrawal struct {
-	FromCU  string `json:"from_cu"`
-	OrderID string `json:"order_id"`
+//________________________________
+type MsgUtxoConsolidationSignFinish struct {
+	OrderID   string `json:"order_id"`
+	SignedTx  []byte `json:"signed_tx"`
+	Validator string `json:"validator"`
 }
 
-func NewMsgCancelWithdrawal(fromCU string, orderID string) MsgCancelWithdrawal {
-	msg := MsgCancelWithdrawal{
-		FromCU:  fromCU,
-		OrderID: orderID,
+func NewMsgUtxoConsolidationSignFinish(valAddr, id string, signedTx []byte) MsgUtxoConsolidationSignFinish {
+	msg := MsgUtxoConsolidationSignFinish{
+		OrderID:   id,
+		SignedTx:  make([]byte, len(signedTx)),
+		Validator: valAddr,
 	}
 
+	copy(msg.SignedTx, signedTx)
 	return msg
 }
 
 //nolint
-func (msg MsgCancelWithdrawal) Route() string { return RouterKey }
-func (msg MsgCancelWithdrawal) Type() string  { return "cancel_withdrawal" }
+func (msg MsgUtxoConsolidationSignFinish) Route() string { return RouterKey }
+func (msg MsgUtxoConsolidationSignFinish) Type() string  { return "utxo_consolidation_sign_finish" }
 
 // Return address(es) that must sign over msg.GetSignBytes()
-func (msg MsgCancelWithdrawal) GetSigners() []sdk.CUAddress {
-	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+func (msg MsgUtxoConsolidationSignFinish) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.Validator)
 	if err != nil {
 		return []sdk.CUAddress{}
 	}
@@ -1460,20 +1723,710 @@ func (msg MsgCancelWithdrawal) GetSigners() []sdk.CUAddress {
 }
 
 // GetSignBytes returns the message bytes to sign over.
-func (msg MsgCancelWithdrawal) GetSignBytes() []byte {
+func (msg MsgUtxoConsolidationSignFinish) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
 
 // quick validity check
-func (msg MsgCancelWithdrawal) ValidateBasic() sdk.Error {
-	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+func (msg MsgUtxoConsolidationSignFinish) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.Validator)
 	if err != nil {
 		return ErrBadAddress(DefaultCodespace)
 	}
-	if sdk.IsIllegalOrderID(msg.OrderID) {
+
+	if len(msg.SignedTx) == 0 {
+		return ErrNilSignedTx(DefaultCodespace)
+	}
+
+	return nil
+}
+
+func (msg MsgUtxoConsolidationSignFinish) IsSettleOnlyMsg() bool {
+	return true
+}
+
+//________________________________
+type MsgUtxoConsolidationFinish struct {
+	OrderID   string  `json:"order_id"`
+	CostFee   sdk.Int `json:"cost_fee"`
+	Validator string  `json:"validator"`
+}
+
+func NewMsgUtxoConsolidationFinish(valAddr string, id string, fee sdk.Int) MsgUtxoConsolidationFinish {
+	msg := MsgUtxoConsolidationFinish{
+		OrderID:   id,
+		Validator: valAddr,
+		CostFee:   fee,
+	}
+
+	return msg
+}
+
+//nolint
+func (msg MsgUtxoConsolidationFinish) Route() string { return RouterKey }
+func (msg MsgUtxoConsolidationFinish) Type() string  { return "utxo_consolidation_finish" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgUtxoConsolidationFinish) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgUtxoConsolidationFinish) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgUtxoConsolidationFinish) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.Validator)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+
+	if msg.CostFee.IsNegative() {
+		return ErrBadCostFee(DefaultCodespace)
+	}
+
+	return nil
+}
+
+func (msg MsgUtxoConsolidationFinish) IsSettleOnlyMsg() bool {
+	return true
+}
+
+type EvidenceValidator struct {
+	EvidenceType int    `json:"evidence_type"`
+	Validator    string `json:"validator"`
+}
+
+//________________________________
+type MsgOrderRetry struct {
+	OrderIDs   []string            `json:"order_ids"`
+	RetryTimes uint32              `json:"retry_times"`
+	Evidences  []EvidenceValidator `json:"evidences,omitempty"`
+	From       string              `json:"from"`
+}
+
+func NewMsgOrderRetry(from string, ids []string, retrytimes uint32, evidences []EvidenceValidator) MsgOrderRetry {
+	msg := MsgOrderRetry{
+		OrderIDs:   make([]string, len(ids)),
+		RetryTimes: retrytimes,
+		Evidences:  make([]EvidenceValidator, len(evidences)),
+		From:       from,
+	}
+	copy(msg.OrderIDs, ids)
+	copy(msg.Evidences, evidences)
+	return msg
+}
+
+//nolint
+func (msg MsgOrderRetry) Route() string { return RouterKey }
+func (msg MsgOrderRetry) Type() string  { return "order_retry" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgOrderRetry) GetSigners() []sdk.CUAddress {
+	// delegator is first signer so delegator pays fees
+	addr, err := sdk.CUAddressFromBase58(msg.From)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgOrderRetry) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgOrderRetry) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.From)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+
+	if len(msg.OrderIDs) == 0 {
+		return ErrNilOrderID(DefaultCodespace)
+	}
+	if sdk.IsIllegalOrderIDList(msg.OrderIDs) {
+		return ErrNilOrderID(DefaultCodespace)
+	}
+	// evidence 中 validator 不可重复
+	validators := make(map[string]bool)
+	for _, evidence := range msg.Evidences {
+		if validators[evidence.Validator] {
+			return sdk.NewError(DefaultCodespace, sdk.CodeInvalidTx, "duplicated validators")
+		}
+		validators[evidence.Validator] = true
+	}
+	return nil
+}
+
+func (msg MsgOrderRetry) IsSettleOnlyMsg() bool {
+	return true
+}
+
+type MsgCancelWithdrawal struct {
+	FromCU  string `json:"from_cu"`
+	OrderID string `json:"order_id"`
+}
+
+func NewMsgCancelWithdrawal(fromCU string, orderID string) MsgCancelWithdrawal {
+	msg := MsgCancelWithdrawal{
+		FromCU:  fromCU,
+		OrderID: orderID,
+	}
+
+	return msg
+}
+
+//nolint
+func (msg MsgCancelWithdrawal) Route() string { return RouterKey }
+func (msg MsgCancelWithdrawal) Type() string  { return "cancel_withdrawal" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgCancelWithdrawal) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgCancelWithdrawal) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgCancelWithdrawal) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if sdk.IsIllegalOrderID(msg.OrderID) {
 		return ErrNilOrderID(DefaultCodespace)
 	}
 
 	return nil
 }
+
+//________________________________
+// MsgSetDepositRoute routes the deposits to the addresses of FromCU carrying Memo to ToCU, empty ToCU removes the route
+type MsgSetDepositRoute struct {
+	FromCU string `json:"from_cu"`
+	Memo   string `json:"memo"`
+	ToCU   string `json:"to_cu"`
+}
+
+func NewMsgSetDepositRoute(fromCU, memo, toCU string) MsgSetDepositRoute {
+	return MsgSetDepositRoute{
+		FromCU: fromCU,
+		Memo:   memo,
+		ToCU:   toCU,
+	}
+}
+
+//nolint
+func (msg MsgSetDepositRoute) Route() string { return RouterKey }
+func (msg MsgSetDepositRoute) Type() string  { return "set_deposit_route" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgSetDepositRoute) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgSetDepositRoute) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgSetDepositRoute) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if msg.Memo == "" || len(msg.Memo) > MaxDepositMemoLength {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid memo length %v", len(msg.Memo)))
+	}
+	if msg.ToCU != "" {
+		_, err = sdk.CUAddressFromBase58(msg.ToCU)
+		if err != nil {
+			return ErrBadAddress(DefaultCodespace)
+		}
+	}
+	return nil
+}
+
+//________________________________
+// MsgReassignSuspense moves the suspense balance of FromCU to ToCU
+type MsgReassignSuspense struct {
+	FromCU string  `json:"from_cu"`
+	ToCU   string  `json:"to_cu"`
+	Symbol string  `json:"symbol"`
+	Amount sdk.Int `json:"amount"`
+}
+
+func NewMsgReassignSuspense(fromCU, toCU, symbol string, amount sdk.Int) MsgReassignSuspense {
+	return MsgReassignSuspense{
+		FromCU: fromCU,
+		ToCU:   toCU,
+		Symbol: symbol,
+		Amount: amount,
+	}
+}
+
+//nolint
+func (msg MsgReassignSuspense) Route() string { return RouterKey }
+func (msg MsgReassignSuspense) Type() string  { return "reassign_suspense" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgReassignSuspense) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgReassignSuspense) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgReassignSuspense) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if !sdk.Symbol(msg.Symbol).IsValid() {
+		return sdk.ErrInvalidSymbol(msg.Symbol)
+	}
+	if !msg.Amount.IsPositive() {
+		return sdk.ErrInvalidAmount("amount is not positive")
+	}
+	return nil
+}
+
+//________________________________
+// MsgCreateHTLC locks Amount of FromCU for ToCU under Hashlock for TimeLock blocks
+type MsgCreateHTLC struct {
+	FromCU   string    `json:"from_cu"`
+	ToCU     string    `json:"to_cu"`
+	Amount   sdk.Coins `json:"amount"`
+	Hashlock string    `json:"hashlock"`
+	TimeLock uint64    `json:"time_lock"`
+}
+
+func NewMsgCreateHTLC(fromCU, toCU string, amount sdk.Coins, hashlock string, timeLock uint64) MsgCreateHTLC {
+	return MsgCreateHTLC{
+		FromCU:   fromCU,
+		ToCU:     toCU,
+		Amount:   amount,
+		Hashlock: hashlock,
+		TimeLock: timeLock,
+	}
+}
+
+//nolint
+func (msg MsgCreateHTLC) Route() string { return RouterKey }
+func (msg MsgCreateHTLC) Type() string  { return "create_htlc" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgCreateHTLC) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgCreateHTLC) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgCreateHTLC) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if !msg.Amount.IsValid() || msg.Amount.Empty() {
+		return sdk.ErrInvalidAmount(msg.Amount.String())
+	}
+	if _, err := DecodeHTLCHash(msg.Hashlock); err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err))
+	}
+	if msg.TimeLock < MinHTLCTimeLock || msg.TimeLock > MaxHTLCTimeLock {
+		return sdk.ErrInvalidTx(fmt.Sprintf("time lock %v out of range [%v, %v]", msg.TimeLock, MinHTLCTimeLock, MaxHTLCTimeLock))
+	}
+	return nil
+}
+
+//________________________________
+// MsgClaimHTLC pays the HTLC of Sender to its receiver with the preimage of its hashlock, it can be sent by anyone
+type MsgClaimHTLC struct {
+	FromCU   string `json:"from_cu"`
+	Hashlock string `json:"hashlock"`
+	Preimage string `json:"preimage"`
+	Sender   string `json:"sender"`
+}
+
+func NewMsgClaimHTLC(fromCU, sender, hashlock, preimage string) MsgClaimHTLC {
+	return MsgClaimHTLC{
+		FromCU:   fromCU,
+		Hashlock: hashlock,
+		Preimage: preimage,
+		Sender:   sender,
+	}
+}
+
+//nolint
+func (msg MsgClaimHTLC) Route() string { return RouterKey }
+func (msg MsgClaimHTLC) Type() string  { return "claim_htlc" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgClaimHTLC) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgClaimHTLC) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgClaimHTLC) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.Sender)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if _, err := DecodeHTLCHash(msg.Hashlock); err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err))
+	}
+	if _, err := DecodeHTLCHash(msg.Preimage); err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid preimage: %v", err))
+	}
+	return nil
+}
+
+//________________________________
+// MsgRefundHTLC returns an expired HTLC to its Sender, it can be sent by anyone
+type MsgRefundHTLC struct {
+	FromCU   string `json:"from_cu"`
+	Hashlock string `json:"hashlock"`
+	Sender   string `json:"sender"`
+}
+
+func NewMsgRefundHTLC(fromCU, sender, hashlock string) MsgRefundHTLC {
+	return MsgRefundHTLC{
+		FromCU:   fromCU,
+		Hashlock: hashlock,
+		Sender:   sender,
+	}
+}
+
+//nolint
+func (msg MsgRefundHTLC) Route() string { return RouterKey }
+func (msg MsgRefundHTLC) Type() string  { return "refund_htlc" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgRefundHTLC) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgRefundHTLC) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgRefundHTLC) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.Sender)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if _, err := DecodeHTLCHash(msg.Hashlock); err != nil {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid hashlock: %v", err))
+	}
+	return nil
+}
+
+//________________________________
+// MsgScheduleTransfer pays Amount from FromCU to ToCU at Start, and repeats every Interval for Times in total.
+// Start and Interval are in unix seconds of the block time if ByTime, otherwise in block heights.
+type MsgScheduleTransfer struct {
+	FromCU   string    `json:"from_cu"`
+	ToCU     string    `json:"to_cu"`
+	Amount   sdk.Coins `json:"amount"`
+	ByTime   bool      `json:"by_time"`
+	Start    uint64    `json:"start"`
+	Interval uint64    `json:"interval"`
+	Times    uint64    `json:"times"`
+}
+
+func NewMsgScheduleTransfer(fromCU, toCU string, amount sdk.Coins, byTime bool, start, interval, times uint64) MsgScheduleTransfer {
+	return MsgScheduleTransfer{
+		FromCU:   fromCU,
+		ToCU:     toCU,
+		Amount:   amount,
+		ByTime:   byTime,
+		Start:    start,
+		Interval: interval,
+		Times:    times,
+	}
+}
+
+//nolint
+func (msg MsgScheduleTransfer) Route() string { return RouterKey }
+func (msg MsgScheduleTransfer) Type() string  { return "schedule_transfer" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgScheduleTransfer) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgScheduleTransfer) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgScheduleTransfer) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if !msg.Amount.IsValid() || msg.Amount.Empty() {
+		return sdk.ErrInvalidAmount(msg.Amount.String())
+	}
+	if msg.Times == 0 || msg.Times > MaxScheduledTransferTimes {
+		return sdk.ErrInvalidTx(fmt.Sprintf("times %v out of range [1, %v]", msg.Times, MaxScheduledTransferTimes))
+	}
+	if msg.Times > 1 && msg.Interval == 0 {
+		return sdk.ErrInvalidTx("interval of a recurring transfer is zero")
+	}
+	return nil
+}
+
+//________________________________
+// MsgCancelScheduledTransfer cancels the remaining executions of a scheduled transfer of FromCU
+type MsgCancelScheduledTransfer struct {
+	FromCU string `json:"from_cu"`
+	ID     uint64 `json:"id"`
+}
+
+func NewMsgCancelScheduledTransfer(fromCU string, id uint64) MsgCancelScheduledTransfer {
+	return MsgCancelScheduledTransfer{
+		FromCU: fromCU,
+		ID:     id,
+	}
+}
+
+//nolint
+func (msg MsgCancelScheduledTransfer) Route() string { return RouterKey }
+func (msg MsgCancelScheduledTransfer) Type() string  { return "cancel_scheduled_transfer" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgCancelScheduledTransfer) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgCancelScheduledTransfer) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgCancelScheduledTransfer) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	return nil
+}
+
+//________________________________
+// MsgCreateVestingCU creates ToCU as a vesting CU funded with Amount by FromCU. Amount vests continuously
+// from StartTime to EndTime, or all at EndTime if StartTime is zero. The times are in unix seconds.
+type MsgCreateVestingCU struct {
+	FromCU    string    `json:"from_cu"`
+	ToCU      string    `json:"to_cu"`
+	Amount    sdk.Coins `json:"amount"`
+	StartTime int64     `json:"start_time"`
+	EndTime   int64     `json:"end_time"`
+}
+
+func NewMsgCreateVestingCU(fromCU, toCU string, amount sdk.Coins, startTime, endTime int64) MsgCreateVestingCU {
+	return MsgCreateVestingCU{
+		FromCU:    fromCU,
+		ToCU:      toCU,
+		Amount:    amount,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+//nolint
+func (msg MsgCreateVestingCU) Route() string { return RouterKey }
+func (msg MsgCreateVestingCU) Type() string  { return "create_vesting_cu" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgCreateVestingCU) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgCreateVestingCU) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgCreateVestingCU) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if !msg.Amount.IsValid() || msg.Amount.Empty() {
+		return sdk.ErrInvalidAmount(msg.Amount.String())
+	}
+	if msg.StartTime < 0 || msg.EndTime <= 0 {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid vesting time [%v, %v]", msg.StartTime, msg.EndTime))
+	}
+	if msg.StartTime != 0 && msg.StartTime >= msg.EndTime {
+		return sdk.ErrInvalidTx("vesting start-time cannot be after end-time")
+	}
+	return nil
+}
+
+//________________________________
+// MsgCreatePeriodicVestingCU creates ToCU as a periodic vesting CU funded by FromCU with the total amount
+// of Periods. The amount of each period vests at its end, the first period starts at StartTime in unix seconds.
+type MsgCreatePeriodicVestingCU struct {
+	FromCU    string                 `json:"from_cu"`
+	ToCU      string                 `json:"to_cu"`
+	StartTime int64                  `json:"start_time"`
+	Periods   cutypes.VestingPeriods `json:"periods"`
+}
+
+func NewMsgCreatePeriodicVestingCU(fromCU, toCU string, startTime int64, periods cutypes.VestingPeriods) MsgCreatePeriodicVestingCU {
+	return MsgCreatePeriodicVestingCU{
+		FromCU:    fromCU,
+		ToCU:      toCU,
+		StartTime: startTime,
+		Periods:   periods,
+	}
+}
+
+//nolint
+func (msg MsgCreatePeriodicVestingCU) Route() string { return RouterKey }
+func (msg MsgCreatePeriodicVestingCU) Type() string  { return "create_periodic_vesting_cu" }
+
+// Return address(es) that must sign over msg.GetSignBytes()
+func (msg MsgCreatePeriodicVestingCU) GetSigners() []sdk.CUAddress {
+	addr, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return []sdk.CUAddress{}
+	}
+	return []sdk.CUAddress{addr}
+}
+
+// GetSignBytes returns the message bytes to sign over.
+func (msg MsgCreatePeriodicVestingCU) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgCreatePeriodicVestingCU) ValidateBasic() sdk.Error {
+	_, err := sdk.CUAddressFromBase58(msg.FromCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	_, err = sdk.CUAddressFromBase58(msg.ToCU)
+	if err != nil {
+		return ErrBadAddress(DefaultCodespace)
+	}
+	if msg.StartTime <= 0 {
+		return sdk.ErrInvalidTx(fmt.Sprintf("invalid vesting start-time %v", msg.StartTime))
+	}
+	if len(msg.Periods) == 0 {
+		return sdk.ErrInvalidTx("no vesting periods")
+	}
+	for i, period := range msg.Periods {
+		if period.Length <= 0 {
+			return sdk.ErrInvalidTx(fmt.Sprintf("invalid length %v of vesting period %v", period.Length, i))
+		}
+		if !period.Amount.IsValid() || period.Amount.Empty() {
+			return sdk.ErrInvalidAmount(period.Amount.String())
+		}
+	}
+	return nil
+}
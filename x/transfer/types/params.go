@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
 	"github.com/hbtc-chain/bhchain/x/params"
 )
 
@@ -11,14 +14,96 @@ const (
 	DefaultSendEnabled = true
 
 	MaxSystransferNum = 10
+
+	// MaxBatchWithdrawalNum is the max number of outputs of a batch withdrawal
+	MaxBatchWithdrawalNum = 20
+
+	// MaxDepositMemoLength is the max length of a memo in the deposit routing table
+	MaxDepositMemoLength = 64
+
+	// UtxoConsolidationInterval is the number of blocks between two checks of OPCUs' utxos
+	UtxoConsolidationInterval = 100
+	// UtxoDustFeeMultiple defines a dust utxo, whose amount is less than the multiple of fee to spend it
+	UtxoDustFeeMultiple = 3
+	// GasPriceAverageWindow is the smoothing window of the moving average of gas price
+	GasPriceAverageWindow = 10
+
+	// OpCURebalanceInterval is the number of blocks between two checks of hot OPCUs' balances
+	OpCURebalanceInterval = 100
+
+	// MinHTLCTimeLock is the min number of blocks an HTLC stays locked
+	MinHTLCTimeLock = 50
+	// MaxHTLCTimeLock is the max number of blocks an HTLC stays locked
+	MaxHTLCTimeLock = 100000
+
+	// MaxScheduledTransferTimes is the max number of executions of a scheduled transfer
+	MaxScheduledTransferTimes = 1000
+	// MaxScheduledTransfersPerBlock is the max number of scheduled transfers executed in one block,
+	// the rest are postponed to the next block
+	MaxScheduledTransfersPerBlock = 100
+
+	// MaxDustDepositsPerAddress is the max number of dust deposits waiting for aggregation on an address of a user CU,
+	// deposits below 1/MaxDustDepositsPerAddress of the deposit threshold are rejected
+	MaxDustDepositsPerAddress = 20
 )
 
 // ParamStoreKeySendEnabled is store's key for SendEnabled
 var ParamStoreKeySendEnabled = []byte("sendenabled")
 
+// ParamStoreKeyUtxoConsolidation is store's key for UtxoConsolidationParams
+var ParamStoreKeyUtxoConsolidation = []byte("utxoconsolidation")
+
 // ParamKeyTable type declaration for parameters
 func ParamKeyTable() params.KeyTable {
 	return params.NewKeyTable(
 		ParamStoreKeySendEnabled, false,
+		ParamStoreKeyUtxoConsolidation, UtxoConsolidationParams{},
 	)
 }
+
+// UtxoConsolidationParams defines when the utxos of OPCUs are consolidated
+type UtxoConsolidationParams struct {
+	// NumThreshold is the utxo number of an OPCU above which a consolidation is scheduled
+	NumThreshold uint64 `json:"num_threshold" yaml:"num_threshold"`
+	// DustRatio is the ratio of dust utxos above which a consolidation is scheduled
+	DustRatio sdk.Dec `json:"dust_ratio" yaml:"dust_ratio"`
+	// GasPriceRatio is the ratio to the average gas price below which the gas price is low enough for a consolidation
+	GasPriceRatio sdk.Dec `json:"gas_price_ratio" yaml:"gas_price_ratio"`
+	// Timeout is the number of blocks after which a consolidation order not signed yet is cancelled
+	Timeout uint64 `json:"timeout" yaml:"timeout"`
+}
+
+// DefaultUtxoConsolidationParams returns the default utxo consolidation params
+func DefaultUtxoConsolidationParams() UtxoConsolidationParams {
+	return UtxoConsolidationParams{
+		NumThreshold:  50,
+		DustRatio:     sdk.NewDecWithPrec(5, 1),
+		GasPriceRatio: sdk.NewDecWithPrec(8, 1),
+		Timeout:       600,
+	}
+}
+
+// Validate checks the utxo consolidation params
+func (p UtxoConsolidationParams) Validate() error {
+	if p.NumThreshold < sdk.MaxVinNum {
+		return fmt.Errorf("utxo consolidation num threshold %v is less than %v", p.NumThreshold, sdk.MaxVinNum)
+	}
+	if p.DustRatio.IsNil() || !p.DustRatio.IsPositive() || p.DustRatio.GT(sdk.OneDec()) {
+		return fmt.Errorf("invalid utxo consolidation dust ratio %v", p.DustRatio)
+	}
+	if p.GasPriceRatio.IsNil() || !p.GasPriceRatio.IsPositive() || p.GasPriceRatio.GT(sdk.OneDec()) {
+		return fmt.Errorf("invalid utxo consolidation gas price ratio %v", p.GasPriceRatio)
+	}
+	if p.Timeout == 0 {
+		return fmt.Errorf("utxo consolidation timeout is 0")
+	}
+	return nil
+}
+
+func (p UtxoConsolidationParams) String() string {
+	return fmt.Sprintf(`Utxo Consolidation Params:
+  Num Threshold:   %d
+  Dust Ratio:      %s
+  Gas Price Ratio: %s
+  Timeout:         %d`, p.NumThreshold, p.DustRatio, p.GasPriceRatio, p.Timeout)
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+	govtypes "github.com/hbtc-chain/bhchain/x/gov/types"
+)
+
+const (
+	ProposalTypeFreezeAddresses = "FreezeAddresses"
+
+	// MaxFreezeAddresses is the max number of addresses frozen or unfrozen by one proposal
+	MaxFreezeAddresses = 100
+)
+
+var _ govtypes.Content = FreezeAddressesProposal{}
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeFreezeAddresses)
+	govtypes.RegisterProposalTypeCodec(FreezeAddressesProposal{}, "hbtcchain/transfer/FreezeAddressesProposal")
+}
+
+// FreezeAddressesProposal freezes the addresses if Freeze is true, otherwise unfreezes them.
+// A frozen address can not send, withdraw or swap, but can still receive deposits.
+type FreezeAddressesProposal struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Addresses   []sdk.CUAddress `json:"addresses"`
+	Freeze      bool            `json:"freeze"`
+}
+
+func NewFreezeAddressesProposal(title, desc string, addresses []sdk.CUAddress, freeze bool) FreezeAddressesProposal {
+	return FreezeAddressesProposal{
+		Title:       title,
+		Description: desc,
+		Addresses:   addresses,
+		Freeze:      freeze,
+	}
+}
+
+func (fap FreezeAddressesProposal) GetTitle() string { return fap.Title }
+
+func (fap FreezeAddressesProposal) GetDescription() string { return fap.Description }
+
+func (fap FreezeAddressesProposal) ProposalRoute() string { return RouterKey }
+
+func (fap FreezeAddressesProposal) ProposalToken() string { return sdk.NativeToken }
+
+func (fap FreezeAddressesProposal) ProposalType() string { return ProposalTypeFreezeAddresses }
+
+// ValidateBasic runs basic stateless validity checks
+func (fap FreezeAddressesProposal) ValidateBasic() sdk.Error {
+	err := govtypes.ValidateAbstract(DefaultCodespace, fap)
+	if err != nil {
+		return err
+	}
+	if len(fap.Addresses) == 0 || len(fap.Addresses) > MaxFreezeAddresses {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("number of addresses %v out of range [1, %v]", len(fap.Addresses), MaxFreezeAddresses))
+	}
+	seen := make(map[string]bool, len(fap.Addresses))
+	for _, addr := range fap.Addresses {
+		if !addr.IsValidAddr() {
+			return sdk.ErrInvalidAddress(fmt.Sprintf("invalid address: %s", addr.String()))
+		}
+		if seen[addr.String()] {
+			return sdk.ErrInvalidAddress(fmt.Sprintf("duplicated address: %s", addr.String()))
+		}
+		seen[addr.String()] = true
+	}
+	return nil
+}
+
+// String implements the Stringer interface.
+func (fap FreezeAddressesProposal) String() string {
+	addrs := make([]string, len(fap.Addresses))
+	for i, addr := range fap.Addresses {
+		addrs[i] = addr.String()
+	}
+	return fmt.Sprintf(`Freeze Addresses Proposal:
+  Title:       %s
+  Description: %s
+  Addresses:   %s
+  Freeze:      %v
+`,
+		fap.Title, fap.Description, strings.Join(addrs, ","), fap.Freeze)
+}
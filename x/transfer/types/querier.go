@@ -4,8 +4,17 @@ import sdk "github.com/hbtc-chain/bhchain/types"
 
 const (
 	// query balance path
-	QueryBalance    = "balance"
-	QueryAllBalance = "balances"
+	QueryBalance      = "balance"
+	QueryAllBalance   = "balances"
+	QueryReserve      = "reserve"
+	QueryDepositRoute = "deposit_route"
+	QueryHTLC         = "htlc"
+
+	QueryScheduledTransfers = "scheduled_transfers"
+	QueryFrozenAddresses    = "frozen_addresses"
+	QueryAddressFrozen      = "address_frozen"
+	QueryWithdrawalFee      = "withdrawal_fee"
+	QueryDepositDeficit     = "deposit_deficit"
 )
 
 type QueryBalanceParams struct {
@@ -39,3 +48,128 @@ type ResAllBalance struct {
 	Available sdk.Coins `json:"available"`
 	Locked    sdk.Coins `json:"locked"`
 }
+
+type QueryReserveParams struct {
+	Symbol string
+	// Addr is the CU whose liability proof is returned, optional
+	Addr sdk.CUAddress
+	// CrossCheck queries the external balances of CUs from chainnode
+	CrossCheck bool
+}
+
+func NewQueryReserveParams(symbol string, addr sdk.CUAddress, crossCheck bool) QueryReserveParams {
+	return QueryReserveParams{
+		Symbol:     symbol,
+		Addr:       addr,
+		CrossCheck: crossCheck,
+	}
+}
+
+type QueryDepositRouteParams struct {
+	Addr sdk.CUAddress
+}
+
+func NewQueryDepositRouteParams(addr sdk.CUAddress) QueryDepositRouteParams {
+	return QueryDepositRouteParams{
+		Addr: addr,
+	}
+}
+
+type DepositRoute struct {
+	Memo string        `json:"memo"`
+	ToCU sdk.CUAddress `json:"to_cu"`
+}
+
+// ResDepositRoute is the deposit routing table of a CU and its suspense balance
+type ResDepositRoute struct {
+	Routes   []DepositRoute `json:"routes"`
+	Suspense sdk.Coins      `json:"suspense"`
+}
+
+type QueryHTLCParams struct {
+	Sender   sdk.CUAddress
+	Hashlock []byte
+}
+
+func NewQueryHTLCParams(sender sdk.CUAddress, hashlock []byte) QueryHTLCParams {
+	return QueryHTLCParams{
+		Sender:   sender,
+		Hashlock: hashlock,
+	}
+}
+
+type QueryScheduledTransfersParams struct {
+	Addr sdk.CUAddress
+}
+
+func NewQueryScheduledTransfersParams(addr sdk.CUAddress) QueryScheduledTransfersParams {
+	return QueryScheduledTransfersParams{
+		Addr: addr,
+	}
+}
+
+type QueryAddressFrozenParams struct {
+	Addr sdk.CUAddress
+}
+
+func NewQueryAddressFrozenParams(addr sdk.CUAddress) QueryAddressFrozenParams {
+	return QueryAddressFrozenParams{
+		Addr: addr,
+	}
+}
+
+// ResAddressFrozen is the freeze state of an address
+type ResAddressFrozen struct {
+	Address sdk.CUAddress `json:"address"`
+	Frozen  bool          `json:"frozen"`
+}
+
+type QueryWithdrawalFeeParams struct {
+	Symbol string
+	Amount sdk.Int
+	// ToAddr is the destination of the withdrawal, optional
+	ToAddr string
+}
+
+func NewQueryWithdrawalFeeParams(symbol string, amount sdk.Int, toAddr string) QueryWithdrawalFeeParams {
+	return QueryWithdrawalFeeParams{
+		Symbol: symbol,
+		Amount: amount,
+		ToAddr: toAddr,
+	}
+}
+
+// ResWithdrawalFee is the estimated fee of a withdrawal at the current gas price
+type ResWithdrawalFee struct {
+	Symbol   string  `json:"symbol"`
+	GasPrice sdk.Int `json:"gas_price"`
+	// UtxoInputs is the number of inputs expected to spend, UTXO based tokens only
+	UtxoInputs int `json:"utxo_inputs"`
+	// NeedSysTransfer is set if OPCUs have to be topped up with gas before the withdrawal
+	NeedSysTransfer bool `json:"need_sys_transfer"`
+	// GasFee is the expected cost of the withdrawal transaction, together with the sys transfer if needed
+	GasFee sdk.Coin `json:"gas_fee"`
+	// PlatformFee is charged by the chain on top of the static gas fee
+	PlatformFee sdk.Coin `json:"platform_fee"`
+	// MinGasFee is the minimum gasFee accepted by MsgWithdrawal
+	MinGasFee sdk.Coin `json:"min_gas_fee"`
+	// RecommendedGasFee is the gasFee to put in MsgWithdrawal
+	RecommendedGasFee sdk.Coin `json:"recommended_gas_fee"`
+}
+
+type QueryDepositDeficitParams struct {
+	Addr sdk.CUAddress
+}
+
+func NewQueryDepositDeficitParams(addr sdk.CUAddress) QueryDepositDeficitParams {
+	return QueryDepositDeficitParams{
+		Addr: addr,
+	}
+}
+
+// ResDepositDeficit is the unsettled deposit deficits of a CU and whether its withdrawals are frozen by them
+type ResDepositDeficit struct {
+	Address          sdk.CUAddress    `json:"address"`
+	WithdrawalFrozen bool             `json:"withdrawal_frozen"`
+	Deficits         []DepositDeficit `json:"deficits"`
+}
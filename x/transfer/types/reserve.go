@@ -0,0 +1,144 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+var (
+	liabilityLeafPrefix  = []byte{0x00}
+	liabilityInnerPrefix = []byte{0x01}
+)
+
+// Liability is the balance, including the locked part, one CU holds of a token
+type Liability struct {
+	Address sdk.CUAddress `json:"address"`
+	Amount  sdk.Int       `json:"amount"`
+}
+
+func (l Liability) hash() []byte {
+	return tmhash.Sum(bytes.Join([][]byte{liabilityLeafPrefix, l.Address, []byte(l.Amount.String())}, nil))
+}
+
+// LiabilityProofNode is a sibling node on the path from a liability leaf to the root
+type LiabilityProofNode struct {
+	Hash []byte  `json:"hash"`
+	Sum  sdk.Int `json:"sum"`
+	// Left indicates whether the sibling node is on the left side
+	Left bool `json:"left"`
+}
+
+// LiabilityProof proves a liability is included in the liability root, and contributes to its sum
+type LiabilityProof struct {
+	Liability Liability            `json:"liability"`
+	Aunts     []LiabilityProofNode `json:"aunts"`
+}
+
+// Verify checks the proof against the liability root and sum of a reserve report
+func (p LiabilityProof) Verify(root []byte, sum sdk.Int) error {
+	if p.Liability.Amount == (sdk.Int{}) || p.Liability.Amount.IsNegative() {
+		return errors.New("invalid liability amount")
+	}
+	hash, total := p.Liability.hash(), p.Liability.Amount
+	for _, aunt := range p.Aunts {
+		if aunt.Sum == (sdk.Int{}) || aunt.Sum.IsNegative() {
+			return errors.New("invalid aunt sum")
+		}
+		if aunt.Left {
+			hash = innerHash(aunt.Hash, aunt.Sum, hash, total)
+		} else {
+			hash = innerHash(hash, total, aunt.Hash, aunt.Sum)
+		}
+		total = total.Add(aunt.Sum)
+	}
+	if !bytes.Equal(hash, root) {
+		return fmt.Errorf("root mismatch, expected:%X, computed:%X", root, hash)
+	}
+	if !total.Equal(sum) {
+		return fmt.Errorf("sum mismatch, expected:%v, computed:%v", sum, total)
+	}
+	return nil
+}
+
+func innerHash(leftHash []byte, leftSum sdk.Int, rightHash []byte, rightSum sdk.Int) []byte {
+	return tmhash.Sum(bytes.Join([][]byte{liabilityInnerPrefix, leftHash, []byte(leftSum.String()), rightHash, []byte(rightSum.String())}, nil))
+}
+
+// LiabilityRoot builds a merkle sum tree over the liabilities, and returns the root hash and the sum of all
+// liabilities. If prove is not nil, the proof of the liability with that address is returned as well.
+func LiabilityRoot(liabilities []Liability, prove sdk.CUAddress) ([]byte, sdk.Int, *LiabilityProof) {
+	if len(liabilities) == 0 {
+		return nil, sdk.ZeroInt(), nil
+	}
+
+	type node struct {
+		hash []byte
+		sum  sdk.Int
+	}
+	level := make([]node, len(liabilities))
+	proveIndex := -1
+	for i, l := range liabilities {
+		level[i] = node{hash: l.hash(), sum: l.Amount}
+		if prove != nil && l.Address.Equals(prove) {
+			proveIndex = i
+		}
+	}
+
+	var proof *LiabilityProof
+	if proveIndex >= 0 {
+		proof = &LiabilityProof{Liability: liabilities[proveIndex]}
+	}
+
+	for len(level) > 1 {
+		next := make([]node, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				//odd node is promoted to the next level
+				next = append(next, level[i])
+				continue
+			}
+			left, right := level[i], level[i+1]
+			if proof != nil && proveIndex == i {
+				proof.Aunts = append(proof.Aunts, LiabilityProofNode{Hash: right.hash, Sum: right.sum, Left: false})
+			} else if proof != nil && proveIndex == i+1 {
+				proof.Aunts = append(proof.Aunts, LiabilityProofNode{Hash: left.hash, Sum: left.sum, Left: true})
+			}
+			next = append(next, node{hash: innerHash(left.hash, left.sum, right.hash, right.sum), sum: left.sum.Add(right.sum)})
+		}
+		if proof != nil {
+			proveIndex /= 2
+		}
+		level = next
+	}
+	return level[0].hash, level[0].sum, proof
+}
+
+// ExternalBalance compares the reserve recorded for a CU with the balance of its external addresses queried from chainnode
+type ExternalBalance struct {
+	CUAddress sdk.CUAddress `json:"cu_address"`
+	Addresses []string      `json:"addresses"`
+	Recorded  sdk.Int       `json:"recorded"`
+	OnChain   sdk.Int       `json:"on_chain"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// ReserveReport reconciles the liabilities against the custodied reserves of a token
+type ReserveReport struct {
+	Symbol string `json:"symbol"`
+	// Liabilities is the sum of balances of all CUs, including the locked part
+	Liabilities    sdk.Int         `json:"liabilities"`
+	LiabilityRoot  []byte          `json:"liability_root"`
+	LiabilityCount int             `json:"liability_count"`
+	LiabilityProof *LiabilityProof `json:"liability_proof,omitempty"`
+	// OpCUReserves and UserCUReserves are the asset coins, including the locked part, custodied by CUs
+	OpCUReserves     sdk.Int           `json:"opcu_reserves"`
+	UserCUReserves   sdk.Int           `json:"usercu_reserves"`
+	Reserves         sdk.Int           `json:"reserves"`
+	Surplus          sdk.Int           `json:"surplus"`
+	ExternalBalances []ExternalBalance `json:"external_balances,omitempty"`
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+func TestLiabilityRoot(t *testing.T) {
+	root, sum, proof := LiabilityRoot(nil, nil)
+	require.Nil(t, root)
+	require.Equal(t, sdk.ZeroInt(), sum)
+	require.Nil(t, proof)
+
+	for n := 1; n <= 9; n++ {
+		var liabilities []Liability
+		total := sdk.ZeroInt()
+		for i := 0; i < n; i++ {
+			addr := sdk.CUAddress([]byte{byte(i), 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19})
+			amt := sdk.NewInt(int64(i*100 + 1))
+			liabilities = append(liabilities, Liability{Address: addr, Amount: amt})
+			total = total.Add(amt)
+		}
+
+		root, sum, proof := LiabilityRoot(liabilities, nil)
+		require.NotNil(t, root)
+		require.Equal(t, total, sum)
+		require.Nil(t, proof)
+
+		for _, l := range liabilities {
+			r, s, p := LiabilityRoot(liabilities, l.Address)
+			require.Equal(t, root, r)
+			require.Equal(t, sum, s)
+			require.NotNil(t, p)
+			require.Equal(t, l, p.Liability)
+			require.Nil(t, p.Verify(root, sum), "n:%d, addr:%v", n, l.Address)
+
+			// a CU can not claim a different balance
+			tampered := *p
+			tampered.Liability.Amount = l.Amount.AddRaw(1)
+			require.NotNil(t, tampered.Verify(root, sum))
+			require.NotNil(t, tampered.Verify(root, sum.AddRaw(1)))
+		}
+	}
+
+	_, _, proof = LiabilityRoot([]Liability{{Address: sdk.CUAddress([]byte{1}), Amount: sdk.OneInt()}}, sdk.CUAddress([]byte{2}))
+	require.Nil(t, proof)
+}
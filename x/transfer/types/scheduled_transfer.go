@@ -0,0 +1,33 @@
+package types
+
+import (
+	sdk "github.com/hbtc-chain/bhchain/types"
+)
+
+// ScheduledTransfer pays Amount from From to To at Next, and repeats every Interval until Remaining runs out.
+// Next and Interval are in unix seconds of the block time if ByTime, otherwise in block heights.
+// The amount of all the remaining executions is held on From.
+type ScheduledTransfer struct {
+	ID        uint64        `json:"id"`
+	From      sdk.CUAddress `json:"from"`
+	To        sdk.CUAddress `json:"to"`
+	Amount    sdk.Coins     `json:"amount"`
+	ByTime    bool          `json:"by_time"`
+	Next      uint64        `json:"next"`
+	Interval  uint64        `json:"interval"`
+	Remaining uint64        `json:"remaining"`
+}
+
+// Reserved returns the amount held for the remaining executions
+func (s ScheduledTransfer) Reserved() sdk.Coins {
+	return MulCoins(s.Amount, s.Remaining)
+}
+
+// MulCoins returns coins multiplied by n
+func MulCoins(coins sdk.Coins, n uint64) sdk.Coins {
+	res := make(sdk.Coins, 0, len(coins))
+	for _, coin := range coins {
+		res = append(res, sdk.NewCoin(coin.Denom, coin.Amount.MulRaw(int64(n))))
+	}
+	return res
+}